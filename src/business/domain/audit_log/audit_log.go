@@ -0,0 +1,69 @@
+package auditlog
+
+import (
+	"context"
+	"go-clean/src/business/entity"
+
+	"gorm.io/gorm"
+)
+
+type Interface interface {
+	Create(ctx context.Context, auditLog entity.AuditLog) (entity.AuditLog, error)
+	GetList(ctx context.Context, param entity.AuditLogParam) ([]entity.AuditLog, error)
+	Count(ctx context.Context, param entity.AuditLogParam) (int64, error)
+}
+
+type auditLog struct {
+	db *gorm.DB
+}
+
+func Init(db *gorm.DB) Interface {
+	al := &auditLog{
+		db: db,
+	}
+
+	return al
+}
+
+func (al *auditLog) Create(ctx context.Context, auditLog entity.AuditLog) (entity.AuditLog, error) {
+	if err := al.db.WithContext(ctx).Create(&auditLog).Error; err != nil {
+		return auditLog, err
+	}
+
+	return auditLog, nil
+}
+
+func (al *auditLog) GetList(ctx context.Context, param entity.AuditLogParam) ([]entity.AuditLog, error) {
+	res := []entity.AuditLog{}
+
+	if err := al.query(ctx, param).Scopes(param.Seek).Order(param.OrderBy).Limit(param.Limit).Offset(param.Offset).Find(&res).Error; err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (al *auditLog) Count(ctx context.Context, param entity.AuditLogParam) (int64, error) {
+	var count int64
+
+	if err := al.query(ctx, param).Model(&entity.AuditLog{}).Count(&count).Error; err != nil {
+		return count, err
+	}
+
+	return count, nil
+}
+
+// query filters the audit logs of GetList and Count by param.
+func (al *auditLog) query(ctx context.Context, param entity.AuditLogParam) *gorm.DB {
+	query := al.db.WithContext(ctx).Where(param)
+
+	if !param.From.IsZero() {
+		query = query.Where("created_at >= ?", param.From)
+	}
+
+	if !param.To.IsZero() {
+		query = query.Where("created_at < ?", param.To)
+	}
+
+	return query
+}
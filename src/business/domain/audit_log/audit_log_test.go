@@ -0,0 +1,278 @@
+package auditlog
+
+import (
+	"context"
+	"database/sql"
+	"go-clean/src/business/entity"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func Test_auditLog_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "INSERT INTO"
+	query := regexp.QuoteMeta(querySql)
+
+	mockAuditLog := entity.AuditLog{
+		ActorID:    1,
+		ActorName:  "admin",
+		ActorRole:  "super_admin",
+		Action:     "payment.mark_as_paid",
+		TargetType: entity.AuditTargetPayment,
+		TargetID:   "order-1",
+		Before:     `{"status":"pending"}`,
+		After:      `{"status":"success"}`,
+		IP:         "127.0.0.1",
+		StatusCode: 200,
+	}
+
+	type args struct {
+		auditLog entity.AuditLog
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		wantErr     bool
+	}{
+		{
+			name: "failed to create audit log",
+			args: args{
+				auditLog: mockAuditLog,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			wantErr: true,
+		},
+		{
+			name: "all success",
+			args: args{
+				auditLog: mockAuditLog,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(1, 1))
+				sqlMock.ExpectCommit()
+				sqlMock.ExpectationsWereMet()
+				return sqlServer, err
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			al := Init(sqlClient)
+			_, err = al.Create(context.Background(), tt.args.auditLog)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("auditLog.Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func Test_auditLog_GetList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	querySql := "SELECT * FROM `audit_logs` WHERE `audit_logs`.`action` = ? AND created_at >= ? AND created_at < ? AND `audit_logs`.`deleted_at` IS NULL ORDER BY id desc LIMIT 10 OFFSET 10"
+	query := regexp.QuoteMeta(querySql)
+
+	mockParam := entity.AuditLogParam{
+		Action: "menu.update",
+		From:   from,
+		To:     to,
+		PaginationParam: entity.PaginationParam{
+			Limit:   10,
+			Offset:  10,
+			OrderBy: "id desc",
+		},
+	}
+
+	type args struct {
+		param entity.AuditLogParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        []entity.AuditLog
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    []entity.AuditLog{},
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"id", "actor_id", "action", "target_type", "target_id"})
+				row.AddRow(1, 1, "menu.update", entity.AuditTargetMenu, "2")
+				sqlMock.ExpectQuery(query).WithArgs("menu.update", from, to).WillReturnRows(row)
+				return sqlServer, err
+			},
+			want: []entity.AuditLog{
+				{
+					Model: gorm.Model{
+						ID: 1,
+					},
+					ActorID:    1,
+					Action:     "menu.update",
+					TargetType: entity.AuditTargetMenu,
+					TargetID:   "2",
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			al := Init(sqlClient)
+			got, err := al.GetList(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("auditLog.GetList() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_auditLog_Count(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "SELECT count(*) FROM `audit_logs` WHERE `audit_logs`.`action` = ? AND created_at >= ? AND created_at < ? AND `audit_logs`.`deleted_at` IS NULL"
+	query := regexp.QuoteMeta(querySql)
+
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	mockParam := entity.AuditLogParam{
+		Action: "menu.update",
+		From:   from,
+		To:     to,
+	}
+
+	type args struct {
+		param entity.AuditLogParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        int64
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"count"})
+				row.AddRow(3)
+				sqlMock.ExpectQuery(query).WithArgs("menu.update", from, to).WillReturnRows(row)
+				return sqlServer, err
+			},
+			want:    3,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			got, err := u.Count(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("auditLog.Count() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
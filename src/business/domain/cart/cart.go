@@ -1,22 +1,40 @@
 package cart
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"go-clean/src/business/entity"
+	"go-clean/src/lib/timeutils"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type Interface interface {
-	Create(cart entity.Cart) (entity.Cart, error)
-	GetList(param entity.CartParam) ([]entity.Cart, error)
-	GetListInByID(ids []int64) ([]entity.Cart, error)
-	GetListInByTransactionID(transaction_ids []uint) ([]entity.Cart, error)
-	GetListInByStatus(status []string, param entity.CartParam) ([]entity.Cart, error)
-	Get(param entity.CartParam) (entity.Cart, error)
-	Update(selectParam entity.CartParam, updateParam entity.UpdateCartParam) error
-	UpdatesByIDs(ids []uint, updateParam entity.UpdateCartParam) error
-	Delete(param entity.CartParam) error
+	Create(ctx context.Context, cart entity.Cart) (entity.Cart, error)
+	GetList(ctx context.Context, param entity.CartParam) ([]entity.Cart, error)
+	GetListInByID(ctx context.Context, ids []int64) ([]entity.Cart, error)
+	GetListInByTransactionID(ctx context.Context, transaction_ids []uint) ([]entity.Cart, error)
+	GetListInByStatus(ctx context.Context, status []string, param entity.CartParam) ([]entity.Cart, error)
+	CountTransactionsInByStatus(ctx context.Context, status []string) (int64, error)
+	GetTransactionIDs(ctx context.Context, param entity.CartTransactionParam) ([]uint, error)
+	CountTransactions(ctx context.Context, param entity.CartTransactionParam) (int64, error)
+	GetSalesAggregate(ctx context.Context, param entity.SalesAggregateParam) ([]entity.SalesAggregate, error)
+	GetMenuAggregate(ctx context.Context, param entity.MenuAggregateParam) ([]entity.MenuAggregate, error)
+	GetHeatmapAggregate(ctx context.Context, param entity.HeatmapAggregateParam) ([]entity.HeatmapAggregate, error)
+	GetCancellationAggregate(ctx context.Context, param entity.CancellationAggregateParam) ([]entity.CancellationAggregate, error)
+	Get(ctx context.Context, param entity.CartParam) (entity.Cart, error)
+	Update(ctx context.Context, selectParam entity.CartParam, updateParam entity.UpdateCartParam) error
+	UpdatesByIDs(ctx context.Context, ids []uint, updateParam entity.UpdateCartParam) error
+	Delete(ctx context.Context, param entity.CartParam) error
+}
+
+var periodFormats = map[string]string{
+	entity.GranularityHour:  "%Y-%m-%d %H:00",
+	entity.GranularityDay:   "%Y-%m-%d",
+	entity.GranularityWeek:  "%x-W%v",
+	entity.GranularityMonth: "%Y-%m",
 }
 
 type cart struct {
@@ -31,17 +49,17 @@ func Init(db *gorm.DB) Interface {
 	return c
 }
 
-func (c *cart) Create(cart entity.Cart) (entity.Cart, error) {
-	if err := c.db.Create(&cart).Error; err != nil {
+func (c *cart) Create(ctx context.Context, cart entity.Cart) (entity.Cart, error) {
+	if err := c.db.WithContext(ctx).Create(&cart).Error; err != nil {
 		return cart, err
 	}
 
 	return cart, nil
 }
 
-func (c *cart) GetList(param entity.CartParam) ([]entity.Cart, error) {
+func (c *cart) GetList(ctx context.Context, param entity.CartParam) ([]entity.Cart, error) {
 	cart := []entity.Cart{}
-	query := c.db.Where(param).Where("created_at LIKE ?", fmt.Sprintf("%%%s%%", param.CreatedAt))
+	query := c.db.WithContext(ctx).Where(param)
 
 	if param.CreatedAt != "" {
 		query = query.Where("created_at LIKE ?", fmt.Sprintf("%%%s%%", param.CreatedAt))
@@ -56,66 +74,227 @@ func (c *cart) GetList(param entity.CartParam) ([]entity.Cart, error) {
 	return cart, nil
 }
 
-func (c *cart) GetListInByID(ids []int64) ([]entity.Cart, error) {
+func (c *cart) GetListInByID(ctx context.Context, ids []int64) ([]entity.Cart, error) {
 	carts := []entity.Cart{}
 
-	if err := c.db.Where(ids).Find(&carts).Error; err != nil {
+	if err := c.db.WithContext(ctx).Where(ids).Find(&carts).Error; err != nil {
 		return carts, err
 	}
 
 	return carts, nil
 }
 
-func (c *cart) GetListInByTransactionID(transaction_ids []uint) ([]entity.Cart, error) {
+func (c *cart) GetListInByTransactionID(ctx context.Context, transaction_ids []uint) ([]entity.Cart, error) {
 	carts := []entity.Cart{}
 
-	if err := c.db.Where("transaction_id IN ?", transaction_ids).Find(&carts).Error; err != nil {
+	if err := c.db.WithContext(ctx).Where("transaction_id IN ?", transaction_ids).Find(&carts).Error; err != nil {
 		return carts, err
 	}
 
 	return carts, nil
 }
 
-func (c *cart) GetListInByStatus(status []string, param entity.CartParam) ([]entity.Cart, error) {
+func (c *cart) GetListInByStatus(ctx context.Context, status []string, param entity.CartParam) ([]entity.Cart, error) {
 	carts := []entity.Cart{}
 
-	if err := c.db.Where("status IN ?", status).Where(param).Find(&carts).Error; err != nil {
+	if err := c.db.WithContext(ctx).Where("status IN ?", status).Where(param).Find(&carts).Error; err != nil {
 		return carts, err
 	}
 
 	return carts, nil
 }
 
-func (c *cart) Get(param entity.CartParam) (entity.Cart, error) {
+func (c *cart) CountTransactionsInByStatus(ctx context.Context, status []string) (int64, error) {
+	var count int64
+
+	if err := c.db.WithContext(ctx).Model(&entity.Cart{}).Where("status IN ?", status).Distinct("transaction_id").Count(&count).Error; err != nil {
+		return count, err
+	}
+
+	return count, nil
+}
+
+func (c *cart) GetTransactionIDs(ctx context.Context, param entity.CartTransactionParam) ([]uint, error) {
+	ids := []uint{}
+
+	if err := c.transactionQuery(ctx, param).Scopes(param.Seek).Order(param.OrderBy).Limit(param.Limit).Offset(param.Offset).Pluck("carts.transaction_id", &ids).Error; err != nil {
+		return ids, err
+	}
+
+	return ids, nil
+}
+
+func (c *cart) CountTransactions(ctx context.Context, param entity.CartTransactionParam) (int64, error) {
+	var count int64
+
+	if err := c.transactionQuery(ctx, param).Count(&count).Error; err != nil {
+		return count, err
+	}
+
+	return count, nil
+}
+
+// transactionQuery selects the distinct transactions of GetTransactionIDs and CountTransactions, only transactions
+// with a midtrans transaction are listed.
+func (c *cart) transactionQuery(ctx context.Context, param entity.CartTransactionParam) *gorm.DB {
+	query := c.db.WithContext(ctx).Model(&entity.Cart{}).
+		Joins("JOIN midtrans_transactions ON midtrans_transactions.transaction_id = carts.transaction_id AND midtrans_transactions.deleted_at IS NULL").
+		Where("carts.status IN ?", param.Statuses)
+
+	if param.UmkmID != 0 {
+		query = query.Where("carts.umkm_id = ?", param.UmkmID)
+	}
+
+	if param.OrderIDLike != "" {
+		query = query.Where("midtrans_transactions.order_id LIKE ?", fmt.Sprintf("%%%s%%", param.OrderIDLike))
+	}
+
+	return query.Distinct("carts.transaction_id")
+}
+
+func (c *cart) GetSalesAggregate(ctx context.Context, param entity.SalesAggregateParam) ([]entity.SalesAggregate, error) {
+	result := []entity.SalesAggregate{}
+
+	periodFormat, ok := periodFormats[param.Granularity]
+	if !ok {
+		return result, errors.New("unsupported granularity")
+	}
+
+	storedOffset, targetOffset := getOffsets(param.Range, param.Location)
+
+	query := c.db.WithContext(ctx).Model(&entity.Cart{}).
+		Select("DATE_FORMAT(CONVERT_TZ(transactions.created_at, ?, ?), ?) AS period, COUNT(DISTINCT carts.transaction_id) AS total_transaction, COALESCE(SUM(carts.total_price), 0) AS total_revenue", storedOffset, targetOffset, periodFormat).
+		Joins("JOIN transactions ON transactions.id = carts.transaction_id").
+		Where("carts.status = ?", param.Status).
+		Where("transactions.created_at >= ? AND transactions.created_at < ?", param.Range.Start, param.Range.End)
+
+	if param.UmkmID != 0 {
+		query = query.Where("carts.umkm_id = ?", param.UmkmID)
+	}
+
+	if err := query.Group("period").Order("period").Scan(&result).Error; err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func (c *cart) GetMenuAggregate(ctx context.Context, param entity.MenuAggregateParam) ([]entity.MenuAggregate, error) {
+	result := []entity.MenuAggregate{}
+
+	query := c.db.WithContext(ctx).Model(&entity.Cart{}).
+		Select("carts.umkm_id, carts.menu_id, "+
+			"COALESCE(SUM(CASE WHEN carts.status = ? THEN carts.amount END), 0) AS total_quantity, "+
+			"COALESCE(SUM(CASE WHEN carts.status = ? THEN carts.total_price END), 0) AS total_revenue, "+
+			"COUNT(DISTINCT CASE WHEN carts.status = ? THEN carts.transaction_id END) AS total_transaction, "+
+			"COUNT(CASE WHEN carts.status = ? THEN 1 END) AS total_done_item, "+
+			"COUNT(CASE WHEN carts.status = ? THEN 1 END) AS total_cancel_item",
+			entity.StatusDone, entity.StatusDone, entity.StatusDone, entity.StatusDone, entity.StatusCancel).
+		Joins("JOIN transactions ON transactions.id = carts.transaction_id").
+		Where("carts.status IN ?", []string{entity.StatusDone, entity.StatusCancel}).
+		Where("transactions.created_at >= ? AND transactions.created_at < ?", param.Range.Start, param.Range.End)
+
+	if param.UmkmID != 0 {
+		query = query.Where("carts.umkm_id = ?", param.UmkmID)
+	}
+
+	if err := query.Group("carts.umkm_id, carts.menu_id").Scan(&result).Error; err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func (c *cart) GetHeatmapAggregate(ctx context.Context, param entity.HeatmapAggregateParam) ([]entity.HeatmapAggregate, error) {
+	result := []entity.HeatmapAggregate{}
+
+	storedOffset, targetOffset := getOffsets(param.Range, param.Location)
+
+	query := c.db.WithContext(ctx).Model(&entity.Cart{}).
+		Select("DAYOFWEEK(CONVERT_TZ(transactions.created_at, ?, ?)) - 1 AS day_of_week, "+
+			"HOUR(CONVERT_TZ(transactions.created_at, ?, ?)) AS hour, "+
+			"COUNT(DISTINCT carts.transaction_id) AS total_transaction, "+
+			"COALESCE(SUM(carts.total_price), 0) AS total_revenue, "+
+			"AVG(TIMESTAMPDIFF(SECOND, carts.paid_at, carts.done_at)) AS avg_prep_time",
+			storedOffset, targetOffset, storedOffset, targetOffset).
+		Joins("JOIN transactions ON transactions.id = carts.transaction_id").
+		Where("carts.status = ?", entity.StatusDone).
+		Where("transactions.created_at >= ? AND transactions.created_at < ?", param.Range.Start, param.Range.End)
+
+	if param.UmkmID != 0 {
+		query = query.Where("carts.umkm_id = ?", param.UmkmID)
+	}
+
+	if err := query.Group("day_of_week, hour").Scan(&result).Error; err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func (c *cart) GetCancellationAggregate(ctx context.Context, param entity.CancellationAggregateParam) ([]entity.CancellationAggregate, error) {
+	result := []entity.CancellationAggregate{}
+
+	refunded := "carts.status = ? AND (carts.paid_at IS NOT NULL OR transactions.is_refunded = ?)"
+
+	if err := c.db.WithContext(ctx).Model(&entity.Cart{}).
+		Select("carts.umkm_id, "+
+			"COUNT(*) AS total_item, "+
+			"COUNT(CASE WHEN carts.status = ? THEN 1 END) AS total_cancel_item, "+
+			"COUNT(CASE WHEN "+refunded+" THEN 1 END) AS total_refund_item, "+
+			"COALESCE(SUM(CASE WHEN "+refunded+" THEN carts.total_price END), 0) AS total_refund_amount",
+			entity.StatusCancel, entity.StatusCancel, true, entity.StatusCancel, true).
+		Joins("JOIN transactions ON transactions.id = carts.transaction_id").
+		Where("carts.status IN ?", []string{entity.StatusDone, entity.StatusCancel}).
+		Where("transactions.created_at >= ? AND transactions.created_at < ?", param.Range.Start, param.Range.End).
+		Group("carts.umkm_id").
+		Scan(&result).Error; err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func (c *cart) Get(ctx context.Context, param entity.CartParam) (entity.Cart, error) {
 	cart := entity.Cart{}
 
-	if err := c.db.Where(param).First(&cart).Error; err != nil {
+	if err := c.db.WithContext(ctx).Where(param).First(&cart).Error; err != nil {
 		return cart, err
 	}
 
 	return cart, nil
 }
 
-func (c *cart) Update(selectParam entity.CartParam, updateParam entity.UpdateCartParam) error {
-	if err := c.db.Model(entity.Cart{}).Where(selectParam).Updates(updateParam).Error; err != nil {
+func (c *cart) Update(ctx context.Context, selectParam entity.CartParam, updateParam entity.UpdateCartParam) error {
+	if err := c.db.WithContext(ctx).Model(entity.Cart{}).Where(selectParam).Updates(updateParam).Error; err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (c *cart) UpdatesByIDs(ids []uint, updateParam entity.UpdateCartParam) error {
-	if err := c.db.Model(entity.Cart{}).Where("id IN ?", ids).Updates(updateParam).Error; err != nil {
+func (c *cart) UpdatesByIDs(ctx context.Context, ids []uint, updateParam entity.UpdateCartParam) error {
+	if err := c.db.WithContext(ctx).Model(entity.Cart{}).Where("id IN ?", ids).Updates(updateParam).Error; err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (c *cart) Delete(param entity.CartParam) error {
-	if err := c.db.Where(param).Delete(&entity.Cart{}).Error; err != nil {
+func (c *cart) Delete(ctx context.Context, param entity.CartParam) error {
+	if err := c.db.WithContext(ctx).Where(param).Delete(&entity.Cart{}).Error; err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// getOffsets returns the offset created_at is stored in, the server's local time
+// as set by the loc option of the DSN, and the offset of the requested location.
+func getOffsets(tr entity.TimeRange, location *time.Location) (string, string) {
+	if location == nil {
+		location = time.Local
+	}
+
+	return timeutils.UTCOffset(tr.Start.In(time.Local)), timeutils.UTCOffset(tr.Start.In(location))
+}
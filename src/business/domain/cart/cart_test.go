@@ -1,11 +1,13 @@
 package cart
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"go-clean/src/business/entity"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -84,7 +86,7 @@ func Test_cart_Create(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			_, err = u.Create(tt.args.cart)
+			_, err = u.Create(context.Background(), tt.args.cart)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("cart.Create() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -97,7 +99,7 @@ func Test_cart_GetList(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	querySql := "SELECT * FROM `carts` WHERE created_at LIKE ? AND `carts`.`deleted_at` IS NULL"
+	querySql := "SELECT * FROM `carts` WHERE `carts`.`deleted_at` IS NULL"
 	query := regexp.QuoteMeta(querySql)
 
 	mockParam := entity.CartParam{}
@@ -164,7 +166,7 @@ func Test_cart_GetList(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			got, err := u.GetList(tt.args.param)
+			got, err := u.GetList(context.Background(), tt.args.param)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("cart.GetAll() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -245,7 +247,7 @@ func Test_cart_GetListInByID(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			got, err := u.GetListInByID(tt.args.param)
+			got, err := u.GetListInByID(context.Background(), tt.args.param)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("cart.GetListInByID() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -255,6 +257,391 @@ func Test_cart_GetListInByID(t *testing.T) {
 	}
 }
 
+func Test_cart_GetSalesAggregate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "SELECT DATE_FORMAT(CONVERT_TZ(transactions.created_at, ?, ?), ?) AS period, COUNT(DISTINCT carts.transaction_id) AS total_transaction, COALESCE(SUM(carts.total_price), 0) AS total_revenue FROM `carts` JOIN transactions ON transactions.id = carts.transaction_id WHERE carts.status = ? AND (transactions.created_at >= ? AND transactions.created_at < ?) AND carts.umkm_id = ? AND `carts`.`deleted_at` IS NULL GROUP BY `period` ORDER BY period"
+	query := regexp.QuoteMeta(querySql)
+
+	now := time.Now()
+	mockParam := entity.SalesAggregateParam{
+		UmkmID:      1,
+		Status:      entity.StatusDone,
+		Granularity: entity.GranularityDay,
+		Range: entity.TimeRange{
+			Start: now.AddDate(0, 0, -1),
+			End:   now,
+		},
+	}
+
+	type args struct {
+		param entity.SalesAggregateParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        []entity.SalesAggregate
+		wantErr     bool
+	}{
+		{
+			name: "unsupported granularity",
+			args: args{
+				param: entity.SalesAggregateParam{
+					Granularity: "decade",
+				},
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, _, err := sqlmock.New()
+				return sqlServer, err
+			},
+			want:    []entity.SalesAggregate{},
+			wantErr: true,
+		},
+		{
+			name: "failed to exec query",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    []entity.SalesAggregate{},
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"period", "total_transaction", "total_revenue"})
+				row.AddRow("2023-01-01", 2, 30000)
+				sqlMock.ExpectQuery(query).WillReturnRows(row)
+				return sqlServer, err
+			},
+			want: []entity.SalesAggregate{
+				{
+					Period:           "2023-01-01",
+					TotalTransaction: 2,
+					TotalRevenue:     30000,
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			got, err := u.GetSalesAggregate(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("cart.GetSalesAggregate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_cart_GetMenuAggregate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "SELECT carts.umkm_id, carts.menu_id, COALESCE(SUM(CASE WHEN carts.status = ? THEN carts.amount END), 0) AS total_quantity, COALESCE(SUM(CASE WHEN carts.status = ? THEN carts.total_price END), 0) AS total_revenue, COUNT(DISTINCT CASE WHEN carts.status = ? THEN carts.transaction_id END) AS total_transaction, COUNT(CASE WHEN carts.status = ? THEN 1 END) AS total_done_item, COUNT(CASE WHEN carts.status = ? THEN 1 END) AS total_cancel_item FROM `carts` JOIN transactions ON transactions.id = carts.transaction_id WHERE carts.status IN (?,?) AND (transactions.created_at >= ? AND transactions.created_at < ?) AND carts.umkm_id = ? AND `carts`.`deleted_at` IS NULL GROUP BY carts.umkm_id, carts.menu_id"
+	query := regexp.QuoteMeta(querySql)
+
+	now := time.Now()
+	mockParam := entity.MenuAggregateParam{
+		UmkmID: 1,
+		Range: entity.TimeRange{
+			Start: now.AddDate(0, 0, -1),
+			End:   now,
+		},
+	}
+
+	type args struct {
+		param entity.MenuAggregateParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        []entity.MenuAggregate
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    []entity.MenuAggregate{},
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"umkm_id", "menu_id", "total_quantity", "total_revenue", "total_transaction", "total_done_item", "total_cancel_item"})
+				row.AddRow(1, 1, 5, 50000, 3, 3, 1)
+				sqlMock.ExpectQuery(query).WillReturnRows(row)
+				return sqlServer, err
+			},
+			want: []entity.MenuAggregate{
+				{
+					UmkmID:           1,
+					MenuID:           1,
+					TotalQuantity:    5,
+					TotalRevenue:     50000,
+					TotalTransaction: 3,
+					TotalDoneItem:    3,
+					TotalCancelItem:  1,
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			got, err := u.GetMenuAggregate(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("cart.GetMenuAggregate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_cart_GetHeatmapAggregate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "SELECT DAYOFWEEK(CONVERT_TZ(transactions.created_at, ?, ?)) - 1 AS day_of_week, HOUR(CONVERT_TZ(transactions.created_at, ?, ?)) AS hour, COUNT(DISTINCT carts.transaction_id) AS total_transaction, COALESCE(SUM(carts.total_price), 0) AS total_revenue, AVG(TIMESTAMPDIFF(SECOND, carts.paid_at, carts.done_at)) AS avg_prep_time FROM `carts` JOIN transactions ON transactions.id = carts.transaction_id WHERE carts.status = ? AND (transactions.created_at >= ? AND transactions.created_at < ?) AND carts.umkm_id = ? AND `carts`.`deleted_at` IS NULL GROUP BY day_of_week, hour"
+	query := regexp.QuoteMeta(querySql)
+
+	now := time.Now()
+	mockParam := entity.HeatmapAggregateParam{
+		UmkmID: 1,
+		Range: entity.TimeRange{
+			Start: now.AddDate(0, 0, -7),
+			End:   now,
+		},
+		Location: time.UTC,
+	}
+
+	avgPrepTime := float64(300)
+
+	type args struct {
+		param entity.HeatmapAggregateParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        []entity.HeatmapAggregate
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    []entity.HeatmapAggregate{},
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"day_of_week", "hour", "total_transaction", "total_revenue", "avg_prep_time"})
+				row.AddRow(1, 12, 2, 30000, 300)
+				row.AddRow(2, 13, 1, 10000, nil)
+				sqlMock.ExpectQuery(query).WillReturnRows(row)
+				return sqlServer, err
+			},
+			want: []entity.HeatmapAggregate{
+				{
+					DayOfWeek:        1,
+					Hour:             12,
+					TotalTransaction: 2,
+					TotalRevenue:     30000,
+					AvgPrepTime:      &avgPrepTime,
+				},
+				{
+					DayOfWeek:        2,
+					Hour:             13,
+					TotalTransaction: 1,
+					TotalRevenue:     10000,
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			got, err := u.GetHeatmapAggregate(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("cart.GetHeatmapAggregate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_cart_GetCancellationAggregate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "SELECT carts.umkm_id, COUNT(*) AS total_item, COUNT(CASE WHEN carts.status = ? THEN 1 END) AS total_cancel_item, COUNT(CASE WHEN carts.status = ? AND (carts.paid_at IS NOT NULL OR transactions.is_refunded = ?) THEN 1 END) AS total_refund_item, COALESCE(SUM(CASE WHEN carts.status = ? AND (carts.paid_at IS NOT NULL OR transactions.is_refunded = ?) THEN carts.total_price END), 0) AS total_refund_amount FROM `carts` JOIN transactions ON transactions.id = carts.transaction_id WHERE carts.status IN (?,?) AND (transactions.created_at >= ? AND transactions.created_at < ?) AND `carts`.`deleted_at` IS NULL GROUP BY `carts`.`umkm_id`"
+	query := regexp.QuoteMeta(querySql)
+
+	now := time.Now()
+	mockParam := entity.CancellationAggregateParam{
+		Range: entity.TimeRange{
+			Start: now.AddDate(0, 0, -7),
+			End:   now,
+		},
+	}
+
+	type args struct {
+		param entity.CancellationAggregateParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        []entity.CancellationAggregate
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    []entity.CancellationAggregate{},
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"umkm_id", "total_item", "total_cancel_item", "total_refund_item", "total_refund_amount"})
+				row.AddRow(1, 4, 1, 1, 15000)
+				sqlMock.ExpectQuery(query).WillReturnRows(row)
+				return sqlServer, err
+			},
+			want: []entity.CancellationAggregate{
+				{
+					UmkmID:            1,
+					TotalItem:         4,
+					TotalCancelItem:   1,
+					TotalRefundItem:   1,
+					TotalRefundAmount: 15000,
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			got, err := u.GetCancellationAggregate(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("cart.GetCancellationAggregate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func Test_cart_Get(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -324,7 +711,7 @@ func Test_cart_Get(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			got, err := u.Get(tt.args.param)
+			got, err := u.Get(context.Background(), tt.args.param)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("cart.Get() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -406,7 +793,7 @@ func Test_cart_Update(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			err = u.Update(tt.args.selectParam, tt.args.updateParam)
+			err = u.Update(context.Background(), tt.args.selectParam, tt.args.updateParam)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("cart.Update() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -480,7 +867,7 @@ func Test_blog_Delete(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			err = u.Delete(tt.args.param)
+			err = u.Delete(context.Background(), tt.args.param)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("cart.Delete() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -488,3 +875,241 @@ func Test_blog_Delete(t *testing.T) {
 		})
 	}
 }
+
+func Test_cart_CountTransactionsInByStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "SELECT COUNT(DISTINCT(`transaction_id`)) FROM `carts` WHERE status IN (?,?) AND `carts`.`deleted_at` IS NULL"
+	query := regexp.QuoteMeta(querySql)
+
+	mockParam := []string{entity.StatusUnpaid, entity.StatusPaid}
+
+	type args struct {
+		param []string
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        int64
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"count"})
+				row.AddRow(3)
+				sqlMock.ExpectQuery(query).WillReturnRows(row)
+				return sqlServer, err
+			},
+			want:    3,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			got, err := u.CountTransactionsInByStatus(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("cart.CountTransactionsInByStatus() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_cart_CountTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "SELECT COUNT(DISTINCT(`carts`.`transaction_id`)) FROM `carts` JOIN midtrans_transactions ON midtrans_transactions.transaction_id = carts.transaction_id AND midtrans_transactions.deleted_at IS NULL WHERE carts.status IN (?,?) AND carts.umkm_id = ? AND midtrans_transactions.order_id LIKE ? AND `carts`.`deleted_at` IS NULL"
+	query := regexp.QuoteMeta(querySql)
+
+	mockParam := entity.CartTransactionParam{
+		UmkmID:      1,
+		Statuses:    []string{entity.StatusPaid, entity.StatusDone},
+		OrderIDLike: "order",
+	}
+
+	type args struct {
+		param entity.CartTransactionParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        int64
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"count"})
+				row.AddRow(3)
+				sqlMock.ExpectQuery(query).WithArgs(entity.StatusPaid, entity.StatusDone, 1, "%order%").WillReturnRows(row)
+				return sqlServer, err
+			},
+			want:    3,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			got, err := u.CountTransactions(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("cart.CountTransactions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_cart_GetTransactionIDs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "SELECT DISTINCT carts.transaction_id FROM `carts` JOIN midtrans_transactions ON midtrans_transactions.transaction_id = carts.transaction_id AND midtrans_transactions.deleted_at IS NULL WHERE carts.status IN (?,?) AND carts.umkm_id = ? AND `carts`.`deleted_at` IS NULL ORDER BY carts.transaction_id desc LIMIT 10 OFFSET 10"
+	query := regexp.QuoteMeta(querySql)
+
+	mockParam := entity.CartTransactionParam{
+		UmkmID:   1,
+		Statuses: []string{entity.StatusPaid, entity.StatusDone},
+		PaginationParam: entity.PaginationParam{
+			Limit:   10,
+			Offset:  10,
+			OrderBy: "carts.transaction_id desc",
+		},
+	}
+
+	type args struct {
+		param entity.CartTransactionParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        []uint
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    []uint{},
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"transaction_id"})
+				row.AddRow(5)
+				row.AddRow(4)
+				sqlMock.ExpectQuery(query).WithArgs(entity.StatusPaid, entity.StatusDone, 1).WillReturnRows(row)
+				return sqlServer, err
+			},
+			want:    []uint{5, 4},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			got, err := u.GetTransactionIDs(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("cart.GetTransactionIDs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
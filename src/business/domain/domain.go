@@ -1,10 +1,15 @@
 package domain
 
 import (
+	auditlog "go-clean/src/business/domain/audit_log"
 	"go-clean/src/business/domain/cart"
+	loginevent "go-clean/src/business/domain/login_event"
 	"go-clean/src/business/domain/menu"
 	"go-clean/src/business/domain/midtrans"
 	midtranstransaction "go-clean/src/business/domain/midtrans_transaction"
+	passwordresetcode "go-clean/src/business/domain/password_reset_code"
+	refreshtoken "go-clean/src/business/domain/refresh_token"
+	revokedtoken "go-clean/src/business/domain/revoked_token"
 	"go-clean/src/business/domain/transaction"
 	"go-clean/src/business/domain/umkm"
 	"go-clean/src/business/domain/user"
@@ -23,6 +28,11 @@ type Domains struct {
 	Midtrans            midtrans.Interface
 	MidtransTransaction midtranstransaction.Interface
 	Withdraw            withdraw.Interface
+	RefreshToken        refreshtoken.Interface
+	RevokedToken        revokedtoken.Interface
+	PasswordResetCode   passwordresetcode.Interface
+	LoginEvent          loginevent.Interface
+	AuditLog            auditlog.Interface
 }
 
 func Init(db *gorm.DB, m midtransSdk.Interface) *Domains {
@@ -35,6 +45,11 @@ func Init(db *gorm.DB, m midtransSdk.Interface) *Domains {
 		Midtrans:            midtrans.Init(m),
 		MidtransTransaction: midtranstransaction.Init(db),
 		Withdraw:            withdraw.Init(db),
+		RefreshToken:        refreshtoken.Init(db),
+		RevokedToken:        revokedtoken.Init(db),
+		PasswordResetCode:   passwordresetcode.Init(db),
+		LoginEvent:          loginevent.Init(db),
+		AuditLog:            auditlog.Init(db),
 	}
 
 	return d
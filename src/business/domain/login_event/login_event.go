@@ -0,0 +1,100 @@
+package loginevent
+
+import (
+	"context"
+	"go-clean/src/business/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Interface interface {
+	Create(ctx context.Context, loginEvent entity.LoginEvent) (entity.LoginEvent, error)
+	GetList(ctx context.Context, param entity.LoginEventParam) ([]entity.LoginEvent, error)
+	Count(ctx context.Context, param entity.LoginEventParam) (int64, error)
+	GetFailureSummary(ctx context.Context, param entity.LoginFailureParam) (entity.LoginFailureSummary, error)
+}
+
+type loginEvent struct {
+	db *gorm.DB
+}
+
+func Init(db *gorm.DB) Interface {
+	le := &loginEvent{
+		db: db,
+	}
+
+	return le
+}
+
+func (le *loginEvent) Create(ctx context.Context, loginEvent entity.LoginEvent) (entity.LoginEvent, error) {
+	if err := le.db.WithContext(ctx).Create(&loginEvent).Error; err != nil {
+		return loginEvent, err
+	}
+
+	return loginEvent, nil
+}
+
+func (le *loginEvent) GetList(ctx context.Context, param entity.LoginEventParam) ([]entity.LoginEvent, error) {
+	res := []entity.LoginEvent{}
+	if err := le.db.WithContext(ctx).Where(param).Scopes(param.Seek).Order(param.OrderBy).Limit(param.Limit).Offset(param.Offset).Find(&res).Error; err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (le *loginEvent) Count(ctx context.Context, param entity.LoginEventParam) (int64, error) {
+	var count int64
+
+	if err := le.db.WithContext(ctx).Model(&entity.LoginEvent{}).Where(param).Count(&count).Error; err != nil {
+		return count, err
+	}
+
+	return count, nil
+}
+
+// GetFailureSummary counts the failures of the username or IP since param.Since and their last success, a success resets
+// the count. Rejections of a locked account don't count, the lock already holds the caller back.
+func (le *loginEvent) GetFailureSummary(ctx context.Context, param entity.LoginFailureParam) (entity.LoginFailureSummary, error) {
+	res := entity.LoginFailureSummary{}
+
+	lastSuccess := struct {
+		LastSuccessAt *time.Time
+	}{}
+	if err := le.failureQuery(ctx, param).
+		Select("MAX(created_at) AS last_success_at").
+		Where("success = ?", true).
+		Scan(&lastSuccess).Error; err != nil {
+		return res, err
+	}
+
+	since := param.Since
+	if lastSuccess.LastSuccessAt != nil && lastSuccess.LastSuccessAt.After(since) {
+		since = *lastSuccess.LastSuccessAt
+	}
+
+	if err := le.failureQuery(ctx, param).
+		Select("COUNT(*) AS total_failure, MAX(created_at) AS last_failure_at").
+		Where("success = ? AND reason <> ? AND created_at >= ?", false, entity.LoginReasonLocked, since).
+		Scan(&res).Error; err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// failureQuery filters the events of GetFailureSummary by username or IP.
+func (le *loginEvent) failureQuery(ctx context.Context, param entity.LoginFailureParam) *gorm.DB {
+	query := le.db.WithContext(ctx).Model(&entity.LoginEvent{})
+
+	if param.Username != "" {
+		query = query.Where("username = ?", param.Username)
+	}
+
+	if param.IP != "" {
+		query = query.Where("ip = ?", param.IP)
+	}
+
+	return query
+}
@@ -0,0 +1,383 @@
+package loginevent
+
+import (
+	"context"
+	"database/sql"
+	"go-clean/src/business/entity"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func Test_loginEvent_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "INSERT INTO"
+	query := regexp.QuoteMeta(querySql)
+
+	mockLoginEvent := entity.LoginEvent{
+		UserID:   1,
+		Username: "username",
+		IP:       "127.0.0.1",
+		Success:  false,
+		Reason:   entity.LoginReasonWrongPassword,
+	}
+
+	type args struct {
+		loginEvent entity.LoginEvent
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		wantErr     bool
+	}{
+		{
+			name: "failed to create login event",
+			args: args{
+				loginEvent: mockLoginEvent,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			wantErr: true,
+		},
+		{
+			name: "all success",
+			args: args{
+				loginEvent: mockLoginEvent,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(1, 1))
+				sqlMock.ExpectCommit()
+				sqlMock.ExpectationsWereMet()
+				return sqlServer, err
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			le := Init(sqlClient)
+			_, err = le.Create(context.Background(), tt.args.loginEvent)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("loginEvent.Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func Test_loginEvent_GetList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "SELECT * FROM `login_events` WHERE `login_events`.`username` = ? AND `login_events`.`deleted_at` IS NULL ORDER BY created_at desc LIMIT 10 OFFSET 10"
+	query := regexp.QuoteMeta(querySql)
+
+	mockParam := entity.LoginEventParam{
+		Username: "username",
+		PaginationParam: entity.PaginationParam{
+			Limit:   10,
+			Offset:  10,
+			OrderBy: "created_at desc",
+		},
+	}
+
+	type args struct {
+		param entity.LoginEventParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        []entity.LoginEvent
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    []entity.LoginEvent{},
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"id", "user_id", "username", "ip", "success", "reason"})
+				row.AddRow(1, 1, "username", "127.0.0.1", false, entity.LoginReasonWrongPassword)
+				sqlMock.ExpectQuery(query).WillReturnRows(row)
+				return sqlServer, err
+			},
+			want: []entity.LoginEvent{
+				{
+					Model: gorm.Model{
+						ID: 1,
+					},
+					UserID:   1,
+					Username: "username",
+					IP:       "127.0.0.1",
+					Success:  false,
+					Reason:   entity.LoginReasonWrongPassword,
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			le := Init(sqlClient)
+			got, err := le.GetList(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("loginEvent.GetList() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_loginEvent_GetFailureSummary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	successQuery := regexp.QuoteMeta("SELECT MAX(created_at) AS last_success_at FROM `login_events` WHERE ip = ? AND success = ? AND `login_events`.`deleted_at` IS NULL")
+	query := regexp.QuoteMeta("SELECT COUNT(*) AS total_failure, MAX(created_at) AS last_failure_at FROM `login_events` WHERE ip = ? AND (success = ? AND reason <> ? AND created_at >= ?) AND `login_events`.`deleted_at` IS NULL")
+
+	since := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	lastSuccessAt := time.Date(2022, 1, 1, 0, 5, 0, 0, time.UTC)
+	lastFailureAt := time.Date(2022, 1, 1, 0, 10, 0, 0, time.UTC)
+
+	mockParam := entity.LoginFailureParam{
+		IP:    "127.0.0.1",
+		Since: since,
+	}
+
+	type args struct {
+		param entity.LoginFailureParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        entity.LoginFailureSummary
+		wantErr     bool
+	}{
+		{
+			name: "failed to get last success",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(successQuery).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    entity.LoginFailureSummary{},
+			wantErr: true,
+		},
+		{
+			name: "failed to exec query",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(successQuery).WillReturnRows(sqlmock.NewRows([]string{"last_success_at"}).AddRow(nil))
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    entity.LoginFailureSummary{},
+			wantErr: true,
+		},
+		{
+			name: "no success in the window",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(successQuery).WithArgs("127.0.0.1", true).WillReturnRows(sqlmock.NewRows([]string{"last_success_at"}).AddRow(nil))
+				row := sqlmock.NewRows([]string{"total_failure", "last_failure_at"})
+				row.AddRow(4, lastFailureAt)
+				sqlMock.ExpectQuery(query).WithArgs("127.0.0.1", false, entity.LoginReasonLocked, since).WillReturnRows(row)
+				return sqlServer, err
+			},
+			want: entity.LoginFailureSummary{
+				TotalFailure:  4,
+				LastFailureAt: &lastFailureAt,
+			},
+			wantErr: false,
+		},
+		{
+			name: "counts failures after the last success",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(successQuery).WithArgs("127.0.0.1", true).WillReturnRows(sqlmock.NewRows([]string{"last_success_at"}).AddRow(lastSuccessAt))
+				row := sqlmock.NewRows([]string{"total_failure", "last_failure_at"})
+				row.AddRow(1, lastFailureAt)
+				sqlMock.ExpectQuery(query).WithArgs("127.0.0.1", false, entity.LoginReasonLocked, lastSuccessAt).WillReturnRows(row)
+				return sqlServer, err
+			},
+			want: entity.LoginFailureSummary{
+				TotalFailure:  1,
+				LastFailureAt: &lastFailureAt,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			le := Init(sqlClient)
+			got, err := le.GetFailureSummary(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("loginEvent.GetFailureSummary() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_loginEvent_Count(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "SELECT count(*) FROM `login_events` WHERE `login_events`.`username` = ? AND `login_events`.`deleted_at` IS NULL"
+	query := regexp.QuoteMeta(querySql)
+
+	mockParam := entity.LoginEventParam{
+		Username: "username",
+	}
+
+	type args struct {
+		param entity.LoginEventParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        int64
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"count"})
+				row.AddRow(3)
+				sqlMock.ExpectQuery(query).WithArgs("username").WillReturnRows(row)
+				return sqlServer, err
+			},
+			want:    3,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			got, err := u.Count(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("loginEvent.Count() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
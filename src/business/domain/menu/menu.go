@@ -1,6 +1,7 @@
 package menu
 
 import (
+	"context"
 	"fmt"
 	"go-clean/src/business/entity"
 
@@ -8,12 +9,13 @@ import (
 )
 
 type Interface interface {
-	Create(menu entity.Menu) (entity.Menu, error)
-	GetAll(param entity.MenuParam) ([]entity.Menu, error)
-	GetListInByID(ids []int64) ([]entity.Menu, error)
-	Get(param entity.MenuParam) (entity.Menu, error)
-	Update(selectParam entity.MenuParam, updateParam entity.UpdateMenuParam) error
-	Delete(param entity.MenuParam) error
+	Create(ctx context.Context, menu entity.Menu) (entity.Menu, error)
+	GetAll(ctx context.Context, param entity.MenuParam) ([]entity.Menu, error)
+	GetListInByID(ctx context.Context, ids []int64) ([]entity.Menu, error)
+	Count(ctx context.Context, param entity.MenuParam) (int64, error)
+	Get(ctx context.Context, param entity.MenuParam) (entity.Menu, error)
+	Update(ctx context.Context, selectParam entity.MenuParam, updateParam entity.UpdateMenuParam) error
+	Delete(ctx context.Context, param entity.MenuParam) error
 }
 
 type menu struct {
@@ -28,54 +30,69 @@ func Init(db *gorm.DB) Interface {
 	return m
 }
 
-func (m *menu) Create(menu entity.Menu) (entity.Menu, error) {
-	if err := m.db.Create(&menu).Error; err != nil {
+func (m *menu) Create(ctx context.Context, menu entity.Menu) (entity.Menu, error) {
+	if err := m.db.WithContext(ctx).Create(&menu).Error; err != nil {
 		return menu, err
 	}
 
 	return menu, nil
 }
 
-func (m *menu) GetAll(param entity.MenuParam) ([]entity.Menu, error) {
+func (m *menu) GetAll(ctx context.Context, param entity.MenuParam) ([]entity.Menu, error) {
 	menus := []entity.Menu{}
 
-	if err := m.db.Where(param).Where("name LIKE ?", fmt.Sprintf("%%%s%%", param.Name)).Find(&menus).Error; err != nil {
+	if err := m.query(ctx, param).Scopes(param.Seek).Order(param.OrderBy).Limit(param.Limit).Offset(param.Offset).Find(&menus).Error; err != nil {
 		return menus, err
 	}
 
 	return menus, nil
 }
 
-func (m *menu) GetListInByID(ids []int64) ([]entity.Menu, error) {
+func (m *menu) Count(ctx context.Context, param entity.MenuParam) (int64, error) {
+	var count int64
+
+	if err := m.query(ctx, param).Model(&entity.Menu{}).Count(&count).Error; err != nil {
+		return count, err
+	}
+
+	return count, nil
+}
+
+// query filters the menus of GetAll and Count by param.
+func (m *menu) query(ctx context.Context, param entity.MenuParam) *gorm.DB {
+	return m.db.WithContext(ctx).Where(param).Where("name LIKE ?", fmt.Sprintf("%%%s%%", param.Name))
+}
+
+func (m *menu) GetListInByID(ctx context.Context, ids []int64) ([]entity.Menu, error) {
 	menus := []entity.Menu{}
 
-	if err := m.db.Where(ids).Find(&menus).Error; err != nil {
+	if err := m.db.WithContext(ctx).Where(ids).Find(&menus).Error; err != nil {
 		return menus, err
 	}
 
 	return menus, nil
 }
 
-func (m *menu) Get(param entity.MenuParam) (entity.Menu, error) {
+func (m *menu) Get(ctx context.Context, param entity.MenuParam) (entity.Menu, error) {
 	menu := entity.Menu{}
 
-	if err := m.db.Where(param).First(&menu).Error; err != nil {
+	if err := m.db.WithContext(ctx).Where(param).First(&menu).Error; err != nil {
 		return menu, err
 	}
 
 	return menu, nil
 }
 
-func (m *menu) Update(selectParam entity.MenuParam, updateParam entity.UpdateMenuParam) error {
-	if err := m.db.Model(entity.Menu{}).Where(selectParam).Updates(updateParam).Error; err != nil {
+func (m *menu) Update(ctx context.Context, selectParam entity.MenuParam, updateParam entity.UpdateMenuParam) error {
+	if err := m.db.WithContext(ctx).Model(entity.Menu{}).Where(selectParam).Updates(updateParam).Error; err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (m *menu) Delete(param entity.MenuParam) error {
-	if err := m.db.Where(param).Delete(&entity.Menu{}).Error; err != nil {
+func (m *menu) Delete(ctx context.Context, param entity.MenuParam) error {
+	if err := m.db.WithContext(ctx).Where(param).Delete(&entity.Menu{}).Error; err != nil {
 		return err
 	}
 
@@ -1,6 +1,7 @@
 package menu
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"go-clean/src/business/entity"
@@ -82,7 +83,7 @@ func Test_menu_Create(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			_, err = u.Create(tt.args.menu)
+			_, err = u.Create(context.Background(), tt.args.menu)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("menu.Create() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -160,7 +161,7 @@ func Test_menu_GetAll(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			got, err := u.GetAll(tt.args.param)
+			got, err := u.GetAll(context.Background(), tt.args.param)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("menu.GetAll() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -239,7 +240,7 @@ func Test_menu_GetListInByID(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			got, err := u.GetListInByID(tt.args.param)
+			got, err := u.GetListInByID(context.Background(), tt.args.param)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("menu.GetListInByID() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -316,7 +317,7 @@ func Test_menu_Get(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			got, err := u.Get(tt.args.param)
+			got, err := u.Get(context.Background(), tt.args.param)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("menu.Get() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -398,7 +399,7 @@ func Test_menu_Update(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			err = u.Update(tt.args.selectParam, tt.args.updateParam)
+			err = u.Update(context.Background(), tt.args.selectParam, tt.args.updateParam)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("menu.Update() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -472,7 +473,7 @@ func Test_menu_Delete(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			err = u.Delete(tt.args.param)
+			err = u.Delete(context.Background(), tt.args.param)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("menu.Delete() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -480,3 +481,81 @@ func Test_menu_Delete(t *testing.T) {
 		})
 	}
 }
+
+func Test_menu_Count(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "SELECT count(*) FROM `menus` WHERE `menus`.`umkm_id` = ? AND name LIKE ? AND `menus`.`deleted_at` IS NULL"
+	query := regexp.QuoteMeta(querySql)
+
+	mockParam := entity.MenuParam{
+		UmkmID: 1,
+		Name:   "nasi",
+	}
+
+	type args struct {
+		param entity.MenuParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        int64
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"count"})
+				row.AddRow(3)
+				sqlMock.ExpectQuery(query).WithArgs(1, "%nasi%").WillReturnRows(row)
+				return sqlServer, err
+			},
+			want:    3,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			got, err := u.Count(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("menu.Count() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
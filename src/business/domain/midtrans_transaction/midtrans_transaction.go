@@ -1,6 +1,7 @@
 package midtranstransaction
 
 import (
+	"context"
 	"fmt"
 	"go-clean/src/business/entity"
 
@@ -8,11 +9,13 @@ import (
 )
 
 type Interface interface {
-	Create(midtransTransaction entity.MidtransTransaction) (entity.MidtransTransaction, error)
-	Get(param entity.MidtransTransactionParam) (entity.MidtransTransaction, error)
-	GetList(param entity.MidtransTransactionParam) ([]entity.MidtransTransaction, error)
-	GetListByTrxIDs(ids []uint, param entity.MidtransTransactionParam) ([]entity.MidtransTransaction, error)
-	Update(selectParam entity.MidtransTransactionParam, updateParam entity.UpdateMidtransTransactionParam) error
+	Create(ctx context.Context, midtransTransaction entity.MidtransTransaction) (entity.MidtransTransaction, error)
+	Get(ctx context.Context, param entity.MidtransTransactionParam) (entity.MidtransTransaction, error)
+	GetList(ctx context.Context, param entity.MidtransTransactionParam) ([]entity.MidtransTransaction, error)
+	GetListByTrxIDs(ctx context.Context, ids []uint, param entity.MidtransTransactionParam) ([]entity.MidtransTransaction, error)
+	Count(ctx context.Context, param entity.MidtransTransactionParam) (int64, error)
+	GetPaymentAggregate(ctx context.Context, param entity.PaymentAggregateParam) ([]entity.PaymentAggregate, error)
+	Update(ctx context.Context, selectParam entity.MidtransTransactionParam, updateParam entity.UpdateMidtransTransactionParam) error
 }
 
 type midtransTransaction struct {
@@ -27,26 +30,46 @@ func Init(db *gorm.DB) Interface {
 	return mt
 }
 
-func (mt *midtransTransaction) Create(midtransTransaction entity.MidtransTransaction) (entity.MidtransTransaction, error) {
-	if err := mt.db.Create(&midtransTransaction).Error; err != nil {
+func (mt *midtransTransaction) Create(ctx context.Context, midtransTransaction entity.MidtransTransaction) (entity.MidtransTransaction, error) {
+	if err := mt.db.WithContext(ctx).Create(&midtransTransaction).Error; err != nil {
 		return midtransTransaction, err
 	}
 
 	return midtransTransaction, nil
 }
 
-func (mt *midtransTransaction) Get(param entity.MidtransTransactionParam) (entity.MidtransTransaction, error) {
+func (mt *midtransTransaction) Get(ctx context.Context, param entity.MidtransTransactionParam) (entity.MidtransTransaction, error) {
 	res := entity.MidtransTransaction{}
-	if err := mt.db.Where(param).First(&res).Error; err != nil {
+	if err := mt.db.WithContext(ctx).Where(param).First(&res).Error; err != nil {
 		return res, err
 	}
 
 	return res, nil
 }
 
-func (mt *midtransTransaction) GetList(param entity.MidtransTransactionParam) ([]entity.MidtransTransaction, error) {
+func (mt *midtransTransaction) GetList(ctx context.Context, param entity.MidtransTransactionParam) ([]entity.MidtransTransaction, error) {
 	res := []entity.MidtransTransaction{}
-	query := mt.db.Where(param).Where("order_id LIKE ?", fmt.Sprintf("%%%s%%", param.OrderIDLike))
+
+	if err := mt.query(ctx, param).Scopes(param.Seek).Limit(param.Limit).Offset(param.Offset).Order(param.OrderBy).Find(&res).Error; err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (mt *midtransTransaction) Count(ctx context.Context, param entity.MidtransTransactionParam) (int64, error) {
+	var count int64
+
+	if err := mt.query(ctx, param).Model(&entity.MidtransTransaction{}).Count(&count).Error; err != nil {
+		return count, err
+	}
+
+	return count, nil
+}
+
+// query filters the midtrans transactions of GetList and Count by param.
+func (mt *midtransTransaction) query(ctx context.Context, param entity.MidtransTransactionParam) *gorm.DB {
+	query := mt.db.WithContext(ctx).Where(param).Where("order_id LIKE ?", fmt.Sprintf("%%%s%%", param.OrderIDLike))
 
 	if param.CreatedAt != "" {
 		query = query.Where("created_at LIKE ?", fmt.Sprintf("%%%s%%", param.CreatedAt))
@@ -54,24 +77,33 @@ func (mt *midtransTransaction) GetList(param entity.MidtransTransactionParam) ([
 		query = query.Where("created_at > ?", param.CreatedAtMoreThan)
 	}
 
-	if err := query.Limit(param.Limit).Offset(param.Offset).Order(param.OrderBy).Find(&res).Error; err != nil {
+	return query
+}
+
+func (mt *midtransTransaction) GetListByTrxIDs(ctx context.Context, ids []uint, param entity.MidtransTransactionParam) ([]entity.MidtransTransaction, error) {
+	res := []entity.MidtransTransaction{}
+	if err := mt.db.WithContext(ctx).Where("transaction_id IN ? AND order_id LIKE ?", ids, fmt.Sprintf("%%%s%%", param.OrderIDLike)).Find(&res).Error; err != nil {
 		return res, err
 	}
 
 	return res, nil
 }
 
-func (mt *midtransTransaction) GetListByTrxIDs(ids []uint, param entity.MidtransTransactionParam) ([]entity.MidtransTransaction, error) {
-	res := []entity.MidtransTransaction{}
-	if err := mt.db.Where("transaction_id IN ? AND order_id LIKE ?", ids, fmt.Sprintf("%%%s%%", param.OrderIDLike)).Find(&res).Error; err != nil {
+func (mt *midtransTransaction) GetPaymentAggregate(ctx context.Context, param entity.PaymentAggregateParam) ([]entity.PaymentAggregate, error) {
+	res := []entity.PaymentAggregate{}
+	if err := mt.db.WithContext(ctx).Model(&entity.MidtransTransaction{}).
+		Select("payment_type, status, COUNT(*) AS total_transaction, COALESCE(SUM(gross_amount), 0) AS total_amount").
+		Where("created_at >= ? AND created_at < ?", param.Range.Start, param.Range.End).
+		Group("payment_type, status").
+		Scan(&res).Error; err != nil {
 		return res, err
 	}
 
 	return res, nil
 }
 
-func (mt *midtransTransaction) Update(selectParam entity.MidtransTransactionParam, updateParam entity.UpdateMidtransTransactionParam) error {
-	if err := mt.db.Model(entity.MidtransTransaction{}).Where(selectParam).Updates(updateParam).Error; err != nil {
+func (mt *midtransTransaction) Update(ctx context.Context, selectParam entity.MidtransTransactionParam, updateParam entity.UpdateMidtransTransactionParam) error {
+	if err := mt.db.WithContext(ctx).Model(entity.MidtransTransaction{}).Where(selectParam).Updates(updateParam).Error; err != nil {
 		return err
 	}
 
@@ -1,11 +1,13 @@
 package midtranstransaction
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"go-clean/src/business/entity"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -82,7 +84,7 @@ func Test_midtransTransaction_Create(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			_, err = u.Create(tt.args.midtransTransaction)
+			_, err = u.Create(context.Background(), tt.args.midtransTransaction)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("midtransTransaction.Create() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -158,7 +160,7 @@ func Test_midtransTransaction_Get(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			got, err := u.Get(tt.args.param)
+			got, err := u.Get(context.Background(), tt.args.param)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("midtransTransaction.Get() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -168,6 +170,94 @@ func Test_midtransTransaction_Get(t *testing.T) {
 	}
 }
 
+func Test_midtransTransaction_GetPaymentAggregate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "SELECT payment_type, status, COUNT(*) AS total_transaction, COALESCE(SUM(gross_amount), 0) AS total_amount FROM `midtrans_transactions` WHERE (created_at >= ? AND created_at < ?) AND `midtrans_transactions`.`deleted_at` IS NULL GROUP BY payment_type, status"
+	query := regexp.QuoteMeta(querySql)
+
+	now := time.Now()
+	mockParam := entity.PaymentAggregateParam{
+		Range: entity.TimeRange{
+			Start: now.AddDate(0, 0, -7),
+			End:   now,
+		},
+	}
+
+	type args struct {
+		param entity.PaymentAggregateParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        []entity.PaymentAggregate
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    []entity.PaymentAggregate{},
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"payment_type", "status", "total_transaction", "total_amount"})
+				row.AddRow(2, entity.StatusSuccess, 3, 45000)
+				sqlMock.ExpectQuery(query).WillReturnRows(row)
+				return sqlServer, err
+			},
+			want: []entity.PaymentAggregate{
+				{
+					PaymentType:      2,
+					Status:           entity.StatusSuccess,
+					TotalTransaction: 3,
+					TotalAmount:      45000,
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			got, err := u.GetPaymentAggregate(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("midtransTransaction.GetPaymentAggregate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func Test_midtransTransaction_Update(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -240,7 +330,7 @@ func Test_midtransTransaction_Update(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			err = u.Update(tt.args.selectParam, tt.args.updateParam)
+			err = u.Update(context.Background(), tt.args.selectParam, tt.args.updateParam)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("midtransTransaction.Update() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -248,3 +338,80 @@ func Test_midtransTransaction_Update(t *testing.T) {
 		})
 	}
 }
+
+func Test_midtransTransaction_Count(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "SELECT count(*) FROM `midtrans_transactions` WHERE order_id LIKE ? AND `midtrans_transactions`.`deleted_at` IS NULL"
+	query := regexp.QuoteMeta(querySql)
+
+	mockParam := entity.MidtransTransactionParam{
+		OrderIDLike: "order",
+	}
+
+	type args struct {
+		param entity.MidtransTransactionParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        int64
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"count"})
+				row.AddRow(3)
+				sqlMock.ExpectQuery(query).WithArgs("%order%").WillReturnRows(row)
+				return sqlServer, err
+			},
+			want:    3,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			got, err := u.Count(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("midtransTransaction.Count() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
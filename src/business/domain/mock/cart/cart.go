@@ -5,6 +5,7 @@
 package mock_cart
 
 import (
+	context "context"
 	entity "go-clean/src/business/entity"
 	reflect "reflect"
 
@@ -34,134 +35,239 @@ func (m *MockInterface) EXPECT() *MockInterfaceMockRecorder {
 	return m.recorder
 }
 
+// CountTransactions mocks base method.
+func (m *MockInterface) CountTransactions(ctx context.Context, param entity.CartTransactionParam) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTransactions", ctx, param)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTransactions indicates an expected call of CountTransactions.
+func (mr *MockInterfaceMockRecorder) CountTransactions(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTransactions", reflect.TypeOf((*MockInterface)(nil).CountTransactions), ctx, param)
+}
+
+// CountTransactionsInByStatus mocks base method.
+func (m *MockInterface) CountTransactionsInByStatus(ctx context.Context, status []string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTransactionsInByStatus", ctx, status)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTransactionsInByStatus indicates an expected call of CountTransactionsInByStatus.
+func (mr *MockInterfaceMockRecorder) CountTransactionsInByStatus(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTransactionsInByStatus", reflect.TypeOf((*MockInterface)(nil).CountTransactionsInByStatus), ctx, status)
+}
+
 // Create mocks base method.
-func (m *MockInterface) Create(cart entity.Cart) (entity.Cart, error) {
+func (m *MockInterface) Create(ctx context.Context, cart entity.Cart) (entity.Cart, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", cart)
+	ret := m.ctrl.Call(m, "Create", ctx, cart)
 	ret0, _ := ret[0].(entity.Cart)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockInterfaceMockRecorder) Create(cart interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) Create(ctx, cart interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInterface)(nil).Create), cart)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInterface)(nil).Create), ctx, cart)
 }
 
 // Delete mocks base method.
-func (m *MockInterface) Delete(param entity.CartParam) error {
+func (m *MockInterface) Delete(ctx context.Context, param entity.CartParam) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", param)
+	ret := m.ctrl.Call(m, "Delete", ctx, param)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockInterfaceMockRecorder) Delete(param interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) Delete(ctx, param interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockInterface)(nil).Delete), param)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockInterface)(nil).Delete), ctx, param)
 }
 
 // Get mocks base method.
-func (m *MockInterface) Get(param entity.CartParam) (entity.Cart, error) {
+func (m *MockInterface) Get(ctx context.Context, param entity.CartParam) (entity.Cart, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", param)
+	ret := m.ctrl.Call(m, "Get", ctx, param)
 	ret0, _ := ret[0].(entity.Cart)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockInterfaceMockRecorder) Get(param interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) Get(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockInterface)(nil).Get), ctx, param)
+}
+
+// GetCancellationAggregate mocks base method.
+func (m *MockInterface) GetCancellationAggregate(ctx context.Context, param entity.CancellationAggregateParam) ([]entity.CancellationAggregate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCancellationAggregate", ctx, param)
+	ret0, _ := ret[0].([]entity.CancellationAggregate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCancellationAggregate indicates an expected call of GetCancellationAggregate.
+func (mr *MockInterfaceMockRecorder) GetCancellationAggregate(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCancellationAggregate", reflect.TypeOf((*MockInterface)(nil).GetCancellationAggregate), ctx, param)
+}
+
+// GetHeatmapAggregate mocks base method.
+func (m *MockInterface) GetHeatmapAggregate(ctx context.Context, param entity.HeatmapAggregateParam) ([]entity.HeatmapAggregate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHeatmapAggregate", ctx, param)
+	ret0, _ := ret[0].([]entity.HeatmapAggregate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHeatmapAggregate indicates an expected call of GetHeatmapAggregate.
+func (mr *MockInterfaceMockRecorder) GetHeatmapAggregate(ctx, param interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockInterface)(nil).Get), param)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeatmapAggregate", reflect.TypeOf((*MockInterface)(nil).GetHeatmapAggregate), ctx, param)
 }
 
 // GetList mocks base method.
-func (m *MockInterface) GetList(param entity.CartParam) ([]entity.Cart, error) {
+func (m *MockInterface) GetList(ctx context.Context, param entity.CartParam) ([]entity.Cart, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetList", param)
+	ret := m.ctrl.Call(m, "GetList", ctx, param)
 	ret0, _ := ret[0].([]entity.Cart)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetList indicates an expected call of GetList.
-func (mr *MockInterfaceMockRecorder) GetList(param interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) GetList(ctx, param interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockInterface)(nil).GetList), param)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockInterface)(nil).GetList), ctx, param)
 }
 
 // GetListInByID mocks base method.
-func (m *MockInterface) GetListInByID(ids []int64) ([]entity.Cart, error) {
+func (m *MockInterface) GetListInByID(ctx context.Context, ids []int64) ([]entity.Cart, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetListInByID", ids)
+	ret := m.ctrl.Call(m, "GetListInByID", ctx, ids)
 	ret0, _ := ret[0].([]entity.Cart)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetListInByID indicates an expected call of GetListInByID.
-func (mr *MockInterfaceMockRecorder) GetListInByID(ids interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) GetListInByID(ctx, ids interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListInByID", reflect.TypeOf((*MockInterface)(nil).GetListInByID), ids)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListInByID", reflect.TypeOf((*MockInterface)(nil).GetListInByID), ctx, ids)
 }
 
 // GetListInByStatus mocks base method.
-func (m *MockInterface) GetListInByStatus(status []string, param entity.CartParam) ([]entity.Cart, error) {
+func (m *MockInterface) GetListInByStatus(ctx context.Context, status []string, param entity.CartParam) ([]entity.Cart, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetListInByStatus", status, param)
+	ret := m.ctrl.Call(m, "GetListInByStatus", ctx, status, param)
 	ret0, _ := ret[0].([]entity.Cart)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetListInByStatus indicates an expected call of GetListInByStatus.
-func (mr *MockInterfaceMockRecorder) GetListInByStatus(status, param interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) GetListInByStatus(ctx, status, param interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListInByStatus", reflect.TypeOf((*MockInterface)(nil).GetListInByStatus), status, param)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListInByStatus", reflect.TypeOf((*MockInterface)(nil).GetListInByStatus), ctx, status, param)
 }
 
 // GetListInByTransactionID mocks base method.
-func (m *MockInterface) GetListInByTransactionID(transaction_ids []uint) ([]entity.Cart, error) {
+func (m *MockInterface) GetListInByTransactionID(ctx context.Context, transaction_ids []uint) ([]entity.Cart, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetListInByTransactionID", transaction_ids)
+	ret := m.ctrl.Call(m, "GetListInByTransactionID", ctx, transaction_ids)
 	ret0, _ := ret[0].([]entity.Cart)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetListInByTransactionID indicates an expected call of GetListInByTransactionID.
-func (mr *MockInterfaceMockRecorder) GetListInByTransactionID(transaction_ids interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) GetListInByTransactionID(ctx, transaction_ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListInByTransactionID", reflect.TypeOf((*MockInterface)(nil).GetListInByTransactionID), ctx, transaction_ids)
+}
+
+// GetMenuAggregate mocks base method.
+func (m *MockInterface) GetMenuAggregate(ctx context.Context, param entity.MenuAggregateParam) ([]entity.MenuAggregate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMenuAggregate", ctx, param)
+	ret0, _ := ret[0].([]entity.MenuAggregate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMenuAggregate indicates an expected call of GetMenuAggregate.
+func (mr *MockInterfaceMockRecorder) GetMenuAggregate(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMenuAggregate", reflect.TypeOf((*MockInterface)(nil).GetMenuAggregate), ctx, param)
+}
+
+// GetSalesAggregate mocks base method.
+func (m *MockInterface) GetSalesAggregate(ctx context.Context, param entity.SalesAggregateParam) ([]entity.SalesAggregate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSalesAggregate", ctx, param)
+	ret0, _ := ret[0].([]entity.SalesAggregate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSalesAggregate indicates an expected call of GetSalesAggregate.
+func (mr *MockInterfaceMockRecorder) GetSalesAggregate(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSalesAggregate", reflect.TypeOf((*MockInterface)(nil).GetSalesAggregate), ctx, param)
+}
+
+// GetTransactionIDs mocks base method.
+func (m *MockInterface) GetTransactionIDs(ctx context.Context, param entity.CartTransactionParam) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionIDs", ctx, param)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionIDs indicates an expected call of GetTransactionIDs.
+func (mr *MockInterfaceMockRecorder) GetTransactionIDs(ctx, param interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListInByTransactionID", reflect.TypeOf((*MockInterface)(nil).GetListInByTransactionID), transaction_ids)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionIDs", reflect.TypeOf((*MockInterface)(nil).GetTransactionIDs), ctx, param)
 }
 
 // Update mocks base method.
-func (m *MockInterface) Update(selectParam entity.CartParam, updateParam entity.UpdateCartParam) error {
+func (m *MockInterface) Update(ctx context.Context, selectParam entity.CartParam, updateParam entity.UpdateCartParam) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", selectParam, updateParam)
+	ret := m.ctrl.Call(m, "Update", ctx, selectParam, updateParam)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockInterfaceMockRecorder) Update(selectParam, updateParam interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) Update(ctx, selectParam, updateParam interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockInterface)(nil).Update), selectParam, updateParam)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockInterface)(nil).Update), ctx, selectParam, updateParam)
 }
 
 // UpdatesByIDs mocks base method.
-func (m *MockInterface) UpdatesByIDs(ids []uint, updateParam entity.UpdateCartParam) error {
+func (m *MockInterface) UpdatesByIDs(ctx context.Context, ids []uint, updateParam entity.UpdateCartParam) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdatesByIDs", ids, updateParam)
+	ret := m.ctrl.Call(m, "UpdatesByIDs", ctx, ids, updateParam)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdatesByIDs indicates an expected call of UpdatesByIDs.
-func (mr *MockInterfaceMockRecorder) UpdatesByIDs(ids, updateParam interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) UpdatesByIDs(ctx, ids, updateParam interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatesByIDs", reflect.TypeOf((*MockInterface)(nil).UpdatesByIDs), ids, updateParam)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatesByIDs", reflect.TypeOf((*MockInterface)(nil).UpdatesByIDs), ctx, ids, updateParam)
 }
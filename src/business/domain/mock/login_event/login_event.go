@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/business/domain/login_event/login_event.go
+
+// Package mock_loginevent is a generated GoMock package.
+package mock_loginevent
+
+import (
+	context "context"
+	entity "go-clean/src/business/entity"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockInterface is a mock of Interface interface.
+type MockInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockInterfaceMockRecorder
+}
+
+// MockInterfaceMockRecorder is the mock recorder for MockInterface.
+type MockInterfaceMockRecorder struct {
+	mock *MockInterface
+}
+
+// NewMockInterface creates a new mock instance.
+func NewMockInterface(ctrl *gomock.Controller) *MockInterface {
+	mock := &MockInterface{ctrl: ctrl}
+	mock.recorder = &MockInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInterface) EXPECT() *MockInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Count mocks base method.
+func (m *MockInterface) Count(ctx context.Context, param entity.LoginEventParam) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, param)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockInterfaceMockRecorder) Count(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockInterface)(nil).Count), ctx, param)
+}
+
+// Create mocks base method.
+func (m *MockInterface) Create(ctx context.Context, loginEvent entity.LoginEvent) (entity.LoginEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, loginEvent)
+	ret0, _ := ret[0].(entity.LoginEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockInterfaceMockRecorder) Create(ctx, loginEvent interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInterface)(nil).Create), ctx, loginEvent)
+}
+
+// GetFailureSummary mocks base method.
+func (m *MockInterface) GetFailureSummary(ctx context.Context, param entity.LoginFailureParam) (entity.LoginFailureSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFailureSummary", ctx, param)
+	ret0, _ := ret[0].(entity.LoginFailureSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFailureSummary indicates an expected call of GetFailureSummary.
+func (mr *MockInterfaceMockRecorder) GetFailureSummary(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFailureSummary", reflect.TypeOf((*MockInterface)(nil).GetFailureSummary), ctx, param)
+}
+
+// GetList mocks base method.
+func (m *MockInterface) GetList(ctx context.Context, param entity.LoginEventParam) ([]entity.LoginEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, param)
+	ret0, _ := ret[0].([]entity.LoginEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockInterfaceMockRecorder) GetList(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockInterface)(nil).GetList), ctx, param)
+}
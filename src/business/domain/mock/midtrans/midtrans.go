@@ -5,6 +5,7 @@
 package mock_midtrans
 
 import (
+	context "context"
 	midtrans "go-clean/src/lib/midtrans"
 	reflect "reflect"
 
@@ -36,31 +37,31 @@ func (m *MockInterface) EXPECT() *MockInterfaceMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockInterface) Create(params midtrans.CreateOrderParam) (*coreapi.ChargeResponse, error) {
+func (m *MockInterface) Create(ctx context.Context, params midtrans.CreateOrderParam) (*coreapi.ChargeResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", params)
+	ret := m.ctrl.Call(m, "Create", ctx, params)
 	ret0, _ := ret[0].(*coreapi.ChargeResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockInterfaceMockRecorder) Create(params interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) Create(ctx, params interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInterface)(nil).Create), params)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInterface)(nil).Create), ctx, params)
 }
 
 // HandleNotification mocks base method.
-func (m *MockInterface) HandleNotification(id string) (*coreapi.TransactionStatusResponse, error) {
+func (m *MockInterface) HandleNotification(ctx context.Context, id string) (*coreapi.TransactionStatusResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "HandleNotification", id)
+	ret := m.ctrl.Call(m, "HandleNotification", ctx, id)
 	ret0, _ := ret[0].(*coreapi.TransactionStatusResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // HandleNotification indicates an expected call of HandleNotification.
-func (mr *MockInterfaceMockRecorder) HandleNotification(id interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) HandleNotification(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleNotification", reflect.TypeOf((*MockInterface)(nil).HandleNotification), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleNotification", reflect.TypeOf((*MockInterface)(nil).HandleNotification), ctx, id)
 }
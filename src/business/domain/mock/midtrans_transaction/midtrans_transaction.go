@@ -5,6 +5,7 @@
 package mock_midtranstransaction
 
 import (
+	context "context"
 	entity "go-clean/src/business/entity"
 	reflect "reflect"
 
@@ -34,76 +35,106 @@ func (m *MockInterface) EXPECT() *MockInterfaceMockRecorder {
 	return m.recorder
 }
 
+// Count mocks base method.
+func (m *MockInterface) Count(ctx context.Context, param entity.MidtransTransactionParam) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, param)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockInterfaceMockRecorder) Count(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockInterface)(nil).Count), ctx, param)
+}
+
 // Create mocks base method.
-func (m *MockInterface) Create(midtransTransaction entity.MidtransTransaction) (entity.MidtransTransaction, error) {
+func (m *MockInterface) Create(ctx context.Context, midtransTransaction entity.MidtransTransaction) (entity.MidtransTransaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", midtransTransaction)
+	ret := m.ctrl.Call(m, "Create", ctx, midtransTransaction)
 	ret0, _ := ret[0].(entity.MidtransTransaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockInterfaceMockRecorder) Create(midtransTransaction interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) Create(ctx, midtransTransaction interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInterface)(nil).Create), midtransTransaction)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInterface)(nil).Create), ctx, midtransTransaction)
 }
 
 // Get mocks base method.
-func (m *MockInterface) Get(param entity.MidtransTransactionParam) (entity.MidtransTransaction, error) {
+func (m *MockInterface) Get(ctx context.Context, param entity.MidtransTransactionParam) (entity.MidtransTransaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", param)
+	ret := m.ctrl.Call(m, "Get", ctx, param)
 	ret0, _ := ret[0].(entity.MidtransTransaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockInterfaceMockRecorder) Get(param interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) Get(ctx, param interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockInterface)(nil).Get), param)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockInterface)(nil).Get), ctx, param)
 }
 
 // GetList mocks base method.
-func (m *MockInterface) GetList(param entity.MidtransTransactionParam) ([]entity.MidtransTransaction, error) {
+func (m *MockInterface) GetList(ctx context.Context, param entity.MidtransTransactionParam) ([]entity.MidtransTransaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetList", param)
+	ret := m.ctrl.Call(m, "GetList", ctx, param)
 	ret0, _ := ret[0].([]entity.MidtransTransaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetList indicates an expected call of GetList.
-func (mr *MockInterfaceMockRecorder) GetList(param interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) GetList(ctx, param interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockInterface)(nil).GetList), param)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockInterface)(nil).GetList), ctx, param)
 }
 
 // GetListByTrxIDs mocks base method.
-func (m *MockInterface) GetListByTrxIDs(ids []uint, param entity.MidtransTransactionParam) ([]entity.MidtransTransaction, error) {
+func (m *MockInterface) GetListByTrxIDs(ctx context.Context, ids []uint, param entity.MidtransTransactionParam) ([]entity.MidtransTransaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetListByTrxIDs", ids, param)
+	ret := m.ctrl.Call(m, "GetListByTrxIDs", ctx, ids, param)
 	ret0, _ := ret[0].([]entity.MidtransTransaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetListByTrxIDs indicates an expected call of GetListByTrxIDs.
-func (mr *MockInterfaceMockRecorder) GetListByTrxIDs(ids, param interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) GetListByTrxIDs(ctx, ids, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListByTrxIDs", reflect.TypeOf((*MockInterface)(nil).GetListByTrxIDs), ctx, ids, param)
+}
+
+// GetPaymentAggregate mocks base method.
+func (m *MockInterface) GetPaymentAggregate(ctx context.Context, param entity.PaymentAggregateParam) ([]entity.PaymentAggregate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentAggregate", ctx, param)
+	ret0, _ := ret[0].([]entity.PaymentAggregate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentAggregate indicates an expected call of GetPaymentAggregate.
+func (mr *MockInterfaceMockRecorder) GetPaymentAggregate(ctx, param interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListByTrxIDs", reflect.TypeOf((*MockInterface)(nil).GetListByTrxIDs), ids, param)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentAggregate", reflect.TypeOf((*MockInterface)(nil).GetPaymentAggregate), ctx, param)
 }
 
 // Update mocks base method.
-func (m *MockInterface) Update(selectParam entity.MidtransTransactionParam, updateParam entity.UpdateMidtransTransactionParam) error {
+func (m *MockInterface) Update(ctx context.Context, selectParam entity.MidtransTransactionParam, updateParam entity.UpdateMidtransTransactionParam) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", selectParam, updateParam)
+	ret := m.ctrl.Call(m, "Update", ctx, selectParam, updateParam)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockInterfaceMockRecorder) Update(selectParam, updateParam interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) Update(ctx, selectParam, updateParam interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockInterface)(nil).Update), selectParam, updateParam)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockInterface)(nil).Update), ctx, selectParam, updateParam)
 }
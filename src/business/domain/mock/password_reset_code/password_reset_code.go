@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: src/business/domain/password_reset_code/password_reset_code.go
+
+// Package mock_passwordresetcode is a generated GoMock package.
+package mock_passwordresetcode
+
+import (
+	context "context"
+	entity "go-clean/src/business/entity"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockInterface is a mock of Interface interface.
+type MockInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockInterfaceMockRecorder
+}
+
+// MockInterfaceMockRecorder is the mock recorder for MockInterface.
+type MockInterfaceMockRecorder struct {
+	mock *MockInterface
+}
+
+// NewMockInterface creates a new mock instance.
+func NewMockInterface(ctrl *gomock.Controller) *MockInterface {
+	mock := &MockInterface{ctrl: ctrl}
+	mock.recorder = &MockInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInterface) EXPECT() *MockInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockInterface) Create(ctx context.Context, passwordResetCode entity.PasswordResetCode) (entity.PasswordResetCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, passwordResetCode)
+	ret0, _ := ret[0].(entity.PasswordResetCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockInterfaceMockRecorder) Create(ctx, passwordResetCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInterface)(nil).Create), ctx, passwordResetCode)
+}
+
+// Get mocks base method.
+func (m *MockInterface) Get(ctx context.Context, param entity.PasswordResetCodeParam) (entity.PasswordResetCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, param)
+	ret0, _ := ret[0].(entity.PasswordResetCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockInterfaceMockRecorder) Get(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockInterface)(nil).Get), ctx, param)
+}
+
+// InvalidateAll mocks base method.
+func (m *MockInterface) InvalidateAll(ctx context.Context, userID uint, usedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateAll", ctx, userID, usedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateAll indicates an expected call of InvalidateAll.
+func (mr *MockInterfaceMockRecorder) InvalidateAll(ctx, userID, usedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateAll", reflect.TypeOf((*MockInterface)(nil).InvalidateAll), ctx, userID, usedAt)
+}
+
+// Redeem mocks base method.
+func (m *MockInterface) Redeem(ctx context.Context, id uint, usedAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeem", ctx, id, usedAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Redeem indicates an expected call of Redeem.
+func (mr *MockInterfaceMockRecorder) Redeem(ctx, id, usedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeem", reflect.TypeOf((*MockInterface)(nil).Redeem), ctx, id, usedAt)
+}
+
+// Update mocks base method.
+func (m *MockInterface) Update(ctx context.Context, selectParam entity.PasswordResetCodeParam, updateParam entity.UpdatePasswordResetCodeParam) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, selectParam, updateParam)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockInterfaceMockRecorder) Update(ctx, selectParam, updateParam interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockInterface)(nil).Update), ctx, selectParam, updateParam)
+}
@@ -5,6 +5,7 @@
 package mock_transaction
 
 import (
+	context "context"
 	entity "go-clean/src/business/entity"
 	reflect "reflect"
 
@@ -34,47 +35,121 @@ func (m *MockInterface) EXPECT() *MockInterfaceMockRecorder {
 	return m.recorder
 }
 
+// ClaimGuest mocks base method.
+func (m *MockInterface) ClaimGuest(ctx context.Context, fromGuestID, toGuestID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimGuest", ctx, fromGuestID, toGuestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClaimGuest indicates an expected call of ClaimGuest.
+func (mr *MockInterfaceMockRecorder) ClaimGuest(ctx, fromGuestID, toGuestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimGuest", reflect.TypeOf((*MockInterface)(nil).ClaimGuest), ctx, fromGuestID, toGuestID)
+}
+
+// CountByGuestID mocks base method.
+func (m *MockInterface) CountByGuestID(ctx context.Context, guestID string, param entity.TransactionParam) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByGuestID", ctx, guestID, param)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByGuestID indicates an expected call of CountByGuestID.
+func (mr *MockInterfaceMockRecorder) CountByGuestID(ctx, guestID, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByGuestID", reflect.TypeOf((*MockInterface)(nil).CountByGuestID), ctx, guestID, param)
+}
+
+// CountSearch mocks base method.
+func (m *MockInterface) CountSearch(ctx context.Context, param entity.TransactionSearchParam) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSearch", ctx, param)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSearch indicates an expected call of CountSearch.
+func (mr *MockInterfaceMockRecorder) CountSearch(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSearch", reflect.TypeOf((*MockInterface)(nil).CountSearch), ctx, param)
+}
+
 // Create mocks base method.
-func (m *MockInterface) Create(transaction entity.Transaction) (entity.Transaction, error) {
+func (m *MockInterface) Create(ctx context.Context, transaction entity.Transaction) (entity.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", transaction)
+	ret := m.ctrl.Call(m, "Create", ctx, transaction)
 	ret0, _ := ret[0].(entity.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockInterfaceMockRecorder) Create(transaction interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) Create(ctx, transaction interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInterface)(nil).Create), transaction)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInterface)(nil).Create), ctx, transaction)
 }
 
 // Get mocks base method.
-func (m *MockInterface) Get(param entity.TransactionParam) (entity.Transaction, error) {
+func (m *MockInterface) Get(ctx context.Context, param entity.TransactionParam) (entity.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", param)
+	ret := m.ctrl.Call(m, "Get", ctx, param)
 	ret0, _ := ret[0].(entity.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockInterfaceMockRecorder) Get(param interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) Get(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockInterface)(nil).Get), ctx, param)
+}
+
+// GetListByGuestID mocks base method.
+func (m *MockInterface) GetListByGuestID(ctx context.Context, guestID string, param entity.TransactionParam) ([]entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListByGuestID", ctx, guestID, param)
+	ret0, _ := ret[0].([]entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListByGuestID indicates an expected call of GetListByGuestID.
+func (mr *MockInterfaceMockRecorder) GetListByGuestID(ctx, guestID, param interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockInterface)(nil).Get), param)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListByGuestID", reflect.TypeOf((*MockInterface)(nil).GetListByGuestID), ctx, guestID, param)
 }
 
 // GetListByIDs mocks base method.
-func (m *MockInterface) GetListByIDs(ids []uint) ([]entity.Transaction, error) {
+func (m *MockInterface) GetListByIDs(ctx context.Context, ids []uint) ([]entity.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetListByIDs", ids)
+	ret := m.ctrl.Call(m, "GetListByIDs", ctx, ids)
 	ret0, _ := ret[0].([]entity.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetListByIDs indicates an expected call of GetListByIDs.
-func (mr *MockInterfaceMockRecorder) GetListByIDs(ids interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) GetListByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListByIDs", reflect.TypeOf((*MockInterface)(nil).GetListByIDs), ctx, ids)
+}
+
+// Search mocks base method.
+func (m *MockInterface) Search(ctx context.Context, param entity.TransactionSearchParam) ([]entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, param)
+	ret0, _ := ret[0].([]entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockInterfaceMockRecorder) Search(ctx, param interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListByIDs", reflect.TypeOf((*MockInterface)(nil).GetListByIDs), ids)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockInterface)(nil).Search), ctx, param)
 }
@@ -5,6 +5,7 @@
 package mock_umkm
 
 import (
+	context "context"
 	entity "go-clean/src/business/entity"
 	reflect "reflect"
 
@@ -34,90 +35,105 @@ func (m *MockInterface) EXPECT() *MockInterfaceMockRecorder {
 	return m.recorder
 }
 
+// Count mocks base method.
+func (m *MockInterface) Count(ctx context.Context, param entity.UmkmParam) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, param)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockInterfaceMockRecorder) Count(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockInterface)(nil).Count), ctx, param)
+}
+
 // Create mocks base method.
-func (m *MockInterface) Create(umkm entity.Umkm) (entity.Umkm, error) {
+func (m *MockInterface) Create(ctx context.Context, umkm entity.Umkm) (entity.Umkm, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", umkm)
+	ret := m.ctrl.Call(m, "Create", ctx, umkm)
 	ret0, _ := ret[0].(entity.Umkm)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockInterfaceMockRecorder) Create(umkm interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) Create(ctx, umkm interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInterface)(nil).Create), umkm)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInterface)(nil).Create), ctx, umkm)
 }
 
 // Delete mocks base method.
-func (m *MockInterface) Delete(param entity.UmkmParam) error {
+func (m *MockInterface) Delete(ctx context.Context, param entity.UmkmParam) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", param)
+	ret := m.ctrl.Call(m, "Delete", ctx, param)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockInterfaceMockRecorder) Delete(param interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) Delete(ctx, param interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockInterface)(nil).Delete), param)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockInterface)(nil).Delete), ctx, param)
 }
 
 // Get mocks base method.
-func (m *MockInterface) Get(param entity.UmkmParam) (entity.Umkm, error) {
+func (m *MockInterface) Get(ctx context.Context, param entity.UmkmParam) (entity.Umkm, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", param)
+	ret := m.ctrl.Call(m, "Get", ctx, param)
 	ret0, _ := ret[0].(entity.Umkm)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockInterfaceMockRecorder) Get(param interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) Get(ctx, param interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockInterface)(nil).Get), param)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockInterface)(nil).Get), ctx, param)
 }
 
 // GetList mocks base method.
-func (m *MockInterface) GetList(param entity.UmkmParam) ([]entity.Umkm, error) {
+func (m *MockInterface) GetList(ctx context.Context, param entity.UmkmParam) ([]entity.Umkm, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetList", param)
+	ret := m.ctrl.Call(m, "GetList", ctx, param)
 	ret0, _ := ret[0].([]entity.Umkm)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetList indicates an expected call of GetList.
-func (mr *MockInterfaceMockRecorder) GetList(param interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) GetList(ctx, param interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockInterface)(nil).GetList), param)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockInterface)(nil).GetList), ctx, param)
 }
 
 // GetListInByID mocks base method.
-func (m *MockInterface) GetListInByID(ids []uint) ([]entity.Umkm, error) {
+func (m *MockInterface) GetListInByID(ctx context.Context, ids []uint) ([]entity.Umkm, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetListInByID", ids)
+	ret := m.ctrl.Call(m, "GetListInByID", ctx, ids)
 	ret0, _ := ret[0].([]entity.Umkm)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetListInByID indicates an expected call of GetListInByID.
-func (mr *MockInterfaceMockRecorder) GetListInByID(ids interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) GetListInByID(ctx, ids interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListInByID", reflect.TypeOf((*MockInterface)(nil).GetListInByID), ids)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListInByID", reflect.TypeOf((*MockInterface)(nil).GetListInByID), ctx, ids)
 }
 
 // Update mocks base method.
-func (m *MockInterface) Update(selectParam entity.UmkmParam, updateParam entity.UpdateUmkmParam) error {
+func (m *MockInterface) Update(ctx context.Context, selectParam entity.UmkmParam, updateParam entity.UpdateUmkmParam) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", selectParam, updateParam)
+	ret := m.ctrl.Call(m, "Update", ctx, selectParam, updateParam)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockInterfaceMockRecorder) Update(selectParam, updateParam interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) Update(ctx, selectParam, updateParam interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockInterface)(nil).Update), selectParam, updateParam)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockInterface)(nil).Update), ctx, selectParam, updateParam)
 }
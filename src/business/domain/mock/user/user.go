@@ -5,8 +5,10 @@
 package mock_user
 
 import (
+	context "context"
 	entity "go-clean/src/business/entity"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -34,32 +36,148 @@ func (m *MockInterface) EXPECT() *MockInterfaceMockRecorder {
 	return m.recorder
 }
 
+// CountByParam mocks base method.
+func (m *MockInterface) CountByParam(ctx context.Context, param entity.UserListParam) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByParam", ctx, param)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByParam indicates an expected call of CountByParam.
+func (mr *MockInterfaceMockRecorder) CountByParam(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByParam", reflect.TypeOf((*MockInterface)(nil).CountByParam), ctx, param)
+}
+
 // Create mocks base method.
-func (m *MockInterface) Create(user entity.User) (entity.User, error) {
+func (m *MockInterface) Create(ctx context.Context, user entity.User) (entity.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", user)
+	ret := m.ctrl.Call(m, "Create", ctx, user)
 	ret0, _ := ret[0].(entity.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockInterfaceMockRecorder) Create(user interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) Create(ctx, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInterface)(nil).Create), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInterface)(nil).Create), ctx, user)
+}
+
+// CreateBuyer mocks base method.
+func (m *MockInterface) CreateBuyer(ctx context.Context, user entity.User, fromGuestID string) (entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBuyer", ctx, user, fromGuestID)
+	ret0, _ := ret[0].(entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBuyer indicates an expected call of CreateBuyer.
+func (mr *MockInterfaceMockRecorder) CreateBuyer(ctx, user, fromGuestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBuyer", reflect.TypeOf((*MockInterface)(nil).CreateBuyer), ctx, user, fromGuestID)
+}
+
+// Delete mocks base method.
+func (m *MockInterface) Delete(ctx context.Context, param entity.UserParam) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, param)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockInterfaceMockRecorder) Delete(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockInterface)(nil).Delete), ctx, param)
 }
 
 // Get mocks base method.
-func (m *MockInterface) Get(param entity.UserParam) (entity.User, error) {
+func (m *MockInterface) Get(ctx context.Context, param entity.UserParam) (entity.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", param)
+	ret := m.ctrl.Call(m, "Get", ctx, param)
 	ret0, _ := ret[0].(entity.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockInterfaceMockRecorder) Get(param interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) Get(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockInterface)(nil).Get), ctx, param)
+}
+
+// GetList mocks base method.
+func (m *MockInterface) GetList(ctx context.Context, param entity.UserParam) ([]entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, param)
+	ret0, _ := ret[0].([]entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockInterfaceMockRecorder) GetList(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockInterface)(nil).GetList), ctx, param)
+}
+
+// GetListByParam mocks base method.
+func (m *MockInterface) GetListByParam(ctx context.Context, param entity.UserListParam) ([]entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListByParam", ctx, param)
+	ret0, _ := ret[0].([]entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListByParam indicates an expected call of GetListByParam.
+func (mr *MockInterfaceMockRecorder) GetListByParam(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListByParam", reflect.TypeOf((*MockInterface)(nil).GetListByParam), ctx, param)
+}
+
+// RecordFailedLogin mocks base method.
+func (m *MockInterface) RecordFailedLogin(ctx context.Context, id uint, maxFailedLogin int, lockedUntil time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailedLogin", ctx, id, maxFailedLogin, lockedUntil)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordFailedLogin indicates an expected call of RecordFailedLogin.
+func (mr *MockInterfaceMockRecorder) RecordFailedLogin(ctx, id, maxFailedLogin, lockedUntil interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailedLogin", reflect.TypeOf((*MockInterface)(nil).RecordFailedLogin), ctx, id, maxFailedLogin, lockedUntil)
+}
+
+// Update mocks base method.
+func (m *MockInterface) Update(ctx context.Context, selectParam entity.UserParam, updateParam entity.UpdateUserParam) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, selectParam, updateParam)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockInterfaceMockRecorder) Update(ctx, selectParam, updateParam interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockInterface)(nil).Update), ctx, selectParam, updateParam)
+}
+
+// UpdateLoginState mocks base method.
+func (m *MockInterface) UpdateLoginState(ctx context.Context, id uint, failedLoginCount int, lockedUntil *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLoginState", ctx, id, failedLoginCount, lockedUntil)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLoginState indicates an expected call of UpdateLoginState.
+func (mr *MockInterfaceMockRecorder) UpdateLoginState(ctx, id, failedLoginCount, lockedUntil interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockInterface)(nil).Get), param)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoginState", reflect.TypeOf((*MockInterface)(nil).UpdateLoginState), ctx, id, failedLoginCount, lockedUntil)
 }
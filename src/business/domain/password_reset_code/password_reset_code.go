@@ -0,0 +1,73 @@
+package passwordresetcode
+
+import (
+	"context"
+	"go-clean/src/business/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Interface interface {
+	Create(ctx context.Context, passwordResetCode entity.PasswordResetCode) (entity.PasswordResetCode, error)
+	Get(ctx context.Context, param entity.PasswordResetCodeParam) (entity.PasswordResetCode, error)
+	Update(ctx context.Context, selectParam entity.PasswordResetCodeParam, updateParam entity.UpdatePasswordResetCodeParam) error
+	Redeem(ctx context.Context, id uint, usedAt time.Time) (bool, error)
+	InvalidateAll(ctx context.Context, userID uint, usedAt time.Time) error
+}
+
+type passwordResetCode struct {
+	db *gorm.DB
+}
+
+func Init(db *gorm.DB) Interface {
+	prc := &passwordResetCode{
+		db: db,
+	}
+
+	return prc
+}
+
+func (prc *passwordResetCode) Create(ctx context.Context, passwordResetCode entity.PasswordResetCode) (entity.PasswordResetCode, error) {
+	if err := prc.db.WithContext(ctx).Create(&passwordResetCode).Error; err != nil {
+		return passwordResetCode, err
+	}
+
+	return passwordResetCode, nil
+}
+
+func (prc *passwordResetCode) Get(ctx context.Context, param entity.PasswordResetCodeParam) (entity.PasswordResetCode, error) {
+	res := entity.PasswordResetCode{}
+	if err := prc.db.WithContext(ctx).Where(param).First(&res).Error; err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (prc *passwordResetCode) Update(ctx context.Context, selectParam entity.PasswordResetCodeParam, updateParam entity.UpdatePasswordResetCodeParam) error {
+	if err := prc.db.WithContext(ctx).Model(entity.PasswordResetCode{}).Where(selectParam).Updates(updateParam).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Redeem marks the code as used unless it is already used or expired at usedAt and reports whether this call redeemed
+// it, so only one of two concurrent resets with the same code wins.
+func (prc *passwordResetCode) Redeem(ctx context.Context, id uint, usedAt time.Time) (bool, error) {
+	res := prc.db.WithContext(ctx).Model(entity.PasswordResetCode{}).Where("id = ? AND used_at IS NULL AND expires_at > ?", id, usedAt).Update("used_at", usedAt)
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+func (prc *passwordResetCode) InvalidateAll(ctx context.Context, userID uint, usedAt time.Time) error {
+	if err := prc.db.WithContext(ctx).Model(entity.PasswordResetCode{}).Where("user_id = ? AND used_at IS NULL", userID).Update("used_at", usedAt).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,407 @@
+package passwordresetcode
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"go-clean/src/business/entity"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func Test_passwordResetCode_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "INSERT INTO"
+	query := regexp.QuoteMeta(querySql)
+
+	mockPasswordResetCode := entity.PasswordResetCode{
+		UserID:    1,
+		CodeHash:  "hash",
+		ExpiresAt: time.Now(),
+	}
+
+	type args struct {
+		passwordResetCode entity.PasswordResetCode
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		wantErr     bool
+	}{
+		{
+			name: "failed to create reset code",
+			args: args{
+				passwordResetCode: mockPasswordResetCode,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			wantErr: true,
+		},
+		{
+			name: "all success",
+			args: args{
+				passwordResetCode: mockPasswordResetCode,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(1, 1))
+				sqlMock.ExpectCommit()
+				sqlMock.ExpectationsWereMet()
+				return sqlServer, err
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			_, err = u.Create(context.Background(), tt.args.passwordResetCode)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("passwordResetCode.Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func Test_passwordResetCode_Get(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "SELECT * FROM `password_reset_codes` WHERE `password_reset_codes`.`code_hash` = ? AND `password_reset_codes`.`deleted_at` IS NULL ORDER BY `password_reset_codes`.`id` LIMIT 1"
+	query := regexp.QuoteMeta(querySql)
+
+	mockParam := entity.PasswordResetCodeParam{
+		CodeHash: "hash",
+	}
+
+	type args struct {
+		param entity.PasswordResetCodeParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        entity.PasswordResetCode
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    entity.PasswordResetCode{},
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"id", "user_id", "code_hash"})
+				row.AddRow(1, 1, "hash")
+				sqlMock.ExpectQuery(query).WillReturnRows(row)
+				return sqlServer, err
+			},
+			want: entity.PasswordResetCode{
+				Model: gorm.Model{
+					ID: 1,
+				},
+				UserID:   1,
+				CodeHash: "hash",
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			got, err := u.Get(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("passwordResetCode.Get() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_passwordResetCode_Update(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "UPDATE"
+	query := regexp.QuoteMeta(querySql)
+
+	usedAt := time.Now()
+	selectParam := entity.PasswordResetCodeParam{
+		ID: 1,
+	}
+
+	updateParam := entity.UpdatePasswordResetCodeParam{
+		UsedAt: &usedAt,
+	}
+
+	type args struct {
+		selectParam entity.PasswordResetCodeParam
+		updateParam entity.UpdatePasswordResetCodeParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				selectParam: selectParam,
+				updateParam: updateParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				selectParam: selectParam,
+				updateParam: updateParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(query).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectCommit()
+				sqlMock.ExpectationsWereMet()
+				return sqlServer, err
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			err = u.Update(context.Background(), tt.args.selectParam, tt.args.updateParam)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("passwordResetCode.Update() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func Test_passwordResetCode_Redeem(t *testing.T) {
+	querySql := "UPDATE `password_reset_codes` SET `used_at`=?,`updated_at`=? WHERE (id = ? AND used_at IS NULL AND expires_at > ?) AND `password_reset_codes`.`deleted_at` IS NULL"
+	query := regexp.QuoteMeta(querySql)
+
+	usedAt := time.Now()
+
+	tests := []struct {
+		name        string
+		prepSqlMock func() (*sql.DB, error)
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(query).WillReturnError(assert.AnError)
+				sqlMock.ExpectRollback()
+				return sqlServer, err
+			},
+			want:    false,
+			wantErr: true,
+		},
+		{
+			name: "already used or expired",
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(query).WillReturnResult(driver.RowsAffected(0))
+				sqlMock.ExpectCommit()
+				return sqlServer, err
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "all ok",
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(query).WithArgs(usedAt, sqlmock.AnyArg(), 1, usedAt).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectCommit()
+				return sqlServer, err
+			},
+			want:    true,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			prc := Init(sqlClient)
+			got, err := prc.Redeem(context.Background(), 1, usedAt)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("passwordResetCode.Redeem() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_passwordResetCode_InvalidateAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "UPDATE"
+	query := regexp.QuoteMeta(querySql)
+
+	type args struct {
+		userID uint
+		usedAt time.Time
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				userID: 1,
+				usedAt: time.Now(),
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				userID: 1,
+				usedAt: time.Now(),
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(query).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectCommit()
+				sqlMock.ExpectationsWereMet()
+				return sqlServer, err
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			err = u.InvalidateAll(context.Background(), tt.args.userID, tt.args.usedAt)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("passwordResetCode.InvalidateAll() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
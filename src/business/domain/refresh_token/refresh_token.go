@@ -0,0 +1,73 @@
+package refreshtoken
+
+import (
+	"context"
+	"go-clean/src/business/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Interface interface {
+	Create(ctx context.Context, refreshToken entity.RefreshToken) (entity.RefreshToken, error)
+	Get(ctx context.Context, param entity.RefreshTokenParam) (entity.RefreshToken, error)
+	Update(ctx context.Context, selectParam entity.RefreshTokenParam, updateParam entity.UpdateRefreshTokenParam) error
+	Revoke(ctx context.Context, id uint, revokedAt time.Time) (bool, error)
+	RevokeAll(ctx context.Context, userID uint, revokedAt time.Time) error
+}
+
+type refreshToken struct {
+	db *gorm.DB
+}
+
+func Init(db *gorm.DB) Interface {
+	rt := &refreshToken{
+		db: db,
+	}
+
+	return rt
+}
+
+func (rt *refreshToken) Create(ctx context.Context, refreshToken entity.RefreshToken) (entity.RefreshToken, error) {
+	if err := rt.db.WithContext(ctx).Create(&refreshToken).Error; err != nil {
+		return refreshToken, err
+	}
+
+	return refreshToken, nil
+}
+
+func (rt *refreshToken) Get(ctx context.Context, param entity.RefreshTokenParam) (entity.RefreshToken, error) {
+	res := entity.RefreshToken{}
+	if err := rt.db.WithContext(ctx).Where(param).First(&res).Error; err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (rt *refreshToken) Update(ctx context.Context, selectParam entity.RefreshTokenParam, updateParam entity.UpdateRefreshTokenParam) error {
+	if err := rt.db.WithContext(ctx).Model(entity.RefreshToken{}).Where(selectParam).Updates(updateParam).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Revoke revokes the refresh token unless it is already revoked and reports whether this call revoked it, so only one
+// of two concurrent rotations of the same token wins.
+func (rt *refreshToken) Revoke(ctx context.Context, id uint, revokedAt time.Time) (bool, error) {
+	res := rt.db.WithContext(ctx).Model(entity.RefreshToken{}).Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", revokedAt)
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+func (rt *refreshToken) RevokeAll(ctx context.Context, userID uint, revokedAt time.Time) error {
+	if err := rt.db.WithContext(ctx).Model(entity.RefreshToken{}).Where("user_id = ? AND revoked_at IS NULL", userID).Update("revoked_at", revokedAt).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
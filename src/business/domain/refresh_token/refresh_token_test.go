@@ -0,0 +1,425 @@
+package refreshtoken
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"go-clean/src/business/entity"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func Test_refreshToken_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "INSERT INTO"
+	query := regexp.QuoteMeta(querySql)
+
+	mockRefreshToken := entity.RefreshToken{
+		UserID:    1,
+		TokenHash: "hash",
+		ExpiresAt: time.Now(),
+	}
+
+	type args struct {
+		refreshToken entity.RefreshToken
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		wantErr     bool
+	}{
+		{
+			name: "failed to create refresh token",
+			args: args{
+				refreshToken: mockRefreshToken,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			wantErr: true,
+		},
+		{
+			name: "all success",
+			args: args{
+				refreshToken: mockRefreshToken,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(1, 1))
+				sqlMock.ExpectCommit()
+				sqlMock.ExpectationsWereMet()
+				return sqlServer, err
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			_, err = u.Create(context.Background(), tt.args.refreshToken)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("refreshToken.Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func Test_refreshToken_Get(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "SELECT * FROM `refresh_tokens` WHERE `refresh_tokens`.`token_hash` = ? AND `refresh_tokens`.`deleted_at` IS NULL ORDER BY `refresh_tokens`.`id` LIMIT 1"
+	query := regexp.QuoteMeta(querySql)
+
+	mockParam := entity.RefreshTokenParam{
+		TokenHash: "hash",
+	}
+
+	type args struct {
+		param entity.RefreshTokenParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        entity.RefreshToken
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    entity.RefreshToken{},
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"id", "user_id", "token_hash"})
+				row.AddRow(1, 1, "hash")
+				sqlMock.ExpectQuery(query).WillReturnRows(row)
+				return sqlServer, err
+			},
+			want: entity.RefreshToken{
+				Model: gorm.Model{
+					ID: 1,
+				},
+				UserID:    1,
+				TokenHash: "hash",
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			got, err := u.Get(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("refreshToken.Get() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_refreshToken_Update(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "UPDATE"
+	query := regexp.QuoteMeta(querySql)
+
+	revokedAt := time.Now()
+	selectParam := entity.RefreshTokenParam{
+		ID: 1,
+	}
+
+	updateParam := entity.UpdateRefreshTokenParam{
+		RevokedAt: &revokedAt,
+	}
+
+	type args struct {
+		selectParam entity.RefreshTokenParam
+		updateParam entity.UpdateRefreshTokenParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				selectParam: selectParam,
+				updateParam: updateParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				selectParam: selectParam,
+				updateParam: updateParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(query).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectCommit()
+				sqlMock.ExpectationsWereMet()
+				return sqlServer, err
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			err = u.Update(context.Background(), tt.args.selectParam, tt.args.updateParam)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("refreshToken.Update() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func Test_refreshToken_Revoke(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "UPDATE `refresh_tokens` SET `revoked_at`=?,`updated_at`=? WHERE (id = ? AND revoked_at IS NULL) AND `refresh_tokens`.`deleted_at` IS NULL"
+	query := regexp.QuoteMeta(querySql)
+
+	type args struct {
+		id        uint
+		revokedAt time.Time
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				id:        1,
+				revokedAt: time.Now(),
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(query).WillReturnError(assert.AnError)
+				sqlMock.ExpectRollback()
+				return sqlServer, err
+			},
+			want:    false,
+			wantErr: true,
+		},
+		{
+			name: "already revoked",
+			args: args{
+				id:        1,
+				revokedAt: time.Now(),
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(query).WillReturnResult(driver.RowsAffected(0))
+				sqlMock.ExpectCommit()
+				return sqlServer, err
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "all ok",
+			args: args{
+				id:        1,
+				revokedAt: time.Now(),
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(query).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 1).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectCommit()
+				return sqlServer, err
+			},
+			want:    true,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			got, err := u.Revoke(context.Background(), tt.args.id, tt.args.revokedAt)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("refreshToken.Revoke() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_refreshToken_RevokeAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "UPDATE"
+	query := regexp.QuoteMeta(querySql)
+
+	type args struct {
+		userID    uint
+		revokedAt time.Time
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				userID:    1,
+				revokedAt: time.Now(),
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				userID:    1,
+				revokedAt: time.Now(),
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(query).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectCommit()
+				sqlMock.ExpectationsWereMet()
+				return sqlServer, err
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			err = u.RevokeAll(context.Background(), tt.args.userID, tt.args.revokedAt)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("refreshToken.RevokeAll() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package revokedtoken
+
+import (
+	"context"
+	"go-clean/src/business/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Interface interface {
+	Create(ctx context.Context, revokedToken entity.RevokedToken) (entity.RevokedToken, error)
+	Get(ctx context.Context, param entity.RevokedTokenParam) (entity.RevokedToken, error)
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
+
+type revokedToken struct {
+	db *gorm.DB
+}
+
+func Init(db *gorm.DB) Interface {
+	rt := &revokedToken{
+		db: db,
+	}
+
+	return rt
+}
+
+func (rt *revokedToken) Create(ctx context.Context, revokedToken entity.RevokedToken) (entity.RevokedToken, error) {
+	if err := rt.db.WithContext(ctx).Create(&revokedToken).Error; err != nil {
+		return revokedToken, err
+	}
+
+	return revokedToken, nil
+}
+
+// Get is called on every authenticated request, a missing row is returned as an empty result instead of an error.
+func (rt *revokedToken) Get(ctx context.Context, param entity.RevokedTokenParam) (entity.RevokedToken, error) {
+	res := entity.RevokedToken{}
+	if err := rt.db.WithContext(ctx).Where(param).Limit(1).Find(&res).Error; err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// DeleteExpired removes the rows of tokens that expired before now, they are rejected by their exp claim anyway.
+func (rt *revokedToken) DeleteExpired(ctx context.Context, now time.Time) error {
+	if err := rt.db.WithContext(ctx).Unscoped().Where("expires_at < ?", now).Delete(&entity.RevokedToken{}).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
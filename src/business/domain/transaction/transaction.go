@@ -1,15 +1,25 @@
 package transaction
 
 import (
+	"context"
 	"go-clean/src/business/entity"
+	"strings"
 
 	"gorm.io/gorm"
 )
 
+// fullTextOperators are stripped from search words so they can't change the meaning of the boolean mode query.
+const fullTextOperators = `+-<>()~*"@`
+
 type Interface interface {
-	Create(transaction entity.Transaction) (entity.Transaction, error)
-	Get(param entity.TransactionParam) (entity.Transaction, error)
-	GetListByIDs(ids []uint) ([]entity.Transaction, error)
+	Create(ctx context.Context, transaction entity.Transaction) (entity.Transaction, error)
+	Get(ctx context.Context, param entity.TransactionParam) (entity.Transaction, error)
+	GetListByIDs(ctx context.Context, ids []uint) ([]entity.Transaction, error)
+	GetListByGuestID(ctx context.Context, guestID string, param entity.TransactionParam) ([]entity.Transaction, error)
+	CountByGuestID(ctx context.Context, guestID string, param entity.TransactionParam) (int64, error)
+	Search(ctx context.Context, param entity.TransactionSearchParam) ([]entity.Transaction, error)
+	CountSearch(ctx context.Context, param entity.TransactionSearchParam) (int64, error)
+	ClaimGuest(ctx context.Context, fromGuestID string, toGuestID string) error
 }
 
 type transaction struct {
@@ -24,30 +34,185 @@ func Init(db *gorm.DB) Interface {
 	return t
 }
 
-func (t *transaction) Create(transaction entity.Transaction) (entity.Transaction, error) {
-	if err := t.db.Create(&transaction).Error; err != nil {
+func (t *transaction) Create(ctx context.Context, transaction entity.Transaction) (entity.Transaction, error) {
+	if err := t.db.WithContext(ctx).Create(&transaction).Error; err != nil {
 		return transaction, err
 	}
 
 	return transaction, nil
 }
 
-func (t *transaction) Get(param entity.TransactionParam) (entity.Transaction, error) {
+func (t *transaction) Get(ctx context.Context, param entity.TransactionParam) (entity.Transaction, error) {
 	transaction := entity.Transaction{}
 
-	if err := t.db.Where(param).First(&transaction).Error; err != nil {
+	if err := t.db.WithContext(ctx).Where(param).First(&transaction).Error; err != nil {
 		return transaction, err
 	}
 
 	return transaction, nil
 }
 
-func (t *transaction) GetListByIDs(ids []uint) ([]entity.Transaction, error) {
+func (t *transaction) GetListByIDs(ctx context.Context, ids []uint) ([]entity.Transaction, error) {
+	transactions := []entity.Transaction{}
+
+	if err := t.db.WithContext(ctx).Where(ids).Find(&transactions).Error; err != nil {
+		return transactions, err
+	}
+
+	return transactions, nil
+}
+
+func (t *transaction) GetListByGuestID(ctx context.Context, guestID string, param entity.TransactionParam) ([]entity.Transaction, error) {
+	transactions := []entity.Transaction{}
+
+	if err := t.guestQuery(ctx, guestID, param).Scopes(param.Seek).Order(param.OrderBy).Limit(param.Limit).Offset(param.Offset).Find(&transactions).Error; err != nil {
+		return transactions, err
+	}
+
+	return transactions, nil
+}
+
+func (t *transaction) CountByGuestID(ctx context.Context, guestID string, param entity.TransactionParam) (int64, error) {
+	var count int64
+
+	if err := t.guestQuery(ctx, guestID, param).Count(&count).Error; err != nil {
+		return count, err
+	}
+
+	return count, nil
+}
+
+// guestQuery selects the transactions of GetListByGuestID and CountByGuestID, MidtransOrderID keeps the transactions
+// with a payment whose order id contains it.
+func (t *transaction) guestQuery(ctx context.Context, guestID string, param entity.TransactionParam) *gorm.DB {
+	query := t.db.WithContext(ctx).Model(&entity.Transaction{}).Where("guest_id = ?", guestID)
+
+	if param.MidtransOrderID != "" {
+		payments := t.db.Table("midtrans_transactions").Select("1").
+			Where("midtrans_transactions.transaction_id = transactions.id AND midtrans_transactions.deleted_at IS NULL").
+			Where("midtrans_transactions.order_id LIKE ?", "%"+param.MidtransOrderID+"%")
+		query = query.Where("EXISTS (?)", payments)
+	}
+
+	return query
+}
+
+func (t *transaction) Search(ctx context.Context, param entity.TransactionSearchParam) ([]entity.Transaction, error) {
 	transactions := []entity.Transaction{}
 
-	if err := t.db.Where(ids).Find(&transactions).Error; err != nil {
+	if err := t.searchQuery(ctx, param).Scopes(param.Seek).Order(param.OrderBy).Limit(param.Limit).Offset(param.Offset).Find(&transactions).Error; err != nil {
 		return transactions, err
 	}
 
 	return transactions, nil
 }
+
+func (t *transaction) CountSearch(ctx context.Context, param entity.TransactionSearchParam) (int64, error) {
+	var count int64
+
+	if err := t.searchQuery(ctx, param).Count(&count).Error; err != nil {
+		return count, err
+	}
+
+	return count, nil
+}
+
+// searchQuery applies every filter of param that is set. Filters on payments, tenants and menus are subqueries so a
+// transaction is listed once however many carts or payments it has.
+func (t *transaction) searchQuery(ctx context.Context, param entity.TransactionSearchParam) *gorm.DB {
+	query := t.db.WithContext(ctx).Model(&entity.Transaction{})
+
+	if terms := fullTextTerms(param.Query); terms != "" {
+		query = query.Where("MATCH (transactions.buyer_name, transactions.seat, transactions.email) AGAINST (? IN BOOLEAN MODE)", terms)
+	}
+
+	if param.BuyerName != "" {
+		query = query.Where("transactions.buyer_name LIKE ?", param.BuyerName+"%")
+	}
+
+	if param.Seat != "" {
+		query = query.Where("transactions.seat = ?", param.Seat)
+	}
+
+	if param.Email != "" {
+		query = query.Where("transactions.email = ?", strings.ToLower(param.Email))
+	}
+
+	if param.MinAmount > 0 {
+		query = query.Where("transactions.price >= ?", param.MinAmount)
+	}
+
+	if param.MaxAmount > 0 {
+		query = query.Where("transactions.price <= ?", param.MaxAmount)
+	}
+
+	if !param.From.IsZero() {
+		query = query.Where("transactions.created_at >= ?", param.From)
+	}
+
+	if !param.To.IsZero() {
+		query = query.Where("transactions.created_at < ?", param.To)
+	}
+
+	if param.OrderID != "" || len(param.Statuses) > 0 || param.PaymentTypeID != 0 {
+		payments := t.db.Table("midtrans_transactions").Select("1").
+			Where("midtrans_transactions.transaction_id = transactions.id AND midtrans_transactions.deleted_at IS NULL")
+		if param.OrderID != "" {
+			payments = payments.Where("midtrans_transactions.order_id LIKE ?", param.OrderID+"%")
+		}
+		if len(param.Statuses) > 0 {
+			payments = payments.Where("midtrans_transactions.status IN ?", param.Statuses)
+		}
+		if param.PaymentTypeID != 0 {
+			payments = payments.Where("midtrans_transactions.payment_type = ?", param.PaymentTypeID)
+		}
+		query = query.Where("EXISTS (?)", payments)
+	}
+
+	if param.UmkmID != 0 || param.MenuName != "" {
+		carts := t.db.Table("carts").Select("1").
+			Where("carts.transaction_id = transactions.id AND carts.deleted_at IS NULL")
+		if param.UmkmID != 0 {
+			carts = carts.Where("carts.umkm_id = ?", param.UmkmID)
+		}
+		if param.MenuName != "" {
+			carts = carts.Joins("JOIN menus ON menus.id = carts.menu_id").Where("menus.name LIKE ?", param.MenuName+"%")
+		}
+		query = query.Where("EXISTS (?)", carts)
+	}
+
+	return query
+}
+
+// fullTextTerms requires every word of a free text search, matching words by prefix.
+func fullTextTerms(search string) string {
+	search = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(fullTextOperators, r) {
+			return ' '
+		}
+		return r
+	}, search)
+
+	terms := []string{}
+	for _, word := range strings.Fields(search) {
+		terms = append(terms, "+"+word+"*")
+	}
+
+	return strings.Join(terms, " ")
+}
+
+// ClaimGuest moves the carts and transactions of a guest to another guest in one database transaction so they never end
+// up owned by different guests.
+func (t *transaction) ClaimGuest(ctx context.Context, fromGuestID string, toGuestID string) error {
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.Cart{}).Where("guest_id = ?", fromGuestID).Update("guest_id", toGuestID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&entity.Transaction{}).Where("guest_id = ?", fromGuestID).Update("guest_id", toGuestID).Error; err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
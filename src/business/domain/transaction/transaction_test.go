@@ -1,10 +1,13 @@
 package transaction
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"go-clean/src/business/entity"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -81,7 +84,7 @@ func Test_transaction_Create(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			_, err = u.Create(tt.args.transaction)
+			_, err = u.Create(context.Background(), tt.args.transaction)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("transaction.Create() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -157,7 +160,7 @@ func Test_transaction_Get(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			got, err := u.Get(tt.args.param)
+			got, err := u.Get(context.Background(), tt.args.param)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("transaction.Get() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -236,7 +239,7 @@ func Test_transaction_GetListByIDs(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			got, err := u.GetListByIDs(tt.args.param)
+			got, err := u.GetListByIDs(context.Background(), tt.args.param)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("transaction.GetListByIDs() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -245,3 +248,480 @@ func Test_transaction_GetListByIDs(t *testing.T) {
 		})
 	}
 }
+
+func Test_transaction_GetListByGuestID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "SELECT * FROM `transactions` WHERE guest_id = ? AND `transactions`.`deleted_at` IS NULL ORDER BY id desc LIMIT 10 OFFSET 10"
+	query := regexp.QuoteMeta(querySql)
+
+	orderIDQuerySql := "SELECT * FROM `transactions` WHERE guest_id = ? AND EXISTS (SELECT 1 FROM `midtrans_transactions` WHERE (midtrans_transactions.transaction_id = transactions.id AND midtrans_transactions.deleted_at IS NULL) AND midtrans_transactions.order_id LIKE ?) AND `transactions`.`deleted_at` IS NULL ORDER BY id desc LIMIT 10 OFFSET 10"
+	orderIDQuery := regexp.QuoteMeta(orderIDQuerySql)
+
+	mockParam := entity.TransactionParam{
+		PaginationParam: entity.PaginationParam{
+			Limit:   10,
+			Offset:  10,
+			OrderBy: "id desc",
+		},
+	}
+
+	type args struct {
+		guestID string
+		param   entity.TransactionParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        []entity.Transaction
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				guestID: "guest",
+				param:   mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    []entity.Transaction{},
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				guestID: "guest",
+				param:   mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"buyer_name", "guest_id"})
+				row.AddRow("mail", "guest")
+				sqlMock.ExpectQuery(query).WithArgs("guest").WillReturnRows(row)
+				return sqlServer, err
+			},
+			want: []entity.Transaction{
+				{
+					BuyerName: "mail",
+					GuestID:   "guest",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "all ok with order id",
+			args: args{
+				guestID: "guest",
+				param: entity.TransactionParam{
+					MidtransOrderID: "ORDER-1",
+					PaginationParam: mockParam.PaginationParam,
+				},
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"buyer_name", "guest_id"})
+				row.AddRow("mail", "guest")
+				sqlMock.ExpectQuery(orderIDQuery).WithArgs("guest", "%ORDER-1%").WillReturnRows(row)
+				return sqlServer, err
+			},
+			want: []entity.Transaction{
+				{
+					BuyerName: "mail",
+					GuestID:   "guest",
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			got, err := u.GetListByGuestID(context.Background(), tt.args.guestID, tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("transaction.GetListByGuestID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_transaction_ClaimGuest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cartQuery := regexp.QuoteMeta("UPDATE `carts` SET `guest_id`=?,`updated_at`=? WHERE guest_id = ?")
+	transactionQuery := regexp.QuoteMeta("UPDATE `transactions` SET `guest_id`=?,`updated_at`=? WHERE guest_id = ?")
+
+	type args struct {
+		fromGuestID string
+		toGuestID   string
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		wantErr     bool
+	}{
+		{
+			name: "failed to claim carts",
+			args: args{
+				fromGuestID: "device-guest",
+				toGuestID:   "buyer-guest",
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(cartQuery).WillReturnError(assert.AnError)
+				sqlMock.ExpectRollback()
+				return sqlServer, err
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to claim transactions rolls back carts",
+			args: args{
+				fromGuestID: "device-guest",
+				toGuestID:   "buyer-guest",
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(cartQuery).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectExec(transactionQuery).WillReturnError(assert.AnError)
+				sqlMock.ExpectRollback()
+				return sqlServer, err
+			},
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				fromGuestID: "device-guest",
+				toGuestID:   "buyer-guest",
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(cartQuery).WithArgs("buyer-guest", sqlmock.AnyArg(), "device-guest").WillReturnResult(driver.RowsAffected(3))
+				sqlMock.ExpectExec(transactionQuery).WithArgs("buyer-guest", sqlmock.AnyArg(), "device-guest").WillReturnResult(driver.RowsAffected(2))
+				sqlMock.ExpectCommit()
+				return sqlServer, err
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			err = u.ClaimGuest(context.Background(), tt.args.fromGuestID, tt.args.toGuestID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("transaction.ClaimGuest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func Test_transaction_CountByGuestID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "SELECT count(*) FROM `transactions` WHERE guest_id = ? AND `transactions`.`deleted_at` IS NULL"
+	query := regexp.QuoteMeta(querySql)
+
+	orderIDQuerySql := "SELECT count(*) FROM `transactions` WHERE guest_id = ? AND EXISTS (SELECT 1 FROM `midtrans_transactions` WHERE (midtrans_transactions.transaction_id = transactions.id AND midtrans_transactions.deleted_at IS NULL) AND midtrans_transactions.order_id LIKE ?) AND `transactions`.`deleted_at` IS NULL"
+	orderIDQuery := regexp.QuoteMeta(orderIDQuerySql)
+
+	mockParam := "guest-id"
+
+	type args struct {
+		param       string
+		transaction entity.TransactionParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        int64
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"count"})
+				row.AddRow(3)
+				sqlMock.ExpectQuery(query).WithArgs("guest-id").WillReturnRows(row)
+				return sqlServer, err
+			},
+			want:    3,
+			wantErr: false,
+		},
+		{
+			name: "all ok with order id",
+			args: args{
+				param: mockParam,
+				transaction: entity.TransactionParam{
+					MidtransOrderID: "ORDER-1",
+				},
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"count"})
+				row.AddRow(1)
+				sqlMock.ExpectQuery(orderIDQuery).WithArgs("guest-id", "%ORDER-1%").WillReturnRows(row)
+				return sqlServer, err
+			},
+			want:    1,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			got, err := u.CountByGuestID(context.Background(), tt.args.param, tt.args.transaction)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("transaction.CountByGuestID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_transaction_Search(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "SELECT * FROM `transactions` WHERE MATCH (transactions.buyer_name, transactions.seat, transactions.email) AGAINST (? IN BOOLEAN MODE) AND transactions.price >= ? AND EXISTS (SELECT 1 FROM `midtrans_transactions` WHERE (midtrans_transactions.transaction_id = transactions.id AND midtrans_transactions.deleted_at IS NULL) AND midtrans_transactions.status IN (?) AND midtrans_transactions.payment_type = ?) AND EXISTS (SELECT 1 FROM `carts` JOIN menus ON menus.id = carts.menu_id WHERE (carts.transaction_id = transactions.id AND carts.deleted_at IS NULL) AND carts.umkm_id = ? AND menus.name LIKE ?) AND `transactions`.`deleted_at` IS NULL ORDER BY transactions.id desc LIMIT 10"
+	query := regexp.QuoteMeta(querySql)
+
+	mockParam := entity.TransactionSearchParam{
+		Query:         "budi (a1)",
+		MinAmount:     10000,
+		Statuses:      []string{"settlement"},
+		PaymentTypeID: 2,
+		UmkmID:        1,
+		MenuName:      "nasi",
+		PaginationParam: entity.PaginationParam{
+			Limit:   10,
+			OrderBy: "transactions.id desc",
+		},
+	}
+
+	type args struct {
+		param entity.TransactionSearchParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        []entity.Transaction
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    []entity.Transaction{},
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"buyer_name", "seat"})
+				row.AddRow("budi", "a1")
+				sqlMock.ExpectQuery(query).WithArgs("+budi* +a1*", 10000, "settlement", 2, 1, "nasi%").WillReturnRows(row)
+				return sqlServer, err
+			},
+			want: []entity.Transaction{
+				{
+					BuyerName: "budi",
+					Seat:      "a1",
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			got, err := u.Search(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("transaction.Search() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_transaction_CountSearch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "SELECT count(*) FROM `transactions` WHERE transactions.buyer_name LIKE ? AND transactions.email = ? AND transactions.created_at >= ? AND transactions.created_at < ? AND EXISTS (SELECT 1 FROM `midtrans_transactions` WHERE (midtrans_transactions.transaction_id = transactions.id AND midtrans_transactions.deleted_at IS NULL) AND midtrans_transactions.order_id LIKE ?) AND `transactions`.`deleted_at` IS NULL"
+	query := regexp.QuoteMeta(querySql)
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	mockParam := entity.TransactionSearchParam{
+		BuyerName: "bud",
+		Email:     "Budi@Mail.com",
+		OrderID:   "ORDER-1",
+		From:      from,
+		To:        from.AddDate(0, 0, 1),
+	}
+
+	type args struct {
+		param entity.TransactionSearchParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        int64
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"count"})
+				row.AddRow(3)
+				sqlMock.ExpectQuery(query).WithArgs("bud%", "budi@mail.com", from, from.AddDate(0, 0, 1), "ORDER-1%").WillReturnRows(row)
+				return sqlServer, err
+			},
+			want:    3,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			got, err := u.CountSearch(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("transaction.CountSearch() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
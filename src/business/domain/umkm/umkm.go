@@ -1,6 +1,7 @@
 package umkm
 
 import (
+	"context"
 	"fmt"
 	"go-clean/src/business/entity"
 
@@ -8,12 +9,13 @@ import (
 )
 
 type Interface interface {
-	Create(umkm entity.Umkm) (entity.Umkm, error)
-	GetList(param entity.UmkmParam) ([]entity.Umkm, error)
-	GetListInByID(ids []uint) ([]entity.Umkm, error)
-	Get(param entity.UmkmParam) (entity.Umkm, error)
-	Update(selectParam entity.UmkmParam, updateParam entity.UpdateUmkmParam) error
-	Delete(param entity.UmkmParam) error
+	Create(ctx context.Context, umkm entity.Umkm) (entity.Umkm, error)
+	GetList(ctx context.Context, param entity.UmkmParam) ([]entity.Umkm, error)
+	GetListInByID(ctx context.Context, ids []uint) ([]entity.Umkm, error)
+	Get(ctx context.Context, param entity.UmkmParam) (entity.Umkm, error)
+	Count(ctx context.Context, param entity.UmkmParam) (int64, error)
+	Update(ctx context.Context, selectParam entity.UmkmParam, updateParam entity.UpdateUmkmParam) error
+	Delete(ctx context.Context, param entity.UmkmParam) error
 }
 
 type umkm struct {
@@ -28,54 +30,69 @@ func Init(db *gorm.DB) Interface {
 	return u
 }
 
-func (u *umkm) Create(umkm entity.Umkm) (entity.Umkm, error) {
-	if err := u.db.Create(&umkm).Error; err != nil {
+func (u *umkm) Create(ctx context.Context, umkm entity.Umkm) (entity.Umkm, error) {
+	if err := u.db.WithContext(ctx).Create(&umkm).Error; err != nil {
 		return umkm, err
 	}
 
 	return umkm, nil
 }
 
-func (u *umkm) GetList(param entity.UmkmParam) ([]entity.Umkm, error) {
+func (u *umkm) GetList(ctx context.Context, param entity.UmkmParam) ([]entity.Umkm, error) {
 	umkms := []entity.Umkm{}
 
-	if err := u.db.Where(param).Where("name LIKE ?", fmt.Sprintf("%%%s%%", param.Name)).Find(&umkms).Error; err != nil {
+	if err := u.query(ctx, param).Scopes(param.Seek).Order(param.OrderBy).Limit(param.Limit).Offset(param.Offset).Find(&umkms).Error; err != nil {
 		return umkms, err
 	}
 
 	return umkms, nil
 }
 
-func (u *umkm) GetListInByID(ids []uint) ([]entity.Umkm, error) {
+func (u *umkm) GetListInByID(ctx context.Context, ids []uint) ([]entity.Umkm, error) {
 	umkms := []entity.Umkm{}
 
-	if err := u.db.Where(ids).Find(&umkms).Error; err != nil {
+	if err := u.db.WithContext(ctx).Where(ids).Find(&umkms).Error; err != nil {
 		return umkms, err
 	}
 
 	return umkms, nil
 }
 
-func (u *umkm) Get(param entity.UmkmParam) (entity.Umkm, error) {
+func (u *umkm) Get(ctx context.Context, param entity.UmkmParam) (entity.Umkm, error) {
 	umkm := entity.Umkm{}
 
-	if err := u.db.Where(param).First(&umkm).Error; err != nil {
+	if err := u.db.WithContext(ctx).Where(param).First(&umkm).Error; err != nil {
 		return umkm, err
 	}
 
 	return umkm, nil
 }
 
-func (u *umkm) Update(selectParam entity.UmkmParam, updateParam entity.UpdateUmkmParam) error {
-	if err := u.db.Model(entity.Umkm{}).Where(selectParam).Updates(updateParam).Error; err != nil {
+func (u *umkm) Count(ctx context.Context, param entity.UmkmParam) (int64, error) {
+	var count int64
+
+	if err := u.query(ctx, param).Model(&entity.Umkm{}).Count(&count).Error; err != nil {
+		return count, err
+	}
+
+	return count, nil
+}
+
+// query filters the umkms of GetList and Count by param.
+func (u *umkm) query(ctx context.Context, param entity.UmkmParam) *gorm.DB {
+	return u.db.WithContext(ctx).Where(param).Where("name LIKE ?", fmt.Sprintf("%%%s%%", param.Name))
+}
+
+func (u *umkm) Update(ctx context.Context, selectParam entity.UmkmParam, updateParam entity.UpdateUmkmParam) error {
+	if err := u.db.WithContext(ctx).Model(entity.Umkm{}).Where(selectParam).Updates(updateParam).Error; err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (u *umkm) Delete(param entity.UmkmParam) error {
-	if err := u.db.Where(param).Delete(&entity.Umkm{}).Error; err != nil {
+func (u *umkm) Delete(ctx context.Context, param entity.UmkmParam) error {
+	if err := u.db.WithContext(ctx).Where(param).Delete(&entity.Umkm{}).Error; err != nil {
 		return err
 	}
 
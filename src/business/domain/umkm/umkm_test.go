@@ -1,6 +1,7 @@
 package umkm
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"go-clean/src/business/entity"
@@ -82,7 +83,7 @@ func Test_umkm_Create(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			_, err = u.Create(tt.args.umkm)
+			_, err = u.Create(context.Background(), tt.args.umkm)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("umkm.Create() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -160,7 +161,7 @@ func Test_umkm_GetAll(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			got, err := u.GetList(tt.args.param)
+			got, err := u.GetList(context.Background(), tt.args.param)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("umkm.GetAll() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -237,7 +238,7 @@ func Test_umkm_Get(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			got, err := u.Get(tt.args.param)
+			got, err := u.Get(context.Background(), tt.args.param)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("umkm.Get() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -319,7 +320,7 @@ func Test_umkm_Update(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			err = u.Update(tt.args.selectParam, tt.args.updateParam)
+			err = u.Update(context.Background(), tt.args.selectParam, tt.args.updateParam)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("umkm.Update() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -393,7 +394,7 @@ func Test_umkm_Delete(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			err = u.Delete(tt.args.param)
+			err = u.Delete(context.Background(), tt.args.param)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("umkm.Delete() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -401,3 +402,80 @@ func Test_umkm_Delete(t *testing.T) {
 		})
 	}
 }
+
+func Test_umkm_Count(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "SELECT count(*) FROM `umkms` WHERE `umkms`.`status` = ? AND name LIKE ? AND `umkms`.`deleted_at` IS NULL"
+	query := regexp.QuoteMeta(querySql)
+
+	mockParam := entity.UmkmParam{
+		Status: entity.StatusOpen,
+	}
+
+	type args struct {
+		param entity.UmkmParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        int64
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"count"})
+				row.AddRow(3)
+				sqlMock.ExpectQuery(query).WillReturnRows(row)
+				return sqlServer, err
+			},
+			want:    3,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			got, err := u.Count(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("umkm.Count() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -1,14 +1,25 @@
 package user
 
 import (
+	"context"
 	"go-clean/src/business/entity"
+	"go-clean/src/lib/auth"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type Interface interface {
-	Create(user entity.User) (entity.User, error)
-	Get(param entity.UserParam) (entity.User, error)
+	Create(ctx context.Context, user entity.User) (entity.User, error)
+	CreateBuyer(ctx context.Context, user entity.User, fromGuestID string) (entity.User, error)
+	Get(ctx context.Context, param entity.UserParam) (entity.User, error)
+	GetList(ctx context.Context, param entity.UserParam) ([]entity.User, error)
+	GetListByParam(ctx context.Context, param entity.UserListParam) ([]entity.User, error)
+	CountByParam(ctx context.Context, param entity.UserListParam) (int64, error)
+	Update(ctx context.Context, selectParam entity.UserParam, updateParam entity.UpdateUserParam) error
+	UpdateLoginState(ctx context.Context, id uint, failedLoginCount int, lockedUntil *time.Time) error
+	RecordFailedLogin(ctx context.Context, id uint, maxFailedLogin int, lockedUntil time.Time) error
+	Delete(ctx context.Context, param entity.UserParam) error
 }
 
 type user struct {
@@ -23,20 +34,150 @@ func Init(db *gorm.DB) Interface {
 	return a
 }
 
-func (a *user) Create(user entity.User) (entity.User, error) {
-	if err := a.db.Create(&user).Error; err != nil {
+func (a *user) Create(ctx context.Context, user entity.User) (entity.User, error) {
+	if err := a.db.WithContext(ctx).Create(&user).Error; err != nil {
 		return user, err
 	}
 
 	return user, nil
 }
 
-func (a *user) Get(param entity.UserParam) (entity.User, error) {
+// CreateBuyer creates the buyer and moves the carts and transactions of fromGuestID to the buyer's guest id in one
+// database transaction, so a failed claim leaves no account behind and the sign up can be retried.
+func (a *user) CreateBuyer(ctx context.Context, user entity.User, fromGuestID string) (entity.User, error) {
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+
+		if fromGuestID == "" || fromGuestID == user.GuestID {
+			return nil
+		}
+
+		if err := tx.Model(&entity.Cart{}).Where("guest_id = ?", fromGuestID).Update("guest_id", user.GuestID).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&entity.Transaction{}).Where("guest_id = ?", fromGuestID).Update("guest_id", user.GuestID).Error
+	})
+	if err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
+
+func (a *user) Get(ctx context.Context, param entity.UserParam) (entity.User, error) {
 	user := entity.User{}
 
-	if err := a.db.Where(param).First(&user).Error; err != nil {
+	if err := a.db.WithContext(ctx).Where(param).First(&user).Error; err != nil {
 		return user, err
 	}
 
 	return user, nil
 }
+
+func (a *user) GetList(ctx context.Context, param entity.UserParam) ([]entity.User, error) {
+	users := []entity.User{}
+
+	if err := a.db.WithContext(ctx).Where(param).Find(&users).Error; err != nil {
+		return users, err
+	}
+
+	return users, nil
+}
+
+func (a *user) GetListByParam(ctx context.Context, param entity.UserListParam) ([]entity.User, error) {
+	users := []entity.User{}
+
+	if err := a.listQuery(ctx, param).Scopes(param.Seek).Order(param.OrderBy).Limit(param.Limit).Offset(param.Offset).Find(&users).Error; err != nil {
+		return users, err
+	}
+
+	return users, nil
+}
+
+func (a *user) CountByParam(ctx context.Context, param entity.UserListParam) (int64, error) {
+	var count int64
+
+	if err := a.listQuery(ctx, param).Model(&entity.User{}).Count(&count).Error; err != nil {
+		return count, err
+	}
+
+	return count, nil
+}
+
+// listQuery filters the users of GetListByParam and CountByParam by param. Accounts created before roles have an empty
+// role, they are matched the way entity.User.GetRole falls back to IsAdmin and UmkmID.
+func (a *user) listQuery(ctx context.Context, param entity.UserListParam) *gorm.DB {
+	query := a.db.WithContext(ctx).Where(param)
+
+	switch param.Role {
+	case "":
+	case auth.RoleSuperAdmin:
+		query = query.Where("role = ? OR (COALESCE(role, '') = '' AND is_admin = ?)", param.Role, true)
+	case auth.RoleTenantOwner:
+		query = query.Where("role = ? OR (COALESCE(role, '') = '' AND is_admin = ? AND umkm_id <> ?)", param.Role, false, 0)
+	default:
+		query = query.Where("role = ?", param.Role)
+	}
+	if param.Search != "" {
+		search := "%" + param.Search + "%"
+		query = query.Where("username LIKE ? OR nama LIKE ?", search, search)
+	}
+
+	return query
+}
+
+func (a *user) Update(ctx context.Context, selectParam entity.UserParam, updateParam entity.UpdateUserParam) error {
+	if err := a.db.WithContext(ctx).Model(entity.User{}).Where(selectParam).Updates(updateParam).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateLoginState writes zero values too, so it is used to reset the counter and unlock the account.
+func (a *user) UpdateLoginState(ctx context.Context, id uint, failedLoginCount int, lockedUntil *time.Time) error {
+	if err := a.db.WithContext(ctx).Model(entity.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"failed_login_count": failedLoginCount,
+		"locked_until":       lockedUntil,
+	}).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RecordFailedLogin increments the failed login counter in SQL so concurrent failures all count. Once the stored count
+// reaches maxFailedLogin the account is locked until lockedUntil and the counter reset.
+func (a *user) RecordFailedLogin(ctx context.Context, id uint, maxFailedLogin int, lockedUntil time.Time) error {
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(entity.User{}).Where("id = ?", id).Update("failed_login_count", gorm.Expr("failed_login_count + 1")).Error; err != nil {
+			return err
+		}
+
+		// the update holds the row lock, the count read here includes every concurrent failure committed before it
+		user := entity.User{}
+		if err := tx.Select("failed_login_count").Where("id = ?", id).First(&user).Error; err != nil {
+			return err
+		}
+
+		if user.FailedLoginCount < maxFailedLogin {
+			return nil
+		}
+
+		return tx.Model(entity.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"failed_login_count": 0,
+			"locked_until":       lockedUntil,
+		}).Error
+	})
+}
+
+func (a *user) Delete(ctx context.Context, param entity.UserParam) error {
+	if err := a.db.WithContext(ctx).Where(param).Delete(&entity.User{}).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -1,10 +1,13 @@
 package user
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"go-clean/src/business/entity"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -81,7 +84,7 @@ func Test_user_Create(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			_, err = u.Create(tt.args.user)
+			_, err = u.Create(context.Background(), tt.args.user)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("user.Create() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -90,6 +93,133 @@ func Test_user_Create(t *testing.T) {
 	}
 }
 
+func Test_user_CreateBuyer(t *testing.T) {
+	insertQuery := regexp.QuoteMeta("INSERT INTO `users`")
+	cartQuery := regexp.QuoteMeta("UPDATE `carts` SET `guest_id`=?")
+	transactionQuery := regexp.QuoteMeta("UPDATE `transactions` SET `guest_id`=?")
+
+	mockUser := entity.User{
+		Username: "buyer@mail.com",
+		GuestID:  "buyer-guest",
+	}
+
+	type args struct {
+		user        entity.User
+		fromGuestID string
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, sqlmock.Sqlmock, error)
+		wantErr     bool
+	}{
+		{
+			name: "failed to create buyer",
+			args: args{
+				user:        mockUser,
+				fromGuestID: "device-guest",
+			},
+			prepSqlMock: func() (*sql.DB, sqlmock.Sqlmock, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(insertQuery).WillReturnError(assert.AnError)
+				sqlMock.ExpectRollback()
+				return sqlServer, sqlMock, err
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to claim carts rolls back the buyer",
+			args: args{
+				user:        mockUser,
+				fromGuestID: "device-guest",
+			},
+			prepSqlMock: func() (*sql.DB, sqlmock.Sqlmock, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(insertQuery).WillReturnResult(sqlmock.NewResult(1, 1))
+				sqlMock.ExpectExec(cartQuery).WillReturnError(assert.AnError)
+				sqlMock.ExpectRollback()
+				return sqlServer, sqlMock, err
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to claim transactions rolls back the buyer",
+			args: args{
+				user:        mockUser,
+				fromGuestID: "device-guest",
+			},
+			prepSqlMock: func() (*sql.DB, sqlmock.Sqlmock, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(insertQuery).WillReturnResult(sqlmock.NewResult(1, 1))
+				sqlMock.ExpectExec(cartQuery).WillReturnResult(sqlmock.NewResult(0, 2))
+				sqlMock.ExpectExec(transactionQuery).WillReturnError(assert.AnError)
+				sqlMock.ExpectRollback()
+				return sqlServer, sqlMock, err
+			},
+			wantErr: true,
+		},
+		{
+			name: "success without guest session",
+			args: args{
+				user: mockUser,
+			},
+			prepSqlMock: func() (*sql.DB, sqlmock.Sqlmock, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(insertQuery).WillReturnResult(sqlmock.NewResult(1, 1))
+				sqlMock.ExpectCommit()
+				return sqlServer, sqlMock, err
+			},
+			wantErr: false,
+		},
+		{
+			name: "success claims guest session",
+			args: args{
+				user:        mockUser,
+				fromGuestID: "device-guest",
+			},
+			prepSqlMock: func() (*sql.DB, sqlmock.Sqlmock, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(insertQuery).WillReturnResult(sqlmock.NewResult(1, 1))
+				sqlMock.ExpectExec(cartQuery).WithArgs("buyer-guest", sqlmock.AnyArg(), "device-guest").WillReturnResult(sqlmock.NewResult(0, 2))
+				sqlMock.ExpectExec(transactionQuery).WithArgs("buyer-guest", sqlmock.AnyArg(), "device-guest").WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.ExpectCommit()
+				return sqlServer, sqlMock, err
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, sqlMock, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			_, err = u.CreateBuyer(context.Background(), tt.args.user, tt.args.fromGuestID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("user.CreateBuyer() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
 func Test_user_Get(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -159,7 +289,7 @@ func Test_user_Get(t *testing.T) {
 			}
 
 			u := Init(sqlClient)
-			got, err := u.Get(tt.args.param)
+			got, err := u.Get(context.Background(), tt.args.param)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("umkm.Get() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -168,3 +298,632 @@ func Test_user_Get(t *testing.T) {
 		})
 	}
 }
+
+func Test_user_GetList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "SELECT * FROM `users` WHERE `users`.`umkm_id` = ? AND `users`.`deleted_at` IS NULL"
+	query := regexp.QuoteMeta(querySql)
+
+	mockParam := entity.UserParam{
+		UmkmID: 1,
+	}
+
+	type args struct {
+		param entity.UserParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        []entity.User
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    []entity.User{},
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"username", "umkm_id", "role"})
+				row.AddRow("cashier", 1, "tenant_cashier")
+				sqlMock.ExpectQuery(query).WillReturnRows(row)
+				return sqlServer, err
+			},
+			want: []entity.User{
+				{
+					Username: "cashier",
+					UmkmID:   1,
+					Role:     "tenant_cashier",
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			got, err := u.GetList(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("user.GetList() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_user_GetListByParam(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "SELECT * FROM `users` WHERE role = ? AND (username LIKE ? OR nama LIKE ?) AND `users`.`deleted_at` IS NULL ORDER BY id desc LIMIT 10 OFFSET 20"
+	query := regexp.QuoteMeta(querySql)
+
+	mockParam := entity.UserListParam{
+		Search: "kasir",
+		Role:   "tenant_cashier",
+		PaginationParam: entity.PaginationParam{
+			Limit:   10,
+			Offset:  20,
+			OrderBy: "id desc",
+		},
+	}
+
+	type args struct {
+		param entity.UserListParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        []entity.User
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    []entity.User{},
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"username", "umkm_id", "role"})
+				row.AddRow("cashier", 1, "tenant_cashier")
+				sqlMock.ExpectQuery(query).WithArgs("tenant_cashier", "%kasir%", "%kasir%").WillReturnRows(row)
+				return sqlServer, err
+			},
+			want: []entity.User{
+				{
+					Username: "cashier",
+					UmkmID:   1,
+					Role:     "tenant_cashier",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "all ok after cursor",
+			args: args{
+				param: entity.UserListParam{
+					PaginationParam: entity.PaginationParam{
+						Limit:   10,
+						OrderBy: "id desc",
+						After: &entity.Keyset{
+							Query: "id < ?",
+							Args:  []interface{}{uint64(5)},
+						},
+					},
+				},
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"username", "umkm_id", "role"})
+				row.AddRow("cashier", 1, "tenant_cashier")
+				sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users` WHERE id < ? AND `users`.`deleted_at` IS NULL ORDER BY id desc LIMIT 10")).WithArgs(uint64(5)).WillReturnRows(row)
+				return sqlServer, err
+			},
+			want: []entity.User{
+				{
+					Username: "cashier",
+					UmkmID:   1,
+					Role:     "tenant_cashier",
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			got, err := u.GetListByParam(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("user.GetListByParam() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_user_Update(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "UPDATE"
+	query := regexp.QuoteMeta(querySql)
+
+	selectParam := entity.UserParam{
+		ID: 1,
+	}
+
+	updateParam := entity.UpdateUserParam{
+		Password: "hash",
+	}
+
+	type args struct {
+		selectParam entity.UserParam
+		updateParam entity.UpdateUserParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				selectParam: selectParam,
+				updateParam: updateParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				selectParam: selectParam,
+				updateParam: updateParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(query).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectCommit()
+				sqlMock.ExpectationsWereMet()
+				return sqlServer, err
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			err = u.Update(context.Background(), tt.args.selectParam, tt.args.updateParam)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("user.Update() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func Test_user_UpdateLoginState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "UPDATE `users` SET `failed_login_count`=?,`locked_until`=?,`updated_at`=? WHERE id = ? AND `users`.`deleted_at` IS NULL"
+	query := regexp.QuoteMeta(querySql)
+
+	lockedUntil := time.Now()
+
+	type args struct {
+		id               uint
+		failedLoginCount int
+		lockedUntil      *time.Time
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				id:               1,
+				failedLoginCount: 0,
+				lockedUntil:      &lockedUntil,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			wantErr: true,
+		},
+		{
+			name: "all ok with reset",
+			args: args{
+				id:               1,
+				failedLoginCount: 0,
+				lockedUntil:      nil,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(query).WithArgs(0, nil, sqlmock.AnyArg(), 1).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectCommit()
+				sqlMock.ExpectationsWereMet()
+				return sqlServer, err
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			err = u.UpdateLoginState(context.Background(), tt.args.id, tt.args.failedLoginCount, tt.args.lockedUntil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("user.UpdateLoginState() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func Test_user_RecordFailedLogin(t *testing.T) {
+	incrementQuery := regexp.QuoteMeta("UPDATE `users` SET `failed_login_count`=failed_login_count + 1,`updated_at`=? WHERE id = ? AND `users`.`deleted_at` IS NULL")
+	countQuery := regexp.QuoteMeta("SELECT `failed_login_count` FROM `users` WHERE id = ? AND `users`.`deleted_at` IS NULL ORDER BY `users`.`id` LIMIT 1")
+	lockQuery := regexp.QuoteMeta("UPDATE `users` SET `failed_login_count`=?,`locked_until`=?,`updated_at`=? WHERE id = ? AND `users`.`deleted_at` IS NULL")
+
+	lockedUntil := time.Now().Add(30 * time.Minute)
+
+	tests := []struct {
+		name        string
+		prepSqlMock func() (*sql.DB, error)
+		wantErr     bool
+	}{
+		{
+			name: "failed to increment",
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(incrementQuery).WillReturnError(assert.AnError)
+				sqlMock.ExpectRollback()
+				return sqlServer, err
+			},
+			wantErr: true,
+		},
+		{
+			name: "below the limit",
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(incrementQuery).WithArgs(sqlmock.AnyArg(), 1).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectQuery(countQuery).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"failed_login_count"}).AddRow(2))
+				sqlMock.ExpectCommit()
+				return sqlServer, err
+			},
+			wantErr: false,
+		},
+		{
+			name: "reaching the limit locks the account",
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(incrementQuery).WithArgs(sqlmock.AnyArg(), 1).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectQuery(countQuery).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"failed_login_count"}).AddRow(3))
+				sqlMock.ExpectExec(lockQuery).WithArgs(0, lockedUntil, sqlmock.AnyArg(), 1).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectCommit()
+				return sqlServer, err
+			},
+			wantErr: false,
+		},
+		{
+			name: "failed to lock",
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(incrementQuery).WithArgs(sqlmock.AnyArg(), 1).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectQuery(countQuery).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"failed_login_count"}).AddRow(4))
+				sqlMock.ExpectExec(lockQuery).WillReturnError(assert.AnError)
+				sqlMock.ExpectRollback()
+				return sqlServer, err
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			err = u.RecordFailedLogin(context.Background(), 1, 3, lockedUntil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("user.RecordFailedLogin() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func Test_user_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "UPDATE `users` SET `deleted_at`=? WHERE `users`.`id` = ? AND `users`.`deleted_at` IS NULL"
+	query := regexp.QuoteMeta(querySql)
+
+	type args struct {
+		param entity.UserParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				param: entity.UserParam{ID: 1},
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				param: entity.UserParam{ID: 1},
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(query).WithArgs(sqlmock.AnyArg(), 1).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectCommit()
+				sqlMock.ExpectationsWereMet()
+				return sqlServer, err
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			err = u.Delete(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("user.Delete() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func Test_user_CountByParam(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	querySql := "SELECT count(*) FROM `users` WHERE role = ? AND (username LIKE ? OR nama LIKE ?) AND `users`.`deleted_at` IS NULL"
+	query := regexp.QuoteMeta(querySql)
+
+	mockParam := entity.UserListParam{
+		Search: "kasir",
+		Role:   "tenant_cashier",
+	}
+
+	type args struct {
+		param entity.UserListParam
+	}
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		want        int64
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec query",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				sqlMock.ExpectQuery(query).WillReturnError(assert.AnError)
+				return sqlServer, err
+			},
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name: "all ok",
+			args: args{
+				param: mockParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"count"})
+				row.AddRow(3)
+				sqlMock.ExpectQuery(query).WithArgs("tenant_cashier", "%kasir%", "%kasir%").WillReturnRows(row)
+				return sqlServer, err
+			},
+			want:    3,
+			wantErr: false,
+		},
+		{
+			name: "super admin includes legacy admins",
+			args: args{
+				param: entity.UserListParam{Role: "super_admin"},
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"count"})
+				row.AddRow(2)
+				sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `users` WHERE (role = ? OR (COALESCE(role, '') = '' AND is_admin = ?)) AND `users`.`deleted_at` IS NULL")).
+					WithArgs("super_admin", true).WillReturnRows(row)
+				return sqlServer, err
+			},
+			want:    2,
+			wantErr: false,
+		},
+		{
+			name: "tenant owner includes legacy owners",
+			args: args{
+				param: entity.UserListParam{Role: "tenant_owner"},
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+				row := sqlmock.NewRows([]string{"count"})
+				row.AddRow(5)
+				sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `users` WHERE (role = ? OR (COALESCE(role, '') = '' AND is_admin = ? AND umkm_id <> ?)) AND `users`.`deleted_at` IS NULL")).
+					WithArgs("tenant_owner", false, 0).WillReturnRows(row)
+				return sqlServer, err
+			},
+			want:    5,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Error(err)
+			}
+
+			u := Init(sqlClient)
+			got, err := u.CountByParam(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("user.CountByParam() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -1,16 +1,18 @@
 package withdraw
 
 import (
+	"context"
 	"go-clean/src/business/entity"
 
 	"gorm.io/gorm"
 )
 
 type Interface interface {
-	Create(withdraw entity.Withdraw) (entity.Withdraw, error)
-	Get(param entity.WithdrawParam) (entity.Withdraw, error)
-	GetList(param entity.WithdrawParam) ([]entity.Withdraw, error)
-	Update(selectParam entity.WithdrawParam, updateParam entity.UpdateWithdrawParam) error
+	Create(ctx context.Context, withdraw entity.Withdraw) (entity.Withdraw, error)
+	Get(ctx context.Context, param entity.WithdrawParam) (entity.Withdraw, error)
+	GetList(ctx context.Context, param entity.WithdrawParam) ([]entity.Withdraw, error)
+	Count(ctx context.Context, param entity.WithdrawParam) (int64, error)
+	Update(ctx context.Context, selectParam entity.WithdrawParam, updateParam entity.UpdateWithdrawParam) error
 }
 
 type withdraw struct {
@@ -24,36 +26,46 @@ func Init(db *gorm.DB) Interface {
 	return w
 }
 
-func (w *withdraw) Create(withdraw entity.Withdraw) (entity.Withdraw, error) {
-	if err := w.db.Create(&withdraw).Error; err != nil {
+func (w *withdraw) Create(ctx context.Context, withdraw entity.Withdraw) (entity.Withdraw, error) {
+	if err := w.db.WithContext(ctx).Create(&withdraw).Error; err != nil {
 		return withdraw, err
 	}
 
 	return withdraw, nil
 }
 
-func (w *withdraw) Get(param entity.WithdrawParam) (entity.Withdraw, error) {
+func (w *withdraw) Get(ctx context.Context, param entity.WithdrawParam) (entity.Withdraw, error) {
 	withdraw := entity.Withdraw{}
 
-	if err := w.db.Where(param).First(&withdraw).Error; err != nil {
+	if err := w.db.WithContext(ctx).Where(param).First(&withdraw).Error; err != nil {
 		return withdraw, err
 	}
 
 	return withdraw, nil
 }
 
-func (w *withdraw) GetList(param entity.WithdrawParam) ([]entity.Withdraw, error) {
+func (w *withdraw) GetList(ctx context.Context, param entity.WithdrawParam) ([]entity.Withdraw, error) {
 	withdraws := []entity.Withdraw{}
 
-	if err := w.db.Where(param).Order(param.OrderBy).Limit(param.Limit).Offset(param.Offset).Find(&withdraws).Error; err != nil {
+	if err := w.db.WithContext(ctx).Where(param).Scopes(param.Seek).Order(param.OrderBy).Limit(param.Limit).Offset(param.Offset).Find(&withdraws).Error; err != nil {
 		return withdraws, err
 	}
 
 	return withdraws, nil
 }
 
-func (w *withdraw) Update(selectParam entity.WithdrawParam, updateParam entity.UpdateWithdrawParam) error {
-	if err := w.db.Model(entity.Withdraw{}).Where(selectParam).Updates(updateParam).Error; err != nil {
+func (w *withdraw) Count(ctx context.Context, param entity.WithdrawParam) (int64, error) {
+	var count int64
+
+	if err := w.db.WithContext(ctx).Model(&entity.Withdraw{}).Where(param).Count(&count).Error; err != nil {
+		return count, err
+	}
+
+	return count, nil
+}
+
+func (w *withdraw) Update(ctx context.Context, selectParam entity.WithdrawParam, updateParam entity.UpdateWithdrawParam) error {
+	if err := w.db.WithContext(ctx).Model(entity.Withdraw{}).Where(selectParam).Updates(updateParam).Error; err != nil {
 		return err
 	}
 
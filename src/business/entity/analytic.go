@@ -1,5 +1,14 @@
 package entity
 
+import "time"
+
+const (
+	GranularityHour  = "hour"
+	GranularityDay   = "day"
+	GranularityWeek  = "week"
+	GranularityMonth = "month"
+)
+
 type AnalyticParam struct {
 	UmkmID uint `uri:"umkm_id"`
 }
@@ -14,3 +23,233 @@ type WidgetDashboardResult struct {
 	TotalLastMonthTransaction int
 	TotalLastMonthRevenue     int
 }
+
+// AdminWidgetDashboardResult extends the sales widget with this month's payment and cancellation metrics.
+type AdminWidgetDashboardResult struct {
+	WidgetDashboardResult
+	PaymentMethods   []PaymentMethodBreakdown
+	AbandonedRate    float64
+	CancellationRate float64
+	RefundAmount     int
+}
+
+type SalesAnalyticParam struct {
+	UmkmID      uint   `uri:"umkm_id" form:"umkm_id"`
+	From        string `form:"from" binding:"required"`
+	To          string `form:"to" binding:"required"`
+	Granularity string `form:"granularity"`
+}
+
+type SalesAnalyticResult struct {
+	UmkmID      uint               `json:"umkm_id,omitempty"`
+	Granularity string             `json:"granularity"`
+	From        string             `json:"from"`
+	To          string             `json:"to"`
+	Current     SalesSummary       `json:"current"`
+	Previous    SalesSummary       `json:"previous"`
+	Growth      SalesGrowth        `json:"growth"`
+	Series      []SalesSeriesPoint `json:"series"`
+}
+
+type SalesSummary struct {
+	TotalTransaction int `json:"total_transaction"`
+	GrossAmount      int `json:"gross_amount"`
+	CommissionAmount int `json:"commission_amount"`
+	NetAmount        int `json:"net_amount"`
+}
+
+type SalesSeriesPoint struct {
+	Period string `json:"period"`
+	SalesSummary
+}
+
+// SalesGrowth holds the percentage change against the previous period, nil when the previous value is zero.
+type SalesGrowth struct {
+	TotalTransaction *float64 `json:"total_transaction"`
+	GrossAmount      *float64 `json:"gross_amount"`
+	CommissionAmount *float64 `json:"commission_amount"`
+	NetAmount        *float64 `json:"net_amount"`
+}
+
+type MenuPerformanceParam struct {
+	UmkmID uint   `uri:"umkm_id" form:"umkm_id"`
+	From   string `form:"from" binding:"required"`
+	To     string `form:"to" binding:"required"`
+	Limit  int    `form:"limit"`
+}
+
+type MenuPerformanceResult struct {
+	UmkmID           uint              `json:"umkm_id,omitempty"`
+	From             string            `json:"from"`
+	To               string            `json:"to"`
+	TotalTransaction int               `json:"total_transaction"`
+	TopByQuantity    []MenuPerformance `json:"top_by_quantity"`
+	TopByRevenue     []MenuPerformance `json:"top_by_revenue"`
+}
+
+type MenuPerformance struct {
+	MenuID           uint     `json:"menu_id"`
+	MenuName         string   `json:"menu_name"`
+	UmkmID           uint     `json:"umkm_id"`
+	TotalQuantity    int      `json:"total_quantity"`
+	TotalRevenue     int      `json:"total_revenue"`
+	TotalTransaction int      `json:"total_transaction"`
+	AttachRate       float64  `json:"attach_rate"`
+	CancellationRate float64  `json:"cancellation_rate"`
+	QuantityGrowth   *float64 `json:"quantity_growth"`
+	RevenueGrowth    *float64 `json:"revenue_growth"`
+}
+
+type HeatmapParam struct {
+	UmkmID uint   `uri:"umkm_id" form:"umkm_id"`
+	From   string `form:"from" binding:"required"`
+	To     string `form:"to" binding:"required"`
+}
+
+type HeatmapResult struct {
+	UmkmID uint          `json:"umkm_id,omitempty"`
+	From   string        `json:"from"`
+	To     string        `json:"to"`
+	Cells  []HeatmapCell `json:"cells"`
+}
+
+// HeatmapCell DayOfWeek follows time.Weekday, 0 is Sunday.
+type HeatmapCell struct {
+	DayOfWeek          int      `json:"day_of_week"`
+	Hour               int      `json:"hour"`
+	TotalTransaction   int      `json:"total_transaction"`
+	TotalRevenue       int      `json:"total_revenue"`
+	AvgPrepTimeSeconds *float64 `json:"avg_prep_time_seconds"`
+}
+
+type BreakdownParam struct {
+	From string `form:"from" binding:"required"`
+	To   string `form:"to" binding:"required"`
+}
+
+type BreakdownResult struct {
+	From             string                   `json:"from"`
+	To               string                   `json:"to"`
+	PaymentMethods   []PaymentMethodBreakdown `json:"payment_methods"`
+	Payment          PaymentStatusBreakdown   `json:"payment"`
+	CancellationRate float64                  `json:"cancellation_rate"`
+	Tenants          []TenantCancellation     `json:"tenants"`
+	Refund           RefundSummary            `json:"refund"`
+}
+
+// PaymentMethodBreakdown only counts successful payments, Share is against all successful payments.
+type PaymentMethodBreakdown struct {
+	PaymentType      string  `json:"payment_type"`
+	TotalTransaction int     `json:"total_transaction"`
+	GrossAmount      int     `json:"gross_amount"`
+	Share            float64 `json:"share"`
+}
+
+// PaymentStatusBreakdown counts denied, cancelled and expired payments as failures, AbandonedRate is their share of
+// all payments.
+type PaymentStatusBreakdown struct {
+	TotalTransaction int     `json:"total_transaction"`
+	TotalSuccess     int     `json:"total_success"`
+	TotalPending     int     `json:"total_pending"`
+	TotalFailure     int     `json:"total_failure"`
+	AbandonedRate    float64 `json:"abandoned_rate"`
+}
+
+type TenantCancellation struct {
+	UmkmID           uint    `json:"umkm_id"`
+	UmkmName         string  `json:"umkm_name"`
+	TotalItem        int     `json:"total_item"`
+	TotalCancelItem  int     `json:"total_cancel_item"`
+	CancellationRate float64 `json:"cancellation_rate"`
+	RefundAmount     int     `json:"refund_amount"`
+}
+
+type RefundSummary struct {
+	TotalItem   int `json:"total_item"`
+	TotalAmount int `json:"total_amount"`
+}
+
+// TimeRange is a half-open [Start, End) interval.
+type TimeRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+type SalesAggregateParam struct {
+	UmkmID      uint
+	Status      string
+	Granularity string
+	Range       TimeRange
+	Location    *time.Location
+}
+
+type SalesAggregate struct {
+	Period           string
+	TotalTransaction int
+	TotalRevenue     int
+}
+
+type MenuAggregateParam struct {
+	UmkmID uint
+	Range  TimeRange
+}
+
+type MenuAggregate struct {
+	UmkmID           uint
+	MenuID           uint
+	TotalQuantity    int
+	TotalRevenue     int
+	TotalTransaction int
+	TotalDoneItem    int
+	TotalCancelItem  int
+}
+
+type HeatmapAggregateParam struct {
+	UmkmID   uint
+	Range    TimeRange
+	Location *time.Location
+}
+
+type HeatmapAggregate struct {
+	DayOfWeek        int
+	Hour             int
+	TotalTransaction int
+	TotalRevenue     int
+	AvgPrepTime      *float64
+}
+
+type PaymentAggregateParam struct {
+	Range TimeRange
+}
+
+type PaymentAggregate struct {
+	PaymentType      int
+	Status           string
+	TotalTransaction int
+	TotalAmount      int
+}
+
+type CancellationAggregateParam struct {
+	Range TimeRange
+}
+
+type CancellationAggregate struct {
+	UmkmID            uint
+	TotalItem         int
+	TotalCancelItem   int
+	TotalRefundItem   int
+	TotalRefundAmount int
+}
+
+func (tr TimeRange) Contains(t time.Time) bool {
+	return !t.Before(tr.Start) && t.Before(tr.End)
+}
+
+func (ss *SalesSummary) Add(totalTransaction int, grossAmount int) {
+	commission := grossAmount * CommissionPercentage / 100
+
+	ss.TotalTransaction += totalTransaction
+	ss.GrossAmount += grossAmount
+	ss.CommissionAmount += commission
+	ss.NetAmount += grossAmount - commission
+}
@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	AuditTargetUmkm        = "umkm"
+	AuditTargetMenu        = "menu"
+	AuditTargetTransaction = "transaction"
+	AuditTargetPayment     = "payment"
+	AuditTargetUser        = "user"
+	AuditTargetWithdraw    = "withdraw"
+)
+
+const (
+	AuditActionUmkmCreate        = "umkm.create"
+	AuditActionUmkmUpdate        = "umkm.update"
+	AuditActionUmkmDelete        = "umkm.delete"
+	AuditActionUmkmUploadImage   = "umkm.upload_image"
+	AuditActionMenuCreate        = "menu.create"
+	AuditActionMenuUpdate        = "menu.update"
+	AuditActionMenuDelete        = "menu.delete"
+	AuditActionMenuUploadImage   = "menu.upload_image"
+	AuditActionOrderComplete     = "order.complete"
+	AuditActionOrderCancel       = "order.cancel"
+	AuditActionPaymentMarkAsPaid = "payment.mark_as_paid"
+	AuditActionUserCreate        = "user.create"
+	AuditActionUserUpdate        = "user.update"
+	AuditActionUserDelete        = "user.delete"
+	AuditActionUserDisable       = "user.disable"
+	AuditActionUserEnable        = "user.enable"
+	AuditActionUserResetCode     = "user.reset_code"
+	AuditActionUserUnlock        = "user.unlock"
+	AuditActionStaffInvite       = "staff.invite"
+	AuditActionWithdrawCreate    = "withdraw.create"
+	AuditActionWithdrawUpdate    = "withdraw.update"
+)
+
+type AuditLog struct {
+	gorm.Model
+	ActorID    uint   `gorm:"index"`
+	ActorName  string `gorm:"type:varchar(191)"`
+	ActorRole  string `gorm:"type:varchar(32)"`
+	Action     string `gorm:"type:varchar(64);index"`
+	TargetType string `gorm:"type:varchar(32);index"`
+	TargetID   string `gorm:"type:varchar(64);index"`
+	UmkmID     uint   `gorm:"index"`
+	Before     string `gorm:"type:text"`
+	After      string `gorm:"type:text"`
+	IP         string `gorm:"type:varchar(64)"`
+	Method     string `gorm:"type:varchar(8)"`
+	Path       string
+	StatusCode int
+}
+
+type AuditLogParam struct {
+	ActorID    uint      `form:"actor_id"`
+	Action     string    `form:"action"`
+	TargetType string    `form:"target_type"`
+	TargetID   string    `form:"target_id"`
+	UmkmID     uint      `form:"umkm_id"`
+	StartDate  string    `form:"start_date" gorm:"-"`
+	EndDate    string    `form:"end_date" gorm:"-"`
+	From       time.Time `json:"-" gorm:"-"`
+	To         time.Time `json:"-" gorm:"-"`
+	PaginationParam
+}
+
+// CreateAuditLogParam Before and After are snapshots of the target, they're stored as redacted JSON.
+type CreateAuditLogParam struct {
+	ActorID    uint
+	ActorName  string
+	ActorRole  string
+	UmkmID     uint
+	Action     string
+	TargetType string
+	TargetID   string
+	Before     interface{}
+	After      interface{}
+	IP         string
+	Method     string
+	Path       string
+	StatusCode int
+}
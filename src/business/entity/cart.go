@@ -16,14 +16,16 @@ const (
 
 type Cart struct {
 	gorm.Model
-	UmkmID        uint
+	UmkmID        uint `gorm:"index"`
 	MenuID        uint
-	TransactionID uint
+	TransactionID uint `gorm:"index"`
 	Status        string
 	GuestID       string
 	Amount        int
 	TotalPrice    int
 	PricePerItem  int
+	PaidAt        *time.Time
+	DoneAt        *time.Time
 	Menu          Menu `grom:"-:all"`
 	Umkm          Umkm `grom:"-:all"`
 }
@@ -39,6 +41,14 @@ type CartParam struct {
 	CreatedAtMoreThan time.Time `json:"-" gorm:"-"`
 }
 
+// CartTransactionParam selects the transactions holding carts in one of Statuses, UmkmID and OrderIDLike are optional.
+type CartTransactionParam struct {
+	UmkmID      uint
+	Statuses    []string
+	OrderIDLike string
+	PaginationParam
+}
+
 type CreateCartParam struct {
 	UmkmID uint `binding:"required"`
 	MenuID uint `binding:"required"`
@@ -50,4 +60,6 @@ type UpdateCartParam struct {
 	Status        string
 	TotalPrice    int
 	Amount        int
+	PaidAt        *time.Time
+	DoneAt        *time.Time
 }
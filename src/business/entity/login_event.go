@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	LoginReasonSuccess       = "success"
+	LoginReasonUserNotFound  = "user_not_found"
+	LoginReasonWrongPassword = "wrong_password"
+	LoginReasonLocked        = "locked"
+	LoginReasonDisabled      = "disabled"
+)
+
+type LoginEvent struct {
+	gorm.Model
+	UserID   uint   `gorm:"index"`
+	Username string `gorm:"type:varchar(191);index"`
+	IP       string `gorm:"type:varchar(64);index"`
+	Success  bool
+	Reason   string
+}
+
+type LoginEventParam struct {
+	UserID   uint   `form:"user_id"`
+	Username string `form:"username"`
+	IP       string `form:"ip"`
+	Success  *bool  `form:"success"`
+	PaginationParam
+}
+
+type LoginFailureParam struct {
+	Username string
+	IP       string
+	Since    time.Time
+}
+
+type LoginFailureSummary struct {
+	TotalFailure  int
+	LastFailureAt *time.Time
+}
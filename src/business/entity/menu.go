@@ -4,28 +4,30 @@ import "gorm.io/gorm"
 
 type Menu struct {
 	gorm.Model
-	Name        string
-	Description string
-	Price       int
-	UmkmID      uint
-	IsReady     *bool
-	ImgPath     string
+	Name         string `gorm:"type:varchar(191);index"`
+	Description  string
+	Price        int
+	UmkmID       uint
+	IsReady      *bool
+	ImgPath      string
+	IsBestSeller bool `gorm:"-:all"`
 }
 
 type MenuParam struct {
 	ID     uint   `uri:"menu_id" json:"id"`
 	Name   string `form:"name" json:"name" gorm:"-"`
 	UmkmID uint   `uri:"umkm_id" form:"umkm_id" json:"umkm_id"`
+	PaginationParam
 }
 
 type CreateMenuParam struct {
-	Name        string `binding:"required"`
+	Name        string `binding:"required,max=191"`
 	Description string `binding:"required"`
 	Price       int    `binding:"required"`
 }
 
 type UpdateMenuParam struct {
-	Name        string
+	Name        string `binding:"max=191"`
 	Description string
 	Price       int
 	IsReady     *bool  `json:"is_ready"`
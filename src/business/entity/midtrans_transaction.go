@@ -17,12 +17,12 @@ const (
 
 type MidtransTransaction struct {
 	gorm.Model
-	TransactionID uint
+	TransactionID uint `gorm:"index"`
 	MidtransID    string
-	OrderID       string
-	PaymentType   int
+	OrderID       string `gorm:"type:varchar(64);index"`
+	PaymentType   int    `gorm:"index"`
 	GrossAmount   int
-	Status        string
+	Status        string `gorm:"type:varchar(32);index"`
 	PaymentData   string
 }
 
@@ -39,9 +39,7 @@ type MidtransTransactionParam struct {
 	CreatedAtMoreThan time.Time `json:"-" gorm:"-"`
 	OrderID           string    `uri:"order_id" json:"order_id"`
 	OrderIDLike       string    `json:"order_id_like" gorm:"-"`
-	Limit             int       `json:"-" gorm:"-"`
-	Offset            int       `json:"-" gorm:"-"`
-	OrderBy           string    `json:"-" gorm:"-"`
+	PaginationParam
 }
 
 type MidtransTransactionPaymentDetail struct {
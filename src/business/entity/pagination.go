@@ -0,0 +1,372 @@
+package entity
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-clean/src/lib/apperr"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+
+	defaultPaginationLimit    = 20
+	defaultPaginationMaxLimit = 100
+)
+
+var (
+	ErrInvalidPage      = apperr.Validation("invalid_page", "page dan limit tidak boleh negatif")
+	ErrInvalidSortBy    = apperr.Validation("invalid_sort_by", "sort_by harus salah satu dari %s")
+	ErrInvalidSortOrder = apperr.Validation("invalid_sort_order", "sort_order harus asc atau desc")
+	ErrInvalidCursor    = apperr.Validation("invalid_cursor", "cursor tidak valid")
+)
+
+// cursorSchemas caches the parsed rows NextCursor reads the sort values from.
+var cursorSchemas = &sync.Map{}
+
+type PaginationConfig struct {
+	// DefaultLimit is used when a list is requested without a limit, defaults to 20
+	DefaultLimit int
+	// MaxLimit caps the limit of every list, defaults to 100
+	MaxLimit int
+}
+
+func (c PaginationConfig) Validate() error {
+	errs := []string{}
+	if c.DefaultLimit < 0 || c.MaxLimit < 0 {
+		errs = append(errs, "Usecase.Pagination limits must not be negative")
+	}
+	if c.MaxLimit > 0 && c.DefaultLimit > c.MaxLimit {
+		errs = append(errs, fmt.Sprintf("Usecase.Pagination.DefaultLimit %d must not exceed MaxLimit %d", c.DefaultLimit, c.MaxLimit))
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+func (c PaginationConfig) GetDefaultLimit() int {
+	if c.DefaultLimit == 0 {
+		return defaultPaginationLimit
+	}
+
+	return c.DefaultLimit
+}
+
+func (c PaginationConfig) GetMaxLimit() int {
+	if c.MaxLimit == 0 {
+		return defaultPaginationMaxLimit
+	}
+
+	return c.MaxLimit
+}
+
+// PaginationParam is embedded in the params of every paginated list. Cursor is the NextCursor of the previous page
+// and wins over Page, Offset, OrderBy and After are filled by Paginate.
+type PaginationParam struct {
+	Page      int    `form:"page" json:"-" gorm:"-"`
+	Limit     int    `form:"limit" json:"-" gorm:"-"`
+	SortBy    string `form:"sort_by" json:"-" gorm:"-"`
+	SortOrder string `form:"sort_order" json:"-" gorm:"-"`
+	Cursor    string `form:"cursor" json:"-" gorm:"-"`
+	Offset    int    `json:"-" gorm:"-"`
+	OrderBy   string `json:"-" gorm:"-"`
+	// After leaves out the rows up to the cursor's row, it's nil without a cursor
+	After *Keyset `json:"-" gorm:"-"`
+}
+
+// Keyset compares the sort column and the tie breaker of a row to the values of the cursor's row.
+type Keyset struct {
+	Query string
+	Args  []interface{}
+}
+
+// Seek is a scope starting the list after the cursor, it leaves a list without one as is.
+func (p PaginationParam) Seek(db *gorm.DB) *gorm.DB {
+	if p.After == nil {
+		return db
+	}
+
+	return db.Where(p.After.Query, p.After.Args...)
+}
+
+// SortOptions maps the sort_by values a list accepts to their columns, Default and DefaultOrder are used when the
+// caller doesn't pick one. Ties are broken by TieBreaker so pages don't overlap.
+type SortOptions struct {
+	Columns      map[string]string
+	Default      string
+	DefaultOrder string
+	TieBreaker   string
+}
+
+// Paginate applies the defaults and limits of cfg and resolves the page, cursor and sort parameters into Offset, After
+// and OrderBy. A page fetched by cursor has no page number.
+func (p PaginationParam) Paginate(cfg PaginationConfig, options SortOptions) (PaginationParam, error) {
+	if p.Page < 0 || p.Limit < 0 {
+		return p, ErrInvalidPage
+	}
+
+	if p.Limit == 0 {
+		p.Limit = cfg.GetDefaultLimit()
+	}
+	if p.Limit > cfg.GetMaxLimit() {
+		p.Limit = cfg.GetMaxLimit()
+	}
+
+	if p.SortBy == "" {
+		p.SortBy = options.Default
+	}
+	column, ok := options.Columns[p.SortBy]
+	if !ok {
+		return p, ErrInvalidSortBy.WithArgs(strings.Join(options.keys(), ", "))
+	}
+
+	if p.SortOrder == "" {
+		p.SortOrder = options.DefaultOrder
+	}
+	p.SortOrder = strings.ToLower(p.SortOrder)
+	if p.SortOrder != SortAsc && p.SortOrder != SortDesc {
+		return p, ErrInvalidSortOrder
+	}
+
+	p.OrderBy = fmt.Sprintf("%s %s", column, p.SortOrder)
+	if options.hasTieBreaker(column) {
+		p.OrderBy = fmt.Sprintf("%s, %s %s", p.OrderBy, options.TieBreaker, p.SortOrder)
+	}
+
+	if p.Cursor != "" {
+		after, err := options.keyset(p, column)
+		if err != nil {
+			return p, ErrInvalidCursor.Wrap(err)
+		}
+		p.After = &after
+		p.Page = 0
+		p.Offset = 0
+	} else {
+		if p.Page == 0 {
+			p.Page = 1
+		}
+		p.Offset = (p.Page - 1) * p.Limit
+	}
+
+	return p, nil
+}
+
+func (s SortOptions) hasTieBreaker(column string) bool {
+	return s.TieBreaker != "" && s.TieBreaker != column
+}
+
+// keyset decodes the cursor of p into the condition selecting the rows sorted after the cursor's row.
+func (s SortOptions) keyset(p PaginationParam, column string) (Keyset, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(p.Cursor)
+	if err != nil {
+		return Keyset{}, err
+	}
+
+	c := cursor{}
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return Keyset{}, err
+	}
+	if c.Sort != cursorSort(p) {
+		return Keyset{}, fmt.Errorf("cursor of %q used for %q", c.Sort, cursorSort(p))
+	}
+
+	columns := []string{column}
+	if s.hasTieBreaker(column) {
+		columns = append(columns, s.TieBreaker)
+	}
+	if len(c.Values) != len(columns) {
+		return Keyset{}, fmt.Errorf("cursor has %d values for %d columns", len(c.Values), len(columns))
+	}
+
+	args := make([]interface{}, 0, len(c.Values))
+	for _, v := range c.Values {
+		arg, err := decodeCursorValue(v)
+		if err != nil {
+			return Keyset{}, err
+		}
+		args = append(args, arg)
+	}
+
+	operator := ">"
+	if p.SortOrder == SortDesc {
+		operator = "<"
+	}
+
+	if len(columns) == 1 {
+		return Keyset{Query: fmt.Sprintf("%s %s ?", column, operator), Args: args}, nil
+	}
+
+	return Keyset{Query: fmt.Sprintf("(%s, %s) %s (?, ?)", columns[0], columns[1], operator), Args: args}, nil
+}
+
+func (s SortOptions) keys() []string {
+	keys := make([]string, 0, len(s.Columns))
+	for k := range s.Columns {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Pagination describes the page of a list response, NextCursor is empty on the last page.
+type Pagination struct {
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	TotalItems int64  `json:"total_items"`
+	TotalPages int    `json:"total_pages"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewPagination describes the page requested by param out of total items.
+func NewPagination(param PaginationParam, total int64) Pagination {
+	pagination := Pagination{
+		Page:       param.Page,
+		Limit:      param.Limit,
+		TotalItems: total,
+	}
+
+	if param.Limit > 0 {
+		pagination.TotalPages = int((total + int64(param.Limit) - 1) / int64(param.Limit))
+	}
+
+	return pagination
+}
+
+// SetNextCursor points NextCursor after the last of rows, the page of the list sorted by param with options. The last
+// page gets no cursor, a page picked by number is the last when it reaches TotalItems and one picked by cursor when
+// it's shorter than the limit. Rows without the sort columns get no cursor either.
+func (p *Pagination) SetNextCursor(param PaginationParam, options SortOptions, rows interface{}) {
+	page := reflect.ValueOf(rows)
+	if page.Kind() != reflect.Slice || param.Limit == 0 || page.Len() < param.Limit {
+		return
+	}
+	if param.After == nil && int64(param.Offset+page.Len()) >= p.TotalItems {
+		return
+	}
+	last := reflect.Indirect(page.Index(page.Len() - 1))
+
+	column := options.Columns[param.SortBy]
+	columns := []string{column}
+	if options.hasTieBreaker(column) {
+		columns = append(columns, options.TieBreaker)
+	}
+
+	c := cursor{
+		Sort: cursorSort(param),
+	}
+	for _, column := range columns {
+		value, ok := columnValue(last, column)
+		if !ok {
+			return
+		}
+		encoded, ok := encodeCursorValue(value)
+		if !ok {
+			return
+		}
+		c.Values = append(c.Values, encoded)
+	}
+
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return
+	}
+
+	p.NextCursor = base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// cursor is opaque to clients, it holds the sort it was made for and the sort values of the last row of a page.
+type cursor struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+}
+
+func cursorSort(p PaginationParam) string {
+	return p.SortBy + " " + p.SortOrder
+}
+
+// columnValue reads column, with or without its table, from a row. Rows that aren't structs, e.g. plucked ids, are the
+// value of their only column.
+func columnValue(row reflect.Value, column string) (interface{}, bool) {
+	if row.Kind() != reflect.Struct || row.Type() == reflect.TypeOf(time.Time{}) {
+		return row.Interface(), true
+	}
+
+	rowSchema, err := schema.Parse(row.Addr().Interface(), cursorSchemas, schema.NamingStrategy{})
+	if err != nil {
+		return nil, false
+	}
+
+	field := rowSchema.LookUpField(column[strings.LastIndex(column, ".")+1:])
+	if field == nil {
+		return nil, false
+	}
+
+	value := field.ReflectValueOf(context.Background(), row)
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil, false
+		}
+		value = value.Elem()
+	}
+
+	return value.Interface(), true
+}
+
+// encodeCursorValue keeps the type of the value so it's compared to the column as it was read.
+func encodeCursorValue(v interface{}) (string, bool) {
+	if t, ok := v.(time.Time); ok {
+		return "t:" + t.UTC().Format(time.RFC3339Nano), true
+	}
+
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.String:
+		return "s:" + value.String(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "i:" + strconv.FormatInt(value.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "u:" + strconv.FormatUint(value.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return "f:" + strconv.FormatFloat(value.Float(), 'g', -1, 64), true
+	}
+
+	return "", false
+}
+
+func decodeCursorValue(v string) (interface{}, error) {
+	kind, value, ok := strings.Cut(v, ":")
+	if !ok {
+		return nil, fmt.Errorf("untyped cursor value %q", v)
+	}
+
+	switch kind {
+	case "t":
+		return time.Parse(time.RFC3339Nano, value)
+	case "s":
+		return value, nil
+	case "i":
+		return strconv.ParseInt(value, 10, 64)
+	case "u":
+		return strconv.ParseUint(value, 10, 64)
+	case "f":
+		return strconv.ParseFloat(value, 64)
+	}
+
+	return nil, fmt.Errorf("unknown cursor value type %q", kind)
+}
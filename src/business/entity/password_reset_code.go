@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PasswordResetCode struct {
+	gorm.Model
+	UserID    uint   `gorm:"index"`
+	CodeHash  string `gorm:"type:varchar(64);index"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+type PasswordResetCodeParam struct {
+	ID       uint
+	UserID   uint
+	CodeHash string
+}
+
+type UpdatePasswordResetCodeParam struct {
+	UsedAt *time.Time
+}
+
+type PasswordResetCodeResult struct {
+	Code      string    `json:"code"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -1,12 +1,14 @@
 package entity
 
 type Response struct {
-	Meta Meta        `json:"meta"`
-	Data interface{} `json:"data"`
+	Meta       Meta        `json:"meta"`
+	Data       interface{} `json:"data"`
+	Pagination *Pagination `json:"pagination,omitempty"`
 }
 
 type Meta struct {
-	Message string `json:"message"`
-	Code    int    `json:"code"`
-	IsError bool   `json:"is_error"`
+	Message   string `json:"message"`
+	Code      int    `json:"code"`
+	IsError   bool   `json:"is_error"`
+	ErrorCode string `json:"error_code,omitempty"`
 }
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RefreshToken struct {
+	gorm.Model
+	UserID    uint   `gorm:"index"`
+	TokenHash string `gorm:"type:varchar(64);uniqueIndex"`
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+}
+
+type RefreshTokenParam struct {
+	ID        uint
+	UserID    uint
+	TokenHash string
+}
+
+type UpdateRefreshTokenParam struct {
+	RevokedAt *time.Time
+}
+
+// RevokedToken keeps the jti of logged out access tokens until they expire, expired rows are purged on logout.
+type RevokedToken struct {
+	gorm.Model
+	JTI       string    `gorm:"type:varchar(64);uniqueIndex"`
+	ExpiresAt time.Time `gorm:"index"`
+}
+
+type RevokedTokenParam struct {
+	JTI string
+}
+
+type TokenResult struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
@@ -1,23 +1,32 @@
 package entity
 
-import "gorm.io/gorm"
+import (
+	"time"
 
+	"gorm.io/gorm"
+)
+
+// CommissionPercentage is the share of gross sales kept by the venue, the rest is paid out to the UMKM.
+const CommissionPercentage = 17
+
+// Transaction is searched by buyer name, seat and email through the idx_transactions_search full-text index.
 type Transaction struct {
 	gorm.Model
-	GuestID    string
-	BuyerName  string
-	Seat       string
+	GuestID    string `gorm:"type:varchar(32);index"`
+	BuyerName  string `gorm:"type:varchar(191);index;index:idx_transactions_search,class:FULLTEXT"`
+	Seat       string `gorm:"type:varchar(64);index;index:idx_transactions_search,class:FULLTEXT"`
+	Email      string `gorm:"type:varchar(191);index;index:idx_transactions_search,class:FULLTEXT"`
 	Notes      string
-	Price      int
+	Price      int `gorm:"index"`
 	IsRefunded bool
 }
 
 type CreateTransactionParam struct {
-	BuyerName string `binding:"required"`
-	Seat      string `binding:"required"`
+	BuyerName string `binding:"required,max=191"`
+	Seat      string `binding:"required,max=64"`
 	Notes     string
 	PaymentID int    `binding:"required"`
-	Email     string `binding:"required"`
+	Email     string `binding:"required,max=191"`
 }
 
 type TransactionParam struct {
@@ -27,16 +36,37 @@ type TransactionParam struct {
 	Status          string   `form:"status"`
 	Statuses        []string `form:"statuses" gorm:"-"`
 	MidtransOrderID string   `form:"order_id"`
-	Page            int      `form:"page" json:"-" gorm:"-"`
-	Limit           int      `form:"limit" json:"-" gorm:"-"`
-	Offset          int      `json:"-" gorm:"-"`
-	OrderBy         string   `json:"-" gorm:"-"`
+	PaginationParam
+}
+
+// TransactionSearchParam combines the optional filters of the admin order search. Query is matched against the buyer
+// name, seat and email, BuyerName, OrderID and MenuName match by prefix. From, To and PaymentTypeID are resolved from
+// the dates and payment type by the usecase.
+type TransactionSearchParam struct {
+	Query         string    `form:"q"`
+	BuyerName     string    `form:"buyer_name"`
+	Seat          string    `form:"seat"`
+	Email         string    `form:"email"`
+	OrderID       string    `form:"order_id"`
+	UmkmID        uint      `form:"umkm_id"`
+	MenuName      string    `form:"menu_name"`
+	Statuses      []string  `form:"statuses"`
+	PaymentType   string    `form:"payment_type"`
+	MinAmount     int       `form:"min_amount"`
+	MaxAmount     int       `form:"max_amount"`
+	StartDate     string    `form:"start_date"`
+	EndDate       string    `form:"end_date"`
+	PaymentTypeID int       `form:"-" json:"-"`
+	From          time.Time `form:"-" json:"-"`
+	To            time.Time `form:"-" json:"-"`
+	PaginationParam
 }
 
 type TransactionDetailResponse struct {
 	ID              uint        `json:"transaction_id"`
 	BuyerName       string      `json:"buyer_name"`
 	Seat            string      `json:"seat"`
+	Email           string      `json:"email,omitempty"`
 	Notes           string      `json:"notes"`
 	Price           int         `json:"price"`
 	Status          string      `json:"status"`
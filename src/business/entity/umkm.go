@@ -23,6 +23,7 @@ type UmkmParam struct {
 	Name   string `json:"name" form:"name" gorm:"-"`
 	Slogan string `json:"slogan"`
 	Status string `json:"status" form:"status"`
+	PaginationParam
 }
 
 type CreateUmkmParam struct {
@@ -2,36 +2,132 @@ package entity
 
 import (
 	"go-clean/src/lib/auth"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type User struct {
 	gorm.Model
-	Username   string
-	Password   string `json:"-"`
-	Nama       string
-	IsAdmin    bool
-	UmkmID     uint
-	GuestID    string `json:"-" gorm:"-:all"`
-	UmkmStatus string `gorm:"-:all"`
+	Username         string `gorm:"type:varchar(191);uniqueIndex"`
+	Password         string `json:"-"`
+	Nama             string
+	Email            string `gorm:"type:varchar(191);index"`
+	Phone            string `gorm:"type:varchar(32);index"`
+	IsAdmin          bool
+	UmkmID           uint   `gorm:"index"`
+	Role             string `gorm:"type:varchar(32)"`
+	IsDisabled       bool
+	FailedLoginCount int
+	LockedUntil      *time.Time
+	GuestID          string `json:"-" gorm:"type:varchar(32);index"`
+	UmkmStatus       string `gorm:"-:all"`
 }
 
 type UserParam struct {
-	ID       uint
+	ID       uint `uri:"user_id"`
 	Username string
+	UmkmID   uint `uri:"umkm_id"`
+	Role     string
+}
+
+type UserListParam struct {
+	Search     string `form:"search" gorm:"-"`
+	Role       string `form:"role" gorm:"-"`
+	UmkmID     uint   `form:"umkm_id"`
+	IsDisabled *bool  `form:"is_disabled"`
+	PaginationParam
+}
+
+// UpdateUserParam pointer fields are written even when they hold a zero value.
+type UpdateUserParam struct {
+	Password   string
+	Username   string
+	Nama       string
+	Role       string
+	UmkmID     *uint
+	IsAdmin    *bool
+	IsDisabled *bool
+	GuestID    string
+}
+
+type AdminUpdateUserParam struct {
+	Username string `binding:"omitempty,max=191"`
+	Nama     string
+	Role     string `binding:"omitempty,oneof=super_admin finance_admin tenant_owner tenant_cashier kitchen_staff buyer"`
+	UmkmID   *uint
 }
 
 type CreateUserParam struct {
-	Username string `binding:"required"`
+	Username string `binding:"required,max=191"`
 	Password string `binding:"required"`
 	Nama     string `binding:"required"`
-	UmkmID   uint   `binding:"required"`
+	UmkmID   uint
+	Role     string `binding:"omitempty,oneof=super_admin finance_admin tenant_owner tenant_cashier kitchen_staff buyer"`
+}
+
+type InviteStaffParam struct {
+	UmkmID   uint   `uri:"umkm_id" json:"-"`
+	Username string `binding:"required,max=191"`
+	Nama     string `binding:"required"`
+	Role     string `binding:"required,oneof=tenant_cashier kitchen_staff"`
+}
+
+type StaffInvitationResult struct {
+	User      User
+	ResetCode PasswordResetCodeResult
 }
 
 type LoginUserParam struct {
 	Username string `binding:"required"`
 	Password string `binding:"required"`
+	IP       string `json:"-"`
+	GuestID  string `json:"-"`
+}
+
+// RegisterBuyerParam needs an email or a phone number, whichever is given becomes the login username.
+type RegisterBuyerParam struct {
+	Email    string `binding:"required_without=Phone,omitempty,email,max=191"`
+	Phone    string `binding:"required_without=Email,omitempty,numeric,min=8,max=16"`
+	Nama     string `binding:"required"`
+	Password string `binding:"required"`
+	GuestID  string `json:"-"`
+}
+
+type ChangePasswordParam struct {
+	OldPassword string `binding:"required"`
+	NewPassword string `binding:"required"`
+}
+
+type ResetPasswordParam struct {
+	Username    string `binding:"required"`
+	Code        string `binding:"required"`
+	NewPassword string `binding:"required"`
+}
+
+type RefreshUserTokenParam struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LogoutUserParam struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// GetRole falls back to the legacy flags for accounts created before roles were stored.
+func (u *User) GetRole() string {
+	if u.Role != "" {
+		return u.Role
+	}
+
+	if u.IsAdmin {
+		return auth.RoleSuperAdmin
+	}
+
+	if u.UmkmID != 0 {
+		return auth.RoleTenantOwner
+	}
+
+	return ""
 }
 
 func (u *User) ConvertToAuthUser() auth.User {
@@ -42,5 +138,6 @@ func (u *User) ConvertToAuthUser() auth.User {
 		IsAdmin:  u.IsAdmin,
 		GuestID:  u.GuestID,
 		UmkmID:   u.UmkmID,
+		Role:     u.GetRole(),
 	}
 }
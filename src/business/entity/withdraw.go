@@ -13,13 +13,10 @@ type Withdraw struct {
 }
 
 type WithdrawParam struct {
-	ID      uint   `json:"withdraw_id" uri:"withdraw_id"`
-	Date    string `form:"date"`
-	UmkmID  uint   `form:"umkm_id"`
-	Limit   int    `form:"limit" json:"-" gorm:"-"`
-	Offset  int    `json:"-" gorm:"-"`
-	OrderBy string `json:"-" gorm:"-"`
-	Page    int    `form:"page" json:"-" gorm:"-"`
+	ID     uint   `json:"withdraw_id" uri:"withdraw_id"`
+	Date   string `form:"date"`
+	UmkmID uint   `form:"umkm_id"`
+	PaginationParam
 }
 
 type CreateWithdrawParam struct {
@@ -2,165 +2,589 @@ package analytic
 
 import (
 	"context"
+	"fmt"
 	cartDom "go-clean/src/business/domain/cart"
+	menuDom "go-clean/src/business/domain/menu"
+	midtransTransactionDom "go-clean/src/business/domain/midtrans_transaction"
+	umkmDom "go-clean/src/business/domain/umkm"
 	"go-clean/src/business/entity"
-	"strings"
+	"go-clean/src/lib/apperr"
+	"sort"
 	"time"
 )
 
 type Interface interface {
 	GetDashboardWidget(ctx context.Context, param entity.AnalyticParam) (entity.WidgetDashboardResult, error)
-	GetAllDashboardWidget(ctx context.Context) (entity.WidgetDashboardResult, error)
+	GetAllDashboardWidget(ctx context.Context) (entity.AdminWidgetDashboardResult, error)
+	GetSalesAnalytic(ctx context.Context, param entity.SalesAnalyticParam) (entity.SalesAnalyticResult, error)
+	GetMenuPerformance(ctx context.Context, param entity.MenuPerformanceParam) (entity.MenuPerformanceResult, error)
+	GetHeatmap(ctx context.Context, param entity.HeatmapParam) (entity.HeatmapResult, error)
+	GetBreakdown(ctx context.Context, param entity.BreakdownParam) (entity.BreakdownResult, error)
+}
+
+const (
+	maxSeriesLength     = 1000
+	defaultTopMenuLimit = 10
+)
+
+var (
+	ErrInvalidDate        = apperr.Validation("invalid_date", "tanggal harus berformat YYYY-MM-DD")
+	ErrInvalidRange       = apperr.Validation("invalid_range", "tanggal akhir tidak boleh sebelum tanggal awal")
+	ErrInvalidGranularity = apperr.Validation("invalid_granularity", "granularity harus hour, day, week atau month")
+	ErrRangeTooLong       = apperr.Validation("range_too_long", "rentang tanggal terlalu panjang untuk granularity ini")
+)
+
+type Config struct {
+	Timezone string
 }
 
 type analytic struct {
-	cart cartDom.Interface
+	cart                cartDom.Interface
+	menu                menuDom.Interface
+	umkm                umkmDom.Interface
+	midtransTransaction midtransTransactionDom.Interface
+	location            *time.Location
 }
 
-func Init(cd cartDom.Interface) Interface {
+func Init(cd cartDom.Interface, md menuDom.Interface, ud umkmDom.Interface, mtd midtransTransactionDom.Interface, cfg Config) Interface {
+	location := time.Local
+	if cfg.Timezone != "" {
+		loc, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			panic(fmt.Errorf("invalid analytic timezone. err: %w", err))
+		}
+		location = loc
+	}
+
 	a := &analytic{
-		cart: cd,
+		cart:                cd,
+		menu:                md,
+		umkm:                ud,
+		midtransTransaction: mtd,
+		location:            location,
 	}
 
 	return a
 }
 
 func (a *analytic) GetDashboardWidget(ctx context.Context, param entity.AnalyticParam) (entity.WidgetDashboardResult, error) {
+	return a.getDashboardWidget(ctx, param.UmkmID)
+}
+
+func (a *analytic) GetAllDashboardWidget(ctx context.Context) (entity.AdminWidgetDashboardResult, error) {
+	result := entity.AdminWidgetDashboardResult{}
+
+	widget, err := a.getDashboardWidget(ctx, 0)
+	if err != nil {
+		return result, err
+	}
+
+	now := time.Now().In(a.location)
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, a.location)
+
+	breakdown, err := a.getBreakdown(ctx, entity.TimeRange{Start: thisMonth, End: thisMonth.AddDate(0, 1, 0)})
+	if err != nil {
+		return result, err
+	}
+
+	result.WidgetDashboardResult = widget
+	result.PaymentMethods = breakdown.PaymentMethods
+	result.AbandonedRate = breakdown.Payment.AbandonedRate
+	result.CancellationRate = breakdown.CancellationRate
+	result.RefundAmount = breakdown.Refund.TotalAmount
+
+	return result, nil
+}
+
+func (a *analytic) getDashboardWidget(ctx context.Context, umkmID uint) (entity.WidgetDashboardResult, error) {
 	result := entity.WidgetDashboardResult{}
 
-	now := time.Now()
+	now := time.Now().In(a.location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, a.location)
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, a.location)
 
-	month := now.Format("2006-01")
-	calcLastMonth := now.AddDate(0, -1, 0)
-	lastMonth := calcLastMonth.Format("2006-01")
-	today := now.Format("2006-01-02")
-	calcYesterday := now.AddDate(0, 0, -1)
-	yesterday := calcYesterday.Format("2006-01-02")
+	todayRange := entity.TimeRange{Start: today, End: today.AddDate(0, 0, 1)}
+	yesterdayRange := entity.TimeRange{Start: today.AddDate(0, 0, -1), End: today}
+	monthRange := entity.TimeRange{Start: thisMonth, End: thisMonth.AddDate(0, 1, 0)}
+	lastMonthRange := entity.TimeRange{Start: thisMonth.AddDate(0, -1, 0), End: thisMonth}
 
-	cartsMonth, err := a.cart.GetList(entity.CartParam{
-		Status:    entity.StatusDone,
-		UmkmID:    param.UmkmID,
-		CreatedAt: month,
+	sales, err := a.cart.GetSalesAggregate(ctx, entity.SalesAggregateParam{
+		UmkmID:      umkmID,
+		Status:      entity.StatusDone,
+		Granularity: entity.GranularityDay,
+		Range:       entity.TimeRange{Start: lastMonthRange.Start, End: todayRange.End},
+		Location:    a.location,
 	})
 	if err != nil {
 		return result, err
 	}
 
-	cartsLastsMonth, err := a.cart.GetList(entity.CartParam{
-		Status:    entity.StatusDone,
-		UmkmID:    param.UmkmID,
-		CreatedAt: lastMonth,
+	for _, s := range sales {
+		day, err := time.ParseInLocation("2006-01-02", s.Period, a.location)
+		if err != nil {
+			return result, err
+		}
+
+		if todayRange.Contains(day) {
+			result.TotalTodayTransaction += s.TotalTransaction
+			result.TotalTodayRevenue += s.TotalRevenue
+		}
+		if yesterdayRange.Contains(day) {
+			result.TotalYesterdayTransaction += s.TotalTransaction
+			result.TotalYesterdayRevenue += s.TotalRevenue
+		}
+		if monthRange.Contains(day) {
+			result.TotalMonthTransaction += s.TotalTransaction
+			result.TotalMonthRevenue += s.TotalRevenue
+		}
+		if lastMonthRange.Contains(day) {
+			result.TotalLastMonthTransaction += s.TotalTransaction
+			result.TotalLastMonthRevenue += s.TotalRevenue
+		}
+	}
+
+	return result, nil
+}
+
+func (a *analytic) GetSalesAnalytic(ctx context.Context, param entity.SalesAnalyticParam) (entity.SalesAnalyticResult, error) {
+	result := entity.SalesAnalyticResult{}
+
+	if param.Granularity == "" {
+		param.Granularity = entity.GranularityDay
+	}
+
+	current, previous, err := a.parseRange(param.From, param.To)
+	if err != nil {
+		return result, err
+	}
+
+	periods, err := a.getPeriods(current, param.Granularity)
+	if err != nil {
+		return result, err
+	}
+
+	currentSales, err := a.cart.GetSalesAggregate(ctx, entity.SalesAggregateParam{
+		UmkmID:      param.UmkmID,
+		Status:      entity.StatusDone,
+		Granularity: param.Granularity,
+		Range:       current,
+		Location:    a.location,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	previousSales, err := a.cart.GetSalesAggregate(ctx, entity.SalesAggregateParam{
+		UmkmID:      param.UmkmID,
+		Status:      entity.StatusDone,
+		Granularity: param.Granularity,
+		Range:       previous,
+		Location:    a.location,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	seriesMap := make(map[string]entity.SalesSummary)
+	for _, s := range currentSales {
+		summary := seriesMap[s.Period]
+		summary.Add(s.TotalTransaction, s.TotalRevenue)
+		seriesMap[s.Period] = summary
+		result.Current.Add(s.TotalTransaction, s.TotalRevenue)
+	}
+
+	for _, s := range previousSales {
+		result.Previous.Add(s.TotalTransaction, s.TotalRevenue)
+	}
+
+	result.Series = []entity.SalesSeriesPoint{}
+	for _, p := range periods {
+		result.Series = append(result.Series, entity.SalesSeriesPoint{
+			Period:       p,
+			SalesSummary: seriesMap[p],
+		})
+	}
+
+	result.UmkmID = param.UmkmID
+	result.Granularity = param.Granularity
+	result.From = param.From
+	result.To = param.To
+	result.Growth = entity.SalesGrowth{
+		TotalTransaction: growth(result.Current.TotalTransaction, result.Previous.TotalTransaction),
+		GrossAmount:      growth(result.Current.GrossAmount, result.Previous.GrossAmount),
+		CommissionAmount: growth(result.Current.CommissionAmount, result.Previous.CommissionAmount),
+		NetAmount:        growth(result.Current.NetAmount, result.Previous.NetAmount),
+	}
+
+	return result, nil
+}
+
+func (a *analytic) GetMenuPerformance(ctx context.Context, param entity.MenuPerformanceParam) (entity.MenuPerformanceResult, error) {
+	result := entity.MenuPerformanceResult{}
+
+	if param.Limit <= 0 {
+		param.Limit = defaultTopMenuLimit
+	}
+
+	current, previous, err := a.parseRange(param.From, param.To)
+	if err != nil {
+		return result, err
+	}
+
+	sales, err := a.cart.GetSalesAggregate(ctx, entity.SalesAggregateParam{
+		UmkmID:      param.UmkmID,
+		Status:      entity.StatusDone,
+		Granularity: entity.GranularityMonth,
+		Range:       current,
+		Location:    a.location,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	currentMenus, err := a.cart.GetMenuAggregate(ctx, entity.MenuAggregateParam{
+		UmkmID: param.UmkmID,
+		Range:  current,
 	})
 	if err != nil {
 		return result, err
 	}
 
-	mergedCart := append(cartsMonth, cartsLastsMonth...)
+	previousMenus, err := a.cart.GetMenuAggregate(ctx, entity.MenuAggregateParam{
+		UmkmID: param.UmkmID,
+		Range:  previous,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	totalTransaction := 0
+	for _, s := range sales {
+		totalTransaction += s.TotalTransaction
+	}
+
+	previousMenusMap := make(map[uint]entity.MenuAggregate)
+	for _, m := range previousMenus {
+		previousMenusMap[m.MenuID] = m
+	}
+
+	menuIDs := []int64{}
+	for _, m := range currentMenus {
+		menuIDs = append(menuIDs, int64(m.MenuID))
+	}
 
-	transactionMonth := make(map[uint]bool)
-	revenueMonth := 0
-	transactionLastMonth := make(map[uint]bool)
-	revenueLastMonth := 0
-	transactionToday := make(map[uint]bool)
-	revenueToday := 0
-	transactionYesterday := make(map[uint]bool)
-	revenueYesterday := 0
-	for _, c := range mergedCart {
-		if strings.Contains(c.CreatedAt.String(), month) {
-			transactionMonth[c.TransactionID] = true
-			revenueMonth += c.TotalPrice
+	menusMap := make(map[uint]entity.Menu)
+	if len(menuIDs) > 0 {
+		menus, err := a.menu.GetListInByID(ctx, menuIDs)
+		if err != nil {
+			return result, err
+		}
+		for _, m := range menus {
+			menusMap[m.ID] = m
 		}
-		if strings.Contains(c.CreatedAt.String(), today) {
-			transactionToday[c.TransactionID] = true
-			revenueToday += c.TotalPrice
+	}
+
+	performances := []entity.MenuPerformance{}
+	for _, m := range currentMenus {
+		performance := entity.MenuPerformance{
+			MenuID:           m.MenuID,
+			MenuName:         menusMap[m.MenuID].Name,
+			UmkmID:           m.UmkmID,
+			TotalQuantity:    m.TotalQuantity,
+			TotalRevenue:     m.TotalRevenue,
+			TotalTransaction: m.TotalTransaction,
+			QuantityGrowth:   growth(m.TotalQuantity, previousMenusMap[m.MenuID].TotalQuantity),
+			RevenueGrowth:    growth(m.TotalRevenue, previousMenusMap[m.MenuID].TotalRevenue),
 		}
-		if strings.Contains(c.CreatedAt.String(), lastMonth) {
-			transactionLastMonth[c.TransactionID] = true
-			revenueLastMonth += c.TotalPrice
+		if totalTransaction > 0 {
+			performance.AttachRate = float64(m.TotalTransaction) / float64(totalTransaction)
 		}
-		if strings.Contains(c.CreatedAt.String(), yesterday) {
-			transactionYesterday[c.TransactionID] = true
-			revenueYesterday += c.TotalPrice
+		if totalItem := m.TotalDoneItem + m.TotalCancelItem; totalItem > 0 {
+			performance.CancellationRate = float64(m.TotalCancelItem) / float64(totalItem)
 		}
+		performances = append(performances, performance)
 	}
 
-	result.TotalMonthTransaction = len(transactionMonth)
-	result.TotalMonthRevenue = revenueMonth
-	result.TotalLastMonthTransaction = len(transactionLastMonth)
-	result.TotalLastMonthRevenue = revenueLastMonth
-	result.TotalTodayTransaction = len(transactionToday)
-	result.TotalTodayRevenue = revenueToday
-	result.TotalYesterdayTransaction = len(transactionYesterday)
-	result.TotalYesterdayRevenue = revenueYesterday
+	result.UmkmID = param.UmkmID
+	result.From = param.From
+	result.To = param.To
+	result.TotalTransaction = totalTransaction
+	result.TopByQuantity = topMenus(performances, param.Limit, func(p entity.MenuPerformance) int { return p.TotalQuantity })
+	result.TopByRevenue = topMenus(performances, param.Limit, func(p entity.MenuPerformance) int { return p.TotalRevenue })
 
 	return result, nil
 }
 
-func (a *analytic) GetAllDashboardWidget(ctx context.Context) (entity.WidgetDashboardResult, error) {
-	result := entity.WidgetDashboardResult{}
+func (a *analytic) GetHeatmap(ctx context.Context, param entity.HeatmapParam) (entity.HeatmapResult, error) {
+	result := entity.HeatmapResult{}
 
-	now := time.Now()
+	current, _, err := a.parseRange(param.From, param.To)
+	if err != nil {
+		return result, err
+	}
 
-	month := now.Format("2006-01")
-	calcLastMonth := now.AddDate(0, -1, 0)
-	lastMonth := calcLastMonth.Format("2006-01")
-	today := now.Format("2006-01-02")
-	calcYesterday := now.AddDate(0, 0, -1)
-	yesterday := calcYesterday.Format("2006-01-02")
+	heatmap, err := a.cart.GetHeatmapAggregate(ctx, entity.HeatmapAggregateParam{
+		UmkmID:   param.UmkmID,
+		Range:    current,
+		Location: a.location,
+	})
+	if err != nil {
+		return result, err
+	}
 
-	cartsMonth, err := a.cart.GetList(entity.CartParam{
-		Status:    entity.StatusDone,
-		CreatedAt: month,
+	heatmapMap := make(map[[2]int]entity.HeatmapAggregate)
+	for _, h := range heatmap {
+		heatmapMap[[2]int{h.DayOfWeek, h.Hour}] = h
+	}
+
+	result.Cells = []entity.HeatmapCell{}
+	for day := 0; day < 7; day++ {
+		for hour := 0; hour < 24; hour++ {
+			h := heatmapMap[[2]int{day, hour}]
+			result.Cells = append(result.Cells, entity.HeatmapCell{
+				DayOfWeek:          day,
+				Hour:               hour,
+				TotalTransaction:   h.TotalTransaction,
+				TotalRevenue:       h.TotalRevenue,
+				AvgPrepTimeSeconds: h.AvgPrepTime,
+			})
+		}
+	}
+
+	result.UmkmID = param.UmkmID
+	result.From = param.From
+	result.To = param.To
+
+	return result, nil
+}
+
+func (a *analytic) GetBreakdown(ctx context.Context, param entity.BreakdownParam) (entity.BreakdownResult, error) {
+	result := entity.BreakdownResult{}
+
+	current, _, err := a.parseRange(param.From, param.To)
+	if err != nil {
+		return result, err
+	}
+
+	breakdown, err := a.getBreakdown(ctx, current)
+	if err != nil {
+		return result, err
+	}
+
+	result = breakdown
+	result.From = param.From
+	result.To = param.To
+
+	return result, nil
+}
+
+func (a *analytic) getBreakdown(ctx context.Context, tr entity.TimeRange) (entity.BreakdownResult, error) {
+	result := entity.BreakdownResult{}
+
+	payments, err := a.midtransTransaction.GetPaymentAggregate(ctx, entity.PaymentAggregateParam{
+		Range: tr,
 	})
 	if err != nil {
 		return result, err
 	}
 
-	cartsLastsMonth, err := a.cart.GetList(entity.CartParam{
-		Status:    entity.StatusDone,
-		CreatedAt: lastMonth,
+	cancellations, err := a.cart.GetCancellationAggregate(ctx, entity.CancellationAggregateParam{
+		Range: tr,
 	})
 	if err != nil {
 		return result, err
 	}
 
-	mergedCart := append(cartsMonth, cartsLastsMonth...)
+	umkmNames := make(map[uint]string)
+	if len(cancellations) > 0 {
+		umkmIDs := []uint{}
+		for _, c := range cancellations {
+			umkmIDs = append(umkmIDs, c.UmkmID)
+		}
 
-	transactionMonth := make(map[uint]bool)
-	revenueMonth := 0
-	transactionLastMonth := make(map[uint]bool)
-	revenueLastMonth := 0
-	transactionToday := make(map[uint]bool)
-	revenueToday := 0
-	transactionYesterday := make(map[uint]bool)
-	revenueYesterday := 0
-	for _, c := range mergedCart {
-		if strings.Contains(c.CreatedAt.String(), month) {
-			transactionMonth[c.TransactionID] = true
-			revenueMonth += c.TotalPrice
+		umkms, err := a.umkm.GetListInByID(ctx, umkmIDs)
+		if err != nil {
+			return result, err
 		}
-		if strings.Contains(c.CreatedAt.String(), today) {
-			transactionToday[c.TransactionID] = true
-			revenueToday += c.TotalPrice
+
+		for _, u := range umkms {
+			umkmNames[u.ID] = u.Name
 		}
-		if strings.Contains(c.CreatedAt.String(), lastMonth) {
-			transactionLastMonth[c.TransactionID] = true
-			revenueLastMonth += c.TotalPrice
+	}
+
+	payment := entity.PaymentStatusBreakdown{}
+	paymentTypes := []int{}
+	paymentMethodMap := make(map[int]entity.PaymentMethodBreakdown)
+	for _, p := range payments {
+		payment.TotalTransaction += p.TotalTransaction
+
+		switch p.Status {
+		case entity.StatusSuccess:
+			payment.TotalSuccess += p.TotalTransaction
+
+			method, ok := paymentMethodMap[p.PaymentType]
+			if !ok {
+				mt := entity.MidtransTransaction{PaymentType: p.PaymentType}
+				method.PaymentType = mt.GetPaymentType()
+				paymentTypes = append(paymentTypes, p.PaymentType)
+			}
+			method.TotalTransaction += p.TotalTransaction
+			method.GrossAmount += p.TotalAmount
+			paymentMethodMap[p.PaymentType] = method
+		case entity.StatusPending:
+			payment.TotalPending += p.TotalTransaction
+		case entity.StatusFailure, entity.StatusDeny:
+			// denied, cancelled and expired payments never complete, they are abandoned
+			payment.TotalFailure += p.TotalTransaction
 		}
-		if strings.Contains(c.CreatedAt.String(), yesterday) {
-			transactionYesterday[c.TransactionID] = true
-			revenueYesterday += c.TotalPrice
+	}
+
+	if payment.TotalTransaction > 0 {
+		payment.AbandonedRate = float64(payment.TotalFailure) / float64(payment.TotalTransaction)
+	}
+
+	sort.Ints(paymentTypes)
+	paymentMethods := []entity.PaymentMethodBreakdown{}
+	for _, pt := range paymentTypes {
+		method := paymentMethodMap[pt]
+		method.Share = float64(method.TotalTransaction) / float64(payment.TotalSuccess)
+		paymentMethods = append(paymentMethods, method)
+	}
+
+	totalItem, totalCancelItem := 0, 0
+	refund := entity.RefundSummary{}
+	tenants := []entity.TenantCancellation{}
+	for _, c := range cancellations {
+		tenant := entity.TenantCancellation{
+			UmkmID:          c.UmkmID,
+			UmkmName:        umkmNames[c.UmkmID],
+			TotalItem:       c.TotalItem,
+			TotalCancelItem: c.TotalCancelItem,
+			RefundAmount:    c.TotalRefundAmount,
+		}
+		if c.TotalItem > 0 {
+			tenant.CancellationRate = float64(c.TotalCancelItem) / float64(c.TotalItem)
 		}
+		tenants = append(tenants, tenant)
+
+		totalItem += c.TotalItem
+		totalCancelItem += c.TotalCancelItem
+		refund.TotalItem += c.TotalRefundItem
+		refund.TotalAmount += c.TotalRefundAmount
 	}
 
-	result.TotalMonthTransaction = len(transactionMonth)
-	result.TotalMonthRevenue = revenueMonth
-	result.TotalLastMonthTransaction = len(transactionLastMonth)
-	result.TotalLastMonthRevenue = revenueLastMonth
-	result.TotalTodayTransaction = len(transactionToday)
-	result.TotalTodayRevenue = revenueToday
-	result.TotalYesterdayTransaction = len(transactionYesterday)
-	result.TotalYesterdayRevenue = revenueYesterday
+	sort.SliceStable(tenants, func(i, j int) bool {
+		if tenants[i].CancellationRate != tenants[j].CancellationRate {
+			return tenants[i].CancellationRate > tenants[j].CancellationRate
+		}
+		return tenants[i].UmkmID < tenants[j].UmkmID
+	})
+
+	if totalItem > 0 {
+		result.CancellationRate = float64(totalCancelItem) / float64(totalItem)
+	}
+
+	result.PaymentMethods = paymentMethods
+	result.Payment = payment
+	result.Tenants = tenants
+	result.Refund = refund
 
 	return result, nil
 }
+
+// parseRange turns inclusive from/to dates into the half-open current range and the
+// range of equal length right before it.
+func (a *analytic) parseRange(from string, to string) (entity.TimeRange, entity.TimeRange, error) {
+	fromDate, err := time.ParseInLocation("2006-01-02", from, a.location)
+	if err != nil {
+		return entity.TimeRange{}, entity.TimeRange{}, ErrInvalidDate.Wrap(err)
+	}
+
+	toDate, err := time.ParseInLocation("2006-01-02", to, a.location)
+	if err != nil {
+		return entity.TimeRange{}, entity.TimeRange{}, ErrInvalidDate.Wrap(err)
+	}
+
+	if toDate.Before(fromDate) {
+		return entity.TimeRange{}, entity.TimeRange{}, ErrInvalidRange
+	}
+
+	current := entity.TimeRange{Start: fromDate, End: toDate.AddDate(0, 0, 1)}
+	days := int(current.End.Sub(current.Start).Hours()/24 + 0.5)
+	previous := entity.TimeRange{Start: current.Start.AddDate(0, 0, -days), End: current.Start}
+
+	return current, previous, nil
+}
+
+// getPeriods lists every period key in the range, formatted the same way the cart domain buckets them.
+func (a *analytic) getPeriods(tr entity.TimeRange, granularity string) ([]string, error) {
+	periods := []string{}
+
+	var start time.Time
+	var next func(time.Time) time.Time
+	var format func(time.Time) string
+
+	switch granularity {
+	case entity.GranularityHour:
+		start = tr.Start
+		next = func(t time.Time) time.Time { return t.Add(time.Hour) }
+		format = func(t time.Time) string { return t.Format("2006-01-02 15:00") }
+	case entity.GranularityDay:
+		start = time.Date(tr.Start.Year(), tr.Start.Month(), tr.Start.Day(), 0, 0, 0, 0, a.location)
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+		format = func(t time.Time) string { return t.Format("2006-01-02") }
+	case entity.GranularityWeek:
+		start = time.Date(tr.Start.Year(), tr.Start.Month(), tr.Start.Day()-(int(tr.Start.Weekday())+6)%7, 0, 0, 0, 0, a.location)
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
+		format = func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}
+	case entity.GranularityMonth:
+		start = time.Date(tr.Start.Year(), tr.Start.Month(), 1, 0, 0, 0, 0, a.location)
+		next = func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }
+		format = func(t time.Time) string { return t.Format("2006-01") }
+	default:
+		return periods, ErrInvalidGranularity
+	}
+
+	for t := start; t.Before(tr.End); t = next(t) {
+		if len(periods) == maxSeriesLength {
+			return periods, ErrRangeTooLong
+		}
+		periods = append(periods, format(t))
+	}
+
+	return periods, nil
+}
+
+func growth(current int, previous int) *float64 {
+	if previous == 0 {
+		return nil
+	}
+
+	g := float64(current-previous) * 100 / float64(previous)
+	return &g
+}
+
+func topMenus(performances []entity.MenuPerformance, limit int, value func(entity.MenuPerformance) int) []entity.MenuPerformance {
+	result := []entity.MenuPerformance{}
+	for _, p := range performances {
+		if value(p) > 0 {
+			result = append(result, p)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if value(result[i]) == value(result[j]) {
+			return result[i].MenuID < result[j].MenuID
+		}
+		return value(result[i]) > value(result[j])
+	})
+
+	if len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result
+}
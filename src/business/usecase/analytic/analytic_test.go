@@ -3,6 +3,9 @@ package analytic_test
 import (
 	"context"
 	mock_cart "go-clean/src/business/domain/mock/cart"
+	mock_menu "go-clean/src/business/domain/mock/menu"
+	mock_midtrans_transaction "go-clean/src/business/domain/mock/midtrans_transaction"
+	mock_umkm "go-clean/src/business/domain/mock/umkm"
 	"go-clean/src/business/entity"
 	"go-clean/src/business/usecase/analytic"
 	"testing"
@@ -16,60 +19,53 @@ import (
 func Test_analytic_GetDashboardWidget(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
-	now := time.Now()
-	yesterday := now.AddDate(0, 0, -1)
-	lastMonth := now.AddDate(0, -1, 0)
-	thisMonthFormatted := now.Format("2006-01")
-	lastMonthFormatted := lastMonth.Format("2006-01")
+	location, _ := time.LoadLocation("Asia/Jakarta")
+	now := time.Now().In(location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, location)
+	lastMonth := thisMonth.AddDate(0, -1, 0)
 
 	cartMock := mock_cart.NewMockInterface(ctrl)
+	menuMock := mock_menu.NewMockInterface(ctrl)
+	umkmMock := mock_umkm.NewMockInterface(ctrl)
+	midtransTransactionMock := mock_midtrans_transaction.NewMockInterface(ctrl)
 
 	analyticParamMock := entity.AnalyticParam{
 		UmkmID: 1,
 	}
 
-	cartThisMonthParamMock := entity.CartParam{
-		Status:    entity.StatusDone,
-		UmkmID:    1,
-		CreatedAt: thisMonthFormatted,
-	}
-
-	cartLastMonthParamMock := entity.CartParam{
-		Status:    entity.StatusDone,
-		UmkmID:    1,
-		CreatedAt: lastMonthFormatted,
+	salesParamMock := entity.SalesAggregateParam{
+		UmkmID:      1,
+		Status:      entity.StatusDone,
+		Granularity: entity.GranularityDay,
+		Range: entity.TimeRange{
+			Start: lastMonth,
+			End:   today.AddDate(0, 0, 1),
+		},
+		Location: location,
 	}
 
-	cartsThisMonthResultMock := []entity.Cart{
+	salesResultMock := []entity.SalesAggregate{
 		{
-			TransactionID: 1,
-			TotalPrice:    10000,
-			Model: gorm.Model{
-				CreatedAt: now,
-			},
+			Period:           lastMonth.Format("2006-01-02"),
+			TotalTransaction: 1,
+			TotalRevenue:     30000,
 		},
 		{
-			TransactionID: 2,
-			TotalPrice:    20000,
-			Model: gorm.Model{
-				CreatedAt: yesterday,
-			},
+			Period:           today.AddDate(0, 0, -1).Format("2006-01-02"),
+			TotalTransaction: 1,
+			TotalRevenue:     20000,
 		},
-	}
-
-	cartsLastMonthResultMock := []entity.Cart{
 		{
-			TransactionID: 3,
-			TotalPrice:    30000,
-			Model: gorm.Model{
-				CreatedAt: lastMonth,
-			},
+			Period:           today.Format("2006-01-02"),
+			TotalTransaction: 1,
+			TotalRevenue:     10000,
 		},
 	}
 
 	resultMock := entity.WidgetDashboardResult{
-		TotalMonthTransaction:     2,
-		TotalMonthRevenue:         30000,
+		TotalMonthTransaction:     1,
+		TotalMonthRevenue:         10000,
 		TotalLastMonthTransaction: 1,
 		TotalLastMonthRevenue:     30000,
 		TotalTodayTransaction:     1,
@@ -77,8 +73,15 @@ func Test_analytic_GetDashboardWidget(t *testing.T) {
 		TotalYesterdayTransaction: 1,
 		TotalYesterdayRevenue:     20000,
 	}
+	if today.AddDate(0, 0, -1).Before(thisMonth) {
+		resultMock.TotalLastMonthTransaction++
+		resultMock.TotalLastMonthRevenue += 20000
+	} else {
+		resultMock.TotalMonthTransaction++
+		resultMock.TotalMonthRevenue += 20000
+	}
 
-	a := analytic.Init(cartMock)
+	a := analytic.Init(cartMock, menuMock, umkmMock, midtransTransactionMock, analytic.Config{Timezone: "Asia/Jakarta"})
 
 	type mockFields struct {
 		cart *mock_cart.MockInterface
@@ -101,26 +104,25 @@ func Test_analytic_GetDashboardWidget(t *testing.T) {
 		wantErr  bool
 	}{
 		{
-			name: "failed to get cart list",
+			name: "failed to get sales aggregate",
 			args: args{
 				ctx:   context.Background(),
 				param: analyticParamMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.cart.EXPECT().GetList(cartThisMonthParamMock).Return([]entity.Cart{}, assert.AnError)
+				mock.cart.EXPECT().GetSalesAggregate(gomock.Any(), salesParamMock).Return([]entity.SalesAggregate{}, assert.AnError)
 			},
 			want:    entity.WidgetDashboardResult{},
 			wantErr: true,
 		},
 		{
-			name: "failed to get last month cart list",
+			name: "failed to parse period",
 			args: args{
 				ctx:   context.Background(),
 				param: analyticParamMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.cart.EXPECT().GetList(cartThisMonthParamMock).Return(cartsThisMonthResultMock, nil)
-				mock.cart.EXPECT().GetList(cartLastMonthParamMock).Return([]entity.Cart{}, assert.AnError)
+				mock.cart.EXPECT().GetSalesAggregate(gomock.Any(), salesParamMock).Return([]entity.SalesAggregate{{Period: "invalid"}}, nil)
 			},
 			want:    entity.WidgetDashboardResult{},
 			wantErr: true,
@@ -132,8 +134,7 @@ func Test_analytic_GetDashboardWidget(t *testing.T) {
 				param: analyticParamMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.cart.EXPECT().GetList(cartThisMonthParamMock).Return(cartsThisMonthResultMock, nil)
-				mock.cart.EXPECT().GetList(cartLastMonthParamMock).Return(cartsLastMonthResultMock, nil)
+				mock.cart.EXPECT().GetSalesAggregate(gomock.Any(), salesParamMock).Return(salesResultMock, nil)
 			},
 			want:    resultMock,
 			wantErr: false,
@@ -155,114 +156,995 @@ func Test_analytic_GetDashboardWidget(t *testing.T) {
 func Test_analytic_GetAllDashboardWidget(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
-	now := time.Now()
-	yesterday := now.AddDate(0, 0, -1)
-	lastMonth := now.AddDate(0, -1, 0)
-	thisMonthFormatted := now.Format("2006-01")
-	lastMonthFormatted := lastMonth.Format("2006-01")
+	location, _ := time.LoadLocation("Asia/Jakarta")
+	now := time.Now().In(location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, location)
+	lastMonth := thisMonth.AddDate(0, -1, 0)
 
 	cartMock := mock_cart.NewMockInterface(ctrl)
+	menuMock := mock_menu.NewMockInterface(ctrl)
+	umkmMock := mock_umkm.NewMockInterface(ctrl)
+	midtransTransactionMock := mock_midtrans_transaction.NewMockInterface(ctrl)
 
-	cartThisMonthParamMock := entity.CartParam{
-		Status:    entity.StatusDone,
-		CreatedAt: thisMonthFormatted,
+	salesParamMock := entity.SalesAggregateParam{
+		Status:      entity.StatusDone,
+		Granularity: entity.GranularityDay,
+		Range: entity.TimeRange{
+			Start: lastMonth,
+			End:   today.AddDate(0, 0, 1),
+		},
+		Location: location,
 	}
 
-	cartLastMonthParamMock := entity.CartParam{
-		Status:    entity.StatusDone,
-		CreatedAt: lastMonthFormatted,
+	salesResultMock := []entity.SalesAggregate{
+		{
+			Period:           lastMonth.Format("2006-01-02"),
+			TotalTransaction: 2,
+			TotalRevenue:     30000,
+		},
+		{
+			Period:           today.Format("2006-01-02"),
+			TotalTransaction: 1,
+			TotalRevenue:     10000,
+		},
+	}
+
+	monthRangeMock := entity.TimeRange{
+		Start: thisMonth,
+		End:   thisMonth.AddDate(0, 1, 0),
 	}
 
-	cartsThisMonthResultMock := []entity.Cart{
+	paymentResultMock := []entity.PaymentAggregate{
 		{
-			TransactionID: 1,
-			TotalPrice:    10000,
-			Model: gorm.Model{
-				CreatedAt: now,
+			PaymentType:      1,
+			Status:           entity.StatusSuccess,
+			TotalTransaction: 1,
+			TotalAmount:      10000,
+		},
+		{
+			PaymentType:      2,
+			Status:           entity.StatusFailure,
+			TotalTransaction: 1,
+			TotalAmount:      5000,
+		},
+	}
+
+	cancellationResultMock := []entity.CancellationAggregate{
+		{
+			UmkmID:            1,
+			TotalItem:         4,
+			TotalCancelItem:   1,
+			TotalRefundItem:   1,
+			TotalRefundAmount: 5000,
+		},
+	}
+
+	resultMock := entity.AdminWidgetDashboardResult{
+		WidgetDashboardResult: entity.WidgetDashboardResult{
+			TotalMonthTransaction:     1,
+			TotalMonthRevenue:         10000,
+			TotalLastMonthTransaction: 2,
+			TotalLastMonthRevenue:     30000,
+			TotalTodayTransaction:     1,
+			TotalTodayRevenue:         10000,
+		},
+		PaymentMethods: []entity.PaymentMethodBreakdown{
+			{
+				PaymentType:      "Cash",
+				TotalTransaction: 1,
+				GrossAmount:      10000,
+				Share:            1,
 			},
 		},
+		AbandonedRate:    0.5,
+		CancellationRate: 0.25,
+		RefundAmount:     5000,
+	}
+
+	a := analytic.Init(cartMock, menuMock, umkmMock, midtransTransactionMock, analytic.Config{Timezone: "Asia/Jakarta"})
+
+	type mockFields struct {
+		cart                *mock_cart.MockInterface
+		umkm                *mock_umkm.MockInterface
+		midtransTransaction *mock_midtrans_transaction.MockInterface
+	}
+
+	mocks := mockFields{
+		cart:                cartMock,
+		umkm:                umkmMock,
+		midtransTransaction: midtransTransactionMock,
+	}
+
+	type args struct {
+		ctx context.Context
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		mockFunc func(mock mockFields, arg args)
+		want     entity.AdminWidgetDashboardResult
+		wantErr  bool
+	}{
 		{
-			TransactionID: 2,
-			TotalPrice:    20000,
-			Model: gorm.Model{
-				CreatedAt: yesterday,
+			name: "failed to get sales aggregate",
+			args: args{
+				ctx: context.Background(),
+			},
+			mockFunc: func(mock mockFields, arg args) {
+				mock.cart.EXPECT().GetSalesAggregate(gomock.Any(), salesParamMock).Return([]entity.SalesAggregate{}, assert.AnError)
+			},
+			want:    entity.AdminWidgetDashboardResult{},
+			wantErr: true,
+		},
+		{
+			name: "failed to get payment aggregate",
+			args: args{
+				ctx: context.Background(),
+			},
+			mockFunc: func(mock mockFields, arg args) {
+				mock.cart.EXPECT().GetSalesAggregate(gomock.Any(), salesParamMock).Return(salesResultMock, nil)
+				mock.midtransTransaction.EXPECT().GetPaymentAggregate(gomock.Any(), entity.PaymentAggregateParam{Range: monthRangeMock}).Return([]entity.PaymentAggregate{}, assert.AnError)
+			},
+			want:    entity.AdminWidgetDashboardResult{},
+			wantErr: true,
+		},
+		{
+			name: "all success",
+			args: args{
+				ctx: context.Background(),
+			},
+			mockFunc: func(mock mockFields, arg args) {
+				mock.cart.EXPECT().GetSalesAggregate(gomock.Any(), salesParamMock).Return(salesResultMock, nil)
+				mock.midtransTransaction.EXPECT().GetPaymentAggregate(gomock.Any(), entity.PaymentAggregateParam{Range: monthRangeMock}).Return(paymentResultMock, nil)
+				mock.cart.EXPECT().GetCancellationAggregate(gomock.Any(), entity.CancellationAggregateParam{Range: monthRangeMock}).Return(cancellationResultMock, nil)
+				mock.umkm.EXPECT().GetListInByID(gomock.Any(), []uint{1}).Return([]entity.Umkm{}, nil)
+			},
+			want:    resultMock,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mocks, tt.args)
+			got, err := a.GetAllDashboardWidget(tt.args.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("analytic.GetAllDashboardWidget() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_analytic_GetSalesAnalytic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	location, _ := time.LoadLocation("Asia/Jakarta")
+	from := time.Date(2023, 1, 2, 0, 0, 0, 0, location)
+
+	cartMock := mock_cart.NewMockInterface(ctrl)
+	menuMock := mock_menu.NewMockInterface(ctrl)
+	umkmMock := mock_umkm.NewMockInterface(ctrl)
+	midtransTransactionMock := mock_midtrans_transaction.NewMockInterface(ctrl)
+
+	salesAnalyticParamMock := entity.SalesAnalyticParam{
+		UmkmID: 1,
+		From:   "2023-01-02",
+		To:     "2023-01-04",
+	}
+
+	currentParamMock := entity.SalesAggregateParam{
+		UmkmID:      1,
+		Status:      entity.StatusDone,
+		Granularity: entity.GranularityDay,
+		Range: entity.TimeRange{
+			Start: from,
+			End:   from.AddDate(0, 0, 3),
+		},
+		Location: location,
+	}
+
+	previousParamMock := currentParamMock
+	previousParamMock.Range = entity.TimeRange{
+		Start: from.AddDate(0, 0, -3),
+		End:   from,
+	}
+
+	currentResultMock := []entity.SalesAggregate{
+		{
+			Period:           "2023-01-03",
+			TotalTransaction: 2,
+			TotalRevenue:     20000,
+		},
+	}
+
+	previousResultMock := []entity.SalesAggregate{
+		{
+			Period:           "2022-12-31",
+			TotalTransaction: 1,
+			TotalRevenue:     10000,
+		},
+	}
+
+	transactionGrowth := float64(100)
+	amountGrowth := float64(100)
+
+	resultMock := entity.SalesAnalyticResult{
+		UmkmID:      1,
+		Granularity: entity.GranularityDay,
+		From:        "2023-01-02",
+		To:          "2023-01-04",
+		Current: entity.SalesSummary{
+			TotalTransaction: 2,
+			GrossAmount:      20000,
+			CommissionAmount: 3400,
+			NetAmount:        16600,
+		},
+		Previous: entity.SalesSummary{
+			TotalTransaction: 1,
+			GrossAmount:      10000,
+			CommissionAmount: 1700,
+			NetAmount:        8300,
+		},
+		Growth: entity.SalesGrowth{
+			TotalTransaction: &transactionGrowth,
+			GrossAmount:      &amountGrowth,
+			CommissionAmount: &amountGrowth,
+			NetAmount:        &amountGrowth,
+		},
+		Series: []entity.SalesSeriesPoint{
+			{
+				Period: "2023-01-02",
+			},
+			{
+				Period: "2023-01-03",
+				SalesSummary: entity.SalesSummary{
+					TotalTransaction: 2,
+					GrossAmount:      20000,
+					CommissionAmount: 3400,
+					NetAmount:        16600,
+				},
+			},
+			{
+				Period: "2023-01-04",
 			},
 		},
 	}
 
-	cartsLastMonthResultMock := []entity.Cart{
+	a := analytic.Init(cartMock, menuMock, umkmMock, midtransTransactionMock, analytic.Config{Timezone: "Asia/Jakarta"})
+
+	type mockFields struct {
+		cart *mock_cart.MockInterface
+	}
+
+	mocks := mockFields{
+		cart: cartMock,
+	}
+
+	type args struct {
+		ctx   context.Context
+		param entity.SalesAnalyticParam
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		mockFunc func(mock mockFields, arg args)
+		want     entity.SalesAnalyticResult
+		wantErr  bool
+	}{
+		{
+			name: "invalid date",
+			args: args{
+				ctx: context.Background(),
+				param: entity.SalesAnalyticParam{
+					From: "02-01-2023",
+					To:   "2023-01-04",
+				},
+			},
+			mockFunc: func(mock mockFields, arg args) {},
+			want:     entity.SalesAnalyticResult{},
+			wantErr:  true,
+		},
+		{
+			name: "to before from",
+			args: args{
+				ctx: context.Background(),
+				param: entity.SalesAnalyticParam{
+					From: "2023-01-04",
+					To:   "2023-01-02",
+				},
+			},
+			mockFunc: func(mock mockFields, arg args) {},
+			want:     entity.SalesAnalyticResult{},
+			wantErr:  true,
+		},
+		{
+			name: "invalid granularity",
+			args: args{
+				ctx: context.Background(),
+				param: entity.SalesAnalyticParam{
+					From:        "2023-01-02",
+					To:          "2023-01-04",
+					Granularity: "year",
+				},
+			},
+			mockFunc: func(mock mockFields, arg args) {},
+			want:     entity.SalesAnalyticResult{},
+			wantErr:  true,
+		},
+		{
+			name: "range too long",
+			args: args{
+				ctx: context.Background(),
+				param: entity.SalesAnalyticParam{
+					From:        "2020-01-01",
+					To:          "2023-01-01",
+					Granularity: entity.GranularityHour,
+				},
+			},
+			mockFunc: func(mock mockFields, arg args) {},
+			want:     entity.SalesAnalyticResult{},
+			wantErr:  true,
+		},
+		{
+			name: "failed to get current sales",
+			args: args{
+				ctx:   context.Background(),
+				param: salesAnalyticParamMock,
+			},
+			mockFunc: func(mock mockFields, arg args) {
+				mock.cart.EXPECT().GetSalesAggregate(gomock.Any(), currentParamMock).Return([]entity.SalesAggregate{}, assert.AnError)
+			},
+			want:    entity.SalesAnalyticResult{},
+			wantErr: true,
+		},
+		{
+			name: "failed to get previous sales",
+			args: args{
+				ctx:   context.Background(),
+				param: salesAnalyticParamMock,
+			},
+			mockFunc: func(mock mockFields, arg args) {
+				mock.cart.EXPECT().GetSalesAggregate(gomock.Any(), currentParamMock).Return(currentResultMock, nil)
+				mock.cart.EXPECT().GetSalesAggregate(gomock.Any(), previousParamMock).Return([]entity.SalesAggregate{}, assert.AnError)
+			},
+			want:    entity.SalesAnalyticResult{},
+			wantErr: true,
+		},
+		{
+			name: "all success",
+			args: args{
+				ctx:   context.Background(),
+				param: salesAnalyticParamMock,
+			},
+			mockFunc: func(mock mockFields, arg args) {
+				mock.cart.EXPECT().GetSalesAggregate(gomock.Any(), currentParamMock).Return(currentResultMock, nil)
+				mock.cart.EXPECT().GetSalesAggregate(gomock.Any(), previousParamMock).Return(previousResultMock, nil)
+			},
+			want:    resultMock,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mocks, tt.args)
+			got, err := a.GetSalesAnalytic(tt.args.ctx, tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("analytic.GetSalesAnalytic() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_analytic_GetMenuPerformance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	location, _ := time.LoadLocation("Asia/Jakarta")
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, location)
+
+	cartMock := mock_cart.NewMockInterface(ctrl)
+	menuMock := mock_menu.NewMockInterface(ctrl)
+	umkmMock := mock_umkm.NewMockInterface(ctrl)
+	midtransTransactionMock := mock_midtrans_transaction.NewMockInterface(ctrl)
+
+	menuPerformanceParamMock := entity.MenuPerformanceParam{
+		UmkmID: 1,
+		From:   "2023-01-01",
+		To:     "2023-01-07",
+		Limit:  1,
+	}
+
+	currentRangeMock := entity.TimeRange{
+		Start: from,
+		End:   from.AddDate(0, 0, 7),
+	}
+
+	previousRangeMock := entity.TimeRange{
+		Start: from.AddDate(0, 0, -7),
+		End:   from,
+	}
+
+	salesParamMock := entity.SalesAggregateParam{
+		UmkmID:      1,
+		Status:      entity.StatusDone,
+		Granularity: entity.GranularityMonth,
+		Range:       currentRangeMock,
+		Location:    location,
+	}
+
+	currentMenuParamMock := entity.MenuAggregateParam{
+		UmkmID: 1,
+		Range:  currentRangeMock,
+	}
+
+	previousMenuParamMock := entity.MenuAggregateParam{
+		UmkmID: 1,
+		Range:  previousRangeMock,
+	}
+
+	salesResultMock := []entity.SalesAggregate{
+		{
+			Period:           "2023-01",
+			TotalTransaction: 4,
+			TotalRevenue:     50000,
+		},
+	}
+
+	currentMenuResultMock := []entity.MenuAggregate{
+		{
+			UmkmID:           1,
+			MenuID:           1,
+			TotalQuantity:    5,
+			TotalRevenue:     20000,
+			TotalTransaction: 2,
+			TotalDoneItem:    3,
+			TotalCancelItem:  1,
+		},
+		{
+			UmkmID:           1,
+			MenuID:           2,
+			TotalQuantity:    2,
+			TotalRevenue:     30000,
+			TotalTransaction: 2,
+			TotalDoneItem:    2,
+		},
+	}
+
+	previousMenuResultMock := []entity.MenuAggregate{
+		{
+			UmkmID:        1,
+			MenuID:        1,
+			TotalQuantity: 4,
+			TotalRevenue:  16000,
+		},
+	}
+
+	menusResultMock := []entity.Menu{
 		{
-			TransactionID: 3,
-			TotalPrice:    30000,
 			Model: gorm.Model{
-				CreatedAt: lastMonth,
+				ID: 1,
 			},
+			Name: "menu 1",
+		},
+		{
+			Model: gorm.Model{
+				ID: 2,
+			},
+			Name: "menu 2",
 		},
 	}
 
-	resultMock := entity.WidgetDashboardResult{
-		TotalMonthTransaction:     2,
-		TotalMonthRevenue:         30000,
-		TotalLastMonthTransaction: 1,
-		TotalLastMonthRevenue:     30000,
-		TotalTodayTransaction:     1,
-		TotalTodayRevenue:         10000,
-		TotalYesterdayTransaction: 1,
-		TotalYesterdayRevenue:     20000,
+	growthMock := float64(25)
+
+	resultMock := entity.MenuPerformanceResult{
+		UmkmID:           1,
+		From:             "2023-01-01",
+		To:               "2023-01-07",
+		TotalTransaction: 4,
+		TopByQuantity: []entity.MenuPerformance{
+			{
+				MenuID:           1,
+				MenuName:         "menu 1",
+				UmkmID:           1,
+				TotalQuantity:    5,
+				TotalRevenue:     20000,
+				TotalTransaction: 2,
+				AttachRate:       0.5,
+				CancellationRate: 0.25,
+				QuantityGrowth:   &growthMock,
+				RevenueGrowth:    &growthMock,
+			},
+		},
+		TopByRevenue: []entity.MenuPerformance{
+			{
+				MenuID:           2,
+				MenuName:         "menu 2",
+				UmkmID:           1,
+				TotalQuantity:    2,
+				TotalRevenue:     30000,
+				TotalTransaction: 2,
+				AttachRate:       0.5,
+			},
+		},
 	}
 
-	a := analytic.Init(cartMock)
+	a := analytic.Init(cartMock, menuMock, umkmMock, midtransTransactionMock, analytic.Config{Timezone: "Asia/Jakarta"})
 
 	type mockFields struct {
 		cart *mock_cart.MockInterface
+		menu *mock_menu.MockInterface
 	}
 
 	mocks := mockFields{
 		cart: cartMock,
+		menu: menuMock,
 	}
 
 	type args struct {
-		ctx context.Context
+		ctx   context.Context
+		param entity.MenuPerformanceParam
 	}
 
 	tests := []struct {
 		name     string
 		args     args
 		mockFunc func(mock mockFields, arg args)
-		want     entity.WidgetDashboardResult
+		want     entity.MenuPerformanceResult
 		wantErr  bool
 	}{
 		{
-			name: "failed to get cart list",
+			name: "invalid date",
 			args: args{
 				ctx: context.Background(),
+				param: entity.MenuPerformanceParam{
+					From: "2023-01-01",
+					To:   "07-01-2023",
+				},
+			},
+			mockFunc: func(mock mockFields, arg args) {},
+			want:     entity.MenuPerformanceResult{},
+			wantErr:  true,
+		},
+		{
+			name: "failed to get sales aggregate",
+			args: args{
+				ctx:   context.Background(),
+				param: menuPerformanceParamMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.cart.EXPECT().GetList(cartThisMonthParamMock).Return([]entity.Cart{}, assert.AnError)
+				mock.cart.EXPECT().GetSalesAggregate(gomock.Any(), salesParamMock).Return([]entity.SalesAggregate{}, assert.AnError)
 			},
-			want:    entity.WidgetDashboardResult{},
+			want:    entity.MenuPerformanceResult{},
+			wantErr: true,
+		},
+		{
+			name: "failed to get current menu aggregate",
+			args: args{
+				ctx:   context.Background(),
+				param: menuPerformanceParamMock,
+			},
+			mockFunc: func(mock mockFields, arg args) {
+				mock.cart.EXPECT().GetSalesAggregate(gomock.Any(), salesParamMock).Return(salesResultMock, nil)
+				mock.cart.EXPECT().GetMenuAggregate(gomock.Any(), currentMenuParamMock).Return([]entity.MenuAggregate{}, assert.AnError)
+			},
+			want:    entity.MenuPerformanceResult{},
+			wantErr: true,
+		},
+		{
+			name: "failed to get previous menu aggregate",
+			args: args{
+				ctx:   context.Background(),
+				param: menuPerformanceParamMock,
+			},
+			mockFunc: func(mock mockFields, arg args) {
+				mock.cart.EXPECT().GetSalesAggregate(gomock.Any(), salesParamMock).Return(salesResultMock, nil)
+				mock.cart.EXPECT().GetMenuAggregate(gomock.Any(), currentMenuParamMock).Return(currentMenuResultMock, nil)
+				mock.cart.EXPECT().GetMenuAggregate(gomock.Any(), previousMenuParamMock).Return([]entity.MenuAggregate{}, assert.AnError)
+			},
+			want:    entity.MenuPerformanceResult{},
+			wantErr: true,
+		},
+		{
+			name: "failed to get menu list",
+			args: args{
+				ctx:   context.Background(),
+				param: menuPerformanceParamMock,
+			},
+			mockFunc: func(mock mockFields, arg args) {
+				mock.cart.EXPECT().GetSalesAggregate(gomock.Any(), salesParamMock).Return(salesResultMock, nil)
+				mock.cart.EXPECT().GetMenuAggregate(gomock.Any(), currentMenuParamMock).Return(currentMenuResultMock, nil)
+				mock.cart.EXPECT().GetMenuAggregate(gomock.Any(), previousMenuParamMock).Return(previousMenuResultMock, nil)
+				mock.menu.EXPECT().GetListInByID(gomock.Any(), []int64{1, 2}).Return([]entity.Menu{}, assert.AnError)
+			},
+			want:    entity.MenuPerformanceResult{},
 			wantErr: true,
 		},
 		{
-			name: "failed to get last month cart list",
+			name: "all success",
+			args: args{
+				ctx:   context.Background(),
+				param: menuPerformanceParamMock,
+			},
+			mockFunc: func(mock mockFields, arg args) {
+				mock.cart.EXPECT().GetSalesAggregate(gomock.Any(), salesParamMock).Return(salesResultMock, nil)
+				mock.cart.EXPECT().GetMenuAggregate(gomock.Any(), currentMenuParamMock).Return(currentMenuResultMock, nil)
+				mock.cart.EXPECT().GetMenuAggregate(gomock.Any(), previousMenuParamMock).Return(previousMenuResultMock, nil)
+				mock.menu.EXPECT().GetListInByID(gomock.Any(), []int64{1, 2}).Return(menusResultMock, nil)
+			},
+			want:    resultMock,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mocks, tt.args)
+			got, err := a.GetMenuPerformance(tt.args.ctx, tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("analytic.GetMenuPerformance() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_analytic_GetHeatmap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	location, _ := time.LoadLocation("Asia/Jakarta")
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, location)
+
+	cartMock := mock_cart.NewMockInterface(ctrl)
+	menuMock := mock_menu.NewMockInterface(ctrl)
+	umkmMock := mock_umkm.NewMockInterface(ctrl)
+	midtransTransactionMock := mock_midtrans_transaction.NewMockInterface(ctrl)
+
+	heatmapParamMock := entity.HeatmapParam{
+		UmkmID: 1,
+		From:   "2023-01-01",
+		To:     "2023-01-07",
+	}
+
+	heatmapAggregateParamMock := entity.HeatmapAggregateParam{
+		UmkmID: 1,
+		Range: entity.TimeRange{
+			Start: from,
+			End:   from.AddDate(0, 0, 7),
+		},
+		Location: location,
+	}
+
+	avgPrepTime := float64(300)
+	heatmapResultMock := []entity.HeatmapAggregate{
+		{
+			DayOfWeek:        1,
+			Hour:             12,
+			TotalTransaction: 2,
+			TotalRevenue:     30000,
+			AvgPrepTime:      &avgPrepTime,
+		},
+	}
+
+	cellsMock := []entity.HeatmapCell{}
+	for day := 0; day < 7; day++ {
+		for hour := 0; hour < 24; hour++ {
+			cell := entity.HeatmapCell{
+				DayOfWeek: day,
+				Hour:      hour,
+			}
+			if day == 1 && hour == 12 {
+				cell.TotalTransaction = 2
+				cell.TotalRevenue = 30000
+				cell.AvgPrepTimeSeconds = &avgPrepTime
+			}
+			cellsMock = append(cellsMock, cell)
+		}
+	}
+
+	resultMock := entity.HeatmapResult{
+		UmkmID: 1,
+		From:   "2023-01-01",
+		To:     "2023-01-07",
+		Cells:  cellsMock,
+	}
+
+	a := analytic.Init(cartMock, menuMock, umkmMock, midtransTransactionMock, analytic.Config{Timezone: "Asia/Jakarta"})
+
+	type mockFields struct {
+		cart *mock_cart.MockInterface
+	}
+
+	mocks := mockFields{
+		cart: cartMock,
+	}
+
+	type args struct {
+		ctx   context.Context
+		param entity.HeatmapParam
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		mockFunc func(mock mockFields, arg args)
+		want     entity.HeatmapResult
+		wantErr  bool
+	}{
+		{
+			name: "invalid date range",
 			args: args{
 				ctx: context.Background(),
+				param: entity.HeatmapParam{
+					UmkmID: 1,
+					From:   "2023-01-07",
+					To:     "2023-01-01",
+				},
+			},
+			mockFunc: func(mock mockFields, arg args) {},
+			want:     entity.HeatmapResult{},
+			wantErr:  true,
+		},
+		{
+			name: "failed to get heatmap aggregate",
+			args: args{
+				ctx:   context.Background(),
+				param: heatmapParamMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.cart.EXPECT().GetList(cartThisMonthParamMock).Return(cartsThisMonthResultMock, nil)
-				mock.cart.EXPECT().GetList(cartLastMonthParamMock).Return([]entity.Cart{}, assert.AnError)
+				mock.cart.EXPECT().GetHeatmapAggregate(gomock.Any(), heatmapAggregateParamMock).Return([]entity.HeatmapAggregate{}, assert.AnError)
 			},
-			want:    entity.WidgetDashboardResult{},
+			want:    entity.HeatmapResult{},
 			wantErr: true,
 		},
 		{
 			name: "all success",
+			args: args{
+				ctx:   context.Background(),
+				param: heatmapParamMock,
+			},
+			mockFunc: func(mock mockFields, arg args) {
+				mock.cart.EXPECT().GetHeatmapAggregate(gomock.Any(), heatmapAggregateParamMock).Return(heatmapResultMock, nil)
+			},
+			want:    resultMock,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mocks, tt.args)
+			got, err := a.GetHeatmap(tt.args.ctx, tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("analytic.GetHeatmap() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_analytic_GetBreakdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	location, _ := time.LoadLocation("Asia/Jakarta")
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, location)
+
+	cartMock := mock_cart.NewMockInterface(ctrl)
+	menuMock := mock_menu.NewMockInterface(ctrl)
+	umkmMock := mock_umkm.NewMockInterface(ctrl)
+	midtransTransactionMock := mock_midtrans_transaction.NewMockInterface(ctrl)
+
+	breakdownParamMock := entity.BreakdownParam{
+		From: "2023-01-01",
+		To:   "2023-01-07",
+	}
+
+	rangeMock := entity.TimeRange{
+		Start: from,
+		End:   from.AddDate(0, 0, 7),
+	}
+
+	paymentResultMock := []entity.PaymentAggregate{
+		{
+			PaymentType:      1,
+			Status:           entity.StatusSuccess,
+			TotalTransaction: 1,
+			TotalAmount:      10000,
+		},
+		{
+			PaymentType:      2,
+			Status:           entity.StatusSuccess,
+			TotalTransaction: 3,
+			TotalAmount:      45000,
+		},
+		{
+			PaymentType:      2,
+			Status:           entity.StatusPending,
+			TotalTransaction: 1,
+			TotalAmount:      15000,
+		},
+		{
+			PaymentType:      2,
+			Status:           entity.StatusFailure,
+			TotalTransaction: 1,
+			TotalAmount:      20000,
+		},
+		{
+			PaymentType:      3,
+			Status:           entity.StatusDeny,
+			TotalTransaction: 2,
+			TotalAmount:      30000,
+		},
+	}
+
+	cancellationResultMock := []entity.CancellationAggregate{
+		{
+			UmkmID:          1,
+			TotalItem:       4,
+			TotalCancelItem: 0,
+		},
+		{
+			UmkmID:            2,
+			TotalItem:         4,
+			TotalCancelItem:   2,
+			TotalRefundItem:   1,
+			TotalRefundAmount: 15000,
+		},
+	}
+
+	umkmResultMock := []entity.Umkm{
+		{
+			Model: gorm.Model{
+				ID: 1,
+			},
+			Name: "umkm 1",
+		},
+		{
+			Model: gorm.Model{
+				ID: 2,
+			},
+			Name: "umkm 2",
+		},
+	}
+
+	resultMock := entity.BreakdownResult{
+		From: "2023-01-01",
+		To:   "2023-01-07",
+		PaymentMethods: []entity.PaymentMethodBreakdown{
+			{
+				PaymentType:      "Cash",
+				TotalTransaction: 1,
+				GrossAmount:      10000,
+				Share:            0.25,
+			},
+			{
+				PaymentType:      "Gopay",
+				TotalTransaction: 3,
+				GrossAmount:      45000,
+				Share:            0.75,
+			},
+		},
+		Payment: entity.PaymentStatusBreakdown{
+			TotalTransaction: 8,
+			TotalSuccess:     4,
+			TotalPending:     1,
+			TotalFailure:     3,
+			AbandonedRate:    float64(3) / float64(8),
+		},
+		CancellationRate: 0.25,
+		Tenants: []entity.TenantCancellation{
+			{
+				UmkmID:           2,
+				UmkmName:         "umkm 2",
+				TotalItem:        4,
+				TotalCancelItem:  2,
+				CancellationRate: 0.5,
+				RefundAmount:     15000,
+			},
+			{
+				UmkmID:    1,
+				UmkmName:  "umkm 1",
+				TotalItem: 4,
+			},
+		},
+		Refund: entity.RefundSummary{
+			TotalItem:   1,
+			TotalAmount: 15000,
+		},
+	}
+
+	a := analytic.Init(cartMock, menuMock, umkmMock, midtransTransactionMock, analytic.Config{Timezone: "Asia/Jakarta"})
+
+	type mockFields struct {
+		cart                *mock_cart.MockInterface
+		umkm                *mock_umkm.MockInterface
+		midtransTransaction *mock_midtrans_transaction.MockInterface
+	}
+
+	mocks := mockFields{
+		cart:                cartMock,
+		umkm:                umkmMock,
+		midtransTransaction: midtransTransactionMock,
+	}
+
+	type args struct {
+		ctx   context.Context
+		param entity.BreakdownParam
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		mockFunc func(mock mockFields, arg args)
+		want     entity.BreakdownResult
+		wantErr  bool
+	}{
+		{
+			name: "invalid date range",
 			args: args{
 				ctx: context.Background(),
+				param: entity.BreakdownParam{
+					From: "2023-01-07",
+					To:   "2023-01-01",
+				},
+			},
+			mockFunc: func(mock mockFields, arg args) {},
+			want:     entity.BreakdownResult{},
+			wantErr:  true,
+		},
+		{
+			name: "failed to get payment aggregate",
+			args: args{
+				ctx:   context.Background(),
+				param: breakdownParamMock,
+			},
+			mockFunc: func(mock mockFields, arg args) {
+				mock.midtransTransaction.EXPECT().GetPaymentAggregate(gomock.Any(), entity.PaymentAggregateParam{Range: rangeMock}).Return([]entity.PaymentAggregate{}, assert.AnError)
+			},
+			want:    entity.BreakdownResult{},
+			wantErr: true,
+		},
+		{
+			name: "failed to get cancellation aggregate",
+			args: args{
+				ctx:   context.Background(),
+				param: breakdownParamMock,
+			},
+			mockFunc: func(mock mockFields, arg args) {
+				mock.midtransTransaction.EXPECT().GetPaymentAggregate(gomock.Any(), entity.PaymentAggregateParam{Range: rangeMock}).Return(paymentResultMock, nil)
+				mock.cart.EXPECT().GetCancellationAggregate(gomock.Any(), entity.CancellationAggregateParam{Range: rangeMock}).Return([]entity.CancellationAggregate{}, assert.AnError)
+			},
+			want:    entity.BreakdownResult{},
+			wantErr: true,
+		},
+		{
+			name: "failed to get umkm list",
+			args: args{
+				ctx:   context.Background(),
+				param: breakdownParamMock,
+			},
+			mockFunc: func(mock mockFields, arg args) {
+				mock.midtransTransaction.EXPECT().GetPaymentAggregate(gomock.Any(), entity.PaymentAggregateParam{Range: rangeMock}).Return(paymentResultMock, nil)
+				mock.cart.EXPECT().GetCancellationAggregate(gomock.Any(), entity.CancellationAggregateParam{Range: rangeMock}).Return(cancellationResultMock, nil)
+				mock.umkm.EXPECT().GetListInByID(gomock.Any(), []uint{1, 2}).Return([]entity.Umkm{}, assert.AnError)
+			},
+			want:    entity.BreakdownResult{},
+			wantErr: true,
+		},
+		{
+			name: "all success",
+			args: args{
+				ctx:   context.Background(),
+				param: breakdownParamMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.cart.EXPECT().GetList(cartThisMonthParamMock).Return(cartsThisMonthResultMock, nil)
-				mock.cart.EXPECT().GetList(cartLastMonthParamMock).Return(cartsLastMonthResultMock, nil)
+				mock.midtransTransaction.EXPECT().GetPaymentAggregate(gomock.Any(), entity.PaymentAggregateParam{Range: rangeMock}).Return(paymentResultMock, nil)
+				mock.cart.EXPECT().GetCancellationAggregate(gomock.Any(), entity.CancellationAggregateParam{Range: rangeMock}).Return(cancellationResultMock, nil)
+				mock.umkm.EXPECT().GetListInByID(gomock.Any(), []uint{1, 2}).Return(umkmResultMock, nil)
 			},
 			want:    resultMock,
 			wantErr: false,
@@ -271,9 +1153,9 @@ func Test_analytic_GetAllDashboardWidget(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc(mocks, tt.args)
-			got, err := a.GetAllDashboardWidget(tt.args.ctx)
+			got, err := a.GetBreakdown(tt.args.ctx, tt.args.param)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("analytic.GetAllDashboardWidget() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("analytic.GetBreakdown() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			assert.Equal(t, tt.want, got)
@@ -0,0 +1,212 @@
+package auditlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	auditLogDom "go-clean/src/business/domain/audit_log"
+	"go-clean/src/business/entity"
+	"go-clean/src/lib/apperr"
+	"go-clean/src/lib/csvutil"
+	"go-clean/src/lib/log"
+	"time"
+)
+
+const maxAuditLogExport = 10000
+
+var (
+	ErrInvalidDate  = apperr.Validation("invalid_date", "tanggal harus berformat YYYY-MM-DD")
+	ErrInvalidRange = apperr.Validation("invalid_range", "tanggal akhir tidak boleh sebelum tanggal awal")
+)
+
+var auditLogSortOptions = entity.SortOptions{
+	Columns: map[string]string{
+		"id":         "id",
+		"created_at": "created_at",
+	},
+	Default:      "id",
+	DefaultOrder: entity.SortDesc,
+	TieBreaker:   "id",
+}
+
+type Interface interface {
+	Record(ctx context.Context, param entity.CreateAuditLogParam) error
+	GetList(ctx context.Context, param entity.AuditLogParam) ([]entity.AuditLog, entity.Pagination, error)
+	GenerateCSV(ctx context.Context, param entity.AuditLogParam) ([]byte, string, error)
+}
+
+type auditLog struct {
+	auditLog   auditLogDom.Interface
+	pagination entity.PaginationConfig
+}
+
+func Init(ald auditLogDom.Interface, pagination entity.PaginationConfig) Interface {
+	al := &auditLog{
+		auditLog:   ald,
+		pagination: pagination,
+	}
+
+	return al
+}
+
+func (al *auditLog) Record(ctx context.Context, param entity.CreateAuditLogParam) error {
+	before, err := snapshot(param.Before)
+	if err != nil {
+		return err
+	}
+
+	after, err := snapshot(param.After)
+	if err != nil {
+		return err
+	}
+
+	if _, err := al.auditLog.Create(ctx, entity.AuditLog{
+		ActorID:    param.ActorID,
+		ActorName:  param.ActorName,
+		ActorRole:  param.ActorRole,
+		Action:     param.Action,
+		TargetType: param.TargetType,
+		TargetID:   param.TargetID,
+		UmkmID:     param.UmkmID,
+		Before:     before,
+		After:      after,
+		IP:         param.IP,
+		Method:     param.Method,
+		Path:       param.Path,
+		StatusCode: param.StatusCode,
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (al *auditLog) GetList(ctx context.Context, param entity.AuditLogParam) ([]entity.AuditLog, entity.Pagination, error) {
+	auditLogs := []entity.AuditLog{}
+
+	paginationParam, err := param.PaginationParam.Paginate(al.pagination, auditLogSortOptions)
+	if err != nil {
+		return auditLogs, entity.Pagination{}, err
+	}
+	param.PaginationParam = paginationParam
+
+	if err := parseDateRange(&param); err != nil {
+		return auditLogs, entity.Pagination{}, err
+	}
+
+	total, err := al.auditLog.Count(ctx, param)
+	if err != nil {
+		return auditLogs, entity.Pagination{}, err
+	}
+
+	auditLogs, err = al.auditLog.GetList(ctx, param)
+	if err != nil {
+		return auditLogs, entity.Pagination{}, err
+	}
+
+	pagination := entity.NewPagination(paginationParam, total)
+	pagination.SetNextCursor(paginationParam, auditLogSortOptions, auditLogs)
+
+	return auditLogs, pagination, nil
+}
+
+func (al *auditLog) GenerateCSV(ctx context.Context, param entity.AuditLogParam) ([]byte, string, error) {
+	param.Limit = maxAuditLogExport
+	param.Offset = 0
+	param.OrderBy = "id desc"
+
+	if err := parseDateRange(&param); err != nil {
+		return nil, "", err
+	}
+
+	auditLogs, err := al.auditLog.GetList(ctx, param)
+	if err != nil {
+		return nil, "", err
+	}
+
+	buf := &bytes.Buffer{}
+	w := csvutil.NewWriter(buf)
+
+	if err := w.Write([]string{"id", "created_at", "actor_id", "actor_name", "actor_role", "action", "target_type", "target_id", "umkm_id", "ip", "method", "path", "status_code", "before", "after"}); err != nil {
+		return nil, "", err
+	}
+
+	for _, a := range auditLogs {
+		if err := w.Write([]string{
+			fmt.Sprint(a.ID),
+			a.CreatedAt.Format(time.RFC3339),
+			fmt.Sprint(a.ActorID),
+			a.ActorName,
+			a.ActorRole,
+			a.Action,
+			a.TargetType,
+			a.TargetID,
+			fmt.Sprint(a.UmkmID),
+			a.IP,
+			a.Method,
+			a.Path,
+			fmt.Sprint(a.StatusCode),
+			a.Before,
+			a.After,
+		}); err != nil {
+			return nil, "", err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, "", err
+	}
+
+	return buf.Bytes(), fmt.Sprintf("audit-log-%s", time.Now().Format("20060102150405")), nil
+}
+
+// parseDateRange turns the inclusive start_date and end_date filters into a [From, To) range.
+func parseDateRange(param *entity.AuditLogParam) error {
+	if param.StartDate != "" {
+		from, err := time.ParseInLocation("2006-01-02", param.StartDate, time.Local)
+		if err != nil {
+			return ErrInvalidDate.Wrap(err)
+		}
+		param.From = from
+	}
+
+	if param.EndDate != "" {
+		to, err := time.ParseInLocation("2006-01-02", param.EndDate, time.Local)
+		if err != nil {
+			return ErrInvalidDate.Wrap(err)
+		}
+		param.To = to.AddDate(0, 0, 1)
+	}
+
+	if !param.From.IsZero() && !param.To.IsZero() && !param.To.After(param.From) {
+		return ErrInvalidRange
+	}
+
+	return nil
+}
+
+// snapshot encodes the value as JSON with the sensitive fields redacted.
+func snapshot(v interface{}) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return "", err
+	}
+
+	res, err := json.Marshal(log.Redact(data))
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
@@ -0,0 +1,395 @@
+package auditlog_test
+
+import (
+	"context"
+	"errors"
+	mock_audit_log "go-clean/src/business/domain/mock/audit_log"
+	"go-clean/src/business/entity"
+	auditlog "go-clean/src/business/usecase/audit_log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func Test_auditLog_Record(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	auditLogMock := mock_audit_log.NewMockInterface(ctrl)
+
+	al := auditlog.Init(auditLogMock, entity.PaginationConfig{})
+
+	mockParam := entity.CreateAuditLogParam{
+		ActorID:    1,
+		ActorName:  "admin",
+		ActorRole:  "super_admin",
+		Action:     entity.AuditActionStaffInvite,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   "2",
+		UmkmID:     3,
+		After: entity.StaffInvitationResult{
+			User: entity.User{
+				Username: "cashier",
+				Email:    "cashier@mail.com",
+				Password: "hashed",
+			},
+			ResetCode: entity.PasswordResetCodeResult{
+				Code: "secret",
+			},
+		},
+		IP:         "127.0.0.1",
+		Method:     "POST",
+		Path:       "/api/v1/umkm/3/staff",
+		StatusCode: 201,
+	}
+
+	type args struct {
+		param entity.CreateAuditLogParam
+	}
+
+	tests := []struct {
+		name     string
+		mockFunc func(arg args)
+		args     args
+		wantErr  bool
+	}{
+		{
+			name: "failed to create audit log",
+			mockFunc: func(arg args) {
+				auditLogMock.EXPECT().Create(gomock.Any(), gomock.Any()).Return(entity.AuditLog{}, assert.AnError)
+			},
+			args: args{
+				param: mockParam,
+			},
+			wantErr: true,
+		},
+		{
+			name: "success redacts sensitive fields",
+			mockFunc: func(arg args) {
+				auditLogMock.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, a entity.AuditLog) (entity.AuditLog, error) {
+					assert.Equal(t, uint(1), a.ActorID)
+					assert.Equal(t, entity.AuditActionStaffInvite, a.Action)
+					assert.Equal(t, "2", a.TargetID)
+					assert.Equal(t, uint(3), a.UmkmID)
+					assert.Equal(t, "", a.Before)
+					assert.Contains(t, a.After, `"Username":"cashier"`)
+					assert.Contains(t, a.After, `"code":"[REDACTED]"`)
+					assert.NotContains(t, a.After, "secret")
+					assert.NotContains(t, a.After, "hashed")
+					assert.NotContains(t, a.After, "cashier@mail.com")
+					return a, nil
+				})
+			},
+			args: args{
+				param: mockParam,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(tt.args)
+			err := al.Record(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("auditLog.Record() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func Test_auditLog_GetList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	auditLogMock := mock_audit_log.NewMockInterface(ctrl)
+
+	al := auditlog.Init(auditLogMock, entity.PaginationConfig{})
+
+	auditLogsMock := []entity.AuditLog{
+		{
+			Model: gorm.Model{
+				ID: 1,
+			},
+			Action: entity.AuditActionPaymentMarkAsPaid,
+		},
+	}
+
+	type args struct {
+		param entity.AuditLogParam
+	}
+
+	tests := []struct {
+		name           string
+		mockFunc       func(arg args)
+		args           args
+		want           []entity.AuditLog
+		wantPagination entity.Pagination
+		wantErr        error
+	}{
+		{
+			name:     "invalid start date",
+			mockFunc: func(arg args) {},
+			args: args{
+				param: entity.AuditLogParam{
+					StartDate: "01-01-2022",
+				},
+			},
+			want:    []entity.AuditLog{},
+			wantErr: auditlog.ErrInvalidDate,
+		},
+		{
+			name:     "invalid end date",
+			mockFunc: func(arg args) {},
+			args: args{
+				param: entity.AuditLogParam{
+					EndDate: "2022-02-30",
+				},
+			},
+			want:    []entity.AuditLog{},
+			wantErr: auditlog.ErrInvalidDate,
+		},
+		{
+			name:     "end date before start date",
+			mockFunc: func(arg args) {},
+			args: args{
+				param: entity.AuditLogParam{
+					StartDate: "2022-01-31",
+					EndDate:   "2022-01-01",
+				},
+			},
+			want:    []entity.AuditLog{},
+			wantErr: auditlog.ErrInvalidRange,
+		},
+		{
+			name:     "invalid sort by",
+			mockFunc: func(arg args) {},
+			args: args{
+				param: entity.AuditLogParam{
+					PaginationParam: entity.PaginationParam{
+						SortBy: "action",
+					},
+				},
+			},
+			want:    []entity.AuditLog{},
+			wantErr: entity.ErrInvalidSortBy,
+		},
+		{
+			name: "failed to count audit logs",
+			mockFunc: func(arg args) {
+				auditLogMock.EXPECT().Count(gomock.Any(), gomock.Any()).Return(int64(0), assert.AnError)
+			},
+			args: args{
+				param: entity.AuditLogParam{},
+			},
+			want:    []entity.AuditLog{},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "failed to get audit logs",
+			mockFunc: func(arg args) {
+				auditLogMock.EXPECT().Count(gomock.Any(), gomock.Any()).Return(int64(1), nil)
+				auditLogMock.EXPECT().GetList(gomock.Any(), gomock.Any()).Return([]entity.AuditLog{}, assert.AnError)
+			},
+			args: args{
+				param: entity.AuditLogParam{},
+			},
+			want:    []entity.AuditLog{},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "success with default pagination",
+			mockFunc: func(arg args) {
+				listParam := entity.AuditLogParam{
+					PaginationParam: entity.PaginationParam{
+						Limit:     20,
+						Page:      1,
+						Offset:    0,
+						SortBy:    "id",
+						SortOrder: entity.SortDesc,
+						OrderBy:   "id desc",
+					},
+				}
+				auditLogMock.EXPECT().Count(gomock.Any(), listParam).Return(int64(1), nil)
+				auditLogMock.EXPECT().GetList(gomock.Any(), listParam).Return(auditLogsMock, nil)
+			},
+			args: args{
+				param: entity.AuditLogParam{},
+			},
+			want: auditLogsMock,
+			wantPagination: entity.Pagination{
+				Page:       1,
+				Limit:      20,
+				TotalItems: 1,
+				TotalPages: 1,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "success with date range",
+			mockFunc: func(arg args) {
+				auditLogMock.EXPECT().Count(gomock.Any(), gomock.Any()).Return(int64(25), nil)
+				auditLogMock.EXPECT().GetList(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, param entity.AuditLogParam) ([]entity.AuditLog, error) {
+					assert.Equal(t, time.Date(2022, 1, 1, 0, 0, 0, 0, time.Local), param.From)
+					assert.Equal(t, time.Date(2022, 2, 1, 0, 0, 0, 0, time.Local), param.To)
+					assert.Equal(t, 10, param.Offset)
+					return auditLogsMock, nil
+				})
+			},
+			args: args{
+				param: entity.AuditLogParam{
+					StartDate: "2022-01-01",
+					EndDate:   "2022-01-31",
+					PaginationParam: entity.PaginationParam{
+						Limit: 10,
+						Page:  2,
+					},
+				},
+			},
+			want: auditLogsMock,
+			wantPagination: entity.Pagination{
+				Page:       2,
+				Limit:      10,
+				TotalItems: 25,
+				TotalPages: 3,
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(tt.args)
+			got, pagination, err := al.GetList(context.Background(), tt.args.param)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("auditLog.GetList() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantPagination, pagination)
+		})
+	}
+}
+
+func Test_auditLog_GenerateCSV(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	auditLogMock := mock_audit_log.NewMockInterface(ctrl)
+
+	al := auditlog.Init(auditLogMock, entity.PaginationConfig{})
+
+	createdAt := time.Date(2022, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	auditLogsMock := []entity.AuditLog{
+		{
+			Model: gorm.Model{
+				ID:        1,
+				CreatedAt: createdAt,
+			},
+			ActorID:    1,
+			ActorName:  "admin",
+			ActorRole:  "super_admin",
+			Action:     entity.AuditActionMenuUpdate,
+			TargetType: entity.AuditTargetMenu,
+			TargetID:   "2",
+			UmkmID:     3,
+			Before:     `{"Price":10000}`,
+			After:      `{"Price":12000}`,
+			IP:         "127.0.0.1",
+			Method:     "PUT",
+			Path:       "/api/v1/menu/2",
+			StatusCode: 200,
+		},
+	}
+
+	type args struct {
+		param entity.AuditLogParam
+	}
+
+	tests := []struct {
+		name     string
+		mockFunc func(arg args)
+		args     args
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "failed to get audit logs",
+			mockFunc: func(arg args) {
+				auditLogMock.EXPECT().GetList(gomock.Any(), gomock.Any()).Return([]entity.AuditLog{}, assert.AnError)
+			},
+			args: args{
+				param: entity.AuditLogParam{},
+			},
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name: "success",
+			mockFunc: func(arg args) {
+				auditLogMock.EXPECT().GetList(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, param entity.AuditLogParam) ([]entity.AuditLog, error) {
+					assert.Equal(t, entity.AuditTargetMenu, param.TargetType)
+					assert.Equal(t, 0, param.Offset)
+					return auditLogsMock, nil
+				})
+			},
+			args: args{
+				param: entity.AuditLogParam{
+					TargetType: entity.AuditTargetMenu,
+					PaginationParam: entity.PaginationParam{
+						Page: 3,
+					},
+				},
+			},
+			want: strings.Join([]string{
+				"id,created_at,actor_id,actor_name,actor_role,action,target_type,target_id,umkm_id,ip,method,path,status_code,before,after",
+				`1,2022-01-01T10:00:00Z,1,admin,super_admin,menu.update,menu,2,3,127.0.0.1,PUT,/api/v1/menu/2,200,"{""Price"":10000}","{""Price"":12000}"`,
+				"",
+			}, "\n"),
+			wantErr: false,
+		},
+		{
+			name: "escapes formulas",
+			mockFunc: func(arg args) {
+				auditLogMock.EXPECT().GetList(gomock.Any(), gomock.Any()).Return([]entity.AuditLog{
+					{
+						Model: gorm.Model{
+							ID:        2,
+							CreatedAt: createdAt,
+						},
+						ActorName: `=HYPERLINK("http://evil")`,
+						Action:    entity.AuditActionMenuUpdate,
+						After:     "@SUM(A1)",
+					},
+				}, nil)
+			},
+			args: args{
+				param: entity.AuditLogParam{},
+			},
+			want: strings.Join([]string{
+				"id,created_at,actor_id,actor_name,actor_role,action,target_type,target_id,umkm_id,ip,method,path,status_code,before,after",
+				`2,2022-01-01T10:00:00Z,0,"'=HYPERLINK(""http://evil"")",,menu.update,,,0,,,,0,,'@SUM(A1)`,
+				"",
+			}, "\n"),
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(tt.args)
+			got, filename, err := al.GenerateCSV(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("auditLog.GenerateCSV() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				assert.True(t, strings.HasPrefix(filename, "audit-log-"))
+			}
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
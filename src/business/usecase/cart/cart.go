@@ -2,14 +2,19 @@ package cart
 
 import (
 	"context"
-	"errors"
 	cartDom "go-clean/src/business/domain/cart"
 	menuDom "go-clean/src/business/domain/menu"
 	umkmDom "go-clean/src/business/domain/umkm"
 	"go-clean/src/business/entity"
+	"go-clean/src/lib/apperr"
 	"go-clean/src/lib/auth"
 )
 
+var (
+	ErrMenuUnavailable = apperr.Conflict("menu_unavailable", "menu tidak tersedia")
+	ErrCartForbidden   = apperr.Forbidden("cart_forbidden", "tidak memiliki akses ke keranjang ini")
+)
+
 type Interface interface {
 	Create(ctx context.Context, params entity.CreateCartParam) (entity.Cart, error)
 	DecreaseItem(ctx context.Context, params entity.CartParam) error
@@ -44,7 +49,7 @@ func (c *cart) Create(ctx context.Context, params entity.CreateCartParam) (entit
 		return entity.Cart{}, err
 	}
 
-	menu, err := c.menu.Get(entity.MenuParam{
+	menu, err := c.menu.Get(ctx, entity.MenuParam{
 		ID:     params.MenuID,
 		UmkmID: params.UmkmID,
 	})
@@ -53,10 +58,10 @@ func (c *cart) Create(ctx context.Context, params entity.CreateCartParam) (entit
 	}
 
 	if !*menu.IsReady {
-		return entity.Cart{}, errors.New("menu tidak tersedia")
+		return entity.Cart{}, ErrMenuUnavailable
 	}
 
-	cartExist, _ := c.cart.Get(entity.CartParam{
+	cartExist, _ := c.cart.Get(ctx, entity.CartParam{
 		GuestID: user.User.GuestID,
 		UmkmID:  params.UmkmID,
 		MenuID:  params.MenuID,
@@ -64,7 +69,7 @@ func (c *cart) Create(ctx context.Context, params entity.CreateCartParam) (entit
 	})
 
 	if cartExist.ID != 0 {
-		if err := c.cart.Update(entity.CartParam{
+		if err := c.cart.Update(ctx, entity.CartParam{
 			GuestID: user.User.GuestID,
 			UmkmID:  params.UmkmID,
 			MenuID:  params.MenuID,
@@ -79,7 +84,7 @@ func (c *cart) Create(ctx context.Context, params entity.CreateCartParam) (entit
 		return cartExist, nil
 	}
 
-	cart, err := c.cart.Create(entity.Cart{
+	cart, err := c.cart.Create(ctx, entity.Cart{
 		UmkmID:       params.UmkmID,
 		MenuID:       params.MenuID,
 		GuestID:      user.User.GuestID,
@@ -101,7 +106,7 @@ func (c *cart) DecreaseItem(ctx context.Context, params entity.CartParam) error
 		return err
 	}
 
-	cart, err := c.cart.Get(entity.CartParam{
+	cart, err := c.cart.Get(ctx, entity.CartParam{
 		ID:      params.ID,
 		Status:  entity.StatusInCart,
 		GuestID: user.User.GuestID,
@@ -110,7 +115,7 @@ func (c *cart) DecreaseItem(ctx context.Context, params entity.CartParam) error
 		return err
 	}
 
-	menu, err := c.menu.Get(entity.MenuParam{
+	menu, err := c.menu.Get(ctx, entity.MenuParam{
 		ID: cart.MenuID,
 	})
 	if err != nil {
@@ -118,7 +123,7 @@ func (c *cart) DecreaseItem(ctx context.Context, params entity.CartParam) error
 	}
 
 	if cart.Amount == 1 {
-		if err := c.cart.Delete(entity.CartParam{
+		if err := c.cart.Delete(ctx, entity.CartParam{
 			ID:      params.ID,
 			Status:  entity.StatusInCart,
 			GuestID: user.User.GuestID,
@@ -129,7 +134,7 @@ func (c *cart) DecreaseItem(ctx context.Context, params entity.CartParam) error
 		return nil
 	}
 
-	if err := c.cart.Update(entity.CartParam{
+	if err := c.cart.Update(ctx, entity.CartParam{
 		ID:      params.ID,
 		Status:  entity.StatusInCart,
 		GuestID: user.User.GuestID,
@@ -149,7 +154,7 @@ func (c *cart) GetListByUser(ctx context.Context) ([]entity.Cart, error) {
 		return []entity.Cart{}, err
 	}
 
-	cart, err := c.cart.GetList(entity.CartParam{
+	cart, err := c.cart.GetList(ctx, entity.CartParam{
 		GuestID: user.User.GuestID,
 		Status:  entity.StatusInCart,
 	})
@@ -174,7 +179,7 @@ func (c *cart) GetListByUser(ctx context.Context) ([]entity.Cart, error) {
 		umkmIds = append(umkmIds, m)
 	}
 
-	menus, err := c.menu.GetListInByID(menuIds)
+	menus, err := c.menu.GetListInByID(ctx, menuIds)
 	if err != nil {
 		return cart, err
 	}
@@ -184,7 +189,7 @@ func (c *cart) GetListByUser(ctx context.Context) ([]entity.Cart, error) {
 		menusMap[m.ID] = m
 	}
 
-	umkms, err := c.umkm.GetListInByID(umkmIds)
+	umkms, err := c.umkm.GetListInByID(ctx, umkmIds)
 	if err != nil {
 		return cart, err
 	}
@@ -203,7 +208,7 @@ func (c *cart) GetListByUser(ctx context.Context) ([]entity.Cart, error) {
 }
 
 func (c *cart) ValidateCart(ctx context.Context, cartId uint, guestId string) error {
-	cart, err := c.cart.Get(entity.CartParam{
+	cart, err := c.cart.Get(ctx, entity.CartParam{
 		ID: cartId,
 	})
 	if err != nil {
@@ -211,14 +216,14 @@ func (c *cart) ValidateCart(ctx context.Context, cartId uint, guestId string) er
 	}
 
 	if cart.GuestID != guestId {
-		return errors.New("unauthorized")
+		return ErrCartForbidden
 	}
 
 	return nil
 }
 
 func (c *cart) Delete(ctx context.Context, param entity.CartParam) error {
-	if err := c.cart.Delete(param); err != nil {
+	if err := c.cart.Delete(ctx, param); err != nil {
 		return err
 	}
 
@@ -233,7 +238,7 @@ func (c *cart) GetCartCount(ctx context.Context) (int, error) {
 		return result, err
 	}
 
-	carts, err := c.cart.GetList(entity.CartParam{
+	carts, err := c.cart.GetList(ctx, entity.CartParam{
 		GuestID: user.User.GuestID,
 		Status:  entity.StatusInCart,
 	})
@@ -254,7 +259,7 @@ func (c *cart) ClearCart(ctx context.Context) error {
 		return err
 	}
 
-	if err := c.cart.Delete(entity.CartParam{
+	if err := c.cart.Delete(ctx, entity.CartParam{
 		GuestID: user.User.GuestID,
 	}); err != nil {
 		return err
@@ -127,7 +127,7 @@ func Test_cart_Create(t *testing.T) {
 			},
 			mockFunc: func(mock mockFields, arg args) {
 				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(userAuthMock, nil)
-				mock.menu.EXPECT().Get(menuParamMock).Return(entity.Menu{}, assert.AnError)
+				mock.menu.EXPECT().Get(gomock.Any(), menuParamMock).Return(entity.Menu{}, assert.AnError)
 			},
 			want:    entity.Cart{},
 			wantErr: true,
@@ -140,9 +140,9 @@ func Test_cart_Create(t *testing.T) {
 			},
 			mockFunc: func(mock mockFields, arg args) {
 				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(userAuthMock, nil)
-				mock.menu.EXPECT().Get(menuParamMock).Return(menuResultMock, nil)
-				mock.cart.EXPECT().Get(cartParamMock).Return(cartResultMock, nil)
-				mock.cart.EXPECT().Update(cartUpdateParamMock, cartUpdateMock).Return(assert.AnError)
+				mock.menu.EXPECT().Get(gomock.Any(), menuParamMock).Return(menuResultMock, nil)
+				mock.cart.EXPECT().Get(gomock.Any(), cartParamMock).Return(cartResultMock, nil)
+				mock.cart.EXPECT().Update(gomock.Any(), cartUpdateParamMock, cartUpdateMock).Return(assert.AnError)
 			},
 			want:    cartResultMock,
 			wantErr: true,
@@ -155,9 +155,9 @@ func Test_cart_Create(t *testing.T) {
 			},
 			mockFunc: func(mock mockFields, arg args) {
 				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(userAuthMock, nil)
-				mock.menu.EXPECT().Get(menuParamMock).Return(menuResultMock, nil)
-				mock.cart.EXPECT().Get(cartParamMock).Return(cartResultMock, nil)
-				mock.cart.EXPECT().Update(cartUpdateParamMock, cartUpdateMock).Return(nil)
+				mock.menu.EXPECT().Get(gomock.Any(), menuParamMock).Return(menuResultMock, nil)
+				mock.cart.EXPECT().Get(gomock.Any(), cartParamMock).Return(cartResultMock, nil)
+				mock.cart.EXPECT().Update(gomock.Any(), cartUpdateParamMock, cartUpdateMock).Return(nil)
 			},
 			want:    cartResultMock,
 			wantErr: false,
@@ -170,9 +170,9 @@ func Test_cart_Create(t *testing.T) {
 			},
 			mockFunc: func(mock mockFields, arg args) {
 				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(userAuthMock, nil)
-				mock.menu.EXPECT().Get(menuParamMock).Return(menuResultMock, nil)
-				mock.cart.EXPECT().Get(cartParamMock).Return(entity.Cart{}, nil)
-				mock.cart.EXPECT().Create(createCartMock).Return(entity.Cart{}, assert.AnError)
+				mock.menu.EXPECT().Get(gomock.Any(), menuParamMock).Return(menuResultMock, nil)
+				mock.cart.EXPECT().Get(gomock.Any(), cartParamMock).Return(entity.Cart{}, nil)
+				mock.cart.EXPECT().Create(gomock.Any(), createCartMock).Return(entity.Cart{}, assert.AnError)
 			},
 			want:    entity.Cart{},
 			wantErr: true,
@@ -185,9 +185,9 @@ func Test_cart_Create(t *testing.T) {
 			},
 			mockFunc: func(mock mockFields, arg args) {
 				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(userAuthMock, nil)
-				mock.menu.EXPECT().Get(menuParamMock).Return(menuResultMock, nil)
-				mock.cart.EXPECT().Get(cartParamMock).Return(entity.Cart{}, nil)
-				mock.cart.EXPECT().Create(createCartMock).Return(createCartMock, nil)
+				mock.menu.EXPECT().Get(gomock.Any(), menuParamMock).Return(menuResultMock, nil)
+				mock.cart.EXPECT().Get(gomock.Any(), cartParamMock).Return(entity.Cart{}, nil)
+				mock.cart.EXPECT().Create(gomock.Any(), createCartMock).Return(createCartMock, nil)
 			},
 			want:    createCartMock,
 			wantErr: false,
@@ -299,7 +299,7 @@ func Test_cart_DecreaseItem(t *testing.T) {
 			},
 			mockFunc: func(mock mockFields, arg args) {
 				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(authUserMock, nil)
-				mock.cart.EXPECT().Get(cartParamMock).Return(entity.Cart{}, assert.AnError)
+				mock.cart.EXPECT().Get(gomock.Any(), cartParamMock).Return(entity.Cart{}, assert.AnError)
 			},
 			wantErr: true,
 		},
@@ -311,8 +311,8 @@ func Test_cart_DecreaseItem(t *testing.T) {
 			},
 			mockFunc: func(mock mockFields, arg args) {
 				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(authUserMock, nil)
-				mock.cart.EXPECT().Get(cartParamMock).Return(cartResultMock, nil)
-				mock.menu.EXPECT().Get(menuParamMock).Return(entity.Menu{}, assert.AnError)
+				mock.cart.EXPECT().Get(gomock.Any(), cartParamMock).Return(cartResultMock, nil)
+				mock.menu.EXPECT().Get(gomock.Any(), menuParamMock).Return(entity.Menu{}, assert.AnError)
 			},
 			wantErr: true,
 		},
@@ -324,9 +324,9 @@ func Test_cart_DecreaseItem(t *testing.T) {
 			},
 			mockFunc: func(mock mockFields, arg args) {
 				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(authUserMock, nil)
-				mock.cart.EXPECT().Get(cartParamMock).Return(cartResultMock, nil)
-				mock.menu.EXPECT().Get(menuParamMock).Return(menuResultMock, nil)
-				mock.cart.EXPECT().Delete(cartParamMock).Return(assert.AnError)
+				mock.cart.EXPECT().Get(gomock.Any(), cartParamMock).Return(cartResultMock, nil)
+				mock.menu.EXPECT().Get(gomock.Any(), menuParamMock).Return(menuResultMock, nil)
+				mock.cart.EXPECT().Delete(gomock.Any(), cartParamMock).Return(assert.AnError)
 			},
 			wantErr: true,
 		},
@@ -338,9 +338,9 @@ func Test_cart_DecreaseItem(t *testing.T) {
 			},
 			mockFunc: func(mock mockFields, arg args) {
 				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(authUserMock, nil)
-				mock.cart.EXPECT().Get(cartParamMock).Return(cartResultMock, nil)
-				mock.menu.EXPECT().Get(menuParamMock).Return(menuResultMock, nil)
-				mock.cart.EXPECT().Delete(cartParamMock).Return(nil)
+				mock.cart.EXPECT().Get(gomock.Any(), cartParamMock).Return(cartResultMock, nil)
+				mock.menu.EXPECT().Get(gomock.Any(), menuParamMock).Return(menuResultMock, nil)
+				mock.cart.EXPECT().Delete(gomock.Any(), cartParamMock).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -352,9 +352,9 @@ func Test_cart_DecreaseItem(t *testing.T) {
 			},
 			mockFunc: func(mock mockFields, arg args) {
 				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(authUserMock, nil)
-				mock.cart.EXPECT().Get(cartParamMock).Return(cartTwoAmountResultMock, nil)
-				mock.menu.EXPECT().Get(menuParamMock).Return(menuResultMock, nil)
-				mock.cart.EXPECT().Update(cartParamMock, updateCartParamMock).Return(assert.AnError)
+				mock.cart.EXPECT().Get(gomock.Any(), cartParamMock).Return(cartTwoAmountResultMock, nil)
+				mock.menu.EXPECT().Get(gomock.Any(), menuParamMock).Return(menuResultMock, nil)
+				mock.cart.EXPECT().Update(gomock.Any(), cartParamMock, updateCartParamMock).Return(assert.AnError)
 			},
 			wantErr: true,
 		},
@@ -366,9 +366,9 @@ func Test_cart_DecreaseItem(t *testing.T) {
 			},
 			mockFunc: func(mock mockFields, arg args) {
 				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(authUserMock, nil)
-				mock.cart.EXPECT().Get(cartParamMock).Return(cartTwoAmountResultMock, nil)
-				mock.menu.EXPECT().Get(menuParamMock).Return(menuResultMock, nil)
-				mock.cart.EXPECT().Update(cartParamMock, updateCartParamMock).Return(nil)
+				mock.cart.EXPECT().Get(gomock.Any(), cartParamMock).Return(cartTwoAmountResultMock, nil)
+				mock.menu.EXPECT().Get(gomock.Any(), menuParamMock).Return(menuResultMock, nil)
+				mock.cart.EXPECT().Update(gomock.Any(), cartParamMock, updateCartParamMock).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -494,7 +494,7 @@ func Test_cart_GetListByUser(t *testing.T) {
 			},
 			mockFunc: func(mock mockFields, arg args) {
 				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(authUserMock, nil)
-				mock.cart.EXPECT().GetList(cartParamMock).Return([]entity.Cart{}, assert.AnError)
+				mock.cart.EXPECT().GetList(gomock.Any(), cartParamMock).Return([]entity.Cart{}, assert.AnError)
 			},
 			want:    []entity.Cart{},
 			wantErr: true,
@@ -506,8 +506,8 @@ func Test_cart_GetListByUser(t *testing.T) {
 			},
 			mockFunc: func(mock mockFields, arg args) {
 				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(authUserMock, nil)
-				mock.cart.EXPECT().GetList(cartParamMock).Return(cartResultMock, nil)
-				mock.menu.EXPECT().GetListInByID(menusIDsMock).Return([]entity.Menu{}, assert.AnError)
+				mock.cart.EXPECT().GetList(gomock.Any(), cartParamMock).Return(cartResultMock, nil)
+				mock.menu.EXPECT().GetListInByID(gomock.Any(), menusIDsMock).Return([]entity.Menu{}, assert.AnError)
 			},
 			want:    cartResultMock,
 			wantErr: true,
@@ -519,9 +519,9 @@ func Test_cart_GetListByUser(t *testing.T) {
 			},
 			mockFunc: func(mock mockFields, arg args) {
 				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(authUserMock, nil)
-				mock.cart.EXPECT().GetList(cartParamMock).Return(cartResultMock, nil)
-				mock.menu.EXPECT().GetListInByID(menusIDsMock).Return(menuResultMock, nil)
-				mock.umkm.EXPECT().GetListInByID(umkmIDsMock).Return([]entity.Umkm{}, assert.AnError)
+				mock.cart.EXPECT().GetList(gomock.Any(), cartParamMock).Return(cartResultMock, nil)
+				mock.menu.EXPECT().GetListInByID(gomock.Any(), menusIDsMock).Return(menuResultMock, nil)
+				mock.umkm.EXPECT().GetListInByID(gomock.Any(), umkmIDsMock).Return([]entity.Umkm{}, assert.AnError)
 			},
 			want:    cartResultMock,
 			wantErr: true,
@@ -533,9 +533,9 @@ func Test_cart_GetListByUser(t *testing.T) {
 			},
 			mockFunc: func(mock mockFields, arg args) {
 				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(authUserMock, nil)
-				mock.cart.EXPECT().GetList(cartParamMock).Return(cartResultMock, nil)
-				mock.menu.EXPECT().GetListInByID(menusIDsMock).Return(menuResultMock, nil)
-				mock.umkm.EXPECT().GetListInByID(umkmIDsMock).Return(umkmResultMock, nil)
+				mock.cart.EXPECT().GetList(gomock.Any(), cartParamMock).Return(cartResultMock, nil)
+				mock.menu.EXPECT().GetListInByID(gomock.Any(), menusIDsMock).Return(menuResultMock, nil)
+				mock.umkm.EXPECT().GetListInByID(gomock.Any(), umkmIDsMock).Return(umkmResultMock, nil)
 			},
 			want:    resultMock,
 			wantErr: false,
@@ -624,7 +624,7 @@ func Test_cart_GetCartCount(t *testing.T) {
 			},
 			mockFunc: func(mock mockFields, arg args) {
 				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(authUserMock, nil)
-				mock.cart.EXPECT().GetList(cartParamMock).Return([]entity.Cart{}, assert.AnError)
+				mock.cart.EXPECT().GetList(gomock.Any(), cartParamMock).Return([]entity.Cart{}, assert.AnError)
 			},
 			want:    0,
 			wantErr: true,
@@ -636,7 +636,7 @@ func Test_cart_GetCartCount(t *testing.T) {
 			},
 			mockFunc: func(mock mockFields, arg args) {
 				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(authUserMock, nil)
-				mock.cart.EXPECT().GetList(cartParamMock).Return(cartResultMock, nil)
+				mock.cart.EXPECT().GetList(gomock.Any(), cartParamMock).Return(cartResultMock, nil)
 			},
 			want:    resultMock,
 			wantErr: false,
@@ -693,7 +693,7 @@ func Test_cart_Delete(t *testing.T) {
 				param: paramMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.cart.EXPECT().Delete(paramMock).Return(assert.AnError)
+				mock.cart.EXPECT().Delete(gomock.Any(), paramMock).Return(assert.AnError)
 			},
 			wantErr: true,
 		},
@@ -704,7 +704,7 @@ func Test_cart_Delete(t *testing.T) {
 				param: paramMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.cart.EXPECT().Delete(paramMock).Return(nil)
+				mock.cart.EXPECT().Delete(gomock.Any(), paramMock).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -765,7 +765,7 @@ func Test_cart_ValidateCart(t *testing.T) {
 				guestID: "1",
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.cart.EXPECT().Get(cartParamMock).Return(entity.Cart{}, assert.AnError)
+				mock.cart.EXPECT().Get(gomock.Any(), cartParamMock).Return(entity.Cart{}, assert.AnError)
 			},
 			wantErr: true,
 		},
@@ -777,7 +777,7 @@ func Test_cart_ValidateCart(t *testing.T) {
 				guestID: "2",
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.cart.EXPECT().Get(cartParamMock).Return(cartResultMock, nil)
+				mock.cart.EXPECT().Get(gomock.Any(), cartParamMock).Return(cartResultMock, nil)
 			},
 			wantErr: true,
 		},
@@ -789,7 +789,7 @@ func Test_cart_ValidateCart(t *testing.T) {
 				guestID: "1",
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.cart.EXPECT().Get(cartParamMock).Return(cartResultMock, nil)
+				mock.cart.EXPECT().Get(gomock.Any(), cartParamMock).Return(cartResultMock, nil)
 			},
 			wantErr: false,
 		},
@@ -2,37 +2,79 @@ package menu
 
 import (
 	"context"
-	"errors"
+	cartDom "go-clean/src/business/domain/cart"
 	menuDom "go-clean/src/business/domain/menu"
 	"go-clean/src/business/entity"
+	"go-clean/src/lib/apperr"
 	"go-clean/src/lib/auth"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrMenuIDRequired = apperr.Validation("menu_id_required", "menu id wajib diisi")
+	ErrMenuForbidden  = apperr.Forbidden("menu_forbidden", "tidak memiliki akses ke menu ini")
+)
+
+const (
+	bestSellerCount    = 3
+	bestSellerDays     = 30
+	bestSellerCacheTTL = 10 * time.Minute
 )
 
 type Interface interface {
-	Create(inputParam entity.CreateMenuParam, menuParam entity.MenuParam) (entity.Menu, error)
-	GetAll(param entity.MenuParam) ([]entity.Menu, error)
-	Get(params entity.MenuParam) (entity.Menu, error)
-	Update(param entity.MenuParam, inputParam entity.UpdateMenuParam) error
-	Delete(param entity.MenuParam) error
+	Create(ctx context.Context, inputParam entity.CreateMenuParam, menuParam entity.MenuParam) (entity.Menu, error)
+	GetAll(ctx context.Context, param entity.MenuParam) ([]entity.Menu, entity.Pagination, error)
+	Get(ctx context.Context, params entity.MenuParam) (entity.Menu, error)
+	Update(ctx context.Context, param entity.MenuParam, inputParam entity.UpdateMenuParam) error
+	Delete(ctx context.Context, param entity.MenuParam) error
 	ValidateMenu(ctx context.Context, menuID uint, user auth.UserAuthInfo) error
 	SaveImage(ctx context.Context, param entity.MenuParam, fileLocation string) error
 }
 
+var menuSortOptions = entity.SortOptions{
+	Columns: map[string]string{
+		"id":         "id",
+		"name":       "name",
+		"price":      "price",
+		"created_at": "created_at",
+	},
+	Default:      "id",
+	DefaultOrder: entity.SortAsc,
+	TieBreaker:   "id",
+}
+
 type menu struct {
-	menu menuDom.Interface
+	menu       menuDom.Interface
+	cart       cartDom.Interface
+	pagination entity.PaginationConfig
+
+	mu          sync.Mutex
+	bestSellers map[uint]bestSellerCache
 }
 
-func Init(md menuDom.Interface) Interface {
+// bestSellerCache holds the best sellers of a menu list for bestSellerCacheTTL so the public menu list doesn't
+// aggregate carts on every request.
+type bestSellerCache struct {
+	menuIDs   map[uint]bool
+	expiresAt time.Time
+}
+
+func Init(md menuDom.Interface, cd cartDom.Interface, pagination entity.PaginationConfig) Interface {
 	m := &menu{
-		menu: md,
+		menu:        md,
+		cart:        cd,
+		pagination:  pagination,
+		bestSellers: make(map[uint]bestSellerCache),
 	}
 
 	return m
 }
 
-func (m *menu) Create(inputParam entity.CreateMenuParam, menuParam entity.MenuParam) (entity.Menu, error) {
+func (m *menu) Create(ctx context.Context, inputParam entity.CreateMenuParam, menuParam entity.MenuParam) (entity.Menu, error) {
 	isReady := true
-	menu, err := m.menu.Create(entity.Menu{
+	menu, err := m.menu.Create(ctx, entity.Menu{
 		Name:        inputParam.Name,
 		Description: inputParam.Description,
 		Price:       inputParam.Price,
@@ -46,17 +88,91 @@ func (m *menu) Create(inputParam entity.CreateMenuParam, menuParam entity.MenuPa
 	return menu, nil
 }
 
-func (m *menu) GetAll(param entity.MenuParam) ([]entity.Menu, error) {
-	menus, err := m.menu.GetAll(param)
+func (m *menu) GetAll(ctx context.Context, param entity.MenuParam) ([]entity.Menu, entity.Pagination, error) {
+	menus := []entity.Menu{}
+
+	paginationParam, err := param.PaginationParam.Paginate(m.pagination, menuSortOptions)
+	if err != nil {
+		return menus, entity.Pagination{}, err
+	}
+	param.PaginationParam = paginationParam
+
+	total, err := m.menu.Count(ctx, param)
+	if err != nil {
+		return menus, entity.Pagination{}, err
+	}
+
+	menus, err = m.menu.GetAll(ctx, param)
+	if err != nil {
+		return menus, entity.Pagination{}, err
+	}
+
+	bestSellers, err := m.getBestSellers(ctx, param.UmkmID)
 	if err != nil {
-		return menus, err
+		return menus, entity.Pagination{}, err
+	}
+
+	for i := range menus {
+		menus[i].IsBestSeller = bestSellers[menus[i].ID]
+	}
+
+	pagination := entity.NewPagination(param.PaginationParam, total)
+	pagination.SetNextCursor(param.PaginationParam, menuSortOptions, menus)
+
+	return menus, pagination, nil
+}
+
+// getBestSellers marks the most sold menus of each UMKM over the last bestSellerDays days.
+func (m *menu) getBestSellers(ctx context.Context, umkmID uint) (map[uint]bool, error) {
+	result := make(map[uint]bool)
+
+	now := time.Now()
+	m.mu.Lock()
+	cached, ok := m.bestSellers[umkmID]
+	m.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.menuIDs, nil
+	}
+
+	menuAggregates, err := m.cart.GetMenuAggregate(ctx, entity.MenuAggregateParam{
+		UmkmID: umkmID,
+		Range: entity.TimeRange{
+			Start: now.AddDate(0, 0, -bestSellerDays),
+			End:   now,
+		},
+	})
+	if err != nil {
+		return result, err
+	}
+
+	umkmMenus := make(map[uint][]entity.MenuAggregate)
+	for _, ma := range menuAggregates {
+		if ma.TotalQuantity > 0 {
+			umkmMenus[ma.UmkmID] = append(umkmMenus[ma.UmkmID], ma)
+		}
+	}
+
+	for _, menus := range umkmMenus {
+		sort.Slice(menus, func(i, j int) bool {
+			return menus[i].TotalQuantity > menus[j].TotalQuantity
+		})
+		for i := 0; i < len(menus) && i < bestSellerCount; i++ {
+			result[menus[i].MenuID] = true
+		}
+	}
+
+	m.mu.Lock()
+	m.bestSellers[umkmID] = bestSellerCache{
+		menuIDs:   result,
+		expiresAt: now.Add(bestSellerCacheTTL),
 	}
+	m.mu.Unlock()
 
-	return menus, nil
+	return result, nil
 }
 
-func (m *menu) Get(params entity.MenuParam) (entity.Menu, error) {
-	menu, err := m.menu.Get(params)
+func (m *menu) Get(ctx context.Context, params entity.MenuParam) (entity.Menu, error) {
+	menu, err := m.menu.Get(ctx, params)
 	if err != nil {
 		return menu, err
 	}
@@ -64,13 +180,13 @@ func (m *menu) Get(params entity.MenuParam) (entity.Menu, error) {
 	return menu, nil
 }
 
-func (m *menu) Update(param entity.MenuParam, inputParam entity.UpdateMenuParam) error {
-	menu, err := m.menu.Get(param)
+func (m *menu) Update(ctx context.Context, param entity.MenuParam, inputParam entity.UpdateMenuParam) error {
+	menu, err := m.menu.Get(ctx, param)
 	if err != nil {
 		return err
 	}
 
-	if err := m.menu.Update(entity.MenuParam{
+	if err := m.menu.Update(ctx, entity.MenuParam{
 		ID: menu.ID,
 	}, inputParam); err != nil {
 		return err
@@ -79,8 +195,8 @@ func (m *menu) Update(param entity.MenuParam, inputParam entity.UpdateMenuParam)
 	return nil
 }
 
-func (m *menu) Delete(param entity.MenuParam) error {
-	if err := m.menu.Delete(param); err != nil {
+func (m *menu) Delete(ctx context.Context, param entity.MenuParam) error {
+	if err := m.menu.Delete(ctx, param); err != nil {
 		return err
 	}
 
@@ -89,32 +205,32 @@ func (m *menu) Delete(param entity.MenuParam) error {
 
 func (m *menu) ValidateMenu(ctx context.Context, menuID uint, user auth.UserAuthInfo) error {
 	if menuID == 0 {
-		return errors.New("please provide menu id")
+		return ErrMenuIDRequired
 	}
 
-	menu, err := m.menu.Get(entity.MenuParam{
+	menu, err := m.menu.Get(ctx, entity.MenuParam{
 		ID: menuID,
 	})
 	if err != nil {
 		return err
 	}
 
-	if !user.User.IsAdmin && menu.UmkmID != user.User.UmkmID {
-		return errors.New("unauthorized")
+	if !user.User.IsPlatformAdmin() && menu.UmkmID != user.User.UmkmID {
+		return ErrMenuForbidden
 	}
 
 	return nil
 }
 
 func (m *menu) SaveImage(ctx context.Context, param entity.MenuParam, fileLocation string) error {
-	menu, err := m.menu.Get(entity.MenuParam{
+	menu, err := m.menu.Get(ctx, entity.MenuParam{
 		ID: param.ID,
 	})
 	if err != nil {
 		return err
 	}
 
-	if err := m.menu.Update(entity.MenuParam{
+	if err := m.menu.Update(ctx, entity.MenuParam{
 		ID: menu.ID,
 	}, entity.UpdateMenuParam{
 		ImgPath: fileLocation,
@@ -2,6 +2,7 @@ package menu_test
 
 import (
 	"context"
+	mock_cart "go-clean/src/business/domain/mock/cart"
 	mock_menu "go-clean/src/business/domain/mock/menu"
 	"go-clean/src/business/entity"
 	"go-clean/src/business/usecase/menu"
@@ -18,6 +19,7 @@ func Test_menu_Create(t *testing.T) {
 	defer ctrl.Finish()
 
 	menuMock := mock_menu.NewMockInterface(ctrl)
+	cartMock := mock_cart.NewMockInterface(ctrl)
 
 	createMenuParamMock := entity.CreateMenuParam{
 		Name:        "menu",
@@ -42,7 +44,7 @@ func Test_menu_Create(t *testing.T) {
 		},
 	}
 
-	m := menu.Init(menuMock)
+	m := menu.Init(menuMock, cartMock, entity.PaginationConfig{})
 
 	type mockFields struct {
 		menu *mock_menu.MockInterface
@@ -71,7 +73,7 @@ func Test_menu_Create(t *testing.T) {
 				menuParam:  menuParamMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.menu.EXPECT().Create(newMenuMock).Return(entity.Menu{}, assert.AnError)
+				mock.menu.EXPECT().Create(gomock.Any(), newMenuMock).Return(entity.Menu{}, assert.AnError)
 			},
 			want:    entity.Menu{},
 			wantErr: true,
@@ -83,7 +85,7 @@ func Test_menu_Create(t *testing.T) {
 				menuParam:  menuParamMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.menu.EXPECT().Create(newMenuMock).Return(menuResultMock, nil)
+				mock.menu.EXPECT().Create(gomock.Any(), newMenuMock).Return(menuResultMock, nil)
 			},
 			want:    menuResultMock,
 			wantErr: false,
@@ -92,7 +94,7 @@ func Test_menu_Create(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc(mocks, tt.args)
-			got, err := m.Create(tt.args.inputParam, tt.args.menuParam)
+			got, err := m.Create(context.Background(), tt.args.inputParam, tt.args.menuParam)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("menu.Create() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -107,23 +109,73 @@ func Test_menu_GetAll(t *testing.T) {
 	defer ctrl.Finish()
 
 	menuMock := mock_menu.NewMockInterface(ctrl)
+	cartMock := mock_cart.NewMockInterface(ctrl)
 
 	menuParamMock := entity.MenuParam{}
+	menuListParamMock := entity.MenuParam{
+		PaginationParam: entity.PaginationParam{
+			Page:      1,
+			Limit:     20,
+			SortBy:    "id",
+			SortOrder: entity.SortAsc,
+			OrderBy:   "id asc",
+		},
+	}
 
 	menuResultMock := []entity.Menu{
 		{
+			Model: gorm.Model{
+				ID: 1,
+			},
 			Name: "menu",
 		},
+		{
+			Model: gorm.Model{
+				ID: 2,
+			},
+			Name: "menu 2",
+		},
 	}
 
-	m := menu.Init(menuMock)
+	menuAggregateResultMock := []entity.MenuAggregate{
+		{
+			UmkmID:        1,
+			MenuID:        1,
+			TotalQuantity: 10,
+		},
+		{
+			UmkmID:        1,
+			MenuID:        2,
+			TotalQuantity: 0,
+		},
+	}
+
+	wantMock := []entity.Menu{
+		{
+			Model: gorm.Model{
+				ID: 1,
+			},
+			Name:         "menu",
+			IsBestSeller: true,
+		},
+		{
+			Model: gorm.Model{
+				ID: 2,
+			},
+			Name: "menu 2",
+		},
+	}
+
+	m := menu.Init(menuMock, cartMock, entity.PaginationConfig{})
 
 	type mockFields struct {
 		menu *mock_menu.MockInterface
+		cart *mock_cart.MockInterface
 	}
 
 	mocks := mockFields{
 		menu: menuMock,
+		cart: cartMock,
 	}
 
 	type args struct {
@@ -131,44 +183,110 @@ func Test_menu_GetAll(t *testing.T) {
 	}
 
 	tests := []struct {
-		name     string
-		args     args
-		mockFunc func(mock mockFields, arg args)
-		want     []entity.Menu
-		wantErr  bool
+		name           string
+		args           args
+		mockFunc       func(mock mockFields, arg args)
+		want           []entity.Menu
+		wantPagination entity.Pagination
+		wantErr        bool
 	}{
+		{
+			name: "invalid sort order",
+			args: args{
+				param: entity.MenuParam{
+					PaginationParam: entity.PaginationParam{
+						SortOrder: "up",
+					},
+				},
+			},
+			mockFunc: func(mock mockFields, arg args) {},
+			want:     []entity.Menu{},
+			wantErr:  true,
+		},
+		{
+			name: "failed to count menu",
+			args: args{
+				param: menuParamMock,
+			},
+			mockFunc: func(mock mockFields, arg args) {
+				mock.menu.EXPECT().Count(gomock.Any(), menuListParamMock).Return(int64(0), assert.AnError)
+			},
+			want:    []entity.Menu{},
+			wantErr: true,
+		},
 		{
 			name: "failed to get all menu",
 			args: args{
 				param: menuParamMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.menu.EXPECT().GetAll(menuParamMock).Return([]entity.Menu{}, assert.AnError)
+				mock.menu.EXPECT().Count(gomock.Any(), menuListParamMock).Return(int64(2), nil)
+				mock.menu.EXPECT().GetAll(gomock.Any(), menuListParamMock).Return([]entity.Menu{}, assert.AnError)
 			},
 			want:    []entity.Menu{},
 			wantErr: true,
 		},
 		{
-			name: "all success",
+			name: "failed to get menu aggregate",
 			args: args{
 				param: menuParamMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.menu.EXPECT().GetAll(menuParamMock).Return(menuResultMock, nil)
+				mock.menu.EXPECT().Count(gomock.Any(), menuListParamMock).Return(int64(2), nil)
+				mock.menu.EXPECT().GetAll(gomock.Any(), menuListParamMock).Return(menuResultMock, nil)
+				mock.cart.EXPECT().GetMenuAggregate(gomock.Any(), gomock.Any()).Return([]entity.MenuAggregate{}, assert.AnError)
 			},
 			want:    menuResultMock,
+			wantErr: true,
+		},
+		{
+			name: "all success",
+			args: args{
+				param: menuParamMock,
+			},
+			mockFunc: func(mock mockFields, arg args) {
+				mock.menu.EXPECT().Count(gomock.Any(), menuListParamMock).Return(int64(2), nil)
+				mock.menu.EXPECT().GetAll(gomock.Any(), menuListParamMock).Return(menuResultMock, nil)
+				mock.cart.EXPECT().GetMenuAggregate(gomock.Any(), gomock.Any()).Return(menuAggregateResultMock, nil)
+			},
+			want: wantMock,
+			wantPagination: entity.Pagination{
+				Page:       1,
+				Limit:      20,
+				TotalItems: 2,
+				TotalPages: 1,
+			},
+			wantErr: false,
+		},
+		{
+			name: "best sellers from cache",
+			args: args{
+				param: menuParamMock,
+			},
+			mockFunc: func(mock mockFields, arg args) {
+				mock.menu.EXPECT().Count(gomock.Any(), menuListParamMock).Return(int64(2), nil)
+				mock.menu.EXPECT().GetAll(gomock.Any(), menuListParamMock).Return(menuResultMock, nil)
+			},
+			want: wantMock,
+			wantPagination: entity.Pagination{
+				Page:       1,
+				Limit:      20,
+				TotalItems: 2,
+				TotalPages: 1,
+			},
 			wantErr: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc(mocks, tt.args)
-			got, err := m.GetAll(tt.args.param)
+			got, pagination, err := m.GetAll(context.Background(), tt.args.param)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("menu.GetAll() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantPagination, pagination)
 		})
 	}
 }
@@ -178,6 +296,7 @@ func Test_menu_Get(t *testing.T) {
 	defer ctrl.Finish()
 
 	menuMock := mock_menu.NewMockInterface(ctrl)
+	cartMock := mock_cart.NewMockInterface(ctrl)
 
 	menuParamMock := entity.MenuParam{
 		ID: 1,
@@ -187,7 +306,7 @@ func Test_menu_Get(t *testing.T) {
 		Name: "menu",
 	}
 
-	m := menu.Init(menuMock)
+	m := menu.Init(menuMock, cartMock, entity.PaginationConfig{})
 
 	type mockFields struct {
 		menu *mock_menu.MockInterface
@@ -214,7 +333,7 @@ func Test_menu_Get(t *testing.T) {
 				param: menuParamMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.menu.EXPECT().Get(menuParamMock).Return(entity.Menu{}, assert.AnError)
+				mock.menu.EXPECT().Get(gomock.Any(), menuParamMock).Return(entity.Menu{}, assert.AnError)
 			},
 			want:    entity.Menu{},
 			wantErr: true,
@@ -225,7 +344,7 @@ func Test_menu_Get(t *testing.T) {
 				param: menuParamMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.menu.EXPECT().Get(menuParamMock).Return(menuResultMock, nil)
+				mock.menu.EXPECT().Get(gomock.Any(), menuParamMock).Return(menuResultMock, nil)
 			},
 			want:    menuResultMock,
 			wantErr: false,
@@ -233,7 +352,7 @@ func Test_menu_Get(t *testing.T) {
 	}
 	for _, tt := range tests {
 		tt.mockFunc(mocks, tt.args)
-		got, err := m.Get(tt.args.param)
+		got, err := m.Get(context.Background(), tt.args.param)
 		if (err != nil) != tt.wantErr {
 			t.Errorf("menu.Get() error = %v, wantErr %v", err, tt.wantErr)
 			return
@@ -247,6 +366,7 @@ func Test_menu_Update(t *testing.T) {
 	defer ctrl.Finish()
 
 	menuMock := mock_menu.NewMockInterface(ctrl)
+	cartMock := mock_cart.NewMockInterface(ctrl)
 
 	menuParamMock := entity.MenuParam{
 		ID: 1,
@@ -263,7 +383,7 @@ func Test_menu_Update(t *testing.T) {
 		Name: "new menu",
 	}
 
-	m := menu.Init(menuMock)
+	m := menu.Init(menuMock, cartMock, entity.PaginationConfig{})
 
 	type mockFields struct {
 		menu *mock_menu.MockInterface
@@ -291,7 +411,7 @@ func Test_menu_Update(t *testing.T) {
 				inputParam: updateMenuParamMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.menu.EXPECT().Get(menuParamMock).Return(entity.Menu{}, assert.AnError)
+				mock.menu.EXPECT().Get(gomock.Any(), menuParamMock).Return(entity.Menu{}, assert.AnError)
 			},
 			wantErr: true,
 		},
@@ -302,8 +422,8 @@ func Test_menu_Update(t *testing.T) {
 				inputParam: updateMenuParamMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.menu.EXPECT().Get(menuParamMock).Return(menuResultMock, nil)
-				mock.menu.EXPECT().Update(menuParamMock, updateMenuParamMock).Return(assert.AnError)
+				mock.menu.EXPECT().Get(gomock.Any(), menuParamMock).Return(menuResultMock, nil)
+				mock.menu.EXPECT().Update(gomock.Any(), menuParamMock, updateMenuParamMock).Return(assert.AnError)
 			},
 			wantErr: true,
 		},
@@ -314,8 +434,8 @@ func Test_menu_Update(t *testing.T) {
 				inputParam: updateMenuParamMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.menu.EXPECT().Get(menuParamMock).Return(menuResultMock, nil)
-				mock.menu.EXPECT().Update(menuParamMock, updateMenuParamMock).Return(nil)
+				mock.menu.EXPECT().Get(gomock.Any(), menuParamMock).Return(menuResultMock, nil)
+				mock.menu.EXPECT().Update(gomock.Any(), menuParamMock, updateMenuParamMock).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -323,7 +443,7 @@ func Test_menu_Update(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc(mocks, tt.args)
-			err := m.Update(tt.args.param, tt.args.inputParam)
+			err := m.Update(context.Background(), tt.args.param, tt.args.inputParam)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("menu.Get() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -337,12 +457,13 @@ func Test_menu_Delete(t *testing.T) {
 	defer ctrl.Finish()
 
 	menuMock := mock_menu.NewMockInterface(ctrl)
+	cartMock := mock_cart.NewMockInterface(ctrl)
 
 	menuParamMock := entity.MenuParam{
 		ID: 1,
 	}
 
-	m := menu.Init(menuMock)
+	m := menu.Init(menuMock, cartMock, entity.PaginationConfig{})
 
 	type mockFields struct {
 		menu *mock_menu.MockInterface
@@ -368,7 +489,7 @@ func Test_menu_Delete(t *testing.T) {
 				param: menuParamMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.menu.EXPECT().Delete(menuParamMock).Return(assert.AnError)
+				mock.menu.EXPECT().Delete(gomock.Any(), menuParamMock).Return(assert.AnError)
 			},
 			wantErr: true,
 		},
@@ -378,7 +499,7 @@ func Test_menu_Delete(t *testing.T) {
 				param: menuParamMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.menu.EXPECT().Delete(menuParamMock).Return(nil)
+				mock.menu.EXPECT().Delete(gomock.Any(), menuParamMock).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -386,7 +507,7 @@ func Test_menu_Delete(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc(mocks, tt.args)
-			err := m.Delete(tt.args.param)
+			err := m.Delete(context.Background(), tt.args.param)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("menu.Delete() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -400,11 +521,13 @@ func Test_menu_ValidateMenu(t *testing.T) {
 	defer ctrl.Finish()
 
 	menuMock := mock_menu.NewMockInterface(ctrl)
+	cartMock := mock_cart.NewMockInterface(ctrl)
 
 	userAuthAdminMock := auth.UserAuthInfo{
 		User: auth.User{
 			IsAdmin: true,
 			UmkmID:  1,
+			Role:    auth.RoleSuperAdmin,
 		},
 	}
 
@@ -412,6 +535,7 @@ func Test_menu_ValidateMenu(t *testing.T) {
 		User: auth.User{
 			IsAdmin: false,
 			UmkmID:  1,
+			Role:    auth.RoleTenantOwner,
 		},
 	}
 
@@ -431,7 +555,7 @@ func Test_menu_ValidateMenu(t *testing.T) {
 		UmkmID: 2,
 	}
 
-	m := menu.Init(menuMock)
+	m := menu.Init(menuMock, cartMock, entity.PaginationConfig{})
 
 	type mockFields struct {
 		menu *mock_menu.MockInterface
@@ -471,7 +595,7 @@ func Test_menu_ValidateMenu(t *testing.T) {
 				user:   userAuthMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.menu.EXPECT().Get(menuParamMock).Return(entity.Menu{}, assert.AnError)
+				mock.menu.EXPECT().Get(gomock.Any(), menuParamMock).Return(entity.Menu{}, assert.AnError)
 			},
 			wantErr: true,
 		},
@@ -483,7 +607,7 @@ func Test_menu_ValidateMenu(t *testing.T) {
 				user:   userAuthMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.menu.EXPECT().Get(menuParam2Mock).Return(menuResult2Mock, nil)
+				mock.menu.EXPECT().Get(gomock.Any(), menuParam2Mock).Return(menuResult2Mock, nil)
 			},
 			wantErr: true,
 		},
@@ -495,7 +619,19 @@ func Test_menu_ValidateMenu(t *testing.T) {
 				user:   userAuthAdminMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.menu.EXPECT().Get(menuParamMock).Return(menuResultMock, nil)
+				mock.menu.EXPECT().Get(gomock.Any(), menuParamMock).Return(menuResultMock, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "success super admin on other umkm menu",
+			args: args{
+				ctx:    context.Background(),
+				menuID: 2,
+				user:   userAuthAdminMock,
+			},
+			mockFunc: func(mock mockFields, arg args) {
+				mock.menu.EXPECT().Get(gomock.Any(), menuParam2Mock).Return(menuResult2Mock, nil)
 			},
 			wantErr: false,
 		},
@@ -1,18 +1,35 @@
 package midtranstransaction
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	cartDom "go-clean/src/business/domain/cart"
 	midtransDom "go-clean/src/business/domain/midtrans"
 	midtransTransactionDom "go-clean/src/business/domain/midtrans_transaction"
 	"go-clean/src/business/entity"
+	"go-clean/src/lib/apperr"
+	"go-clean/src/lib/metrics"
+	"go-clean/src/lib/tracing"
+	"time"
+)
+
+var (
+	ErrOrderIDMissing     = apperr.Validation("order_id_missing", "order id tidak ditemukan pada notifikasi")
+	ErrPaymentCheckFailed = apperr.Upstream("payment_check_failed", "gagal memeriksa status pembayaran, coba lagi nanti")
+)
+
+const (
+	webhookOutcomeProcessed   = "processed"
+	webhookOutcomeIgnored     = "ignored"
+	webhookOutcomeInvalid     = "invalid"
+	webhookOutcomeCheckFailed = "check_failed"
+	webhookOutcomeError       = "error"
 )
 
 type Interface interface {
-	GetPaymentDetail(param entity.MidtransTransactionParam) (entity.MidtransTransactionPaymentDetail, error)
-	HandleNotification(payload map[string]interface{}) error
-	MarkAsPaid(param entity.MidtransTransactionParam) error
+	GetPaymentDetail(ctx context.Context, param entity.MidtransTransactionParam) (entity.MidtransTransactionPaymentDetail, error)
+	HandleNotification(ctx context.Context, payload map[string]interface{}) error
+	MarkAsPaid(ctx context.Context, param entity.MidtransTransactionParam) error
 }
 
 type midtransTransaction struct {
@@ -31,10 +48,10 @@ func Init(mttd midtransTransactionDom.Interface, md midtransDom.Interface, cd ca
 	return mtt
 }
 
-func (mtt *midtransTransaction) GetPaymentDetail(param entity.MidtransTransactionParam) (entity.MidtransTransactionPaymentDetail, error) {
+func (mtt *midtransTransaction) GetPaymentDetail(ctx context.Context, param entity.MidtransTransactionParam) (entity.MidtransTransactionPaymentDetail, error) {
 	result := entity.MidtransTransactionPaymentDetail{}
 
-	midtransTransaction, err := mtt.midtransTransaction.Get(param)
+	midtransTransaction, err := mtt.midtransTransaction.Get(ctx, param)
 	if err != nil {
 		return result, err
 	}
@@ -51,18 +68,28 @@ func (mtt *midtransTransaction) GetPaymentDetail(param entity.MidtransTransactio
 	return result, nil
 }
 
-func (mtt *midtransTransaction) HandleNotification(payload map[string]interface{}) error {
+func (mtt *midtransTransaction) HandleNotification(ctx context.Context, payload map[string]interface{}) error {
+	ctx, span := tracing.Start(ctx, "midtransTransaction.HandleNotification")
+	defer span.End()
+
+	outcome := webhookOutcomeError
+	defer func() {
+		metrics.WebhookNotifications.WithLabelValues(outcome).Inc()
+	}()
+
 	orderId, exist := payload["order_id"].(string)
 	if !exist {
-		return errors.New("order id not exist")
+		outcome = webhookOutcomeInvalid
+		return ErrOrderIDMissing
 	}
 
-	transactionResponse, err := mtt.midtrans.HandleNotification(orderId)
+	transactionResponse, err := mtt.midtrans.HandleNotification(ctx, orderId)
 	if err != nil {
-		return err
+		outcome = webhookOutcomeCheckFailed
+		return ErrPaymentCheckFailed.Wrap(err)
 	}
 
-	midtransTransaction, err := mtt.midtransTransaction.Get(entity.MidtransTransactionParam{
+	midtransTransaction, err := mtt.midtransTransaction.Get(ctx, entity.MidtransTransactionParam{
 		OrderID: orderId,
 	})
 	if err != nil {
@@ -98,49 +125,71 @@ func (mtt *midtransTransaction) HandleNotification(payload map[string]interface{
 		}
 	}
 
-	if err := mtt.midtransTransaction.Update(entity.MidtransTransactionParam{
-		ID: midtransTransaction.ID,
-	}, entity.UpdateMidtransTransactionParam{
-		Status: status,
-	}); err != nil {
-		return err
+	// midtrans retries and repeats notifications, only a status change is stored and counted
+	changed := status != "" && status != midtransTransaction.Status
+	if changed {
+		if err := mtt.midtransTransaction.Update(ctx, entity.MidtransTransactionParam{
+			ID: midtransTransaction.ID,
+		}, entity.UpdateMidtransTransactionParam{
+			Status: status,
+		}); err != nil {
+			return err
+		}
+
+		metrics.Payments.WithLabelValues(midtransTransaction.GetPaymentType(), status).Inc()
 	}
 
 	if status == entity.StatusSuccess {
-		if err := mtt.cart.Update(entity.CartParam{
+		paidAt := time.Now()
+		if err := mtt.cart.Update(ctx, entity.CartParam{
 			Status:        entity.StatusUnpaid,
 			TransactionID: midtransTransaction.TransactionID,
 		}, entity.UpdateCartParam{
 			Status: entity.StatusPaid,
+			PaidAt: &paidAt,
 		}); err != nil {
 			return err
 		}
 	}
 
+	outcome = webhookOutcomeProcessed
+	if !changed {
+		outcome = webhookOutcomeIgnored
+	}
+
 	return nil
 }
 
-func (mtt *midtransTransaction) MarkAsPaid(param entity.MidtransTransactionParam) error {
-	midtransTransaction, err := mtt.midtransTransaction.Get(entity.MidtransTransactionParam{
+func (mtt *midtransTransaction) MarkAsPaid(ctx context.Context, param entity.MidtransTransactionParam) error {
+	ctx, span := tracing.Start(ctx, "midtransTransaction.MarkAsPaid")
+	defer span.End()
+
+	midtransTransaction, err := mtt.midtransTransaction.Get(ctx, entity.MidtransTransactionParam{
 		OrderID: param.OrderID,
 	})
 	if err != nil {
 		return err
 	}
 
-	if err := mtt.midtransTransaction.Update(entity.MidtransTransactionParam{
-		ID: midtransTransaction.ID,
-	}, entity.UpdateMidtransTransactionParam{
-		Status: entity.StatusSuccess,
-	}); err != nil {
-		return err
+	if midtransTransaction.Status != entity.StatusSuccess {
+		if err := mtt.midtransTransaction.Update(ctx, entity.MidtransTransactionParam{
+			ID: midtransTransaction.ID,
+		}, entity.UpdateMidtransTransactionParam{
+			Status: entity.StatusSuccess,
+		}); err != nil {
+			return err
+		}
+
+		metrics.Payments.WithLabelValues(midtransTransaction.GetPaymentType(), entity.StatusSuccess).Inc()
 	}
 
-	if err := mtt.cart.Update(entity.CartParam{
+	paidAt := time.Now()
+	if err := mtt.cart.Update(ctx, entity.CartParam{
 		Status:        entity.StatusUnpaid,
 		TransactionID: midtransTransaction.TransactionID,
 	}, entity.UpdateCartParam{
 		Status: entity.StatusPaid,
+		PaidAt: &paidAt,
 	}); err != nil {
 		return err
 	}
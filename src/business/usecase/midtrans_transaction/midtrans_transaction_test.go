@@ -1,15 +1,18 @@
 package midtranstransaction_test
 
 import (
+	"context"
 	"encoding/json"
 	mock_cart "go-clean/src/business/domain/mock/cart"
 	mock_midtrans "go-clean/src/business/domain/mock/midtrans"
 	mock_midtranstransaction "go-clean/src/business/domain/mock/midtrans_transaction"
 	"go-clean/src/business/entity"
 	midtranstransaction "go-clean/src/business/usecase/midtrans_transaction"
+	"go-clean/src/lib/metrics"
 	"testing"
 
 	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"gorm.io/gorm"
@@ -71,7 +74,7 @@ func Test_midtransTransaction_GetPaymentDetail(t *testing.T) {
 				param: midtransTransactionParamMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.midtrans_transaction.EXPECT().Get(midtransTransactionParamMock).Return(entity.MidtransTransaction{}, assert.AnError)
+				mock.midtrans_transaction.EXPECT().Get(gomock.Any(), midtransTransactionParamMock).Return(entity.MidtransTransaction{}, assert.AnError)
 			},
 			want:    entity.MidtransTransactionPaymentDetail{},
 			wantErr: true,
@@ -82,7 +85,7 @@ func Test_midtransTransaction_GetPaymentDetail(t *testing.T) {
 				param: midtransTransactionParamMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.midtrans_transaction.EXPECT().Get(midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
+				mock.midtrans_transaction.EXPECT().Get(gomock.Any(), midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
 			},
 			want:    resultMock,
 			wantErr: false,
@@ -91,7 +94,7 @@ func Test_midtransTransaction_GetPaymentDetail(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc(mocks, tt.args)
-			got, err := mt.GetPaymentDetail(tt.args.param)
+			got, err := mt.GetPaymentDetail(context.Background(), tt.args.param)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("midtransTransaction.Create() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -150,6 +153,12 @@ func Test_midtransTransaction_HandleNotification(t *testing.T) {
 		TransactionID: 1,
 	}
 
+	settledMidtransTransactionResultMock := midtransTransactionResultMock
+	settledMidtransTransactionResultMock.Status = entity.StatusSuccess
+
+	pendingMidtransTransactionResultMock := midtransTransactionResultMock
+	pendingMidtransTransactionResultMock.Status = entity.StatusPending
+
 	midtransTransactionUpdateParamMock := entity.MidtransTransactionParam{
 		ID: 1,
 	}
@@ -179,9 +188,7 @@ func Test_midtransTransaction_HandleNotification(t *testing.T) {
 		TransactionID: 1,
 	}
 
-	cartUpdateMock := entity.UpdateCartParam{
-		Status: entity.StatusPaid,
-	}
+	cartUpdateMock := paidCartMatcher{}
 
 	mt := midtranstransaction.Init(midtransTransactionMock, midtransMock, cartMock)
 
@@ -205,7 +212,9 @@ func Test_midtransTransaction_HandleNotification(t *testing.T) {
 		name     string
 		args     args
 		mockFunc func(mock mockFields, arg args)
-		wantErr  bool
+		// wantCounted is the payment status expected to be counted once
+		wantCounted string
+		wantErr     bool
 	}{
 		{
 			name: "failed to get order id",
@@ -222,7 +231,7 @@ func Test_midtransTransaction_HandleNotification(t *testing.T) {
 				payload: payloadMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.midtrans.EXPECT().HandleNotification("1").Return(nil, assert.AnError)
+				mock.midtrans.EXPECT().HandleNotification(gomock.Any(), "1").Return(nil, assert.AnError)
 			},
 			wantErr: true,
 		},
@@ -232,8 +241,8 @@ func Test_midtransTransaction_HandleNotification(t *testing.T) {
 				payload: payloadMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.midtrans.EXPECT().HandleNotification("1").Return(transactionResponseMock, nil)
-				mock.midtrans_transaction.EXPECT().Get(midtransTransactionParamMock).Return(entity.MidtransTransaction{}, assert.AnError)
+				mock.midtrans.EXPECT().HandleNotification(gomock.Any(), "1").Return(transactionResponseMock, nil)
+				mock.midtrans_transaction.EXPECT().Get(gomock.Any(), midtransTransactionParamMock).Return(entity.MidtransTransaction{}, assert.AnError)
 			},
 			wantErr: true,
 		},
@@ -243,9 +252,9 @@ func Test_midtransTransaction_HandleNotification(t *testing.T) {
 				payload: payloadMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.midtrans.EXPECT().HandleNotification("1").Return(transactionResponseMock, nil)
-				mock.midtrans_transaction.EXPECT().Get(midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
-				mock.midtrans_transaction.EXPECT().Update(midtransTransactionUpdateParamMock, midtransTransactionUpdateMock).Return(assert.AnError)
+				mock.midtrans.EXPECT().HandleNotification(gomock.Any(), "1").Return(transactionResponseMock, nil)
+				mock.midtrans_transaction.EXPECT().Get(gomock.Any(), midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
+				mock.midtrans_transaction.EXPECT().Update(gomock.Any(), midtransTransactionUpdateParamMock, midtransTransactionUpdateMock).Return(assert.AnError)
 			},
 			wantErr: true,
 		},
@@ -255,12 +264,13 @@ func Test_midtransTransaction_HandleNotification(t *testing.T) {
 				payload: payloadMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.midtrans.EXPECT().HandleNotification("1").Return(transactionResponseMock, nil)
-				mock.midtrans_transaction.EXPECT().Get(midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
-				mock.midtrans_transaction.EXPECT().Update(midtransTransactionUpdateParamMock, midtransTransactionUpdateMock).Return(nil)
-				mock.cart.EXPECT().Update(cartUpdateParamMock, cartUpdateMock).Return(assert.AnError)
+				mock.midtrans.EXPECT().HandleNotification(gomock.Any(), "1").Return(transactionResponseMock, nil)
+				mock.midtrans_transaction.EXPECT().Get(gomock.Any(), midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
+				mock.midtrans_transaction.EXPECT().Update(gomock.Any(), midtransTransactionUpdateParamMock, midtransTransactionUpdateMock).Return(nil)
+				mock.cart.EXPECT().Update(gomock.Any(), cartUpdateParamMock, cartUpdateMock).Return(assert.AnError)
 			},
-			wantErr: true,
+			wantCounted: entity.StatusSuccess,
+			wantErr:     true,
 		},
 		{
 			name: "all success",
@@ -268,12 +278,13 @@ func Test_midtransTransaction_HandleNotification(t *testing.T) {
 				payload: payloadMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.midtrans.EXPECT().HandleNotification("1").Return(transactionResponseMock, nil)
-				mock.midtrans_transaction.EXPECT().Get(midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
-				mock.midtrans_transaction.EXPECT().Update(midtransTransactionUpdateParamMock, midtransTransactionUpdateMock).Return(nil)
-				mock.cart.EXPECT().Update(cartUpdateParamMock, cartUpdateMock).Return(nil)
+				mock.midtrans.EXPECT().HandleNotification(gomock.Any(), "1").Return(transactionResponseMock, nil)
+				mock.midtrans_transaction.EXPECT().Get(gomock.Any(), midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
+				mock.midtrans_transaction.EXPECT().Update(gomock.Any(), midtransTransactionUpdateParamMock, midtransTransactionUpdateMock).Return(nil)
+				mock.cart.EXPECT().Update(gomock.Any(), cartUpdateParamMock, cartUpdateMock).Return(nil)
 			},
-			wantErr: false,
+			wantCounted: entity.StatusSuccess,
+			wantErr:     false,
 		},
 		{
 			name: "all success settlement",
@@ -281,12 +292,13 @@ func Test_midtransTransaction_HandleNotification(t *testing.T) {
 				payload: payloadMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.midtrans.EXPECT().HandleNotification("1").Return(transactionResponseSettlementMock, nil)
-				mock.midtrans_transaction.EXPECT().Get(midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
-				mock.midtrans_transaction.EXPECT().Update(midtransTransactionUpdateParamMock, midtransTransactionUpdateMock).Return(nil)
-				mock.cart.EXPECT().Update(cartUpdateParamMock, cartUpdateMock).Return(nil)
+				mock.midtrans.EXPECT().HandleNotification(gomock.Any(), "1").Return(transactionResponseSettlementMock, nil)
+				mock.midtrans_transaction.EXPECT().Get(gomock.Any(), midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
+				mock.midtrans_transaction.EXPECT().Update(gomock.Any(), midtransTransactionUpdateParamMock, midtransTransactionUpdateMock).Return(nil)
+				mock.cart.EXPECT().Update(gomock.Any(), cartUpdateParamMock, cartUpdateMock).Return(nil)
 			},
-			wantErr: false,
+			wantCounted: entity.StatusSuccess,
+			wantErr:     false,
 		},
 		{
 			name: "all success challenge",
@@ -294,11 +306,12 @@ func Test_midtransTransaction_HandleNotification(t *testing.T) {
 				payload: payloadMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.midtrans.EXPECT().HandleNotification("1").Return(transactionResponseChallengeMock, nil)
-				mock.midtrans_transaction.EXPECT().Get(midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
-				mock.midtrans_transaction.EXPECT().Update(midtransTransactionUpdateParamMock, midtransTransactionUpdateChallangeMock).Return(nil)
+				mock.midtrans.EXPECT().HandleNotification(gomock.Any(), "1").Return(transactionResponseChallengeMock, nil)
+				mock.midtrans_transaction.EXPECT().Get(gomock.Any(), midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
+				mock.midtrans_transaction.EXPECT().Update(gomock.Any(), midtransTransactionUpdateParamMock, midtransTransactionUpdateChallangeMock).Return(nil)
 			},
-			wantErr: false,
+			wantCounted: entity.StatusChallange,
+			wantErr:     false,
 		},
 		{
 			name: "all success deny",
@@ -306,11 +319,12 @@ func Test_midtransTransaction_HandleNotification(t *testing.T) {
 				payload: payloadMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.midtrans.EXPECT().HandleNotification("1").Return(transactionResponseDenyMock, nil)
-				mock.midtrans_transaction.EXPECT().Get(midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
-				mock.midtrans_transaction.EXPECT().Update(midtransTransactionUpdateParamMock, midtransTransactionUpdateDenyMock).Return(nil)
+				mock.midtrans.EXPECT().HandleNotification(gomock.Any(), "1").Return(transactionResponseDenyMock, nil)
+				mock.midtrans_transaction.EXPECT().Get(gomock.Any(), midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
+				mock.midtrans_transaction.EXPECT().Update(gomock.Any(), midtransTransactionUpdateParamMock, midtransTransactionUpdateDenyMock).Return(nil)
 			},
-			wantErr: false,
+			wantCounted: entity.StatusDeny,
+			wantErr:     false,
 		},
 		{
 			name: "all success cancel",
@@ -318,11 +332,12 @@ func Test_midtransTransaction_HandleNotification(t *testing.T) {
 				payload: payloadMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.midtrans.EXPECT().HandleNotification("1").Return(transactionResponseCancelMock, nil)
-				mock.midtrans_transaction.EXPECT().Get(midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
-				mock.midtrans_transaction.EXPECT().Update(midtransTransactionUpdateParamMock, midtransTransactionUpdateFailureMock).Return(nil)
+				mock.midtrans.EXPECT().HandleNotification(gomock.Any(), "1").Return(transactionResponseCancelMock, nil)
+				mock.midtrans_transaction.EXPECT().Get(gomock.Any(), midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
+				mock.midtrans_transaction.EXPECT().Update(gomock.Any(), midtransTransactionUpdateParamMock, midtransTransactionUpdateFailureMock).Return(nil)
 			},
-			wantErr: false,
+			wantCounted: entity.StatusFailure,
+			wantErr:     false,
 		},
 		{
 			name: "all success pending",
@@ -330,21 +345,77 @@ func Test_midtransTransaction_HandleNotification(t *testing.T) {
 				payload: payloadMock,
 			},
 			mockFunc: func(mock mockFields, arg args) {
-				mock.midtrans.EXPECT().HandleNotification("1").Return(transactionResponsePendingMock, nil)
-				mock.midtrans_transaction.EXPECT().Get(midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
-				mock.midtrans_transaction.EXPECT().Update(midtransTransactionUpdateParamMock, midtransTransactionUpdatePendingMock).Return(nil)
+				mock.midtrans.EXPECT().HandleNotification(gomock.Any(), "1").Return(transactionResponsePendingMock, nil)
+				mock.midtrans_transaction.EXPECT().Get(gomock.Any(), midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
+				mock.midtrans_transaction.EXPECT().Update(gomock.Any(), midtransTransactionUpdateParamMock, midtransTransactionUpdatePendingMock).Return(nil)
+			},
+			wantCounted: entity.StatusPending,
+			wantErr:     false,
+		},
+		{
+			name: "repeated notification keeps the status",
+			args: args{
+				payload: payloadMock,
+			},
+			mockFunc: func(mock mockFields, arg args) {
+				mock.midtrans.EXPECT().HandleNotification(gomock.Any(), "1").Return(transactionResponsePendingMock, nil)
+				mock.midtrans_transaction.EXPECT().Get(gomock.Any(), midtransTransactionParamMock).Return(pendingMidtransTransactionResultMock, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "repeated settlement still settles the carts",
+			args: args{
+				payload: payloadMock,
+			},
+			mockFunc: func(mock mockFields, arg args) {
+				mock.midtrans.EXPECT().HandleNotification(gomock.Any(), "1").Return(transactionResponseSettlementMock, nil)
+				mock.midtrans_transaction.EXPECT().Get(gomock.Any(), midtransTransactionParamMock).Return(settledMidtransTransactionResultMock, nil)
+				mock.cart.EXPECT().Update(gomock.Any(), cartUpdateParamMock, cartUpdateMock).Return(nil)
 			},
 			wantErr: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			before := paymentCounts()
+
 			tt.mockFunc(mocks, tt.args)
-			err := mt.HandleNotification(tt.args.payload)
+			err := mt.HandleNotification(context.Background(), tt.args.payload)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("midtransTransaction.HandleNotification() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+
+			after := paymentCounts()
+			for status := range after {
+				want := 0.0
+				if status == tt.wantCounted {
+					want = 1
+				}
+				assert.Equal(t, want, after[status]-before[status], "payments counted as %s", status)
+			}
 		})
 	}
 }
+
+// paymentCounts reads the payments counter of every status, the mocked transactions have no payment type.
+func paymentCounts() map[string]float64 {
+	counts := map[string]float64{}
+	for _, status := range []string{entity.StatusSuccess, entity.StatusChallange, entity.StatusDeny, entity.StatusFailure, entity.StatusPending} {
+		counts[status] = testutil.ToFloat64(metrics.Payments.WithLabelValues("", status))
+	}
+
+	return counts
+}
+
+type paidCartMatcher struct{}
+
+func (m paidCartMatcher) Matches(x interface{}) bool {
+	param, ok := x.(entity.UpdateCartParam)
+	return ok && param.Status == entity.StatusPaid && param.PaidAt != nil
+}
+
+func (m paidCartMatcher) String() string {
+	return "is a paid cart update with paid at"
+}
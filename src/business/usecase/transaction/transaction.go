@@ -1,9 +1,9 @@
 package transaction
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	cartDom "go-clean/src/business/domain/cart"
 	menuDom "go-clean/src/business/domain/menu"
@@ -12,24 +12,79 @@ import (
 	transactionDom "go-clean/src/business/domain/transaction"
 	umkmDom "go-clean/src/business/domain/umkm"
 	"go-clean/src/business/entity"
+	"go-clean/src/lib/apperr"
 	"go-clean/src/lib/auth"
+	"go-clean/src/lib/csvutil"
+	"go-clean/src/lib/i18n"
+	"go-clean/src/lib/log"
+	"go-clean/src/lib/metrics"
 	"go-clean/src/lib/midtrans"
 	"go-clean/src/lib/timeutils"
-	"log"
+	"go-clean/src/lib/tracing"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/midtrans/midtrans-go/coreapi"
 	"github.com/xuri/excelize/v2"
 )
 
+var (
+	ErrCartEmpty          = apperr.Validation("cart_empty", "keranjang kosong")
+	ErrPaymentUnsupported = apperr.Validation("payment_unsupported", "metode pembayaran tidak didukung")
+	ErrPaymentFailed      = apperr.Upstream("payment_failed", "gagal membuat pembayaran, coba lagi nanti")
+	ErrInvalidDate        = apperr.Validation("invalid_date", "tanggal harus berformat YYYY-MM-DD")
+	ErrInvalidRange       = apperr.Validation("invalid_range", "tanggal akhir tidak boleh sebelum tanggal awal")
+	ErrInvalidAmountRange = apperr.Validation("invalid_amount_range", "nominal minimal tidak boleh melebihi nominal maksimal")
+)
+
+const maxSearchExport = 10000
+
+// paymentTypes maps the payment_type search filter to the stored payment type.
+var paymentTypes = map[string]int{
+	"cash":  midtrans.Cash,
+	"gopay": midtrans.GopayPayment,
+}
+
+var (
+	transactionSortOptions = entity.SortOptions{
+		Columns: map[string]string{
+			"id":         "id",
+			"created_at": "created_at",
+		},
+		Default:      "id",
+		DefaultOrder: entity.SortDesc,
+		TieBreaker:   "id",
+	}
+	// umkm transactions are listed from their carts, so only the transaction id can order them
+	umkmTransactionSortOptions = entity.SortOptions{
+		Columns: map[string]string{
+			"id": "carts.transaction_id",
+		},
+		Default:      "id",
+		DefaultOrder: entity.SortDesc,
+	}
+	searchSortOptions = entity.SortOptions{
+		Columns: map[string]string{
+			"id":         "transactions.id",
+			"created_at": "transactions.created_at",
+			"price":      "transactions.price",
+		},
+		Default:      "id",
+		DefaultOrder: entity.SortDesc,
+		TieBreaker:   "transactions.id",
+	}
+)
+
 type Interface interface {
 	Create(ctx context.Context, param entity.CreateTransactionParam) (uint, error)
 	GetOrderDetail(ctx context.Context, param entity.TransactionParam) (entity.TransactionDetailResponse, error)
-	GetTransactionListByUmkm(ctx context.Context, param entity.TransactionParam) ([]entity.TransactionDetailResponse, error)
-	GetTransactionList(ctx context.Context, param entity.TransactionParam) ([]entity.TransactionDetailResponse, error)
-	GetMyTransaction(ctx context.Context, param entity.TransactionParam) ([]entity.TransactionDetailResponse, error)
+	GetTransactionListByUmkm(ctx context.Context, param entity.TransactionParam) ([]entity.TransactionDetailResponse, entity.Pagination, error)
+	GetTransactionList(ctx context.Context, param entity.TransactionParam) ([]entity.TransactionDetailResponse, entity.Pagination, error)
+	GetMyTransaction(ctx context.Context, param entity.TransactionParam) ([]entity.TransactionDetailResponse, entity.Pagination, error)
+	Search(ctx context.Context, param entity.TransactionSearchParam) ([]entity.TransactionDetailResponse, entity.Pagination, error)
+	GenerateSearchCSV(ctx context.Context, param entity.TransactionSearchParam) ([]byte, string, error)
 	GetRecapSalesList(ctx context.Context, param entity.TransactionParam) ([]entity.SalesRecapResponse, error)
 	GenerateExcel(ctx context.Context, param entity.TransactionParam) (*excelize.File, string, error)
 	CompleteOrder(ctx context.Context, param entity.TransactionParam) error
@@ -44,9 +99,10 @@ type transaction struct {
 	umkm                umkmDom.Interface
 	midtrans            midtransDom.Interface
 	midtransTransaction midtransTransactionDom.Interface
+	pagination          entity.PaginationConfig
 }
 
-func Init(auth auth.Interface, td transactionDom.Interface, cd cartDom.Interface, md menuDom.Interface, ud umkmDom.Interface, mtd midtransDom.Interface, mtt midtransTransactionDom.Interface) Interface {
+func Init(auth auth.Interface, td transactionDom.Interface, cd cartDom.Interface, md menuDom.Interface, ud umkmDom.Interface, mtd midtransDom.Interface, mtt midtransTransactionDom.Interface, pagination entity.PaginationConfig) Interface {
 	t := &transaction{
 		transaction:         td,
 		cart:                cd,
@@ -55,18 +111,22 @@ func Init(auth auth.Interface, td transactionDom.Interface, cd cartDom.Interface
 		umkm:                ud,
 		midtrans:            mtd,
 		midtransTransaction: mtt,
+		pagination:          pagination,
 	}
 
 	return t
 }
 
 func (t *transaction) Create(ctx context.Context, param entity.CreateTransactionParam) (uint, error) {
+	ctx, span := tracing.Start(ctx, "transaction.Create")
+	defer span.End()
+
 	user, err := t.auth.GetUserAuthInfo(ctx)
 	if err != nil {
 		return 0, err
 	}
 
-	carts, err := t.cart.GetList(entity.CartParam{
+	carts, err := t.cart.GetList(ctx, entity.CartParam{
 		Status:  entity.StatusInCart,
 		GuestID: user.User.GuestID,
 	})
@@ -75,7 +135,7 @@ func (t *transaction) Create(ctx context.Context, param entity.CreateTransaction
 	}
 
 	if len(carts) == 0 {
-		return 0, errors.New("cart empty")
+		return 0, ErrCartEmpty
 	}
 
 	menuIDs := []int64{}
@@ -83,7 +143,7 @@ func (t *transaction) Create(ctx context.Context, param entity.CreateTransaction
 		menuIDs = append(menuIDs, int64(c.MenuID))
 	}
 
-	menus, err := t.menu.GetListInByID(menuIDs)
+	menus, err := t.menu.GetListInByID(ctx, menuIDs)
 	if err != nil {
 		return 0, err
 	}
@@ -98,10 +158,11 @@ func (t *transaction) Create(ctx context.Context, param entity.CreateTransaction
 		grossAmount += cart.TotalPrice
 	}
 
-	transaction, err := t.transaction.Create(entity.Transaction{
+	transaction, err := t.transaction.Create(ctx, entity.Transaction{
 		GuestID:   user.User.GuestID,
 		BuyerName: param.BuyerName,
 		Seat:      param.Seat,
+		Email:     strings.ToLower(strings.TrimSpace(param.Email)),
 		Notes:     param.Notes,
 		Price:     grossAmount,
 	})
@@ -114,7 +175,7 @@ func (t *transaction) Create(ctx context.Context, param entity.CreateTransaction
 		coreApiRes.TransactionID = "0"
 		coreApiRes.OrderID = fmt.Sprintf("%s-%d-%d", "CL", transaction.ID, time.Now().Unix())
 	} else {
-		coreApiRes, err = t.midtrans.Create(midtrans.CreateOrderParam{
+		coreApiRes, err = t.midtrans.Create(ctx, midtrans.CreateOrderParam{
 			OrderID:      transaction.ID,
 			PaymentID:    param.PaymentID,
 			GrossAmount:  int64(grossAmount),
@@ -125,7 +186,7 @@ func (t *transaction) Create(ctx context.Context, param entity.CreateTransaction
 			},
 		})
 		if err != nil {
-			return 0, err
+			return 0, ErrPaymentFailed.Wrap(err)
 		}
 	}
 
@@ -139,7 +200,7 @@ func (t *transaction) Create(ctx context.Context, param entity.CreateTransaction
 		return 0, err
 	}
 
-	if err := t.cart.Update(entity.CartParam{
+	if err := t.cart.Update(ctx, entity.CartParam{
 		Status:  entity.StatusInCart,
 		GuestID: user.User.GuestID,
 	}, entity.UpdateCartParam{
@@ -149,19 +210,22 @@ func (t *transaction) Create(ctx context.Context, param entity.CreateTransaction
 		return 0, err
 	}
 
-	_, err = t.midtransTransaction.Create(entity.MidtransTransaction{
+	midtransTransaction := entity.MidtransTransaction{
 		TransactionID: transaction.ID,
 		MidtransID:    coreApiRes.TransactionID,
 		OrderID:       coreApiRes.OrderID,
 		PaymentType:   param.PaymentID,
 		GrossAmount:   grossAmount,
-		Status:        "pending",
+		Status:        entity.StatusPending,
 		PaymentData:   string(paymenDataMarshal),
-	})
-	if err != nil {
+	}
+	if _, err := t.midtransTransaction.Create(ctx, midtransTransaction); err != nil {
 		return 0, err
 	}
 
+	metrics.OrdersCreated.WithLabelValues(midtransTransaction.GetPaymentType()).Inc()
+	metrics.Payments.WithLabelValues(midtransTransaction.GetPaymentType(), midtransTransaction.Status).Inc()
+
 	return transaction.ID, nil
 }
 
@@ -173,7 +237,7 @@ func (t *transaction) getPaymentData(paymentId int, coreApiRes *coreapi.ChargeRe
 	} else if paymentId == midtrans.Cash {
 		return paymentData, nil
 	} else {
-		return paymentData, errors.New("failed to get payment data")
+		return paymentData, ErrPaymentUnsupported
 	}
 
 	return paymentData, nil
@@ -195,23 +259,26 @@ func (t *transaction) convertToItemsDetails(carts []entity.Cart, menus map[int]e
 }
 
 func (t *transaction) GetOrderDetail(ctx context.Context, param entity.TransactionParam) (entity.TransactionDetailResponse, error) {
+	ctx, span := tracing.Start(ctx, "transaction.GetOrderDetail")
+	defer span.End()
+
 	result := entity.TransactionDetailResponse{}
 
-	transaction, err := t.transaction.Get(entity.TransactionParam{
+	transaction, err := t.transaction.Get(ctx, entity.TransactionParam{
 		ID: param.ID,
 	})
 	if err != nil {
 		return result, err
 	}
 
-	midtransTransaction, err := t.midtransTransaction.Get(entity.MidtransTransactionParam{
+	midtransTransaction, err := t.midtransTransaction.Get(ctx, entity.MidtransTransactionParam{
 		TransactionID: transaction.ID,
 	})
 	if err != nil {
 		return result, err
 	}
 
-	carts, err := t.cart.GetList(entity.CartParam{
+	carts, err := t.cart.GetList(ctx, entity.CartParam{
 		TransactionID: transaction.ID,
 	})
 	if err != nil {
@@ -223,7 +290,7 @@ func (t *transaction) GetOrderDetail(ctx context.Context, param entity.Transacti
 		menusID = append(menusID, int64(c.MenuID))
 	}
 
-	umkms, err := t.umkm.GetList(entity.UmkmParam{})
+	umkms, err := t.umkm.GetList(ctx, entity.UmkmParam{})
 	if err != nil {
 		return result, err
 	}
@@ -233,7 +300,7 @@ func (t *transaction) GetOrderDetail(ctx context.Context, param entity.Transacti
 		umkmMap[u.ID] = u
 	}
 
-	menus, err := t.menu.GetListInByID(menusID)
+	menus, err := t.menu.GetListInByID(ctx, menusID)
 	if err != nil {
 		return result, err
 	}
@@ -267,51 +334,87 @@ func (t *transaction) GetOrderDetail(ctx context.Context, param entity.Transacti
 	return result, nil
 }
 
-func (t *transaction) GetTransactionListByUmkm(ctx context.Context, param entity.TransactionParam) ([]entity.TransactionDetailResponse, error) {
+func (t *transaction) GetTransactionListByUmkm(ctx context.Context, param entity.TransactionParam) ([]entity.TransactionDetailResponse, entity.Pagination, error) {
+	ctx, span := tracing.Start(ctx, "transaction.GetTransactionListByUmkm")
+	defer span.End()
+
 	result := []entity.TransactionDetailResponse{}
 
-	carts, err := t.cart.GetListInByStatus(param.Statuses, entity.CartParam{
-		UmkmID: param.UmkmID,
-	})
+	paginationParam, err := param.PaginationParam.Paginate(t.pagination, umkmTransactionSortOptions)
 	if err != nil {
-		return result, err
+		return result, entity.Pagination{}, err
 	}
 
-	if len(carts) == 0 {
-		return result, nil
+	cartTransactionParam := entity.CartTransactionParam{
+		UmkmID:          param.UmkmID,
+		Statuses:        param.Statuses,
+		OrderIDLike:     param.MidtransOrderID,
+		PaginationParam: paginationParam,
+	}
+
+	total, err := t.cart.CountTransactions(ctx, cartTransactionParam)
+	if err != nil {
+		return result, entity.Pagination{}, err
+	}
+	pagination := entity.NewPagination(paginationParam, total)
+
+	transactionIDs, err := t.cart.GetTransactionIDs(ctx, cartTransactionParam)
+	if err != nil {
+		return result, entity.Pagination{}, err
+	}
+	pagination.SetNextCursor(paginationParam, umkmTransactionSortOptions, transactionIDs)
+
+	if len(transactionIDs) == 0 {
+		return result, pagination, nil
 	}
 
+	carts, err := t.cart.GetListInByTransactionID(ctx, transactionIDs)
+	if err != nil {
+		return result, entity.Pagination{}, err
+	}
+
+	statuses := make(map[string]bool)
+	for _, s := range param.Statuses {
+		statuses[s] = true
+	}
+
+	// a transaction can hold carts of other umkms or statuses, only the ones of this listing are shown
 	cartsMap := make(map[uint][]entity.Cart)
+	menuIDsMap := make(map[uint]bool)
+	menuIDs := []int64{}
 	for _, c := range carts {
+		if c.UmkmID != param.UmkmID || !statuses[c.Status] {
+			continue
+		}
 		cartsMap[c.TransactionID] = append(cartsMap[c.TransactionID], c)
+		if !menuIDsMap[c.MenuID] {
+			menuIDsMap[c.MenuID] = true
+			menuIDs = append(menuIDs, int64(c.MenuID))
+		}
 	}
 
-	menus, err := t.menu.GetAll(entity.MenuParam{
-		UmkmID: param.UmkmID,
-	})
+	menus, err := t.menu.GetListInByID(ctx, menuIDs)
 	if err != nil {
-		return result, err
+		return result, entity.Pagination{}, err
 	}
 	menusMap := make(map[uint]entity.Menu)
 	for _, m := range menus {
 		menusMap[m.ID] = m
 	}
 
-	transactionIDs := []uint{}
-	for k := range cartsMap {
-		transactionIDs = append(transactionIDs, k)
+	transactions, err := t.transaction.GetListByIDs(ctx, transactionIDs)
+	if err != nil {
+		return result, entity.Pagination{}, err
 	}
 
-	transactions, err := t.transaction.GetListByIDs(transactionIDs)
-	if err != nil {
-		return result, err
+	transactionsMap := make(map[uint]entity.Transaction)
+	for _, tr := range transactions {
+		transactionsMap[tr.ID] = tr
 	}
 
-	midtransTransactions, err := t.midtransTransaction.GetListByTrxIDs(transactionIDs, entity.MidtransTransactionParam{
-		OrderIDLike: param.MidtransOrderID,
-	})
+	midtransTransactions, err := t.midtransTransaction.GetListByTrxIDs(ctx, transactionIDs, entity.MidtransTransactionParam{})
 	if err != nil {
-		return result, err
+		return result, entity.Pagination{}, err
 	}
 
 	midtransTransactionMap := make(map[uint]entity.MidtransTransaction)
@@ -319,61 +422,70 @@ func (t *transaction) GetTransactionListByUmkm(ctx context.Context, param entity
 		midtransTransactionMap[mt.TransactionID] = mt
 	}
 
-	log.Println(len(midtransTransactionMap))
+	log.Debug(ctx, "midtrans transactions of umkm transactions", log.Fields{"count": len(midtransTransactionMap)})
 
-	for _, t := range transactions {
-		if _, ok := midtransTransactionMap[t.ID]; ok {
-			transactionDetail := entity.TransactionDetailResponse{
-				ID:              t.ID,
-				BuyerName:       t.BuyerName,
-				Seat:            t.Seat,
-				Notes:           t.Notes,
-				Price:           t.Price,
-				Status:          cartsMap[t.ID][0].Status,
-				MidtransOrderID: midtransTransactionMap[t.ID].OrderID,
-				CreatedAt:       timeutils.DiffForHumans(t.CreatedAt),
-			}
-			itemMenus := []entity.ItemMenu{}
-			for _, cm := range cartsMap[t.ID] {
-				itemMenus = append(itemMenus, entity.ItemMenu{
-					Name:         menusMap[cm.MenuID].Name,
-					Price:        cm.TotalPrice,
-					Qty:          cm.Amount,
-					PricePerItem: cm.PricePerItem,
-				})
-			}
-			transactionDetail.ItemMenus = itemMenus
-			result = append(result, transactionDetail)
+	for _, id := range transactionIDs {
+		tr, ok := transactionsMap[id]
+		if !ok || len(cartsMap[id]) == 0 {
+			continue
 		}
-	}
 
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].ID > result[j].ID
-	})
+		transactionDetail := entity.TransactionDetailResponse{
+			ID:              tr.ID,
+			BuyerName:       tr.BuyerName,
+			Seat:            tr.Seat,
+			Notes:           tr.Notes,
+			Price:           tr.Price,
+			Status:          cartsMap[id][0].Status,
+			MidtransOrderID: midtransTransactionMap[id].OrderID,
+			CreatedAt:       timeutils.DiffForHumans(ctx, tr.CreatedAt),
+		}
+		itemMenus := []entity.ItemMenu{}
+		for _, cm := range cartsMap[id] {
+			itemMenus = append(itemMenus, entity.ItemMenu{
+				Name:         menusMap[cm.MenuID].Name,
+				Price:        cm.TotalPrice,
+				Qty:          cm.Amount,
+				PricePerItem: cm.PricePerItem,
+			})
+		}
+		transactionDetail.ItemMenus = itemMenus
+		result = append(result, transactionDetail)
+	}
 
-	return result, nil
+	return result, pagination, nil
 }
 
-func (t *transaction) GetTransactionList(ctx context.Context, param entity.TransactionParam) ([]entity.TransactionDetailResponse, error) {
+func (t *transaction) GetTransactionList(ctx context.Context, param entity.TransactionParam) ([]entity.TransactionDetailResponse, entity.Pagination, error) {
+	ctx, span := tracing.Start(ctx, "transaction.GetTransactionList")
+	defer span.End()
+
 	result := []entity.TransactionDetailResponse{}
 
-	midtransTransaction, err := t.midtransTransaction.GetList(entity.MidtransTransactionParam{
-		OrderIDLike: param.MidtransOrderID,
-		Limit:       param.Limit,
-		Offset:      (param.Page - 1) * param.Limit,
-		OrderBy:     "id desc",
-	})
+	paginationParam, err := param.PaginationParam.Paginate(t.pagination, transactionSortOptions)
 	if err != nil {
-		return result, err
+		return result, entity.Pagination{}, err
 	}
 
-	if len(midtransTransaction) == 0 {
-		return result, errors.New("data not found")
+	midtransTransactionParam := entity.MidtransTransactionParam{
+		OrderIDLike:     param.MidtransOrderID,
+		PaginationParam: paginationParam,
 	}
 
-	midtransTransactionMap := make(map[uint]entity.MidtransTransaction)
-	for _, mt := range midtransTransaction {
-		midtransTransactionMap[mt.TransactionID] = mt
+	total, err := t.midtransTransaction.Count(ctx, midtransTransactionParam)
+	if err != nil {
+		return result, entity.Pagination{}, err
+	}
+	pagination := entity.NewPagination(paginationParam, total)
+
+	midtransTransaction, err := t.midtransTransaction.GetList(ctx, midtransTransactionParam)
+	if err != nil {
+		return result, entity.Pagination{}, err
+	}
+	pagination.SetNextCursor(paginationParam, transactionSortOptions, midtransTransaction)
+
+	if len(midtransTransaction) == 0 {
+		return result, pagination, nil
 	}
 
 	transactionIDs := []uint{}
@@ -381,14 +493,19 @@ func (t *transaction) GetTransactionList(ctx context.Context, param entity.Trans
 		transactionIDs = append(transactionIDs, mt.TransactionID)
 	}
 
-	transactions, err := t.transaction.GetListByIDs(transactionIDs)
+	transactions, err := t.transaction.GetListByIDs(ctx, transactionIDs)
 	if err != nil {
-		return result, err
+		return result, entity.Pagination{}, err
+	}
+
+	transactionsMap := make(map[uint]entity.Transaction)
+	for _, tr := range transactions {
+		transactionsMap[tr.ID] = tr
 	}
 
-	carts, err := t.cart.GetListInByTransactionID(transactionIDs)
+	carts, err := t.cart.GetListInByTransactionID(ctx, transactionIDs)
 	if err != nil {
-		return result, err
+		return result, entity.Pagination{}, err
 	}
 	cartsMap := make(map[uint][]entity.Cart)
 	for _, c := range carts {
@@ -404,9 +521,9 @@ func (t *transaction) GetTransactionList(ctx context.Context, param entity.Trans
 		}
 	}
 
-	menus, err := t.menu.GetListInByID(menuIDs)
+	menus, err := t.menu.GetListInByID(ctx, menuIDs)
 	if err != nil {
-		return result, err
+		return result, entity.Pagination{}, err
 	}
 
 	menusMap := make(map[uint]entity.Menu)
@@ -414,9 +531,9 @@ func (t *transaction) GetTransactionList(ctx context.Context, param entity.Trans
 		menusMap[m.ID] = m
 	}
 
-	umkm, err := t.umkm.GetList(entity.UmkmParam{})
+	umkm, err := t.umkm.GetList(ctx, entity.UmkmParam{})
 	if err != nil {
-		return result, err
+		return result, entity.Pagination{}, err
 	}
 
 	umkmsMap := make(map[uint]entity.Umkm)
@@ -424,18 +541,23 @@ func (t *transaction) GetTransactionList(ctx context.Context, param entity.Trans
 		umkmsMap[u.ID] = u
 	}
 
-	for _, t := range transactions {
+	for _, mt := range midtransTransaction {
+		tr, ok := transactionsMap[mt.TransactionID]
+		if !ok {
+			continue
+		}
+
 		transactionDetail := entity.TransactionDetailResponse{
-			ID:              t.ID,
-			BuyerName:       t.BuyerName,
-			Seat:            t.Seat,
-			Notes:           t.Notes,
-			Price:           t.Price,
-			Status:          midtransTransactionMap[t.ID].Status,
-			MidtransOrderID: midtransTransactionMap[t.ID].OrderID,
+			ID:              tr.ID,
+			BuyerName:       tr.BuyerName,
+			Seat:            tr.Seat,
+			Notes:           tr.Notes,
+			Price:           tr.Price,
+			Status:          mt.Status,
+			MidtransOrderID: mt.OrderID,
 		}
 		itemMenus := []entity.ItemMenu{}
-		for _, c := range cartsMap[t.ID] {
+		for _, c := range cartsMap[tr.ID] {
 			itemMenus = append(itemMenus, entity.ItemMenu{
 				UmkmName:     umkmsMap[c.UmkmID].Name,
 				Name:         menusMap[c.MenuID].Name,
@@ -449,30 +571,54 @@ func (t *transaction) GetTransactionList(ctx context.Context, param entity.Trans
 		result = append(result, transactionDetail)
 	}
 
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].ID > result[j].ID
-	})
-
-	return result, nil
+	return result, pagination, nil
 }
 
-func (t *transaction) GetMyTransaction(ctx context.Context, param entity.TransactionParam) ([]entity.TransactionDetailResponse, error) {
+func (t *transaction) GetMyTransaction(ctx context.Context, param entity.TransactionParam) ([]entity.TransactionDetailResponse, entity.Pagination, error) {
+	ctx, span := tracing.Start(ctx, "transaction.GetMyTransaction")
+	defer span.End()
+
 	result := []entity.TransactionDetailResponse{}
 
 	user, err := t.auth.GetUserAuthInfo(ctx)
 	if err != nil {
-		return result, err
+		return result, entity.Pagination{}, err
 	}
 
-	carts, err := t.cart.GetListInByStatus([]string{entity.StatusPaid, entity.StatusUnpaid, entity.StatusDone, entity.StatusCancel}, entity.CartParam{
-		GuestID: user.User.GuestID,
-	})
+	paginationParam, err := param.PaginationParam.Paginate(t.pagination, transactionSortOptions)
 	if err != nil {
-		return result, err
+		return result, entity.Pagination{}, err
 	}
 
-	if len(carts) == 0 {
-		return result, nil
+	guestParam := entity.TransactionParam{
+		MidtransOrderID: param.MidtransOrderID,
+		PaginationParam: paginationParam,
+	}
+
+	total, err := t.transaction.CountByGuestID(ctx, user.User.GuestID, guestParam)
+	if err != nil {
+		return result, entity.Pagination{}, err
+	}
+	pagination := entity.NewPagination(paginationParam, total)
+
+	transactions, err := t.transaction.GetListByGuestID(ctx, user.User.GuestID, guestParam)
+	if err != nil {
+		return result, entity.Pagination{}, err
+	}
+	pagination.SetNextCursor(paginationParam, transactionSortOptions, transactions)
+
+	if len(transactions) == 0 {
+		return result, pagination, nil
+	}
+
+	transactionIDs := []uint{}
+	for _, tr := range transactions {
+		transactionIDs = append(transactionIDs, tr.ID)
+	}
+
+	carts, err := t.cart.GetListInByTransactionID(ctx, transactionIDs)
+	if err != nil {
+		return result, entity.Pagination{}, err
 	}
 
 	cartsMap := make(map[uint][]entity.Cart)
@@ -488,9 +634,9 @@ func (t *transaction) GetMyTransaction(ctx context.Context, param entity.Transac
 	for k := range menusMap {
 		menuIDs = append(menuIDs, int64(k))
 	}
-	menus, err := t.menu.GetListInByID(menuIDs)
+	menus, err := t.menu.GetListInByID(ctx, menuIDs)
 	if err != nil {
-		return result, err
+		return result, entity.Pagination{}, err
 	}
 	for _, m := range menus {
 		menusMap[m.ID] = m
@@ -500,37 +646,19 @@ func (t *transaction) GetMyTransaction(ctx context.Context, param entity.Transac
 	for k := range umkmsMap {
 		umkmIDs = append(umkmIDs, k)
 	}
-	umkms, err := t.umkm.GetListInByID(umkmIDs)
+	umkms, err := t.umkm.GetListInByID(ctx, umkmIDs)
 	if err != nil {
-		return result, err
+		return result, entity.Pagination{}, err
 	}
 	for _, u := range umkms {
 		umkmsMap[u.ID] = u
 	}
 
-	transactionIDs := []uint{}
-	for k := range cartsMap {
-		transactionIDs = append(transactionIDs, k)
-	}
-
-	sort.Slice(transactionIDs, func(i, j int) bool {
-		return transactionIDs[i] > transactionIDs[j]
-	})
-
-	if len(transactionIDs) > 10 {
-		transactionIDs = transactionIDs[:10]
-	}
-
-	transactions, err := t.transaction.GetListByIDs(transactionIDs)
-	if err != nil {
-		return result, err
-	}
-
-	midtransTransactions, err := t.midtransTransaction.GetListByTrxIDs(transactionIDs, entity.MidtransTransactionParam{
+	midtransTransactions, err := t.midtransTransaction.GetListByTrxIDs(ctx, transactionIDs, entity.MidtransTransactionParam{
 		OrderIDLike: param.MidtransOrderID,
 	})
 	if err != nil {
-		return result, err
+		return result, entity.Pagination{}, err
 	}
 
 	midtransTransactionMap := make(map[uint]entity.MidtransTransaction)
@@ -550,7 +678,7 @@ func (t *transaction) GetMyTransaction(ctx context.Context, param entity.Transac
 				Status:          mt.Status,
 				MidtransOrderID: mt.OrderID,
 				PaymentType:     mt.GetPaymentType(),
-				CreatedAt:       timeutils.DiffForHumans(t.CreatedAt),
+				CreatedAt:       timeutils.DiffForHumans(ctx, t.CreatedAt),
 			}
 			itemMenus := []entity.ItemMenu{}
 			for _, cm := range cartsMap[t.ID] {
@@ -566,10 +694,9 @@ func (t *transaction) GetMyTransaction(ctx context.Context, param entity.Transac
 			if transactionDetail.Status == entity.StatusPending {
 				paymentData := entity.PaymentData{}
 				if err := json.Unmarshal([]byte(midtransTransactionMap[t.ID].PaymentData), &paymentData); err != nil {
-					log.Println("failed to un marshal payment data")
+					log.Warn(ctx, "failed to unmarshal payment data", log.Fields{"transaction_id": t.ID, "error": err})
 					continue
 				}
-				log.Println(paymentData)
 				transactionDetail.PaymentData = paymentData
 			}
 			transactionDetail.ItemMenus = itemMenus
@@ -577,17 +704,247 @@ func (t *transaction) GetMyTransaction(ctx context.Context, param entity.Transac
 		}
 	}
 
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].ID > result[j].ID
+	return result, pagination, nil
+}
+
+func (t *transaction) Search(ctx context.Context, param entity.TransactionSearchParam) ([]entity.TransactionDetailResponse, entity.Pagination, error) {
+	ctx, span := tracing.Start(ctx, "transaction.Search")
+	defer span.End()
+
+	result := []entity.TransactionDetailResponse{}
+
+	if err := resolveSearchParam(&param); err != nil {
+		return result, entity.Pagination{}, err
+	}
+
+	paginationParam, err := param.PaginationParam.Paginate(t.pagination, searchSortOptions)
+	if err != nil {
+		return result, entity.Pagination{}, err
+	}
+	param.PaginationParam = paginationParam
+
+	total, err := t.transaction.CountSearch(ctx, param)
+	if err != nil {
+		return result, entity.Pagination{}, err
+	}
+
+	transactions, err := t.transaction.Search(ctx, param)
+	if err != nil {
+		return result, entity.Pagination{}, err
+	}
+
+	result, err = t.getTransactionDetails(ctx, transactions)
+	if err != nil {
+		return result, entity.Pagination{}, err
+	}
+
+	pagination := entity.NewPagination(paginationParam, total)
+	pagination.SetNextCursor(paginationParam, searchSortOptions, transactions)
+
+	return result, pagination, nil
+}
+
+func (t *transaction) GenerateSearchCSV(ctx context.Context, param entity.TransactionSearchParam) ([]byte, string, error) {
+	if err := resolveSearchParam(&param); err != nil {
+		return nil, "", err
+	}
+
+	paginationParam, err := param.PaginationParam.Paginate(t.pagination, searchSortOptions)
+	if err != nil {
+		return nil, "", err
+	}
+	paginationParam.Limit = maxSearchExport
+	paginationParam.Offset = 0
+	paginationParam.After = nil
+	param.PaginationParam = paginationParam
+
+	transactions, err := t.transaction.Search(ctx, param)
+	if err != nil {
+		return nil, "", err
+	}
+
+	details, err := t.getTransactionDetails(ctx, transactions)
+	if err != nil {
+		return nil, "", err
+	}
+
+	buf := &bytes.Buffer{}
+	w := csvutil.NewWriter(buf)
+
+	if err := w.Write([]string{"transaction_id", "created_at", "order_id", "buyer_name", "seat", "email", "status", "payment_type", "price", "items"}); err != nil {
+		return nil, "", err
+	}
+
+	for i, tr := range transactions {
+		items := []string{}
+		for _, item := range details[i].ItemMenus {
+			items = append(items, fmt.Sprintf("%s x%d (%s)", item.Name, item.Qty, item.UmkmName))
+		}
+
+		if err := w.Write([]string{
+			fmt.Sprint(tr.ID),
+			tr.CreatedAt.Format(time.RFC3339),
+			details[i].MidtransOrderID,
+			tr.BuyerName,
+			tr.Seat,
+			tr.Email,
+			details[i].Status,
+			details[i].PaymentType,
+			fmt.Sprint(tr.Price),
+			strings.Join(items, "; "),
+		}); err != nil {
+			return nil, "", err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, "", err
+	}
+
+	return buf.Bytes(), fmt.Sprintf("transaction-search-%s", time.Now().Format("20060102150405")), nil
+}
+
+// getTransactionDetails describes transactions with their payment and items, in the same order.
+func (t *transaction) getTransactionDetails(ctx context.Context, transactions []entity.Transaction) ([]entity.TransactionDetailResponse, error) {
+	result := []entity.TransactionDetailResponse{}
+
+	if len(transactions) == 0 {
+		return result, nil
+	}
+
+	transactionIDs := []uint{}
+	for _, tr := range transactions {
+		transactionIDs = append(transactionIDs, tr.ID)
+	}
+
+	midtransTransactions, err := t.midtransTransaction.GetListByTrxIDs(ctx, transactionIDs, entity.MidtransTransactionParam{})
+	if err != nil {
+		return result, err
+	}
+
+	midtransTransactionMap := make(map[uint]entity.MidtransTransaction)
+	for _, mt := range midtransTransactions {
+		midtransTransactionMap[mt.TransactionID] = mt
+	}
+
+	carts, err := t.cart.GetListInByTransactionID(ctx, transactionIDs)
+	if err != nil {
+		return result, err
+	}
+
+	cartsMap := make(map[uint][]entity.Cart)
+	menusMap := make(map[uint]entity.Menu)
+	umkmsMap := make(map[uint]entity.Umkm)
+	for _, c := range carts {
+		cartsMap[c.TransactionID] = append(cartsMap[c.TransactionID], c)
+		menusMap[c.MenuID] = entity.Menu{}
+		umkmsMap[c.UmkmID] = entity.Umkm{}
+	}
+
+	menuIDs := []int64{}
+	for k := range menusMap {
+		menuIDs = append(menuIDs, int64(k))
+	}
+	sort.Slice(menuIDs, func(i, j int) bool {
+		return menuIDs[i] < menuIDs[j]
 	})
+	menus, err := t.menu.GetListInByID(ctx, menuIDs)
+	if err != nil {
+		return result, err
+	}
+	for _, m := range menus {
+		menusMap[m.ID] = m
+	}
+
+	umkmIDs := []uint{}
+	for k := range umkmsMap {
+		umkmIDs = append(umkmIDs, k)
+	}
+	sort.Slice(umkmIDs, func(i, j int) bool {
+		return umkmIDs[i] < umkmIDs[j]
+	})
+	umkms, err := t.umkm.GetListInByID(ctx, umkmIDs)
+	if err != nil {
+		return result, err
+	}
+	for _, u := range umkms {
+		umkmsMap[u.ID] = u
+	}
+
+	for _, tr := range transactions {
+		mt := midtransTransactionMap[tr.ID]
+		transactionDetail := entity.TransactionDetailResponse{
+			ID:              tr.ID,
+			BuyerName:       tr.BuyerName,
+			Seat:            tr.Seat,
+			Email:           tr.Email,
+			Notes:           tr.Notes,
+			Price:           tr.Price,
+			Status:          mt.Status,
+			PaymentType:     mt.GetPaymentType(),
+			MidtransOrderID: mt.OrderID,
+			CreatedAt:       timeutils.DiffForHumans(ctx, tr.CreatedAt),
+		}
+		itemMenus := []entity.ItemMenu{}
+		for _, c := range cartsMap[tr.ID] {
+			itemMenus = append(itemMenus, entity.ItemMenu{
+				UmkmName:     umkmsMap[c.UmkmID].Name,
+				Name:         menusMap[c.MenuID].Name,
+				Status:       c.Status,
+				Price:        c.TotalPrice,
+				Qty:          c.Amount,
+				PricePerItem: c.PricePerItem,
+			})
+		}
+		transactionDetail.ItemMenus = itemMenus
+		result = append(result, transactionDetail)
+	}
 
 	return result, nil
 }
 
+// resolveSearchParam validates the filters of param and resolves its dates and payment type.
+func resolveSearchParam(param *entity.TransactionSearchParam) error {
+	if param.StartDate != "" {
+		from, err := time.ParseInLocation("2006-01-02", param.StartDate, time.Local)
+		if err != nil {
+			return ErrInvalidDate.Wrap(err)
+		}
+		param.From = from
+	}
+
+	if param.EndDate != "" {
+		to, err := time.ParseInLocation("2006-01-02", param.EndDate, time.Local)
+		if err != nil {
+			return ErrInvalidDate.Wrap(err)
+		}
+		param.To = to.AddDate(0, 0, 1)
+	}
+
+	if !param.From.IsZero() && !param.To.IsZero() && !param.To.After(param.From) {
+		return ErrInvalidRange
+	}
+
+	if param.MinAmount < 0 || param.MaxAmount < 0 || (param.MaxAmount > 0 && param.MinAmount > param.MaxAmount) {
+		return ErrInvalidAmountRange
+	}
+
+	if param.PaymentType != "" {
+		paymentTypeID, ok := paymentTypes[strings.ToLower(param.PaymentType)]
+		if !ok {
+			return ErrPaymentUnsupported
+		}
+		param.PaymentTypeID = paymentTypeID
+	}
+
+	return nil
+}
+
 func (t *transaction) GenerateExcel(ctx context.Context, param entity.TransactionParam) (*excelize.File, string, error) {
 	recap := []entity.SalesRecapResponse{}
 
-	carts, err := t.cart.GetList(entity.CartParam{
+	carts, err := t.cart.GetList(ctx, entity.CartParam{
 		Status:    entity.StatusDone,
 		CreatedAt: param.Date,
 	})
@@ -642,10 +999,10 @@ func (t *transaction) GenerateExcel(ctx context.Context, param entity.Transactio
 		return nil, "", err
 	}
 
-	f.SetCellValue(sheetname, "A1", fmt.Sprintf("Recap Transaksi Bulan : %s", param.Date))
-	f.SetCellValue(sheetname, "A2", "Tanggal")
-	f.SetCellValue(sheetname, "B2", "Pendapatan Kotor")
-	f.SetCellValue(sheetname, "C2", "Pendapatan Bersih")
+	f.SetCellValue(sheetname, "A1", i18n.T(ctx, "excel.recap_title", param.Date))
+	f.SetCellValue(sheetname, "A2", i18n.T(ctx, "excel.date"))
+	f.SetCellValue(sheetname, "B2", i18n.T(ctx, "excel.gross_amount"))
+	f.SetCellValue(sheetname, "C2", i18n.T(ctx, "excel.net_amount"))
 
 	cellIndex := 0
 	sumNet := 0
@@ -659,7 +1016,7 @@ func (t *transaction) GenerateExcel(ctx context.Context, param entity.Transactio
 		sumGross += r.GrossAmount
 	}
 
-	f.SetCellValue(sheetname, fmt.Sprintf("A%d", cellIndex), "TOTAL")
+	f.SetCellValue(sheetname, fmt.Sprintf("A%d", cellIndex), i18n.T(ctx, "excel.total"))
 	f.SetCellValue(sheetname, fmt.Sprintf("B%d", cellIndex), sumGross)
 	f.SetCellValue(sheetname, fmt.Sprintf("C%d", cellIndex), sumNet)
 
@@ -674,7 +1031,7 @@ func (t *transaction) GetRecapSalesList(ctx context.Context, param entity.Transa
 	now := time.Now()
 	calcLastSevenDays := now.AddDate(0, 0, -7)
 
-	carts, err := t.cart.GetList(entity.CartParam{
+	carts, err := t.cart.GetList(ctx, entity.CartParam{
 		Status:            entity.StatusDone,
 		CreatedAt:         param.Date,
 		CreatedAtMoreThan: calcLastSevenDays,
@@ -683,7 +1040,7 @@ func (t *transaction) GetRecapSalesList(ctx context.Context, param entity.Transa
 		return result, err
 	}
 
-	umkms, err := t.umkm.GetList(entity.UmkmParam{})
+	umkms, err := t.umkm.GetList(ctx, entity.UmkmParam{})
 	if err != nil {
 		return result, err
 	}
@@ -772,7 +1129,7 @@ func (t *transaction) GetRecapSalesList(ctx context.Context, param entity.Transa
 }
 
 func (t *transaction) CompleteOrder(ctx context.Context, param entity.TransactionParam) error {
-	carts, err := t.cart.GetList(entity.CartParam{
+	carts, err := t.cart.GetList(ctx, entity.CartParam{
 		TransactionID: param.ID,
 		UmkmID:        param.UmkmID,
 		Status:        entity.StatusPaid,
@@ -786,8 +1143,10 @@ func (t *transaction) CompleteOrder(ctx context.Context, param entity.Transactio
 		cartsID = append(cartsID, c.ID)
 	}
 
-	if err := t.cart.UpdatesByIDs(cartsID, entity.UpdateCartParam{
+	doneAt := time.Now()
+	if err := t.cart.UpdatesByIDs(ctx, cartsID, entity.UpdateCartParam{
 		Status: entity.StatusDone,
+		DoneAt: &doneAt,
 	}); err != nil {
 		return err
 	}
@@ -796,7 +1155,7 @@ func (t *transaction) CompleteOrder(ctx context.Context, param entity.Transactio
 }
 
 func (t *transaction) CancelOrder(ctx context.Context, param entity.TransactionParam) error {
-	carts, err := t.cart.GetList(entity.CartParam{
+	carts, err := t.cart.GetList(ctx, entity.CartParam{
 		TransactionID: param.ID,
 		UmkmID:        param.UmkmID,
 	})
@@ -809,7 +1168,7 @@ func (t *transaction) CancelOrder(ctx context.Context, param entity.TransactionP
 		cartsID = append(cartsID, c.ID)
 	}
 
-	if err := t.cart.UpdatesByIDs(cartsID, entity.UpdateCartParam{
+	if err := t.cart.UpdatesByIDs(ctx, cartsID, entity.UpdateCartParam{
 		Status: entity.StatusCancel,
 	}); err != nil {
 		return err
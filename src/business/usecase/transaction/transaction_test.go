@@ -15,6 +15,7 @@ import (
 	"go-clean/src/lib/midtrans"
 	mock_auth "go-clean/src/lib/tests/mock/auth"
 	"testing"
+	"time"
 
 	"github.com/midtrans/midtrans-go/coreapi"
 	"github.com/stretchr/testify/assert"
@@ -33,7 +34,7 @@ func Test_transaction_Create(t *testing.T) {
 	transactionMock := mock_transaction.NewMockInterface(ctrl)
 	midtransTransactionMock := mock_midtrans_transaction.NewMockInterface(ctrl)
 
-	tr := transaction.Init(authMock, transactionMock, cartMock, menuMock, nil, midtransMock, midtransTransactionMock)
+	tr := transaction.Init(authMock, transactionMock, cartMock, menuMock, nil, midtransMock, midtransTransactionMock, entity.PaginationConfig{})
 
 	userAuthMock := auth.UserAuthInfo{
 		User: auth.User{
@@ -87,6 +88,7 @@ func Test_transaction_Create(t *testing.T) {
 		GuestID:   "1",
 		BuyerName: "mail",
 		Seat:      "a1",
+		Email:     "mail@gmail.com",
 		Notes:     "-",
 		Price:     10000,
 	}
@@ -209,7 +211,7 @@ func Test_transaction_Create(t *testing.T) {
 		{
 			name: "failed to get auth user",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(userAuthMock, assert.AnError)
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(userAuthMock, assert.AnError)
 			},
 			args: args{
 				ctx:   context.Background(),
@@ -221,8 +223,8 @@ func Test_transaction_Create(t *testing.T) {
 		{
 			name: "failed get cart list",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(userAuthMock, nil)
-				mock.cart.EXPECT().GetList(cartParamMock).Return([]entity.Cart{}, assert.AnError)
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(userAuthMock, nil)
+				mock.cart.EXPECT().GetList(gomock.Any(), cartParamMock).Return([]entity.Cart{}, assert.AnError)
 			},
 			args: args{
 				ctx:   context.Background(),
@@ -234,8 +236,8 @@ func Test_transaction_Create(t *testing.T) {
 		{
 			name: "cart empty",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(userAuthMock, nil)
-				mock.cart.EXPECT().GetList(cartParamMock).Return([]entity.Cart{}, nil)
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(userAuthMock, nil)
+				mock.cart.EXPECT().GetList(gomock.Any(), cartParamMock).Return([]entity.Cart{}, nil)
 			},
 			args: args{
 				ctx:   context.Background(),
@@ -247,9 +249,9 @@ func Test_transaction_Create(t *testing.T) {
 		{
 			name: "failed to get menus list",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(userAuthMock, nil)
-				mock.cart.EXPECT().GetList(cartParamMock).Return(cartResultMock, nil)
-				mock.menu.EXPECT().GetListInByID([]int64{1}).Return([]entity.Menu{}, assert.AnError)
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(userAuthMock, nil)
+				mock.cart.EXPECT().GetList(gomock.Any(), cartParamMock).Return(cartResultMock, nil)
+				mock.menu.EXPECT().GetListInByID(gomock.Any(), []int64{1}).Return([]entity.Menu{}, assert.AnError)
 			},
 			args: args{
 				ctx:   context.Background(),
@@ -261,10 +263,10 @@ func Test_transaction_Create(t *testing.T) {
 		{
 			name: "failed to create transaction",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(userAuthMock, nil)
-				mock.cart.EXPECT().GetList(cartParamMock).Return(cartResultMock, nil)
-				mock.menu.EXPECT().GetListInByID([]int64{1}).Return(menuResultMock, nil)
-				mock.transaction.EXPECT().Create(newTransactionMock).Return(transactionResultMock, assert.AnError)
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(userAuthMock, nil)
+				mock.cart.EXPECT().GetList(gomock.Any(), cartParamMock).Return(cartResultMock, nil)
+				mock.menu.EXPECT().GetListInByID(gomock.Any(), []int64{1}).Return(menuResultMock, nil)
+				mock.transaction.EXPECT().Create(gomock.Any(), newTransactionMock).Return(transactionResultMock, assert.AnError)
 			},
 			args: args{
 				ctx:   context.Background(),
@@ -276,11 +278,11 @@ func Test_transaction_Create(t *testing.T) {
 		{
 			name: "failed to create midtrans",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(userAuthMock, nil)
-				mock.cart.EXPECT().GetList(cartParamMock).Return(cartResultMock, nil)
-				mock.menu.EXPECT().GetListInByID([]int64{1}).Return(menuResultMock, nil)
-				mock.transaction.EXPECT().Create(newTransactionMock).Return(transactionResultMock, nil)
-				mock.midtrans.EXPECT().Create(midtransCreateParamMock).Return(nil, assert.AnError)
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(userAuthMock, nil)
+				mock.cart.EXPECT().GetList(gomock.Any(), cartParamMock).Return(cartResultMock, nil)
+				mock.menu.EXPECT().GetListInByID(gomock.Any(), []int64{1}).Return(menuResultMock, nil)
+				mock.transaction.EXPECT().Create(gomock.Any(), newTransactionMock).Return(transactionResultMock, nil)
+				mock.midtrans.EXPECT().Create(gomock.Any(), midtransCreateParamMock).Return(nil, assert.AnError)
 			},
 			args: args{
 				ctx:   context.Background(),
@@ -292,12 +294,12 @@ func Test_transaction_Create(t *testing.T) {
 		{
 			name: "failed to update cart",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(userAuthMock, nil)
-				mock.cart.EXPECT().GetList(cartParamMock).Return(cartResultMock, nil)
-				mock.menu.EXPECT().GetListInByID([]int64{1}).Return(menuResultMock, nil)
-				mock.transaction.EXPECT().Create(newTransactionMock).Return(transactionResultMock, nil)
-				mock.midtrans.EXPECT().Create(midtransCreateParamMock).Return(midtransResultMock, nil)
-				mock.cart.EXPECT().Update(selectParamCartMock, updateParamCartMock).Return(assert.AnError)
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(userAuthMock, nil)
+				mock.cart.EXPECT().GetList(gomock.Any(), cartParamMock).Return(cartResultMock, nil)
+				mock.menu.EXPECT().GetListInByID(gomock.Any(), []int64{1}).Return(menuResultMock, nil)
+				mock.transaction.EXPECT().Create(gomock.Any(), newTransactionMock).Return(transactionResultMock, nil)
+				mock.midtrans.EXPECT().Create(gomock.Any(), midtransCreateParamMock).Return(midtransResultMock, nil)
+				mock.cart.EXPECT().Update(gomock.Any(), selectParamCartMock, updateParamCartMock).Return(assert.AnError)
 			},
 			args: args{
 				ctx:   context.Background(),
@@ -309,11 +311,11 @@ func Test_transaction_Create(t *testing.T) {
 		{
 			name: "failed to get payment data",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(userAuthMock, nil)
-				mock.cart.EXPECT().GetList(cartParamMock).Return(cartResultMock, nil)
-				mock.menu.EXPECT().GetListInByID([]int64{1}).Return(menuResultMock, nil)
-				mock.transaction.EXPECT().Create(newTransactionMock).Return(transactionResultMock, nil)
-				mock.midtrans.EXPECT().Create(midtransCreateParamUndifinedMock).Return(midtransResultMock, nil)
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(userAuthMock, nil)
+				mock.cart.EXPECT().GetList(gomock.Any(), cartParamMock).Return(cartResultMock, nil)
+				mock.menu.EXPECT().GetListInByID(gomock.Any(), []int64{1}).Return(menuResultMock, nil)
+				mock.transaction.EXPECT().Create(gomock.Any(), newTransactionMock).Return(transactionResultMock, nil)
+				mock.midtrans.EXPECT().Create(gomock.Any(), midtransCreateParamUndifinedMock).Return(midtransResultMock, nil)
 			},
 			args: args{
 				ctx:   context.Background(),
@@ -325,13 +327,13 @@ func Test_transaction_Create(t *testing.T) {
 		{
 			name: "failed to create midtrans transaction",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(userAuthMock, nil)
-				mock.cart.EXPECT().GetList(cartParamMock).Return(cartResultMock, nil)
-				mock.menu.EXPECT().GetListInByID([]int64{1}).Return(menuResultMock, nil)
-				mock.transaction.EXPECT().Create(newTransactionMock).Return(transactionResultMock, nil)
-				mock.midtrans.EXPECT().Create(midtransCreateParamMock).Return(midtransResultMock, nil)
-				mock.cart.EXPECT().Update(selectParamCartMock, updateParamCartMock).Return(nil)
-				mock.midtrans_transaction.EXPECT().Create(newMidtransTransactionMock).Return(entity.MidtransTransaction{}, assert.AnError)
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(userAuthMock, nil)
+				mock.cart.EXPECT().GetList(gomock.Any(), cartParamMock).Return(cartResultMock, nil)
+				mock.menu.EXPECT().GetListInByID(gomock.Any(), []int64{1}).Return(menuResultMock, nil)
+				mock.transaction.EXPECT().Create(gomock.Any(), newTransactionMock).Return(transactionResultMock, nil)
+				mock.midtrans.EXPECT().Create(gomock.Any(), midtransCreateParamMock).Return(midtransResultMock, nil)
+				mock.cart.EXPECT().Update(gomock.Any(), selectParamCartMock, updateParamCartMock).Return(nil)
+				mock.midtrans_transaction.EXPECT().Create(gomock.Any(), newMidtransTransactionMock).Return(entity.MidtransTransaction{}, assert.AnError)
 			},
 			args: args{
 				ctx:   context.Background(),
@@ -343,13 +345,13 @@ func Test_transaction_Create(t *testing.T) {
 		{
 			name: "all success",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.auth.EXPECT().GetUserAuthInfo(context.Background()).Return(userAuthMock, nil)
-				mock.cart.EXPECT().GetList(cartParamMock).Return(cartResultMock, nil)
-				mock.menu.EXPECT().GetListInByID([]int64{1}).Return(menuResultMock, nil)
-				mock.transaction.EXPECT().Create(newTransactionMock).Return(transactionResultMock, nil)
-				mock.midtrans.EXPECT().Create(midtransCreateParamMock).Return(midtransResultMock, nil)
-				mock.cart.EXPECT().Update(selectParamCartMock, updateParamCartMock).Return(nil)
-				mock.midtrans_transaction.EXPECT().Create(newMidtransTransactionMock).Return(entity.MidtransTransaction{}, nil)
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(userAuthMock, nil)
+				mock.cart.EXPECT().GetList(gomock.Any(), cartParamMock).Return(cartResultMock, nil)
+				mock.menu.EXPECT().GetListInByID(gomock.Any(), []int64{1}).Return(menuResultMock, nil)
+				mock.transaction.EXPECT().Create(gomock.Any(), newTransactionMock).Return(transactionResultMock, nil)
+				mock.midtrans.EXPECT().Create(gomock.Any(), midtransCreateParamMock).Return(midtransResultMock, nil)
+				mock.cart.EXPECT().Update(gomock.Any(), selectParamCartMock, updateParamCartMock).Return(nil)
+				mock.midtrans_transaction.EXPECT().Create(gomock.Any(), newMidtransTransactionMock).Return(entity.MidtransTransaction{}, nil)
 			},
 			args: args{
 				ctx:   context.Background(),
@@ -381,53 +383,86 @@ func Test_transaction_GetTransactionListByUmkm(t *testing.T) {
 	transactionMock := mock_transaction.NewMockInterface(ctrl)
 	midtransTransactionMock := mock_midtrans_transaction.NewMockInterface(ctrl)
 
-	tr := transaction.Init(nil, transactionMock, cartMock, menuMock, nil, nil, midtransTransactionMock)
+	tr := transaction.Init(nil, transactionMock, cartMock, menuMock, nil, nil, midtransTransactionMock, entity.PaginationConfig{})
 
 	transactionParamMock := entity.TransactionParam{
-		UmkmID:          1,
-		Status:          entity.StatusPaid,
-		MidtransOrderID: "",
+		UmkmID:   1,
+		Statuses: []string{entity.StatusPaid, entity.StatusDone},
 	}
 
-	cartParamMock := entity.CartParam{
-		UmkmID: 1,
-		Status: entity.StatusPaid,
+	cartTransactionParamMock := entity.CartTransactionParam{
+		UmkmID:   1,
+		Statuses: []string{entity.StatusPaid, entity.StatusDone},
+		PaginationParam: entity.PaginationParam{
+			Page:      1,
+			Limit:     20,
+			SortBy:    "id",
+			SortOrder: entity.SortDesc,
+			OrderBy:   "carts.transaction_id desc",
+		},
 	}
 
 	cartResultMock := []entity.Cart{
 		{
+			UmkmID:        1,
+			MenuID:        1,
 			TransactionID: 1,
 			Status:        entity.StatusDone,
 			TotalPrice:    10000,
 			Amount:        1,
 			PricePerItem:  10000,
 		},
-	}
-
-	menuParamMock := entity.MenuParam{
-		UmkmID: 1,
+		{
+			UmkmID:        2,
+			MenuID:        2,
+			TransactionID: 1,
+			Status:        entity.StatusDone,
+			TotalPrice:    5000,
+			Amount:        1,
+			PricePerItem:  5000,
+		},
+		{
+			UmkmID:        1,
+			MenuID:        1,
+			TransactionID: 2,
+			Status:        entity.StatusPaid,
+			TotalPrice:    20000,
+			Amount:        2,
+			PricePerItem:  10000,
+		},
 	}
 
 	menuResultMock := []entity.Menu{
 		{
+			Model: gorm.Model{
+				ID: 1,
+			},
 			Name: "menu 1",
 		},
 	}
 
+	now := time.Now()
 	transactionResultMock := []entity.Transaction{
 		{
 			Model: gorm.Model{
-				ID: 1,
+				ID:        1,
+				CreatedAt: now,
 			},
 			BuyerName: "mail",
 			Seat:      "a1",
 			Notes:     "-",
-			Price:     10000,
+			Price:     15000,
+		},
+		{
+			Model: gorm.Model{
+				ID:        2,
+				CreatedAt: now,
+			},
+			BuyerName: "budi",
+			Seat:      "b2",
+			Notes:     "-",
+			Price:     20000,
 		},
-	}
-
-	midtransTransactionParamMock := entity.MidtransTransactionParam{
-		OrderID: "",
 	}
 
 	midtransTransactionResultMock := []entity.MidtransTransaction{
@@ -435,17 +470,40 @@ func Test_transaction_GetTransactionListByUmkm(t *testing.T) {
 			OrderID:       "1",
 			TransactionID: 1,
 		},
+		{
+			OrderID:       "2",
+			TransactionID: 2,
+		},
 	}
 
 	resultMock := []entity.TransactionDetailResponse{
+		{
+			ID:              2,
+			BuyerName:       "budi",
+			Seat:            "b2",
+			Notes:           "-",
+			Price:           20000,
+			Status:          entity.StatusPaid,
+			MidtransOrderID: "2",
+			CreatedAt:       "0 menit yang lalu",
+			ItemMenus: []entity.ItemMenu{
+				{
+					Name:         "menu 1",
+					Price:        20000,
+					Qty:          2,
+					PricePerItem: 10000,
+				},
+			},
+		},
 		{
 			ID:              1,
 			BuyerName:       "mail",
 			Seat:            "a1",
 			Notes:           "-",
-			Price:           10000,
+			Price:           15000,
 			Status:          entity.StatusDone,
 			MidtransOrderID: "1",
+			CreatedAt:       "0 menit yang lalu",
 			ItemMenus: []entity.ItemMenu{
 				{
 					Name:         "menu 1",
@@ -477,36 +535,69 @@ func Test_transaction_GetTransactionListByUmkm(t *testing.T) {
 	}
 
 	tests := []struct {
-		name     string
-		args     args
-		mockFunc func(mock mockfields, arg args)
-		want     []entity.TransactionDetailResponse
-		wantErr  bool
+		name           string
+		args           args
+		mockFunc       func(mock mockfields, arg args)
+		want           []entity.TransactionDetailResponse
+		wantPagination entity.Pagination
+		wantErr        bool
 	}{
 		{
-			name: "failed to get cart list",
+			name: "failed to count transactions",
 			args: args{
 				ctx:   context.Background(),
 				param: transactionParamMock,
 			},
 			mockFunc: func(mock mockfields, arg args) {
-				mock.cart.EXPECT().GetList(cartParamMock).Return([]entity.Cart{}, assert.AnError)
+				mock.cart.EXPECT().CountTransactions(gomock.Any(), cartTransactionParamMock).Return(int64(0), assert.AnError)
 			},
 			want:    []entity.TransactionDetailResponse{},
 			wantErr: true,
 		},
 		{
-			name: "cart empty",
+			name: "failed to get transaction ids",
 			args: args{
 				ctx:   context.Background(),
 				param: transactionParamMock,
 			},
 			mockFunc: func(mock mockfields, arg args) {
-				mock.cart.EXPECT().GetList(cartParamMock).Return([]entity.Cart{}, nil)
+				mock.cart.EXPECT().CountTransactions(gomock.Any(), cartTransactionParamMock).Return(int64(2), nil)
+				mock.cart.EXPECT().GetTransactionIDs(gomock.Any(), cartTransactionParamMock).Return([]uint{}, assert.AnError)
 			},
 			want:    []entity.TransactionDetailResponse{},
+			wantErr: true,
+		},
+		{
+			name: "no transaction",
+			args: args{
+				ctx:   context.Background(),
+				param: transactionParamMock,
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.cart.EXPECT().CountTransactions(gomock.Any(), cartTransactionParamMock).Return(int64(0), nil)
+				mock.cart.EXPECT().GetTransactionIDs(gomock.Any(), cartTransactionParamMock).Return([]uint{}, nil)
+			},
+			want: []entity.TransactionDetailResponse{},
+			wantPagination: entity.Pagination{
+				Page:  1,
+				Limit: 20,
+			},
 			wantErr: false,
 		},
+		{
+			name: "failed to get cart list",
+			args: args{
+				ctx:   context.Background(),
+				param: transactionParamMock,
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.cart.EXPECT().CountTransactions(gomock.Any(), cartTransactionParamMock).Return(int64(2), nil)
+				mock.cart.EXPECT().GetTransactionIDs(gomock.Any(), cartTransactionParamMock).Return([]uint{2, 1}, nil)
+				mock.cart.EXPECT().GetListInByTransactionID(gomock.Any(), []uint{2, 1}).Return([]entity.Cart{}, assert.AnError)
+			},
+			want:    []entity.TransactionDetailResponse{},
+			wantErr: true,
+		},
 		{
 			name: "failed to get menu list",
 			args: args{
@@ -514,8 +605,10 @@ func Test_transaction_GetTransactionListByUmkm(t *testing.T) {
 				param: transactionParamMock,
 			},
 			mockFunc: func(mock mockfields, arg args) {
-				mock.cart.EXPECT().GetList(cartParamMock).Return(cartResultMock, nil)
-				mock.menu.EXPECT().GetAll(menuParamMock).Return([]entity.Menu{}, assert.AnError)
+				mock.cart.EXPECT().CountTransactions(gomock.Any(), cartTransactionParamMock).Return(int64(2), nil)
+				mock.cart.EXPECT().GetTransactionIDs(gomock.Any(), cartTransactionParamMock).Return([]uint{2, 1}, nil)
+				mock.cart.EXPECT().GetListInByTransactionID(gomock.Any(), []uint{2, 1}).Return(cartResultMock, nil)
+				mock.menu.EXPECT().GetListInByID(gomock.Any(), []int64{1}).Return([]entity.Menu{}, assert.AnError)
 			},
 			want:    []entity.TransactionDetailResponse{},
 			wantErr: true,
@@ -527,9 +620,11 @@ func Test_transaction_GetTransactionListByUmkm(t *testing.T) {
 				param: transactionParamMock,
 			},
 			mockFunc: func(mock mockfields, arg args) {
-				mock.cart.EXPECT().GetList(cartParamMock).Return(cartResultMock, nil)
-				mock.menu.EXPECT().GetAll(menuParamMock).Return(menuResultMock, nil)
-				mock.transaction.EXPECT().GetListByIDs([]uint{1}).Return([]entity.Transaction{}, assert.AnError)
+				mock.cart.EXPECT().CountTransactions(gomock.Any(), cartTransactionParamMock).Return(int64(2), nil)
+				mock.cart.EXPECT().GetTransactionIDs(gomock.Any(), cartTransactionParamMock).Return([]uint{2, 1}, nil)
+				mock.cart.EXPECT().GetListInByTransactionID(gomock.Any(), []uint{2, 1}).Return(cartResultMock, nil)
+				mock.menu.EXPECT().GetListInByID(gomock.Any(), []int64{1}).Return(menuResultMock, nil)
+				mock.transaction.EXPECT().GetListByIDs(gomock.Any(), []uint{2, 1}).Return([]entity.Transaction{}, assert.AnError)
 			},
 			want:    []entity.TransactionDetailResponse{},
 			wantErr: true,
@@ -541,10 +636,12 @@ func Test_transaction_GetTransactionListByUmkm(t *testing.T) {
 				param: transactionParamMock,
 			},
 			mockFunc: func(mock mockfields, arg args) {
-				mock.cart.EXPECT().GetList(cartParamMock).Return(cartResultMock, nil)
-				mock.menu.EXPECT().GetAll(menuParamMock).Return(menuResultMock, nil)
-				mock.transaction.EXPECT().GetListByIDs([]uint{1}).Return(transactionResultMock, nil)
-				mock.midtrans_transaction.EXPECT().GetListByTrxIDs([]uint{1}, midtransTransactionParamMock).Return([]entity.MidtransTransaction{}, assert.AnError)
+				mock.cart.EXPECT().CountTransactions(gomock.Any(), cartTransactionParamMock).Return(int64(2), nil)
+				mock.cart.EXPECT().GetTransactionIDs(gomock.Any(), cartTransactionParamMock).Return([]uint{2, 1}, nil)
+				mock.cart.EXPECT().GetListInByTransactionID(gomock.Any(), []uint{2, 1}).Return(cartResultMock, nil)
+				mock.menu.EXPECT().GetListInByID(gomock.Any(), []int64{1}).Return(menuResultMock, nil)
+				mock.transaction.EXPECT().GetListByIDs(gomock.Any(), []uint{2, 1}).Return(transactionResultMock, nil)
+				mock.midtrans_transaction.EXPECT().GetListByTrxIDs(gomock.Any(), []uint{2, 1}, entity.MidtransTransactionParam{}).Return([]entity.MidtransTransaction{}, assert.AnError)
 			},
 			want:    []entity.TransactionDetailResponse{},
 			wantErr: true,
@@ -556,24 +653,390 @@ func Test_transaction_GetTransactionListByUmkm(t *testing.T) {
 				param: transactionParamMock,
 			},
 			mockFunc: func(mock mockfields, arg args) {
-				mock.cart.EXPECT().GetList(cartParamMock).Return(cartResultMock, nil)
-				mock.menu.EXPECT().GetAll(menuParamMock).Return(menuResultMock, nil)
-				mock.transaction.EXPECT().GetListByIDs([]uint{1}).Return(transactionResultMock, nil)
-				mock.midtrans_transaction.EXPECT().GetListByTrxIDs([]uint{1}, midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
+				mock.cart.EXPECT().CountTransactions(gomock.Any(), cartTransactionParamMock).Return(int64(2), nil)
+				mock.cart.EXPECT().GetTransactionIDs(gomock.Any(), cartTransactionParamMock).Return([]uint{2, 1}, nil)
+				mock.cart.EXPECT().GetListInByTransactionID(gomock.Any(), []uint{2, 1}).Return(cartResultMock, nil)
+				mock.menu.EXPECT().GetListInByID(gomock.Any(), []int64{1}).Return(menuResultMock, nil)
+				mock.transaction.EXPECT().GetListByIDs(gomock.Any(), []uint{2, 1}).Return(transactionResultMock, nil)
+				mock.midtrans_transaction.EXPECT().GetListByTrxIDs(gomock.Any(), []uint{2, 1}, entity.MidtransTransactionParam{}).Return(midtransTransactionResultMock, nil)
+			},
+			want: resultMock,
+			wantPagination: entity.Pagination{
+				Page:       1,
+				Limit:      20,
+				TotalItems: 2,
+				TotalPages: 1,
 			},
-			want:    resultMock,
 			wantErr: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc(mocks, tt.args)
-			got, err := tr.GetTransactionListByUmkm(tt.args.ctx, tt.args.param)
+			got, pagination, err := tr.GetTransactionListByUmkm(tt.args.ctx, tt.args.param)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("transaction.GetTransactionListByUmkm() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantPagination, pagination)
+		})
+	}
+}
+
+func Test_transaction_Search(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cartMock := mock_cart.NewMockInterface(ctrl)
+	menuMock := mock_menu.NewMockInterface(ctrl)
+	umkmMock := mock_umkm.NewMockInterface(ctrl)
+	transactionMock := mock_transaction.NewMockInterface(ctrl)
+	midtransTransactionMock := mock_midtrans_transaction.NewMockInterface(ctrl)
+
+	tr := transaction.Init(nil, transactionMock, cartMock, menuMock, umkmMock, nil, midtransTransactionMock, entity.PaginationConfig{})
+
+	searchParamMock := entity.TransactionSearchParam{
+		Query:       "budi",
+		PaymentType: "Gopay",
+		StartDate:   "2024-01-01",
+		EndDate:     "2024-01-31",
+	}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	resolvedParamMock := entity.TransactionSearchParam{
+		Query:         "budi",
+		PaymentType:   "Gopay",
+		StartDate:     "2024-01-01",
+		EndDate:       "2024-01-31",
+		PaymentTypeID: midtrans.GopayPayment,
+		From:          from,
+		To:            from.AddDate(0, 1, 0),
+		PaginationParam: entity.PaginationParam{
+			Page:      1,
+			Limit:     20,
+			SortBy:    "id",
+			SortOrder: entity.SortDesc,
+			OrderBy:   "transactions.id desc",
+		},
+	}
+
+	now := time.Now()
+	transactionResultMock := []entity.Transaction{
+		{
+			Model: gorm.Model{
+				ID:        1,
+				CreatedAt: now,
+			},
+			BuyerName: "budi",
+			Seat:      "a1",
+			Email:     "budi@mail.com",
+			Price:     10000,
+		},
+	}
+
+	midtransTransactionResultMock := []entity.MidtransTransaction{
+		{
+			OrderID:       "ORDER-1",
+			TransactionID: 1,
+			PaymentType:   midtrans.GopayPayment,
+			Status:        entity.StatusPaid,
+		},
+	}
+
+	cartResultMock := []entity.Cart{
+		{
+			UmkmID:        1,
+			MenuID:        1,
+			TransactionID: 1,
+			Status:        entity.StatusPaid,
+			TotalPrice:    10000,
+			Amount:        1,
+			PricePerItem:  10000,
+		},
+	}
+
+	menuResultMock := []entity.Menu{
+		{
+			Model: gorm.Model{
+				ID: 1,
+			},
+			Name: "menu 1",
+		},
+	}
+
+	umkmResultMock := []entity.Umkm{
+		{
+			Model: gorm.Model{
+				ID: 1,
+			},
+			Name: "umkm 1",
+		},
+	}
+
+	resultMock := []entity.TransactionDetailResponse{
+		{
+			ID:              1,
+			BuyerName:       "budi",
+			Seat:            "a1",
+			Email:           "budi@mail.com",
+			Price:           10000,
+			Status:          entity.StatusPaid,
+			PaymentType:     "Gopay",
+			MidtransOrderID: "ORDER-1",
+			CreatedAt:       "0 menit yang lalu",
+			ItemMenus: []entity.ItemMenu{
+				{
+					UmkmName:     "umkm 1",
+					Name:         "menu 1",
+					Status:       entity.StatusPaid,
+					Price:        10000,
+					Qty:          1,
+					PricePerItem: 10000,
+				},
+			},
+		},
+	}
+
+	type mockfields struct {
+		cart                 *mock_cart.MockInterface
+		menu                 *mock_menu.MockInterface
+		umkm                 *mock_umkm.MockInterface
+		transaction          *mock_transaction.MockInterface
+		midtrans_transaction *mock_midtrans_transaction.MockInterface
+	}
+
+	mocks := mockfields{
+		cart:                 cartMock,
+		menu:                 menuMock,
+		umkm:                 umkmMock,
+		transaction:          transactionMock,
+		midtrans_transaction: midtransTransactionMock,
+	}
+
+	type args struct {
+		ctx   context.Context
+		param entity.TransactionSearchParam
+	}
+
+	tests := []struct {
+		name           string
+		args           args
+		mockFunc       func(mock mockfields, arg args)
+		want           []entity.TransactionDetailResponse
+		wantPagination entity.Pagination
+		wantErr        bool
+	}{
+		{
+			name: "invalid date",
+			args: args{
+				ctx:   context.Background(),
+				param: entity.TransactionSearchParam{StartDate: "01-01-2024"},
+			},
+			mockFunc: func(mock mockfields, arg args) {},
+			want:     []entity.TransactionDetailResponse{},
+			wantErr:  true,
+		},
+		{
+			name: "end date before start date",
+			args: args{
+				ctx:   context.Background(),
+				param: entity.TransactionSearchParam{StartDate: "2024-01-31", EndDate: "2024-01-01"},
+			},
+			mockFunc: func(mock mockfields, arg args) {},
+			want:     []entity.TransactionDetailResponse{},
+			wantErr:  true,
+		},
+		{
+			name: "min amount above max amount",
+			args: args{
+				ctx:   context.Background(),
+				param: entity.TransactionSearchParam{MinAmount: 20000, MaxAmount: 10000},
+			},
+			mockFunc: func(mock mockfields, arg args) {},
+			want:     []entity.TransactionDetailResponse{},
+			wantErr:  true,
+		},
+		{
+			name: "unsupported payment type",
+			args: args{
+				ctx:   context.Background(),
+				param: entity.TransactionSearchParam{PaymentType: "ovo"},
+			},
+			mockFunc: func(mock mockfields, arg args) {},
+			want:     []entity.TransactionDetailResponse{},
+			wantErr:  true,
+		},
+		{
+			name: "failed to count transactions",
+			args: args{
+				ctx:   context.Background(),
+				param: searchParamMock,
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.transaction.EXPECT().CountSearch(gomock.Any(), resolvedParamMock).Return(int64(0), assert.AnError)
+			},
+			want:    []entity.TransactionDetailResponse{},
+			wantErr: true,
+		},
+		{
+			name: "failed to search transactions",
+			args: args{
+				ctx:   context.Background(),
+				param: searchParamMock,
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.transaction.EXPECT().CountSearch(gomock.Any(), resolvedParamMock).Return(int64(1), nil)
+				mock.transaction.EXPECT().Search(gomock.Any(), resolvedParamMock).Return([]entity.Transaction{}, assert.AnError)
+			},
+			want:    []entity.TransactionDetailResponse{},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			args: args{
+				ctx:   context.Background(),
+				param: searchParamMock,
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.transaction.EXPECT().CountSearch(gomock.Any(), resolvedParamMock).Return(int64(1), nil)
+				mock.transaction.EXPECT().Search(gomock.Any(), resolvedParamMock).Return(transactionResultMock, nil)
+				mock.midtrans_transaction.EXPECT().GetListByTrxIDs(gomock.Any(), []uint{1}, entity.MidtransTransactionParam{}).Return(midtransTransactionResultMock, nil)
+				mock.cart.EXPECT().GetListInByTransactionID(gomock.Any(), []uint{1}).Return(cartResultMock, nil)
+				mock.menu.EXPECT().GetListInByID(gomock.Any(), []int64{1}).Return(menuResultMock, nil)
+				mock.umkm.EXPECT().GetListInByID(gomock.Any(), []uint{1}).Return(umkmResultMock, nil)
+			},
+			want: resultMock,
+			wantPagination: entity.Pagination{
+				Page:       1,
+				Limit:      20,
+				TotalItems: 1,
+				TotalPages: 1,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mocks, tt.args)
+			got, pagination, err := tr.Search(tt.args.ctx, tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("transaction.Search() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantPagination, pagination)
+		})
+	}
+}
+
+func Test_transaction_GenerateSearchCSV(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cartMock := mock_cart.NewMockInterface(ctrl)
+	menuMock := mock_menu.NewMockInterface(ctrl)
+	umkmMock := mock_umkm.NewMockInterface(ctrl)
+	transactionMock := mock_transaction.NewMockInterface(ctrl)
+	midtransTransactionMock := mock_midtrans_transaction.NewMockInterface(ctrl)
+
+	tr := transaction.Init(nil, transactionMock, cartMock, menuMock, umkmMock, nil, midtransTransactionMock, entity.PaginationConfig{})
+
+	resolvedParamMock := entity.TransactionSearchParam{
+		Query: "budi",
+		PaginationParam: entity.PaginationParam{
+			Page:      1,
+			Limit:     10000,
+			SortBy:    "id",
+			SortOrder: entity.SortDesc,
+			OrderBy:   "transactions.id desc",
+		},
+	}
+
+	createdAt := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	transactionResultMock := []entity.Transaction{
+		{
+			Model: gorm.Model{
+				ID:        1,
+				CreatedAt: createdAt,
+			},
+			BuyerName: "=HYPERLINK(\"http://evil\")",
+			Seat:      "+a1",
+			Email:     "budi@mail.com",
+			Price:     10000,
+		},
+	}
+
+	type mockfields struct {
+		cart                 *mock_cart.MockInterface
+		menu                 *mock_menu.MockInterface
+		umkm                 *mock_umkm.MockInterface
+		transaction          *mock_transaction.MockInterface
+		midtrans_transaction *mock_midtrans_transaction.MockInterface
+	}
+
+	mocks := mockfields{
+		cart:                 cartMock,
+		menu:                 menuMock,
+		umkm:                 umkmMock,
+		transaction:          transactionMock,
+		midtrans_transaction: midtransTransactionMock,
+	}
+
+	tests := []struct {
+		name     string
+		param    entity.TransactionSearchParam
+		mockFunc func(mock mockfields)
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "invalid date",
+			param:    entity.TransactionSearchParam{StartDate: "01-01-2024"},
+			mockFunc: func(mock mockfields) {},
+			wantErr:  true,
+		},
+		{
+			name:  "failed to search transactions",
+			param: entity.TransactionSearchParam{Query: "budi"},
+			mockFunc: func(mock mockfields) {
+				mock.transaction.EXPECT().Search(gomock.Any(), resolvedParamMock).Return([]entity.Transaction{}, assert.AnError)
+			},
+			wantErr: true,
+		},
+		{
+			name:  "success escapes formulas",
+			param: entity.TransactionSearchParam{Query: "budi"},
+			mockFunc: func(mock mockfields) {
+				mock.transaction.EXPECT().Search(gomock.Any(), resolvedParamMock).Return(transactionResultMock, nil)
+				mock.midtrans_transaction.EXPECT().GetListByTrxIDs(gomock.Any(), []uint{1}, entity.MidtransTransactionParam{}).Return([]entity.MidtransTransaction{
+					{OrderID: "ORDER-1", TransactionID: 1, PaymentType: midtrans.GopayPayment, Status: entity.StatusPaid},
+				}, nil)
+				mock.cart.EXPECT().GetListInByTransactionID(gomock.Any(), []uint{1}).Return([]entity.Cart{
+					{UmkmID: 1, MenuID: 1, TransactionID: 1, Status: entity.StatusPaid, TotalPrice: 10000, Amount: 1, PricePerItem: 10000},
+				}, nil)
+				mock.menu.EXPECT().GetListInByID(gomock.Any(), []int64{1}).Return([]entity.Menu{
+					{Model: gorm.Model{ID: 1}, Name: "-menu"},
+				}, nil)
+				mock.umkm.EXPECT().GetListInByID(gomock.Any(), []uint{1}).Return([]entity.Umkm{
+					{Model: gorm.Model{ID: 1}, Name: "umkm 1"},
+				}, nil)
+			},
+			want: "transaction_id,created_at,order_id,buyer_name,seat,email,status,payment_type,price,items\n" +
+				"1,2024-01-01T10:00:00Z,ORDER-1,\"'=HYPERLINK(\"\"http://evil\"\")\",'+a1,budi@mail.com,paid,Gopay,10000,'-menu x1 (umkm 1)\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mocks)
+			got, _, err := tr.GenerateSearchCSV(context.Background(), tt.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("transaction.GenerateSearchCSV() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, string(got))
+			}
 		})
 	}
 }
@@ -662,7 +1125,7 @@ func Test_transaction_GetOrderDetail(t *testing.T) {
 		},
 	}
 
-	tr := transaction.Init(nil, transactionMock, cartMock, menuMock, umkmMock, nil, midtransTransactionMock)
+	tr := transaction.Init(nil, transactionMock, cartMock, menuMock, umkmMock, nil, midtransTransactionMock, entity.PaginationConfig{})
 
 	type mockfields struct {
 		cart                 *mock_cart.MockInterface
@@ -699,7 +1162,7 @@ func Test_transaction_GetOrderDetail(t *testing.T) {
 				param: transactionParamMock,
 			},
 			mockFunc: func(mock mockfields, arg args) {
-				mock.transaction.EXPECT().Get(transactionParamMock).Return(entity.Transaction{}, assert.AnError)
+				mock.transaction.EXPECT().Get(gomock.Any(), transactionParamMock).Return(entity.Transaction{}, assert.AnError)
 			},
 			want:    entity.TransactionDetailResponse{},
 			wantErr: true,
@@ -711,8 +1174,8 @@ func Test_transaction_GetOrderDetail(t *testing.T) {
 				param: transactionParamMock,
 			},
 			mockFunc: func(mock mockfields, arg args) {
-				mock.transaction.EXPECT().Get(transactionParamMock).Return(transactionResultMock, nil)
-				mock.midtrans_transaction.EXPECT().Get(midtransTransactionParamMock).Return(entity.MidtransTransaction{}, assert.AnError)
+				mock.transaction.EXPECT().Get(gomock.Any(), transactionParamMock).Return(transactionResultMock, nil)
+				mock.midtrans_transaction.EXPECT().Get(gomock.Any(), midtransTransactionParamMock).Return(entity.MidtransTransaction{}, assert.AnError)
 			},
 			want:    entity.TransactionDetailResponse{},
 			wantErr: true,
@@ -724,9 +1187,9 @@ func Test_transaction_GetOrderDetail(t *testing.T) {
 				param: transactionParamMock,
 			},
 			mockFunc: func(mock mockfields, arg args) {
-				mock.transaction.EXPECT().Get(transactionParamMock).Return(transactionResultMock, nil)
-				mock.midtrans_transaction.EXPECT().Get(midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
-				mock.cart.EXPECT().GetList(cartParamMock).Return([]entity.Cart{}, assert.AnError)
+				mock.transaction.EXPECT().Get(gomock.Any(), transactionParamMock).Return(transactionResultMock, nil)
+				mock.midtrans_transaction.EXPECT().Get(gomock.Any(), midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
+				mock.cart.EXPECT().GetList(gomock.Any(), cartParamMock).Return([]entity.Cart{}, assert.AnError)
 			},
 			want:    entity.TransactionDetailResponse{},
 			wantErr: true,
@@ -738,10 +1201,10 @@ func Test_transaction_GetOrderDetail(t *testing.T) {
 				param: transactionParamMock,
 			},
 			mockFunc: func(mock mockfields, arg args) {
-				mock.transaction.EXPECT().Get(transactionParamMock).Return(transactionResultMock, nil)
-				mock.midtrans_transaction.EXPECT().Get(midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
-				mock.cart.EXPECT().GetList(cartParamMock).Return(cartResultMock, nil)
-				mock.umkm.EXPECT().GetList(entity.UmkmParam{}).Return([]entity.Umkm{}, assert.AnError)
+				mock.transaction.EXPECT().Get(gomock.Any(), transactionParamMock).Return(transactionResultMock, nil)
+				mock.midtrans_transaction.EXPECT().Get(gomock.Any(), midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
+				mock.cart.EXPECT().GetList(gomock.Any(), cartParamMock).Return(cartResultMock, nil)
+				mock.umkm.EXPECT().GetList(gomock.Any(), entity.UmkmParam{}).Return([]entity.Umkm{}, assert.AnError)
 			},
 			want:    entity.TransactionDetailResponse{},
 			wantErr: true,
@@ -753,11 +1216,11 @@ func Test_transaction_GetOrderDetail(t *testing.T) {
 				param: transactionParamMock,
 			},
 			mockFunc: func(mock mockfields, arg args) {
-				mock.transaction.EXPECT().Get(transactionParamMock).Return(transactionResultMock, nil)
-				mock.midtrans_transaction.EXPECT().Get(midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
-				mock.cart.EXPECT().GetList(cartParamMock).Return(cartResultMock, nil)
-				mock.umkm.EXPECT().GetList(entity.UmkmParam{}).Return(umkmResultMock, nil)
-				mock.menu.EXPECT().GetListInByID([]int64{1}).Return([]entity.Menu{}, assert.AnError)
+				mock.transaction.EXPECT().Get(gomock.Any(), transactionParamMock).Return(transactionResultMock, nil)
+				mock.midtrans_transaction.EXPECT().Get(gomock.Any(), midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
+				mock.cart.EXPECT().GetList(gomock.Any(), cartParamMock).Return(cartResultMock, nil)
+				mock.umkm.EXPECT().GetList(gomock.Any(), entity.UmkmParam{}).Return(umkmResultMock, nil)
+				mock.menu.EXPECT().GetListInByID(gomock.Any(), []int64{1}).Return([]entity.Menu{}, assert.AnError)
 			},
 			want:    entity.TransactionDetailResponse{},
 			wantErr: true,
@@ -769,11 +1232,11 @@ func Test_transaction_GetOrderDetail(t *testing.T) {
 				param: transactionParamMock,
 			},
 			mockFunc: func(mock mockfields, arg args) {
-				mock.transaction.EXPECT().Get(transactionParamMock).Return(transactionResultMock, nil)
-				mock.midtrans_transaction.EXPECT().Get(midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
-				mock.cart.EXPECT().GetList(cartParamMock).Return(cartResultMock, nil)
-				mock.umkm.EXPECT().GetList(entity.UmkmParam{}).Return(umkmResultMock, nil)
-				mock.menu.EXPECT().GetListInByID([]int64{1}).Return(menuResultMock, nil)
+				mock.transaction.EXPECT().Get(gomock.Any(), transactionParamMock).Return(transactionResultMock, nil)
+				mock.midtrans_transaction.EXPECT().Get(gomock.Any(), midtransTransactionParamMock).Return(midtransTransactionResultMock, nil)
+				mock.cart.EXPECT().GetList(gomock.Any(), cartParamMock).Return(cartResultMock, nil)
+				mock.umkm.EXPECT().GetList(gomock.Any(), entity.UmkmParam{}).Return(umkmResultMock, nil)
+				mock.menu.EXPECT().GetListInByID(gomock.Any(), []int64{1}).Return(menuResultMock, nil)
 			},
 			want:    resultMock,
 			wantErr: false,
@@ -817,11 +1280,9 @@ func Test_transaction_CompleteOrder(t *testing.T) {
 		},
 	}
 
-	updateCartParamMock := entity.UpdateCartParam{
-		Status: entity.StatusDone,
-	}
+	updateCartParamMock := doneCartMatcher{}
 
-	tr := transaction.Init(nil, nil, cartMock, nil, nil, nil, nil)
+	tr := transaction.Init(nil, nil, cartMock, nil, nil, nil, nil, entity.PaginationConfig{})
 
 	type mockfields struct {
 		cart *mock_cart.MockInterface
@@ -849,7 +1310,7 @@ func Test_transaction_CompleteOrder(t *testing.T) {
 				param: transactionParamMock,
 			},
 			mockFunc: func(mock mockfields, arg args) {
-				mock.cart.EXPECT().GetList(cartParamMock).Return([]entity.Cart{}, assert.AnError)
+				mock.cart.EXPECT().GetList(gomock.Any(), cartParamMock).Return([]entity.Cart{}, assert.AnError)
 			},
 			wantErr: true,
 		},
@@ -860,8 +1321,8 @@ func Test_transaction_CompleteOrder(t *testing.T) {
 				param: transactionParamMock,
 			},
 			mockFunc: func(mock mockfields, arg args) {
-				mock.cart.EXPECT().GetList(cartParamMock).Return(cartResultMock, nil)
-				mock.cart.EXPECT().UpdatesByIDs([]uint{1}, updateCartParamMock).Return(assert.AnError)
+				mock.cart.EXPECT().GetList(gomock.Any(), cartParamMock).Return(cartResultMock, nil)
+				mock.cart.EXPECT().UpdatesByIDs(gomock.Any(), []uint{1}, updateCartParamMock).Return(assert.AnError)
 			},
 			wantErr: true,
 		},
@@ -872,8 +1333,8 @@ func Test_transaction_CompleteOrder(t *testing.T) {
 				param: transactionParamMock,
 			},
 			mockFunc: func(mock mockfields, arg args) {
-				mock.cart.EXPECT().GetList(cartParamMock).Return(cartResultMock, nil)
-				mock.cart.EXPECT().UpdatesByIDs([]uint{1}, updateCartParamMock).Return(nil)
+				mock.cart.EXPECT().GetList(gomock.Any(), cartParamMock).Return(cartResultMock, nil)
+				mock.cart.EXPECT().UpdatesByIDs(gomock.Any(), []uint{1}, updateCartParamMock).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -889,3 +1350,14 @@ func Test_transaction_CompleteOrder(t *testing.T) {
 		})
 	}
 }
+
+type doneCartMatcher struct{}
+
+func (m doneCartMatcher) Matches(x interface{}) bool {
+	param, ok := x.(entity.UpdateCartParam)
+	return ok && param.Status == entity.StatusDone && param.DoneAt != nil
+}
+
+func (m doneCartMatcher) String() string {
+	return "is a done cart update with done at"
+}
@@ -2,36 +2,54 @@ package umkm
 
 import (
 	"context"
-	"errors"
 	umkmDom "go-clean/src/business/domain/umkm"
 	"go-clean/src/business/entity"
+	"go-clean/src/lib/apperr"
 	"go-clean/src/lib/auth"
 )
 
+var (
+	ErrUmkmIDRequired = apperr.Validation("umkm_id_required", "umkm id wajib diisi")
+	ErrUmkmForbidden  = apperr.Forbidden("umkm_forbidden", "tidak memiliki akses ke umkm ini")
+)
+
 type Interface interface {
-	Create(params entity.CreateUmkmParam) (entity.Umkm, error)
-	GetList(param entity.UmkmParam) ([]entity.Umkm, error)
-	Get(params entity.UmkmParam) (entity.Umkm, error)
-	Update(param entity.UmkmParam, inputParam entity.UpdateUmkmParam) error
-	Delete(param entity.UmkmParam) error
+	Create(ctx context.Context, params entity.CreateUmkmParam) (entity.Umkm, error)
+	GetList(ctx context.Context, param entity.UmkmParam) ([]entity.Umkm, entity.Pagination, error)
+	Get(ctx context.Context, params entity.UmkmParam) (entity.Umkm, error)
+	Update(ctx context.Context, param entity.UmkmParam, inputParam entity.UpdateUmkmParam) error
+	Delete(ctx context.Context, param entity.UmkmParam) error
 	ValidateUmkm(ctx context.Context, umkmId uint, user auth.UserAuthInfo) error
 	SaveImage(ctx context.Context, param entity.UmkmParam, fileLocation string) error
 }
 
+var umkmSortOptions = entity.SortOptions{
+	Columns: map[string]string{
+		"id":         "id",
+		"name":       "name",
+		"created_at": "created_at",
+	},
+	Default:      "id",
+	DefaultOrder: entity.SortAsc,
+	TieBreaker:   "id",
+}
+
 type umkm struct {
-	umkm umkmDom.Interface
+	umkm       umkmDom.Interface
+	pagination entity.PaginationConfig
 }
 
-func Init(ud umkmDom.Interface) Interface {
+func Init(ud umkmDom.Interface, pagination entity.PaginationConfig) Interface {
 	u := &umkm{
-		umkm: ud,
+		umkm:       ud,
+		pagination: pagination,
 	}
 
 	return u
 }
 
-func (u *umkm) Create(params entity.CreateUmkmParam) (entity.Umkm, error) {
-	umkm, err := u.umkm.Create(entity.Umkm{
+func (u *umkm) Create(ctx context.Context, params entity.CreateUmkmParam) (entity.Umkm, error) {
+	umkm, err := u.umkm.Create(ctx, entity.Umkm{
 		Name:             params.Name,
 		Slogan:           params.Slogan,
 		Status:           entity.StatusClose,
@@ -45,17 +63,33 @@ func (u *umkm) Create(params entity.CreateUmkmParam) (entity.Umkm, error) {
 	return umkm, nil
 }
 
-func (u *umkm) GetList(param entity.UmkmParam) ([]entity.Umkm, error) {
-	umkms, err := u.umkm.GetList(param)
+func (u *umkm) GetList(ctx context.Context, param entity.UmkmParam) ([]entity.Umkm, entity.Pagination, error) {
+	umkms := []entity.Umkm{}
+
+	paginationParam, err := param.PaginationParam.Paginate(u.pagination, umkmSortOptions)
+	if err != nil {
+		return umkms, entity.Pagination{}, err
+	}
+	param.PaginationParam = paginationParam
+
+	total, err := u.umkm.Count(ctx, param)
+	if err != nil {
+		return umkms, entity.Pagination{}, err
+	}
+
+	umkms, err = u.umkm.GetList(ctx, param)
 	if err != nil {
-		return umkms, err
+		return umkms, entity.Pagination{}, err
 	}
 
-	return umkms, nil
+	pagination := entity.NewPagination(param.PaginationParam, total)
+	pagination.SetNextCursor(param.PaginationParam, umkmSortOptions, umkms)
+
+	return umkms, pagination, nil
 }
 
-func (u *umkm) Get(params entity.UmkmParam) (entity.Umkm, error) {
-	umkm, err := u.umkm.Get(params)
+func (u *umkm) Get(ctx context.Context, params entity.UmkmParam) (entity.Umkm, error) {
+	umkm, err := u.umkm.Get(ctx, params)
 	if err != nil {
 		return umkm, err
 	}
@@ -63,21 +97,21 @@ func (u *umkm) Get(params entity.UmkmParam) (entity.Umkm, error) {
 	return umkm, nil
 }
 
-func (u *umkm) Update(param entity.UmkmParam, inputParam entity.UpdateUmkmParam) error {
-	umkm, err := u.umkm.Get(param)
+func (u *umkm) Update(ctx context.Context, param entity.UmkmParam, inputParam entity.UpdateUmkmParam) error {
+	umkm, err := u.umkm.Get(ctx, param)
 	if err != nil {
 		return err
 	}
 
-	if err := u.umkm.Update(entity.UmkmParam{ID: umkm.ID}, inputParam); err != nil {
+	if err := u.umkm.Update(ctx, entity.UmkmParam{ID: umkm.ID}, inputParam); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (u *umkm) Delete(param entity.UmkmParam) error {
-	if err := u.umkm.Delete(param); err != nil {
+func (u *umkm) Delete(ctx context.Context, param entity.UmkmParam) error {
+	if err := u.umkm.Delete(ctx, param); err != nil {
 		return err
 	}
 
@@ -86,25 +120,25 @@ func (u *umkm) Delete(param entity.UmkmParam) error {
 
 func (u *umkm) ValidateUmkm(ctx context.Context, umkmId uint, user auth.UserAuthInfo) error {
 	if umkmId == 0 {
-		return errors.New("please provide umkm id")
+		return ErrUmkmIDRequired
 	}
 
-	if umkmId != user.User.UmkmID && !user.User.IsAdmin {
-		return errors.New("unauthorized")
+	if umkmId != user.User.UmkmID && !user.User.IsPlatformAdmin() {
+		return ErrUmkmForbidden
 	}
 
 	return nil
 }
 
 func (u *umkm) SaveImage(ctx context.Context, param entity.UmkmParam, fileLocation string) error {
-	umkm, err := u.umkm.Get(entity.UmkmParam{
+	umkm, err := u.umkm.Get(ctx, entity.UmkmParam{
 		ID: param.ID,
 	})
 	if err != nil {
 		return err
 	}
 
-	if err := u.umkm.Update(entity.UmkmParam{ID: umkm.ID}, entity.UpdateUmkmParam{
+	if err := u.umkm.Update(ctx, entity.UmkmParam{ID: umkm.ID}, entity.UpdateUmkmParam{
 		ImgPath: fileLocation,
 	}); err != nil {
 		return err
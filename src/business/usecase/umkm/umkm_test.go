@@ -32,7 +32,7 @@ func Test_umkm_Create(t *testing.T) {
 		Slogan: "slogan",
 	}
 
-	u := umkm.Init(umkmMock)
+	u := umkm.Init(umkmMock, entity.PaginationConfig{})
 
 	type mockfields struct {
 		umkm *mock_umkm.MockInterface
@@ -56,7 +56,7 @@ func Test_umkm_Create(t *testing.T) {
 		{
 			name: "failed to create umkm",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.umkm.EXPECT().Create(gomock.Any()).Return(entity.Umkm{}, assert.AnError)
+				mock.umkm.EXPECT().Create(gomock.Any(), gomock.Any()).Return(entity.Umkm{}, assert.AnError)
 			},
 			args: args{
 				params: paramMock,
@@ -67,7 +67,7 @@ func Test_umkm_Create(t *testing.T) {
 		{
 			name: "success",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.umkm.EXPECT().Create(gomock.Any()).Return(umkmResultMock, nil)
+				mock.umkm.EXPECT().Create(gomock.Any(), gomock.Any()).Return(umkmResultMock, nil)
 			},
 			args: args{
 				params: paramMock,
@@ -79,7 +79,7 @@ func Test_umkm_Create(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc(mocks, tt.args)
-			got, err := u.Create(tt.args.params)
+			got, err := u.Create(context.Background(), tt.args.params)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("umkm.Create() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -98,6 +98,16 @@ func Test_umkm_GetList(t *testing.T) {
 	paramMock := entity.UmkmParam{
 		ID: 1,
 	}
+	listParamMock := entity.UmkmParam{
+		ID: 1,
+		PaginationParam: entity.PaginationParam{
+			Page:      1,
+			Limit:     20,
+			SortBy:    "id",
+			SortOrder: entity.SortAsc,
+			OrderBy:   "id asc",
+		},
+	}
 
 	umkmResultMock := []entity.Umkm{
 		{
@@ -109,7 +119,7 @@ func Test_umkm_GetList(t *testing.T) {
 		},
 	}
 
-	u := umkm.Init(umkmMock)
+	u := umkm.Init(umkmMock, entity.PaginationConfig{})
 
 	type mockfields struct {
 		umkm *mock_umkm.MockInterface
@@ -124,16 +134,42 @@ func Test_umkm_GetList(t *testing.T) {
 	}
 
 	tests := []struct {
-		name     string
-		mockFunc func(mock mockfields, arg args)
-		args     args
-		want     []entity.Umkm
-		wantErr  bool
+		name           string
+		mockFunc       func(mock mockfields, arg args)
+		args           args
+		want           []entity.Umkm
+		wantPagination entity.Pagination
+		wantErr        bool
 	}{
+		{
+			name:     "invalid page",
+			mockFunc: func(mock mockfields, arg args) {},
+			args: args{
+				params: entity.UmkmParam{
+					PaginationParam: entity.PaginationParam{
+						Page: -1,
+					},
+				},
+			},
+			want:    []entity.Umkm{},
+			wantErr: true,
+		},
+		{
+			name: "failed to count umkm",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.umkm.EXPECT().Count(gomock.Any(), listParamMock).Return(int64(0), assert.AnError)
+			},
+			args: args{
+				params: paramMock,
+			},
+			want:    []entity.Umkm{},
+			wantErr: true,
+		},
 		{
 			name: "failed to get umkm list",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.umkm.EXPECT().GetList(gomock.Any()).Return([]entity.Umkm{}, assert.AnError)
+				mock.umkm.EXPECT().Count(gomock.Any(), listParamMock).Return(int64(1), nil)
+				mock.umkm.EXPECT().GetList(gomock.Any(), gomock.Any()).Return([]entity.Umkm{}, assert.AnError)
 			},
 			args: args{
 				params: paramMock,
@@ -144,24 +180,32 @@ func Test_umkm_GetList(t *testing.T) {
 		{
 			name: "success",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.umkm.EXPECT().GetList(paramMock).Return(umkmResultMock, nil)
+				mock.umkm.EXPECT().Count(gomock.Any(), listParamMock).Return(int64(1), nil)
+				mock.umkm.EXPECT().GetList(gomock.Any(), listParamMock).Return(umkmResultMock, nil)
 			},
 			args: args{
 				params: paramMock,
 			},
-			want:    umkmResultMock,
+			want: umkmResultMock,
+			wantPagination: entity.Pagination{
+				Page:       1,
+				Limit:      20,
+				TotalItems: 1,
+				TotalPages: 1,
+			},
 			wantErr: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc(mocks, tt.args)
-			got, err := u.GetList(tt.args.params)
+			got, pagination, err := u.GetList(context.Background(), tt.args.params)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("umkm.GetList() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantPagination, pagination)
 		})
 	}
 }
@@ -184,7 +228,7 @@ func Test_umkm_Get(t *testing.T) {
 		Slogan: "slogan",
 	}
 
-	u := umkm.Init(umkmMock)
+	u := umkm.Init(umkmMock, entity.PaginationConfig{})
 
 	type mockfields struct {
 		umkm *mock_umkm.MockInterface
@@ -208,7 +252,7 @@ func Test_umkm_Get(t *testing.T) {
 		{
 			name: "failed to get umkm list",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.umkm.EXPECT().Get(gomock.Any()).Return(entity.Umkm{}, assert.AnError)
+				mock.umkm.EXPECT().Get(gomock.Any(), gomock.Any()).Return(entity.Umkm{}, assert.AnError)
 			},
 			args: args{
 				params: paramMock,
@@ -219,7 +263,7 @@ func Test_umkm_Get(t *testing.T) {
 		{
 			name: "success",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.umkm.EXPECT().Get(paramMock).Return(umkmResultMock, nil)
+				mock.umkm.EXPECT().Get(gomock.Any(), paramMock).Return(umkmResultMock, nil)
 			},
 			args: args{
 				params: paramMock,
@@ -231,7 +275,7 @@ func Test_umkm_Get(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc(mocks, tt.args)
-			got, err := u.Get(tt.args.params)
+			got, err := u.Get(context.Background(), tt.args.params)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("umkm.Get() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -261,7 +305,7 @@ func Test_umkm_Update(t *testing.T) {
 		},
 	}
 
-	u := umkm.Init(umkmMock)
+	u := umkm.Init(umkmMock, entity.PaginationConfig{})
 
 	type mockfields struct {
 		umkm *mock_umkm.MockInterface
@@ -285,7 +329,7 @@ func Test_umkm_Update(t *testing.T) {
 		{
 			name: "failed to get umkm",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.umkm.EXPECT().Get(paramMock).Return(entity.Umkm{}, assert.AnError)
+				mock.umkm.EXPECT().Get(gomock.Any(), paramMock).Return(entity.Umkm{}, assert.AnError)
 			},
 			args: args{
 				params: paramMock,
@@ -295,8 +339,8 @@ func Test_umkm_Update(t *testing.T) {
 		{
 			name: "failed to update umkm",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.umkm.EXPECT().Get(paramMock).Return(umkmResultMock, nil)
-				mock.umkm.EXPECT().Update(paramMock, updateParamMock).Return(assert.AnError)
+				mock.umkm.EXPECT().Get(gomock.Any(), paramMock).Return(umkmResultMock, nil)
+				mock.umkm.EXPECT().Update(gomock.Any(), paramMock, updateParamMock).Return(assert.AnError)
 			},
 			args: args{
 				params:      paramMock,
@@ -307,8 +351,8 @@ func Test_umkm_Update(t *testing.T) {
 		{
 			name: "success",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.umkm.EXPECT().Get(paramMock).Return(umkmResultMock, nil)
-				mock.umkm.EXPECT().Update(paramMock, updateParamMock).Return(nil)
+				mock.umkm.EXPECT().Get(gomock.Any(), paramMock).Return(umkmResultMock, nil)
+				mock.umkm.EXPECT().Update(gomock.Any(), paramMock, updateParamMock).Return(nil)
 			},
 			args: args{
 				params:      paramMock,
@@ -320,7 +364,7 @@ func Test_umkm_Update(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc(mocks, tt.args)
-			err := u.Update(tt.args.params, tt.args.updateParam)
+			err := u.Update(context.Background(), tt.args.params, tt.args.updateParam)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("umkm.Update() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -339,7 +383,7 @@ func Test_umkm_Delete(t *testing.T) {
 		ID: 1,
 	}
 
-	u := umkm.Init(umkmMock)
+	u := umkm.Init(umkmMock, entity.PaginationConfig{})
 
 	type mockfields struct {
 		umkm *mock_umkm.MockInterface
@@ -362,7 +406,7 @@ func Test_umkm_Delete(t *testing.T) {
 		{
 			name: "failed to delete umkm",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.umkm.EXPECT().Delete(paramMock).Return(assert.AnError)
+				mock.umkm.EXPECT().Delete(gomock.Any(), paramMock).Return(assert.AnError)
 			},
 			args: args{
 				params: paramMock,
@@ -372,7 +416,7 @@ func Test_umkm_Delete(t *testing.T) {
 		{
 			name: "success",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.umkm.EXPECT().Delete(paramMock).Return(nil)
+				mock.umkm.EXPECT().Delete(gomock.Any(), paramMock).Return(nil)
 			},
 			args: args{
 				params: paramMock,
@@ -383,7 +427,7 @@ func Test_umkm_Delete(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc(mocks, tt.args)
-			err := u.Delete(tt.args.params)
+			err := u.Delete(context.Background(), tt.args.params)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("umkm.Delete() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -400,6 +444,7 @@ func Test_umkm_ValidateUmkm(t *testing.T) {
 		User: auth.User{
 			IsAdmin: true,
 			UmkmID:  1,
+			Role:    auth.RoleSuperAdmin,
 		},
 	}
 
@@ -407,10 +452,17 @@ func Test_umkm_ValidateUmkm(t *testing.T) {
 		User: auth.User{
 			IsAdmin: false,
 			UmkmID:  1,
+			Role:    auth.RoleTenantOwner,
+		},
+	}
+
+	userAuthFinanceMock := auth.UserAuthInfo{
+		User: auth.User{
+			Role: auth.RoleFinanceAdmin,
 		},
 	}
 
-	u := umkm.Init(nil)
+	u := umkm.Init(nil, entity.PaginationConfig{})
 
 	type args struct {
 		ctx    context.Context
@@ -450,6 +502,24 @@ func Test_umkm_ValidateUmkm(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "success tenant own umkm",
+			args: args{
+				ctx:    context.Background(),
+				umkmId: 1,
+				user:   userAuthMock,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success platform role on any umkm",
+			args: args{
+				ctx:    context.Background(),
+				umkmId: 2,
+				user:   userAuthFinanceMock,
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
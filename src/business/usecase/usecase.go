@@ -2,7 +2,9 @@ package usecase
 
 import (
 	"go-clean/src/business/domain"
+	"go-clean/src/business/entity"
 	analytic "go-clean/src/business/usecase/analytic"
+	auditlog "go-clean/src/business/usecase/audit_log"
 	"go-clean/src/business/usecase/cart"
 	"go-clean/src/business/usecase/menu"
 	midtranstransaction "go-clean/src/business/usecase/midtrans_transaction"
@@ -13,6 +15,16 @@ import (
 	"go-clean/src/lib/auth"
 )
 
+type Config struct {
+	Analytic   analytic.Config
+	User       user.Config
+	Pagination entity.PaginationConfig
+}
+
+func (c Config) Validate() error {
+	return c.Pagination.Validate()
+}
+
 type Usecase struct {
 	User                user.Interface
 	Umkm                umkm.Interface
@@ -22,18 +34,20 @@ type Usecase struct {
 	MidtransTransaction midtranstransaction.Interface
 	Analytic            analytic.Interface
 	Withdraw            withdraw.Interface
+	AuditLog            auditlog.Interface
 }
 
-func Init(auth auth.Interface, d *domain.Domains) *Usecase {
+func Init(auth auth.Interface, d *domain.Domains, cfg Config) *Usecase {
 	uc := &Usecase{
-		User:                user.Init(d.User, auth, d.Cart, d.Umkm),
-		Umkm:                umkm.Init(d.Umkm),
-		Menu:                menu.Init(d.Menu),
+		User:                user.Init(d.User, auth, d.Cart, d.Umkm, d.RefreshToken, d.RevokedToken, d.PasswordResetCode, d.LoginEvent, d.Transaction, cfg.User, cfg.Pagination),
+		Umkm:                umkm.Init(d.Umkm, cfg.Pagination),
+		Menu:                menu.Init(d.Menu, d.Cart, cfg.Pagination),
 		Cart:                cart.Init(d.Cart, auth, d.Menu, d.Umkm),
-		Transaction:         transaction.Init(auth, d.Transaction, d.Cart, d.Menu, d.Umkm, d.Midtrans, d.MidtransTransaction),
+		Transaction:         transaction.Init(auth, d.Transaction, d.Cart, d.Menu, d.Umkm, d.Midtrans, d.MidtransTransaction, cfg.Pagination),
 		MidtransTransaction: midtranstransaction.Init(d.MidtransTransaction, d.Midtrans, d.Cart),
-		Analytic:            analytic.Init(d.Cart),
-		Withdraw:            withdraw.Init(d.Withdraw, d.Umkm),
+		Analytic:            analytic.Init(d.Cart, d.Menu, d.Umkm, d.MidtransTransaction, cfg.Analytic),
+		Withdraw:            withdraw.Init(d.Withdraw, d.Umkm, cfg.Pagination),
+		AuditLog:            auditlog.Init(d.AuditLog, cfg.Pagination),
 	}
 
 	return uc
@@ -4,104 +4,819 @@ import (
 	"context"
 	"errors"
 	cartDom "go-clean/src/business/domain/cart"
+	loginEventDom "go-clean/src/business/domain/login_event"
+	passwordResetCodeDom "go-clean/src/business/domain/password_reset_code"
+	refreshTokenDom "go-clean/src/business/domain/refresh_token"
+	revokedTokenDom "go-clean/src/business/domain/revoked_token"
+	transactionDom "go-clean/src/business/domain/transaction"
 	umkmDom "go-clean/src/business/domain/umkm"
 	userDom "go-clean/src/business/domain/user"
 	"go-clean/src/business/entity"
+	"go-clean/src/lib/apperr"
 	"go-clean/src/lib/auth"
+	libsql "go-clean/src/lib/sql"
+	"math"
+	"strings"
+	"time"
+	"unicode"
 
+	gonanoid "github.com/matoous/go-nanoid/v2"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type Interface interface {
-	Create(params entity.CreateUserParam) (entity.User, error)
-	Login(params entity.LoginUserParam) (string, error)
-	GenerateGuestToken() (string, error)
-	Get(param entity.UserParam) (entity.User, error)
+	Create(ctx context.Context, params entity.CreateUserParam) (entity.User, error)
+	RegisterBuyer(ctx context.Context, params entity.RegisterBuyerParam) (entity.TokenResult, error)
+	InviteStaff(ctx context.Context, params entity.InviteStaffParam) (entity.StaffInvitationResult, error)
+	GetStaffList(ctx context.Context, selectParam entity.UserParam, param entity.UserListParam) ([]entity.User, entity.Pagination, error)
+	Login(ctx context.Context, params entity.LoginUserParam) (entity.TokenResult, error)
+	RefreshToken(ctx context.Context, params entity.RefreshUserTokenParam) (entity.TokenResult, error)
+	Logout(ctx context.Context, params entity.LogoutUserParam) error
+	ValidateTokenID(ctx context.Context, jti string) error
+	ChangePassword(ctx context.Context, params entity.ChangePasswordParam) error
+	CreatePasswordResetCode(ctx context.Context, param entity.UserParam) (entity.PasswordResetCodeResult, error)
+	ResetPassword(ctx context.Context, params entity.ResetPasswordParam) error
+	Unlock(ctx context.Context, param entity.UserParam) error
+	GetLoginEventList(ctx context.Context, param entity.LoginEventParam) ([]entity.LoginEvent, entity.Pagination, error)
+	GenerateGuestToken(ctx context.Context) (string, error)
+	Get(ctx context.Context, param entity.UserParam) (entity.User, error)
+	GetList(ctx context.Context, param entity.UserListParam) ([]entity.User, entity.Pagination, error)
+	Update(ctx context.Context, selectParam entity.UserParam, param entity.AdminUpdateUserParam) error
+	SetDisabled(ctx context.Context, param entity.UserParam, isDisabled bool) error
+	Delete(ctx context.Context, param entity.UserParam) error
 	Me(ctx context.Context) (entity.User, error)
 }
 
+const (
+	defaultPasswordMinLength  = 8
+	defaultResetCodeTTL       = 24 * time.Hour
+	resetCodeAlphabet         = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	resetCodeLength           = 10
+	defaultMaxFailedLogin     = 5
+	defaultLockoutDuration    = 30 * time.Minute
+	defaultBackoffThreshold   = 3
+	defaultIPBackoffThreshold = 10
+	defaultLoginBackoffBase   = time.Second
+	defaultLoginBackoffMax    = 5 * time.Minute
+	defaultLoginFailureWindow = 15 * time.Minute
+	invitePasswordLength      = 32
+)
+
+var (
+	ErrAccountExists       = apperr.Conflict("account_exists", "email atau nomor telepon sudah terdaftar")
+	ErrUsernameTaken       = apperr.Conflict("username_taken", "username sudah digunakan")
+	ErrInvalidCredentials  = apperr.Unauthorized("invalid_credentials", "user tidak ditemukan atau password tidak sesuai")
+	ErrAccountLocked       = apperr.Locked("account_locked", "akun terkunci, coba lagi nanti atau hubungi admin")
+	ErrLoginThrottled      = apperr.TooManyRequests("login_throttled", "terlalu banyak percobaan login, coba lagi dalam %d detik")
+	ErrRefreshTokenInvalid = apperr.Unauthorized("refresh_token_invalid", "refresh token tidak valid")
+	ErrRefreshTokenRevoked = apperr.Unauthorized("refresh_token_revoked", "refresh token sudah tidak berlaku")
+	ErrRefreshTokenExpired = apperr.Unauthorized("refresh_token_expired", "refresh token sudah kedaluwarsa")
+	ErrGuestPasswordChange = apperr.Forbidden("guest_password_change", "guest tidak dapat mengganti password")
+	ErrWrongPassword       = apperr.Validation("wrong_password", "password lama tidak sesuai")
+	ErrResetCodeInvalid    = apperr.Validation("reset_code_invalid", "kode reset tidak valid atau sudah kedaluwarsa")
+	ErrPasswordTooShort    = apperr.Validation("password_too_short", "password minimal %d karakter")
+	ErrPasswordWeak        = apperr.Validation("password_weak", "password harus mengandung huruf dan angka")
+	ErrSelfRoleChange      = apperr.Forbidden("self_role_change", "tidak dapat mengubah role akun sendiri")
+	ErrSelfDisable         = apperr.Forbidden("self_disable", "tidak dapat menonaktifkan akun sendiri")
+	ErrSelfDelete          = apperr.Forbidden("self_delete", "tidak dapat menghapus akun sendiri")
+	ErrRoleInvalid         = apperr.Validation("role_invalid", "role tidak valid")
+	ErrRoleUmkmRequired    = apperr.Validation("role_umkm_required", "umkm id wajib diisi untuk role tenant")
+	ErrRoleUmkmNotAllowed  = apperr.Validation("role_umkm_not_allowed", "role ini tidak boleh terikat ke umkm")
+)
+
+var (
+	userSortOptions = entity.SortOptions{
+		Columns: map[string]string{
+			"id":         "id",
+			"username":   "username",
+			"nama":       "nama",
+			"created_at": "created_at",
+		},
+		Default:      "id",
+		DefaultOrder: entity.SortDesc,
+		TieBreaker:   "id",
+	}
+	loginEventSortOptions = entity.SortOptions{
+		Columns: map[string]string{
+			"created_at": "created_at",
+		},
+		Default:      "created_at",
+		DefaultOrder: entity.SortDesc,
+		TieBreaker:   "id",
+	}
+)
+
+type Config struct {
+	BcryptCost        int
+	PasswordMinLength int
+	ResetCodeTTL      time.Duration
+	// MaxFailedLogin consecutive wrong passwords lock the account for LockoutDuration.
+	MaxFailedLogin  int
+	LockoutDuration time.Duration
+	// failures inside LoginFailureWindow beyond the thresholds delay the next attempt,
+	// doubling from LoginBackoffBase up to LoginBackoffMax
+	BackoffThreshold   int
+	IPBackoffThreshold int
+	LoginBackoffBase   time.Duration
+	LoginBackoffMax    time.Duration
+	LoginFailureWindow time.Duration
+}
+
 type user struct {
-	user userDom.Interface
-	auth auth.Interface
-	cart cartDom.Interface
-	umkm umkmDom.Interface
+	user              userDom.Interface
+	auth              auth.Interface
+	cart              cartDom.Interface
+	umkm              umkmDom.Interface
+	refreshToken      refreshTokenDom.Interface
+	revokedToken      revokedTokenDom.Interface
+	passwordResetCode passwordResetCodeDom.Interface
+	loginEvent        loginEventDom.Interface
+	transaction       transactionDom.Interface
+	cfg               Config
+	pagination        entity.PaginationConfig
 }
 
-func Init(ad userDom.Interface, auth auth.Interface, cd cartDom.Interface, ud umkmDom.Interface) Interface {
+func Init(ad userDom.Interface, auth auth.Interface, cd cartDom.Interface, ud umkmDom.Interface, rtd refreshTokenDom.Interface, rvd revokedTokenDom.Interface, prd passwordResetCodeDom.Interface, led loginEventDom.Interface, td transactionDom.Interface, cfg Config, pagination entity.PaginationConfig) Interface {
+	if cfg.BcryptCost == 0 {
+		cfg.BcryptCost = bcrypt.DefaultCost
+	}
+	if cfg.PasswordMinLength == 0 {
+		cfg.PasswordMinLength = defaultPasswordMinLength
+	}
+	if cfg.ResetCodeTTL == 0 {
+		cfg.ResetCodeTTL = defaultResetCodeTTL
+	}
+	if cfg.MaxFailedLogin == 0 {
+		cfg.MaxFailedLogin = defaultMaxFailedLogin
+	}
+	if cfg.LockoutDuration == 0 {
+		cfg.LockoutDuration = defaultLockoutDuration
+	}
+	if cfg.BackoffThreshold == 0 {
+		cfg.BackoffThreshold = defaultBackoffThreshold
+	}
+	if cfg.IPBackoffThreshold == 0 {
+		cfg.IPBackoffThreshold = defaultIPBackoffThreshold
+	}
+	if cfg.LoginBackoffBase == 0 {
+		cfg.LoginBackoffBase = defaultLoginBackoffBase
+	}
+	if cfg.LoginBackoffMax == 0 {
+		cfg.LoginBackoffMax = defaultLoginBackoffMax
+	}
+	if cfg.LoginFailureWindow == 0 {
+		cfg.LoginFailureWindow = defaultLoginFailureWindow
+	}
+
 	a := &user{
-		user: ad,
-		auth: auth,
-		cart: cd,
-		umkm: ud,
+		user:              ad,
+		auth:              auth,
+		cart:              cd,
+		umkm:              ud,
+		refreshToken:      rtd,
+		revokedToken:      rvd,
+		passwordResetCode: prd,
+		loginEvent:        led,
+		transaction:       td,
+		cfg:               cfg,
+		pagination:        pagination,
 	}
 
 	return a
 }
 
-func (a *user) Create(params entity.CreateUserParam) (entity.User, error) {
+func (a *user) Create(ctx context.Context, params entity.CreateUserParam) (entity.User, error) {
 	user := entity.User{
 		Username: params.Username,
 		Nama:     params.Nama,
 		UmkmID:   params.UmkmID,
+		Role:     params.Role,
+	}
+
+	if user.Role == "" && user.UmkmID != 0 {
+		user.Role = auth.RoleTenantOwner
+	}
+
+	if err := validateRole(user.Role, user.UmkmID); err != nil {
+		return user, err
+	}
+	user.IsAdmin = auth.IsPlatformRole(user.Role)
+
+	if user.Role == auth.RoleBuyer {
+		guestID, err := gonanoid.New()
+		if err != nil {
+			return user, err
+		}
+		user.GuestID = guestID
 	}
 
-	hashPass, err := bcrypt.GenerateFromPassword([]byte(params.Password), bcrypt.MinCost)
+	if err := a.validatePassword(params.Password); err != nil {
+		return user, err
+	}
+
+	hashPass, err := bcrypt.GenerateFromPassword([]byte(params.Password), a.cfg.BcryptCost)
 	if err != nil {
 		return user, err
 	}
 
 	user.Password = string(hashPass)
 
-	newUser, err := a.user.Create(user)
-	if err != nil {
+	newUser, err := a.user.Create(ctx, user)
+	if libsql.IsDuplicateKey(err) {
+		return newUser, ErrUsernameTaken
+	} else if err != nil {
 		return newUser, err
 	}
 
 	return newUser, nil
 }
 
-func (a *user) Get(param entity.UserParam) (entity.User, error) {
-	user, err := a.user.Get(entity.UserParam{
+// RegisterBuyer signs up a buyer and moves the carts and orders of the current guest session into the account.
+func (a *user) RegisterBuyer(ctx context.Context, params entity.RegisterBuyerParam) (entity.TokenResult, error) {
+	email := strings.ToLower(strings.TrimSpace(params.Email))
+	phone := strings.TrimSpace(params.Phone)
+
+	username := email
+	if username == "" {
+		username = phone
+	}
+
+	if err := a.validatePassword(params.Password); err != nil {
+		return entity.TokenResult{}, err
+	}
+
+	_, err := a.user.Get(ctx, entity.UserParam{
+		Username: username,
+	})
+	if err == nil {
+		return entity.TokenResult{}, ErrAccountExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.TokenResult{}, err
+	}
+
+	hashPass, err := bcrypt.GenerateFromPassword([]byte(params.Password), a.cfg.BcryptCost)
+	if err != nil {
+		return entity.TokenResult{}, err
+	}
+
+	// the account gets its own guest id so a leaked guest token can't reach the account's orders
+	guestID, err := gonanoid.New()
+	if err != nil {
+		return entity.TokenResult{}, err
+	}
+
+	// the unique username index catches a sign up racing the lookup above
+	buyer, err := a.user.CreateBuyer(ctx, entity.User{
+		Username: username,
+		Password: string(hashPass),
+		Nama:     params.Nama,
+		Email:    email,
+		Phone:    phone,
+		Role:     auth.RoleBuyer,
+		GuestID:  guestID,
+	}, params.GuestID)
+	if libsql.IsDuplicateKey(err) {
+		return entity.TokenResult{}, ErrAccountExists
+	} else if err != nil {
+		return entity.TokenResult{}, err
+	}
+
+	return a.generateTokenResult(ctx, buyer)
+}
+
+// InviteStaff creates a staff account without a known password, the returned reset code
+// is handed to the staff member to choose their own password.
+func (a *user) InviteStaff(ctx context.Context, params entity.InviteStaffParam) (entity.StaffInvitationResult, error) {
+	result := entity.StaffInvitationResult{}
+
+	if err := validateRole(params.Role, params.UmkmID); err != nil {
+		return result, err
+	}
+
+	_, err := a.user.Get(ctx, entity.UserParam{
+		Username: params.Username,
+	})
+	if err == nil {
+		return result, ErrUsernameTaken
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return result, err
+	}
+
+	password, err := gonanoid.New(invitePasswordLength)
+	if err != nil {
+		return result, err
+	}
+
+	hashPass, err := bcrypt.GenerateFromPassword([]byte(password), a.cfg.BcryptCost)
+	if err != nil {
+		return result, err
+	}
+
+	staff, err := a.user.Create(ctx, entity.User{
+		Username: params.Username,
+		Password: string(hashPass),
+		Nama:     params.Nama,
+		UmkmID:   params.UmkmID,
+		Role:     params.Role,
+	})
+	if libsql.IsDuplicateKey(err) {
+		return result, ErrUsernameTaken
+	} else if err != nil {
+		return result, err
+	}
+
+	resetCode, err := a.issuePasswordResetCode(ctx, staff.ID)
+	if err != nil {
+		return result, err
+	}
+
+	result.User = staff
+	result.ResetCode = resetCode
+
+	return result, nil
+}
+
+// GetStaffList pages the users of the umkm picked by selectParam, an umkm_id filter in param is ignored.
+func (a *user) GetStaffList(ctx context.Context, selectParam entity.UserParam, param entity.UserListParam) ([]entity.User, entity.Pagination, error) {
+	param.UmkmID = selectParam.UmkmID
+
+	return a.GetList(ctx, param)
+}
+
+func (a *user) Get(ctx context.Context, param entity.UserParam) (entity.User, error) {
+	user, err := a.user.Get(ctx, entity.UserParam{
 		ID: param.ID,
 	})
 	if err != nil {
 		return user, err
 	}
 
+	user.Role = user.GetRole()
+
 	return user, nil
 }
 
-func (a *user) Login(params entity.LoginUserParam) (string, error) {
-	user, err := a.user.Get(entity.UserParam{
-		Username: params.Username,
+func (a *user) GetList(ctx context.Context, param entity.UserListParam) ([]entity.User, entity.Pagination, error) {
+	users := []entity.User{}
+
+	paginationParam, err := param.PaginationParam.Paginate(a.pagination, userSortOptions)
+	if err != nil {
+		return users, entity.Pagination{}, err
+	}
+
+	listParam := entity.UserListParam{
+		Search:          param.Search,
+		Role:            param.Role,
+		UmkmID:          param.UmkmID,
+		IsDisabled:      param.IsDisabled,
+		PaginationParam: paginationParam,
+	}
+
+	total, err := a.user.CountByParam(ctx, listParam)
+	if err != nil {
+		return users, entity.Pagination{}, err
+	}
+
+	users, err = a.user.GetListByParam(ctx, listParam)
+	if err != nil {
+		return users, entity.Pagination{}, err
+	}
+
+	for i := range users {
+		users[i].Role = users[i].GetRole()
+	}
+
+	pagination := entity.NewPagination(paginationParam, total)
+	pagination.SetNextCursor(paginationParam, userSortOptions, users)
+
+	return users, pagination, nil
+}
+
+func (a *user) Update(ctx context.Context, selectParam entity.UserParam, param entity.AdminUpdateUserParam) error {
+	user, err := a.user.Get(ctx, entity.UserParam{
+		ID: selectParam.ID,
 	})
 	if err != nil {
-		return "", err
+		return err
+	}
+
+	role := user.GetRole()
+	if param.Role != "" {
+		role = param.Role
+	}
+
+	umkmID := user.UmkmID
+	if param.UmkmID != nil {
+		umkmID = *param.UmkmID
+	}
+
+	if role != user.GetRole() {
+		if err := a.validateNotSelf(ctx, user.ID, ErrSelfRoleChange); err != nil {
+			return err
+		}
+	}
+
+	if err := validateRole(role, umkmID); err != nil {
+		return err
+	}
+
+	if param.Username != "" && param.Username != user.Username {
+		_, err := a.user.Get(ctx, entity.UserParam{
+			Username: param.Username,
+		})
+		if err == nil {
+			return ErrUsernameTaken
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+	}
+
+	isAdmin := auth.IsPlatformRole(role)
+
+	// buyer carts and orders are keyed by the guest id, so a buyer must always own one
+	guestID := ""
+	if role == auth.RoleBuyer && user.GuestID == "" {
+		guestID, err = gonanoid.New()
+		if err != nil {
+			return err
+		}
+	}
+
+	err = a.user.Update(ctx, entity.UserParam{
+		ID: user.ID,
+	}, entity.UpdateUserParam{
+		Username: param.Username,
+		Nama:     param.Nama,
+		Role:     role,
+		UmkmID:   &umkmID,
+		IsAdmin:  &isAdmin,
+		GuestID:  guestID,
+	})
+	if libsql.IsDuplicateKey(err) {
+		return ErrUsernameTaken
+	}
+
+	return err
+}
+
+func (a *user) SetDisabled(ctx context.Context, param entity.UserParam, isDisabled bool) error {
+	user, err := a.user.Get(ctx, entity.UserParam{
+		ID: param.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if isDisabled {
+		if err := a.validateNotSelf(ctx, user.ID, ErrSelfDisable); err != nil {
+			return err
+		}
+	}
+
+	if err := a.user.Update(ctx, entity.UserParam{
+		ID: user.ID,
+	}, entity.UpdateUserParam{
+		IsDisabled: &isDisabled,
+	}); err != nil {
+		return err
+	}
+
+	if isDisabled {
+		if err := a.refreshToken.RevokeAll(ctx, user.ID, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *user) Delete(ctx context.Context, param entity.UserParam) error {
+	user, err := a.user.Get(ctx, entity.UserParam{
+		ID: param.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := a.validateNotSelf(ctx, user.ID, ErrSelfDelete); err != nil {
+		return err
+	}
+
+	if err := a.user.Delete(ctx, entity.UserParam{
+		ID: user.ID,
+	}); err != nil {
+		return err
+	}
+
+	if err := a.refreshToken.RevokeAll(ctx, user.ID, time.Now()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *user) Login(ctx context.Context, params entity.LoginUserParam) (entity.TokenResult, error) {
+	now := time.Now()
+
+	if err := a.checkLoginBackoff(ctx, params, now); err != nil {
+		return entity.TokenResult{}, err
+	}
+
+	user, err := a.user.Get(ctx, entity.UserParam{
+		Username: params.Username,
+	})
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.TokenResult{}, err
 	}
 
 	if user.ID == 0 {
-		return "", errors.New("user tidak ditemukan atau password tidak sesuai")
+		if err := a.recordLoginEvent(ctx, params, 0, entity.LoginReasonUserNotFound); err != nil {
+			return entity.TokenResult{}, err
+		}
+		return entity.TokenResult{}, ErrInvalidCredentials
 	}
 
+	// the password is checked first so the lock is only revealed to callers who know it
+	locked := user.LockedUntil != nil && now.Before(*user.LockedUntil)
+
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(params.Password)); err != nil {
-		return "", errors.New("user tidak ditemukan atau password tidak sesuai")
+		// guesses while locked only count for the backoff, they don't extend the lock
+		if !locked {
+			if err := a.user.RecordFailedLogin(ctx, user.ID, a.cfg.MaxFailedLogin, now.Add(a.cfg.LockoutDuration)); err != nil {
+				return entity.TokenResult{}, err
+			}
+		}
+
+		if err := a.recordLoginEvent(ctx, params, user.ID, entity.LoginReasonWrongPassword); err != nil {
+			return entity.TokenResult{}, err
+		}
+		return entity.TokenResult{}, ErrInvalidCredentials
+	}
+
+	if locked {
+		if err := a.recordLoginEvent(ctx, params, user.ID, entity.LoginReasonLocked); err != nil {
+			return entity.TokenResult{}, err
+		}
+		return entity.TokenResult{}, ErrAccountLocked
+	}
+
+	if user.IsDisabled {
+		if err := a.recordLoginEvent(ctx, params, user.ID, entity.LoginReasonDisabled); err != nil {
+			return entity.TokenResult{}, err
+		}
+		return entity.TokenResult{}, auth.ErrAccountDisabled
+	}
+
+	if user.FailedLoginCount != 0 || user.LockedUntil != nil {
+		if err := a.user.UpdateLoginState(ctx, user.ID, 0, nil); err != nil {
+			return entity.TokenResult{}, err
+		}
+	}
+
+	if err := a.recordLoginEvent(ctx, params, user.ID, entity.LoginReasonSuccess); err != nil {
+		return entity.TokenResult{}, err
+	}
+
+	if user.GetRole() == auth.RoleBuyer {
+		if err := a.claimGuest(ctx, params.GuestID, user.GuestID); err != nil {
+			return entity.TokenResult{}, err
+		}
+	}
+
+	a.rehashPassword(ctx, user, params.Password)
+
+	return a.generateTokenResult(ctx, user)
+}
+
+func (a *user) RefreshToken(ctx context.Context, params entity.RefreshUserTokenParam) (entity.TokenResult, error) {
+	refreshToken, err := a.refreshToken.Get(ctx, entity.RefreshTokenParam{
+		TokenHash: auth.HashToken(params.RefreshToken),
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.TokenResult{}, ErrRefreshTokenInvalid
+	} else if err != nil {
+		return entity.TokenResult{}, err
+	}
+
+	now := time.Now()
+
+	// a rotated refresh token being used again means it leaked, end every session of the user
+	if refreshToken.RevokedAt != nil {
+		if err := a.refreshToken.RevokeAll(ctx, refreshToken.UserID, now); err != nil {
+			return entity.TokenResult{}, err
+		}
+		return entity.TokenResult{}, ErrRefreshTokenRevoked
+	}
+
+	if !now.Before(refreshToken.ExpiresAt) {
+		return entity.TokenResult{}, ErrRefreshTokenExpired
 	}
 
-	token, err := a.auth.GenerateToken(user.ConvertToAuthUser())
+	user, err := a.user.Get(ctx, entity.UserParam{
+		ID: refreshToken.UserID,
+	})
 	if err != nil {
-		return "", err
+		return entity.TokenResult{}, err
 	}
 
-	return token, nil
+	if user.IsDisabled {
+		return entity.TokenResult{}, auth.ErrAccountDisabled
+	}
+
+	revoked, err := a.refreshToken.Revoke(ctx, refreshToken.ID, now)
+	if err != nil {
+		return entity.TokenResult{}, err
+	}
+
+	// another request rotated the same refresh token first, it is being reused
+	if !revoked {
+		if err := a.refreshToken.RevokeAll(ctx, refreshToken.UserID, now); err != nil {
+			return entity.TokenResult{}, err
+		}
+		return entity.TokenResult{}, ErrRefreshTokenRevoked
+	}
+
+	return a.generateTokenResult(ctx, user)
+}
+
+func (a *user) Logout(ctx context.Context, params entity.LogoutUserParam) error {
+	user, err := a.auth.GetUserAuthInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := a.revokedToken.DeleteExpired(ctx, now); err != nil {
+		return err
+	}
+
+	if _, err := a.revokedToken.Create(ctx, entity.RevokedToken{
+		JTI:       user.TokenID,
+		ExpiresAt: user.TokenExpiresAt,
+	}); err != nil {
+		return err
+	}
+
+	if params.RefreshToken != "" && user.User.ID != 0 {
+		if err := a.refreshToken.Update(ctx, entity.RefreshTokenParam{
+			UserID:    user.User.ID,
+			TokenHash: auth.HashToken(params.RefreshToken),
+		}, entity.UpdateRefreshTokenParam{
+			RevokedAt: &now,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *user) ValidateTokenID(ctx context.Context, jti string) error {
+	revokedToken, err := a.revokedToken.Get(ctx, entity.RevokedTokenParam{
+		JTI: jti,
+	})
+	if err != nil {
+		return err
+	}
+
+	if revokedToken.ID != 0 {
+		return auth.ErrTokenRevoked
+	}
+
+	return nil
+}
+
+func (a *user) ChangePassword(ctx context.Context, params entity.ChangePasswordParam) error {
+	authUser, err := a.auth.GetUserAuthInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	if authUser.User.ID == 0 {
+		return ErrGuestPasswordChange
+	}
+
+	if err := a.validatePassword(params.NewPassword); err != nil {
+		return err
+	}
+
+	user, err := a.user.Get(ctx, entity.UserParam{
+		ID: authUser.User.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(params.OldPassword)); err != nil {
+		return ErrWrongPassword
+	}
+
+	return a.updatePassword(ctx, user.ID, params.NewPassword)
+}
+
+func (a *user) CreatePasswordResetCode(ctx context.Context, param entity.UserParam) (entity.PasswordResetCodeResult, error) {
+	user, err := a.user.Get(ctx, entity.UserParam{
+		ID: param.ID,
+	})
+	if err != nil {
+		return entity.PasswordResetCodeResult{}, err
+	}
+
+	return a.issuePasswordResetCode(ctx, user.ID)
+}
+
+func (a *user) ResetPassword(ctx context.Context, params entity.ResetPasswordParam) error {
+	if err := a.validatePassword(params.NewPassword); err != nil {
+		return err
+	}
+
+	user, err := a.user.Get(ctx, entity.UserParam{
+		Username: params.Username,
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrResetCodeInvalid
+	} else if err != nil {
+		return err
+	}
+
+	resetCode, err := a.passwordResetCode.Get(ctx, entity.PasswordResetCodeParam{
+		UserID:   user.ID,
+		CodeHash: auth.HashToken(params.Code),
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrResetCodeInvalid
+	} else if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if resetCode.UsedAt != nil || !now.Before(resetCode.ExpiresAt) {
+		return ErrResetCodeInvalid
+	}
+
+	// another request may redeem the same code in between
+	redeemed, err := a.passwordResetCode.Redeem(ctx, resetCode.ID, now)
+	if err != nil {
+		return err
+	}
+	if !redeemed {
+		return ErrResetCodeInvalid
+	}
+
+	return a.updatePassword(ctx, user.ID, params.NewPassword)
+}
+
+func (a *user) Unlock(ctx context.Context, param entity.UserParam) error {
+	user, err := a.user.Get(ctx, entity.UserParam{
+		ID: param.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.user.UpdateLoginState(ctx, user.ID, 0, nil)
+}
+
+func (a *user) GetLoginEventList(ctx context.Context, param entity.LoginEventParam) ([]entity.LoginEvent, entity.Pagination, error) {
+	loginEvents := []entity.LoginEvent{}
+
+	paginationParam, err := param.PaginationParam.Paginate(a.pagination, loginEventSortOptions)
+	if err != nil {
+		return loginEvents, entity.Pagination{}, err
+	}
+
+	listParam := entity.LoginEventParam{
+		UserID:          param.UserID,
+		Username:        param.Username,
+		IP:              param.IP,
+		Success:         param.Success,
+		PaginationParam: paginationParam,
+	}
+
+	total, err := a.loginEvent.Count(ctx, listParam)
+	if err != nil {
+		return loginEvents, entity.Pagination{}, err
+	}
+
+	loginEvents, err = a.loginEvent.GetList(ctx, listParam)
+	if err != nil {
+		return loginEvents, entity.Pagination{}, err
+	}
+
+	pagination := entity.NewPagination(paginationParam, total)
+	pagination.SetNextCursor(paginationParam, loginEventSortOptions, loginEvents)
+
+	return loginEvents, pagination, nil
 }
 
-func (a *user) GenerateGuestToken() (string, error) {
+func (a *user) GenerateGuestToken(ctx context.Context) (string, error) {
 	token, err := a.auth.GenerateGuestToken()
 	if err != nil {
 		return "", err
 	}
 
-	return token, nil
+	return token.Value, nil
 }
 
 func (u *user) Me(ctx context.Context) (entity.User, error) {
@@ -110,15 +825,17 @@ func (u *user) Me(ctx context.Context) (entity.User, error) {
 		return entity.User{}, err
 	}
 
-	me, err := u.user.Get(entity.UserParam{
+	me, err := u.user.Get(ctx, entity.UserParam{
 		ID: user.User.ID,
 	})
 	if err != nil {
 		return me, err
 	}
 
+	me.Role = me.GetRole()
+
 	if me.UmkmID != 0 {
-		umkm, err := u.umkm.Get(entity.UmkmParam{
+		umkm, err := u.umkm.Get(ctx, entity.UmkmParam{
 			ID: me.UmkmID,
 		})
 		if err != nil {
@@ -129,3 +846,236 @@ func (u *user) Me(ctx context.Context) (entity.User, error) {
 
 	return me, nil
 }
+
+func (a *user) generateTokenResult(ctx context.Context, user entity.User) (entity.TokenResult, error) {
+	result := entity.TokenResult{}
+
+	accessToken, err := a.auth.GenerateToken(user.ConvertToAuthUser())
+	if err != nil {
+		return result, err
+	}
+
+	refreshToken, err := a.auth.GenerateRefreshToken()
+	if err != nil {
+		return result, err
+	}
+
+	if _, err := a.refreshToken.Create(ctx, entity.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: auth.HashToken(refreshToken.Value),
+		ExpiresAt: refreshToken.ExpiresAt,
+	}); err != nil {
+		return result, err
+	}
+
+	result.Token = accessToken.Value
+	result.RefreshToken = refreshToken.Value
+	result.ExpiresIn = int(accessToken.ExpiresAt.Sub(accessToken.IssuedAt).Seconds())
+
+	return result, nil
+}
+
+// checkLoginBackoff rejects the attempt while the delay earned by recent failures of the username or IP hasn't passed.
+func (a *user) checkLoginBackoff(ctx context.Context, params entity.LoginUserParam, now time.Time) error {
+	since := now.Add(-a.cfg.LoginFailureWindow)
+
+	retryAfter, err := a.loginRetryAfter(ctx, entity.LoginFailureParam{
+		Username: params.Username,
+		Since:    since,
+	}, a.cfg.BackoffThreshold, now)
+	if err != nil {
+		return err
+	}
+
+	if params.IP != "" {
+		ipRetryAfter, err := a.loginRetryAfter(ctx, entity.LoginFailureParam{
+			IP:    params.IP,
+			Since: since,
+		}, a.cfg.IPBackoffThreshold, now)
+		if err != nil {
+			return err
+		}
+
+		if ipRetryAfter > retryAfter {
+			retryAfter = ipRetryAfter
+		}
+	}
+
+	if retryAfter > 0 {
+		return ErrLoginThrottled.WithArgs(int(math.Ceil(retryAfter.Seconds())))
+	}
+
+	return nil
+}
+
+func (a *user) loginRetryAfter(ctx context.Context, param entity.LoginFailureParam, threshold int, now time.Time) (time.Duration, error) {
+	summary, err := a.loginEvent.GetFailureSummary(ctx, param)
+	if err != nil {
+		return 0, err
+	}
+
+	if summary.TotalFailure < threshold || summary.LastFailureAt == nil {
+		return 0, nil
+	}
+
+	return summary.LastFailureAt.Add(a.loginBackoff(summary.TotalFailure - threshold)).Sub(now), nil
+}
+
+func (a *user) loginBackoff(exceeded int) time.Duration {
+	backoff := a.cfg.LoginBackoffBase
+	for i := 0; i < exceeded; i++ {
+		backoff *= 2
+		if backoff >= a.cfg.LoginBackoffMax {
+			return a.cfg.LoginBackoffMax
+		}
+	}
+
+	return backoff
+}
+
+func (a *user) recordLoginEvent(ctx context.Context, params entity.LoginUserParam, userID uint, reason string) error {
+	if _, err := a.loginEvent.Create(ctx, entity.LoginEvent{
+		UserID:   userID,
+		Username: params.Username,
+		IP:       params.IP,
+		Success:  reason == entity.LoginReasonSuccess,
+		Reason:   reason,
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *user) claimGuest(ctx context.Context, fromGuestID string, toGuestID string) error {
+	if fromGuestID == "" || toGuestID == "" || fromGuestID == toGuestID {
+		return nil
+	}
+
+	if err := a.transaction.ClaimGuest(ctx, fromGuestID, toGuestID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *user) issuePasswordResetCode(ctx context.Context, userID uint) (entity.PasswordResetCodeResult, error) {
+	result := entity.PasswordResetCodeResult{}
+
+	now := time.Now()
+	if err := a.passwordResetCode.InvalidateAll(ctx, userID, now); err != nil {
+		return result, err
+	}
+
+	code, err := gonanoid.Generate(resetCodeAlphabet, resetCodeLength)
+	if err != nil {
+		return result, err
+	}
+
+	expiresAt := now.Add(a.cfg.ResetCodeTTL)
+	if _, err := a.passwordResetCode.Create(ctx, entity.PasswordResetCode{
+		UserID:    userID,
+		CodeHash:  auth.HashToken(code),
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return result, err
+	}
+
+	result.Code = code
+	result.ExpiresAt = expiresAt
+
+	return result, nil
+}
+
+func (a *user) updatePassword(ctx context.Context, userID uint, password string) error {
+	hashPass, err := bcrypt.GenerateFromPassword([]byte(password), a.cfg.BcryptCost)
+	if err != nil {
+		return err
+	}
+
+	if err := a.user.Update(ctx, entity.UserParam{
+		ID: userID,
+	}, entity.UpdateUserParam{
+		Password: string(hashPass),
+	}); err != nil {
+		return err
+	}
+
+	// sessions opened with the old password must not survive the change
+	if err := a.refreshToken.RevokeAll(ctx, userID, time.Now()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// rehashPassword upgrades hashes created with a lower cost, a failure is retried on the next login.
+func (a *user) rehashPassword(ctx context.Context, user entity.User, password string) {
+	cost, err := bcrypt.Cost([]byte(user.Password))
+	if err != nil || cost >= a.cfg.BcryptCost {
+		return
+	}
+
+	hashPass, err := bcrypt.GenerateFromPassword([]byte(password), a.cfg.BcryptCost)
+	if err != nil {
+		return
+	}
+
+	_ = a.user.Update(ctx, entity.UserParam{
+		ID: user.ID,
+	}, entity.UpdateUserParam{
+		Password: string(hashPass),
+	})
+}
+
+func (a *user) validatePassword(password string) error {
+	if len(password) < a.cfg.PasswordMinLength {
+		return ErrPasswordTooShort.WithArgs(a.cfg.PasswordMinLength)
+	}
+
+	hasLetter, hasDigit := false, false
+	for _, c := range password {
+		switch {
+		case unicode.IsLetter(c):
+			hasLetter = true
+		case unicode.IsDigit(c):
+			hasDigit = true
+		}
+	}
+
+	if !hasLetter || !hasDigit {
+		return ErrPasswordWeak
+	}
+
+	return nil
+}
+
+func (a *user) validateNotSelf(ctx context.Context, userID uint, selfErr error) error {
+	authInfo, err := a.auth.GetUserAuthInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	if authInfo.User.ID == userID {
+		return selfErr
+	}
+
+	return nil
+}
+
+// validateRole keeps tenant roles bound to an umkm and every other role free of one.
+func validateRole(role string, umkmID uint) error {
+	if !auth.IsValidRole(role) {
+		return ErrRoleInvalid
+	}
+
+	if auth.IsTenantRole(role) && umkmID == 0 {
+		return ErrRoleUmkmRequired
+	}
+
+	if !auth.IsTenantRole(role) && umkmID != 0 {
+		return ErrRoleUmkmNotAllowed
+	}
+
+	return nil
+}
@@ -2,13 +2,21 @@ package user_test
 
 import (
 	"context"
+	mock_cart "go-clean/src/business/domain/mock/cart"
+	mock_login_event "go-clean/src/business/domain/mock/login_event"
+	mock_password_reset_code "go-clean/src/business/domain/mock/password_reset_code"
+	mock_refresh_token "go-clean/src/business/domain/mock/refresh_token"
+	mock_revoked_token "go-clean/src/business/domain/mock/revoked_token"
+	mock_transaction "go-clean/src/business/domain/mock/transaction"
 	mock_user "go-clean/src/business/domain/mock/user"
 	"go-clean/src/business/entity"
 	"go-clean/src/business/usecase/user"
 	"go-clean/src/lib/auth"
 	mock_auth "go-clean/src/lib/tests/mock/auth"
 	"testing"
+	"time"
 
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"golang.org/x/crypto/bcrypt"
@@ -24,7 +32,7 @@ func Test_user_Create(t *testing.T) {
 
 	mockParams := entity.CreateUserParam{
 		Username: "username",
-		Password: "password",
+		Password: "password123",
 		Nama:     "nama",
 		UmkmID:   1,
 	}
@@ -37,7 +45,7 @@ func Test_user_Create(t *testing.T) {
 		Password: string(hashPass),
 	}
 
-	u := user.Init(userMock, nil, nil, nil)
+	u := user.Init(userMock, nil, nil, nil, nil, nil, nil, nil, nil, user.Config{BcryptCost: bcrypt.MinCost}, entity.PaginationConfig{})
 
 	type mockfields struct {
 		user *mock_user.MockInterface
@@ -58,10 +66,75 @@ func Test_user_Create(t *testing.T) {
 		want     entity.User
 		wantErr  bool
 	}{
+		{
+			name:     "password too short",
+			mockFunc: func(mock mockfields, arg args) {},
+			args: args{
+				params: entity.CreateUserParam{
+					Username: "username",
+					Password: "pass1",
+					Nama:     "nama",
+					UmkmID:   1,
+				},
+			},
+			want: entity.User{
+				Username: "username",
+			},
+			wantErr: true,
+		},
+		{
+			name:     "password without digit",
+			mockFunc: func(mock mockfields, arg args) {},
+			args: args{
+				params: entity.CreateUserParam{
+					Username: "username",
+					Password: "password",
+					Nama:     "nama",
+					UmkmID:   1,
+				},
+			},
+			want: entity.User{
+				Username: "username",
+			},
+			wantErr: true,
+		},
+		{
+			name:     "tenant role without umkm",
+			mockFunc: func(mock mockfields, arg args) {},
+			args: args{
+				params: entity.CreateUserParam{
+					Username: "username",
+					Password: "password123",
+					Nama:     "nama",
+					Role:     auth.RoleTenantCashier,
+				},
+			},
+			want: entity.User{
+				Username: "username",
+			},
+			wantErr: true,
+		},
+		{
+			name:     "platform role with umkm",
+			mockFunc: func(mock mockfields, arg args) {},
+			args: args{
+				params: entity.CreateUserParam{
+					Username: "username",
+					Password: "password123",
+					Nama:     "nama",
+					UmkmID:   1,
+					Role:     auth.RoleFinanceAdmin,
+				},
+			},
+			want: entity.User{
+				Username: "username",
+			},
+			wantErr: true,
+		},
 		{
 			name: "failed to create user",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.user.EXPECT().Create(gomock.Any()).Return(mockUserResult, assert.AnError)
+				mock.user.EXPECT().Create(gomock.Any(), gomock.Any()).Return(mockUserResult, assert.AnError)
 			},
 			args: args{
 				params: mockParams,
@@ -74,7 +147,11 @@ func Test_user_Create(t *testing.T) {
 		{
 			name: "success",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.user.EXPECT().Create(gomock.Any()).Return(mockUserResult, nil)
+				mock.user.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, u entity.User) (entity.User, error) {
+					assert.Equal(t, auth.RoleTenantOwner, u.Role)
+					assert.False(t, u.IsAdmin)
+					return mockUserResult, nil
+				})
 			},
 			args: args{
 				params: mockParams,
@@ -84,11 +161,33 @@ func Test_user_Create(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "success platform role",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, u entity.User) (entity.User, error) {
+					assert.Equal(t, auth.RoleFinanceAdmin, u.Role)
+					assert.True(t, u.IsAdmin)
+					return mockUserResult, nil
+				})
+			},
+			args: args{
+				params: entity.CreateUserParam{
+					Username: "username",
+					Password: "password123",
+					Nama:     "nama",
+					Role:     auth.RoleFinanceAdmin,
+				},
+			},
+			want: entity.User{
+				Username: "username",
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc(mocks, tt.args)
-			got, err := u.Create(tt.args.params)
+			got, err := u.Create(context.Background(), tt.args.params)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("user.Create() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -98,142 +197,170 @@ func Test_user_Create(t *testing.T) {
 	}
 }
 
-func Test_user_Login(t *testing.T) {
+func Test_user_InviteStaff(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	userMock := mock_user.NewMockInterface(ctrl)
-	authMock := mock_auth.NewMockInterface(ctrl)
-	hashPass, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
-	tokenMock := "token"
+	passwordResetCodeMock := mock_password_reset_code.NewMockInterface(ctrl)
 
-	mockParams := entity.LoginUserParam{
-		Username: "username",
-		Password: "password",
+	mockParams := entity.InviteStaffParam{
+		UmkmID:   1,
+		Username: "kitchen",
+		Nama:     "kitchen",
+		Role:     auth.RoleKitchenStaff,
 	}
 
-	mockUserResult := entity.User{
+	mockStaffResult := entity.User{
 		Model: gorm.Model{
-			ID: 1,
+			ID: 2,
 		},
-		Username: "username",
-		Password: string(hashPass),
+		Username: "kitchen",
+		Nama:     "kitchen",
+		UmkmID:   1,
+		Role:     auth.RoleKitchenStaff,
 	}
 
-	u := user.Init(userMock, authMock, nil, nil)
+	u := user.Init(userMock, nil, nil, nil, nil, nil, passwordResetCodeMock, nil, nil, user.Config{BcryptCost: bcrypt.MinCost}, entity.PaginationConfig{})
 
 	type mockfields struct {
-		user *mock_user.MockInterface
-		auth *mock_auth.MockInterface
+		user              *mock_user.MockInterface
+		passwordResetCode *mock_password_reset_code.MockInterface
 	}
 
 	mocks := mockfields{
-		user: userMock,
-		auth: authMock,
+		user:              userMock,
+		passwordResetCode: passwordResetCodeMock,
 	}
 
 	type args struct {
-		params entity.LoginUserParam
+		params entity.InviteStaffParam
 	}
 
 	tests := []struct {
 		name     string
 		mockFunc func(mock mockfields, arg args)
 		args     args
-		want     string
+		want     entity.User
 		wantErr  bool
 	}{
 		{
-			name: "failed to find user",
+			name:     "platform role is not a staff role",
+			mockFunc: func(mock mockfields, arg args) {},
+			args: args{
+				params: entity.InviteStaffParam{
+					UmkmID:   1,
+					Username: "kitchen",
+					Nama:     "kitchen",
+					Role:     auth.RoleSuperAdmin,
+				},
+			},
+			want:    entity.User{},
+			wantErr: true,
+		},
+		{
+			name: "username already used",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.user.EXPECT().Get(gomock.Any()).Return(entity.User{}, assert.AnError)
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{Username: "kitchen"}).Return(mockStaffResult, nil)
 			},
 			args: args{
 				params: mockParams,
 			},
-			want:    "",
+			want:    entity.User{},
 			wantErr: true,
 		},
 		{
-			name: "user not found",
+			name: "failed to check username",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.user.EXPECT().Get(gomock.Any()).Return(entity.User{}, nil)
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{Username: "kitchen"}).Return(entity.User{}, assert.AnError)
 			},
 			args: args{
 				params: mockParams,
 			},
-			want:    "",
+			want:    entity.User{},
 			wantErr: true,
 		},
 		{
-			name: "password incorrect",
+			name: "username taken concurrently",
 			mockFunc: func(mock mockfields, arg args) {
-				mockUserResultWithWrongPassword := mockUserResult
-				mockUserResultWithWrongPassword.Password = "wrongPassword"
-				mock.user.EXPECT().Get(gomock.Any()).Return(mockUserResultWithWrongPassword, nil)
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{Username: "kitchen"}).Return(entity.User{}, gorm.ErrRecordNotFound)
+				mock.user.EXPECT().Create(gomock.Any(), gomock.Any()).Return(entity.User{}, &mysqldriver.MySQLError{Number: 1062})
 			},
 			args: args{
 				params: mockParams,
 			},
-			want:    "",
+			want:    entity.User{},
 			wantErr: true,
 		},
 		{
-			name: "failed to generate token",
+			name: "failed to create staff",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.user.EXPECT().Get(gomock.Any()).Return(mockUserResult, nil)
-				mock.auth.EXPECT().GenerateToken(gomock.Any()).Return("", assert.AnError)
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{Username: "kitchen"}).Return(entity.User{}, gorm.ErrRecordNotFound)
+				mock.user.EXPECT().Create(gomock.Any(), gomock.Any()).Return(entity.User{}, assert.AnError)
 			},
 			args: args{
 				params: mockParams,
 			},
-			want:    "",
+			want:    entity.User{},
+			wantErr: true,
+		},
+		{
+			name: "failed to issue reset code",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{Username: "kitchen"}).Return(entity.User{}, gorm.ErrRecordNotFound)
+				mock.user.EXPECT().Create(gomock.Any(), gomock.Any()).Return(mockStaffResult, nil)
+				mock.passwordResetCode.EXPECT().InvalidateAll(gomock.Any(), uint(2), gomock.Any()).Return(nil)
+				mock.passwordResetCode.EXPECT().Create(gomock.Any(), gomock.Any()).Return(entity.PasswordResetCode{}, assert.AnError)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    entity.User{},
 			wantErr: true,
 		},
 		{
 			name: "success",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.user.EXPECT().Get(gomock.Any()).Return(mockUserResult, nil)
-				mock.auth.EXPECT().GenerateToken(gomock.Any()).Return(tokenMock, nil)
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{Username: "kitchen"}).Return(entity.User{}, gorm.ErrRecordNotFound)
+				mock.user.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, u entity.User) (entity.User, error) {
+					assert.Equal(t, uint(1), u.UmkmID)
+					assert.Equal(t, auth.RoleKitchenStaff, u.Role)
+					assert.NotEmpty(t, u.Password)
+					return mockStaffResult, nil
+				})
+				mock.passwordResetCode.EXPECT().InvalidateAll(gomock.Any(), uint(2), gomock.Any()).Return(nil)
+				mock.passwordResetCode.EXPECT().Create(gomock.Any(), gomock.Any()).Return(entity.PasswordResetCode{}, nil)
 			},
 			args: args{
 				params: mockParams,
 			},
-			want:    tokenMock,
+			want:    mockStaffResult,
 			wantErr: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc(mocks, tt.args)
-			got, err := u.Login(tt.args.params)
+			got, err := u.InviteStaff(context.Background(), tt.args.params)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("user.Create() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("user.InviteStaff() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want, got.User)
+			if !tt.wantErr {
+				assert.Len(t, got.ResetCode.Code, 10)
+			}
 		})
 	}
 }
 
-func Test_user_Get(t *testing.T) {
+func Test_user_GetStaffList(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	userMock := mock_user.NewMockInterface(ctrl)
 
-	paramsMock := entity.UserParam{
-		ID: 1,
-	}
-
-	userResultMock := entity.User{
-		Model: gorm.Model{
-			ID: 1,
-		},
-		Username: "username",
-	}
-
-	u := user.Init(userMock, nil, nil, nil)
+	u := user.Init(userMock, nil, nil, nil, nil, nil, nil, nil, nil, user.Config{BcryptCost: bcrypt.MinCost}, entity.PaginationConfig{})
 
 	type mockfields struct {
 		user *mock_user.MockInterface
@@ -244,196 +371,2618 @@ func Test_user_Get(t *testing.T) {
 	}
 
 	type args struct {
-		params entity.UserParam
+		selectParam entity.UserParam
+		param       entity.UserListParam
+	}
+
+	listParam := entity.UserListParam{
+		UmkmID: 1,
+		PaginationParam: entity.PaginationParam{
+			Page:      2,
+			Limit:     1,
+			Offset:    1,
+			SortBy:    "id",
+			SortOrder: entity.SortDesc,
+			OrderBy:   "id desc",
+		},
 	}
 
 	tests := []struct {
-		name     string
-		mockFunc func(mock mockfields, arg args)
-		args     args
-		want     entity.User
-		wantErr  bool
+		name           string
+		mockFunc       func(mock mockfields, arg args)
+		args           args
+		want           []entity.User
+		wantPagination entity.Pagination
+		wantErr        bool
 	}{
 		{
-			name: "failed to get user",
+			name: "failed to get staff list",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.user.EXPECT().Get(gomock.Any()).Return(entity.User{}, assert.AnError)
+				mock.user.EXPECT().CountByParam(gomock.Any(), listParam).Return(int64(2), nil)
+				mock.user.EXPECT().GetListByParam(gomock.Any(), listParam).Return([]entity.User{}, assert.AnError)
 			},
 			args: args{
-				params: paramsMock,
+				selectParam: entity.UserParam{UmkmID: 1},
+				param:       entity.UserListParam{PaginationParam: entity.PaginationParam{Page: 2, Limit: 1}},
 			},
-			want:    entity.User{},
+			want:    []entity.User{},
 			wantErr: true,
 		},
 		{
-			name: "success",
+			name: "success with legacy owner and umkm of the path",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.user.EXPECT().Get(gomock.Any()).Return(userResultMock, nil)
+				mock.user.EXPECT().CountByParam(gomock.Any(), listParam).Return(int64(2), nil)
+				mock.user.EXPECT().GetListByParam(gomock.Any(), listParam).Return([]entity.User{
+					{Username: "owner", UmkmID: 1},
+				}, nil)
 			},
 			args: args{
-				params: paramsMock,
+				selectParam: entity.UserParam{UmkmID: 1},
+				param:       entity.UserListParam{UmkmID: 2, PaginationParam: entity.PaginationParam{Page: 2, Limit: 1}},
+			},
+			want: []entity.User{
+				{Username: "owner", UmkmID: 1, Role: auth.RoleTenantOwner},
+			},
+			wantPagination: entity.Pagination{
+				Page:       2,
+				Limit:      1,
+				TotalItems: 2,
+				TotalPages: 2,
 			},
-			want:    userResultMock,
 			wantErr: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc(mocks, tt.args)
-			got, err := u.Get(tt.args.params)
+			got, pagination, err := u.GetStaffList(context.Background(), tt.args.selectParam, tt.args.param)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("user.Create() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("user.GetStaffList() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantPagination, pagination)
 		})
 	}
 }
 
-func Test_user_GenerateGuestToken(t *testing.T) {
+func Test_user_Login(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	userMock := mock_user.NewMockInterface(ctrl)
 	authMock := mock_auth.NewMockInterface(ctrl)
-	tokenMock := "token"
-
-	u := user.Init(nil, authMock, nil, nil)
+	refreshTokenMock := mock_refresh_token.NewMockInterface(ctrl)
+	loginEventMock := mock_login_event.NewMockInterface(ctrl)
+	hashPass, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	now := time.Now()
 
-	type mockfields struct {
-		auth *mock_auth.MockInterface
+	accessTokenMock := auth.Token{
+		ID:        "jti",
+		Value:     "token",
+		IssuedAt:  now,
+		ExpiresAt: now.Add(15 * time.Minute),
 	}
 
-	mocks := mockfields{
-		auth: authMock,
+	refreshTokenResultMock := auth.Token{
+		Value:     "refresh-token",
+		IssuedAt:  now,
+		ExpiresAt: now.Add(720 * time.Hour),
 	}
 
-	tests := []struct {
-		name     string
-		mockFunc func(mock mockfields)
-		want     string
-		wantErr  bool
-	}{
-		{
-			name: "failed to generate guest token",
-			mockFunc: func(mock mockfields) {
-				mock.auth.EXPECT().GenerateGuestToken().Return("", assert.AnError)
-			},
-			want:    "",
-			wantErr: true,
-		},
-		{
-			name: "success",
-			mockFunc: func(mock mockfields) {
-				mock.auth.EXPECT().GenerateGuestToken().Return(tokenMock, nil)
-			},
-			want:    tokenMock,
-			wantErr: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			tt.mockFunc(mocks)
-			got, err := u.GenerateGuestToken()
-			if (err != nil) != tt.wantErr {
-				t.Errorf("user.Create() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			assert.Equal(t, tt.want, got)
-		})
+	createRefreshTokenMock := entity.RefreshToken{
+		UserID:    1,
+		TokenHash: auth.HashToken("refresh-token"),
+		ExpiresAt: refreshTokenResultMock.ExpiresAt,
 	}
-}
-
-func Test_user_Me(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	authMock := mock_auth.NewMockInterface(ctrl)
-	userMock := mock_user.NewMockInterface(ctrl)
 
-	u := user.Init(userMock, authMock, nil, nil)
-
-	mockAuthUserInfo := auth.UserAuthInfo{
-		User: auth.User{
-			ID: 1,
-		},
+	tokenMock := entity.TokenResult{
+		Token:        "token",
+		RefreshToken: "refresh-token",
+		ExpiresIn:    900,
 	}
 
-	mockUserParam := entity.UserParam{
-		ID: 1,
+	mockParams := entity.LoginUserParam{
+		Username: "username",
+		Password: "password",
+		IP:       "127.0.0.1",
 	}
 
 	mockUserResult := entity.User{
 		Model: gorm.Model{
 			ID: 1,
 		},
+		Username: "username",
+		Password: string(hashPass),
 	}
 
+	rehashedPass, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost+1)
+	mockRehashedUserResult := mockUserResult
+	mockRehashedUserResult.Password = string(rehashedPass)
+
+	mockWrongPasswordUserResult := mockUserResult
+	mockWrongPasswordUserResult.Password = "wrongPassword"
+
+	lockedUntil := now.Add(10 * time.Minute)
+	mockLockedUserResult := mockUserResult
+	mockLockedUserResult.LockedUntil = &lockedUntil
+
+	expiredLock := now.Add(-time.Minute)
+	mockExpiredLockUserResult := mockRehashedUserResult
+	mockExpiredLockUserResult.FailedLoginCount = 2
+	mockExpiredLockUserResult.LockedUntil = &expiredLock
+
+	recentFailure := now.Add(-time.Second)
+	oldFailure := now.Add(-time.Hour)
+
+	loginEvent := func(userID uint, reason string) entity.LoginEvent {
+		return entity.LoginEvent{
+			UserID:   userID,
+			Username: "username",
+			IP:       "127.0.0.1",
+			Success:  reason == entity.LoginReasonSuccess,
+			Reason:   reason,
+		}
+	}
+
+	u := user.Init(userMock, authMock, nil, nil, refreshTokenMock, nil, nil, loginEventMock, nil, user.Config{
+		BcryptCost:         bcrypt.MinCost + 1,
+		MaxFailedLogin:     3,
+		LockoutDuration:    30 * time.Minute,
+		BackoffThreshold:   3,
+		IPBackoffThreshold: 10,
+		LoginBackoffBase:   time.Minute,
+		LoginBackoffMax:    5 * time.Minute,
+		LoginFailureWindow: 15 * time.Minute,
+	}, entity.PaginationConfig{})
+
 	type mockfields struct {
-		user *mock_user.MockInterface
-		auth *mock_auth.MockInterface
+		user         *mock_user.MockInterface
+		auth         *mock_auth.MockInterface
+		refreshToken *mock_refresh_token.MockInterface
+		loginEvent   *mock_login_event.MockInterface
 	}
 
 	mocks := mockfields{
-		user: userMock,
-		auth: authMock,
+		user:         userMock,
+		auth:         authMock,
+		refreshToken: refreshTokenMock,
+		loginEvent:   loginEventMock,
+	}
+
+	noFailure := func(mock mockfields) {
+		mock.loginEvent.EXPECT().GetFailureSummary(gomock.Any(), gomock.Any()).Return(entity.LoginFailureSummary{}, nil).Times(2)
 	}
 
 	type args struct {
-		ctx context.Context
+		params entity.LoginUserParam
 	}
 
 	tests := []struct {
 		name     string
-		args     args
 		mockFunc func(mock mockfields, arg args)
-		want     entity.User
+		args     args
+		want     entity.TokenResult
 		wantErr  bool
 	}{
 		{
-			name: "failed to get user auth",
+			name: "failed to get failure summary",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.loginEvent.EXPECT().GetFailureSummary(gomock.Any(), gomock.Any()).Return(entity.LoginFailureSummary{}, assert.AnError)
+			},
 			args: args{
-				ctx: context.Background(),
+				params: mockParams,
 			},
+			want:    entity.TokenResult{},
+			wantErr: true,
+		},
+		{
+			name: "username throttled",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(auth.UserAuthInfo{}, assert.AnError)
+				mock.loginEvent.EXPECT().GetFailureSummary(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, param entity.LoginFailureParam) (entity.LoginFailureSummary, error) {
+					assert.Equal(t, "username", param.Username)
+					return entity.LoginFailureSummary{TotalFailure: 4, LastFailureAt: &recentFailure}, nil
+				})
+				mock.loginEvent.EXPECT().GetFailureSummary(gomock.Any(), gomock.Any()).Return(entity.LoginFailureSummary{}, nil)
 			},
-			want:    entity.User{},
+			args: args{
+				params: mockParams,
+			},
+			want:    entity.TokenResult{},
 			wantErr: true,
 		},
 		{
-			name: "failed to get user",
+			name: "ip throttled",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.loginEvent.EXPECT().GetFailureSummary(gomock.Any(), gomock.Any()).Return(entity.LoginFailureSummary{}, nil)
+				mock.loginEvent.EXPECT().GetFailureSummary(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, param entity.LoginFailureParam) (entity.LoginFailureSummary, error) {
+					assert.Equal(t, "127.0.0.1", param.IP)
+					return entity.LoginFailureSummary{TotalFailure: 10, LastFailureAt: &recentFailure}, nil
+				})
+			},
 			args: args{
-				ctx: context.Background(),
+				params: mockParams,
 			},
+			want:    entity.TokenResult{},
+			wantErr: true,
+		},
+		{
+			name: "failed to find user",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(mockAuthUserInfo, nil)
-				mock.user.EXPECT().Get(mockUserParam).Return(entity.User{}, assert.AnError)
+				noFailure(mock)
+				mock.user.EXPECT().Get(gomock.Any(), gomock.Any()).Return(entity.User{}, assert.AnError)
 			},
-			want:    entity.User{},
+			args: args{
+				params: mockParams,
+			},
+			want:    entity.TokenResult{},
 			wantErr: true,
 		},
 		{
-			name: "success",
+			name: "user not found",
+			mockFunc: func(mock mockfields, arg args) {
+				noFailure(mock)
+				mock.user.EXPECT().Get(gomock.Any(), gomock.Any()).Return(entity.User{}, gorm.ErrRecordNotFound)
+				mock.loginEvent.EXPECT().Create(gomock.Any(), loginEvent(0, entity.LoginReasonUserNotFound)).Return(entity.LoginEvent{}, nil)
+			},
 			args: args{
-				ctx: context.Background(),
+				params: mockParams,
 			},
+			want:    entity.TokenResult{},
+			wantErr: true,
+		},
+		{
+			name: "account locked",
 			mockFunc: func(mock mockfields, arg args) {
-				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(mockAuthUserInfo, nil)
-				mock.user.EXPECT().Get(mockUserParam).Return(mockUserResult, nil)
+				noFailure(mock)
+				mock.user.EXPECT().Get(gomock.Any(), gomock.Any()).Return(mockLockedUserResult, nil)
+				mock.loginEvent.EXPECT().Create(gomock.Any(), loginEvent(1, entity.LoginReasonLocked)).Return(entity.LoginEvent{}, nil)
 			},
-			want:    mockUserResult,
-			wantErr: false,
+			args: args{
+				params: mockParams,
+			},
+			want:    entity.TokenResult{},
+			wantErr: true,
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			tt.mockFunc(mocks, tt.args)
-			got, err := u.Me(tt.args.ctx)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("user.Me() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			assert.Equal(t, tt.want, got)
+		{
+			name: "account locked with wrong password",
+			mockFunc: func(mock mockfields, arg args) {
+				noFailure(mock)
+				lockedWrongPasswordUser := mockLockedUserResult
+				lockedWrongPasswordUser.Password = "wrongPassword"
+				mock.user.EXPECT().Get(gomock.Any(), gomock.Any()).Return(lockedWrongPasswordUser, nil)
+				mock.loginEvent.EXPECT().Create(gomock.Any(), loginEvent(1, entity.LoginReasonWrongPassword)).Return(entity.LoginEvent{}, nil)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    entity.TokenResult{},
+			wantErr: true,
+		},
+		{
+			name: "password incorrect",
+			mockFunc: func(mock mockfields, arg args) {
+				noFailure(mock)
+				mock.user.EXPECT().Get(gomock.Any(), gomock.Any()).Return(mockWrongPasswordUserResult, nil)
+				mock.user.EXPECT().RecordFailedLogin(gomock.Any(), uint(1), 3, gomock.Any()).DoAndReturn(func(_ context.Context, id uint, maxFailedLogin int, lockedUntil time.Time) error {
+					assert.WithinDuration(t, now.Add(30*time.Minute), lockedUntil, time.Minute)
+					return nil
+				})
+				mock.loginEvent.EXPECT().Create(gomock.Any(), loginEvent(1, entity.LoginReasonWrongPassword)).Return(entity.LoginEvent{}, nil)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    entity.TokenResult{},
+			wantErr: true,
+		},
+		{
+			name: "failed to record failed login",
+			mockFunc: func(mock mockfields, arg args) {
+				noFailure(mock)
+				mock.user.EXPECT().Get(gomock.Any(), gomock.Any()).Return(mockWrongPasswordUserResult, nil)
+				mock.user.EXPECT().RecordFailedLogin(gomock.Any(), uint(1), 3, gomock.Any()).Return(assert.AnError)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    entity.TokenResult{},
+			wantErr: true,
+		},
+		{
+			name: "disabled account",
+			mockFunc: func(mock mockfields, arg args) {
+				noFailure(mock)
+				disabledUser := mockRehashedUserResult
+				disabledUser.IsDisabled = true
+				mock.user.EXPECT().Get(gomock.Any(), gomock.Any()).Return(disabledUser, nil)
+				mock.loginEvent.EXPECT().Create(gomock.Any(), loginEvent(1, entity.LoginReasonDisabled)).Return(entity.LoginEvent{}, nil)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    entity.TokenResult{},
+			wantErr: true,
+		},
+		{
+			name: "failed to record login event",
+			mockFunc: func(mock mockfields, arg args) {
+				noFailure(mock)
+				mock.user.EXPECT().Get(gomock.Any(), gomock.Any()).Return(mockRehashedUserResult, nil)
+				mock.loginEvent.EXPECT().Create(gomock.Any(), loginEvent(1, entity.LoginReasonSuccess)).Return(entity.LoginEvent{}, assert.AnError)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    entity.TokenResult{},
+			wantErr: true,
+		},
+		{
+			name: "failed to generate token",
+			mockFunc: func(mock mockfields, arg args) {
+				noFailure(mock)
+				mock.user.EXPECT().Get(gomock.Any(), gomock.Any()).Return(mockUserResult, nil)
+				mock.loginEvent.EXPECT().Create(gomock.Any(), loginEvent(1, entity.LoginReasonSuccess)).Return(entity.LoginEvent{}, nil)
+				mock.user.EXPECT().Update(gomock.Any(), entity.UserParam{ID: 1}, gomock.Any()).Return(nil)
+				mock.auth.EXPECT().GenerateToken(gomock.Any()).Return(auth.Token{}, assert.AnError)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    entity.TokenResult{},
+			wantErr: true,
+		},
+		{
+			name: "failed to generate refresh token",
+			mockFunc: func(mock mockfields, arg args) {
+				noFailure(mock)
+				mock.user.EXPECT().Get(gomock.Any(), gomock.Any()).Return(mockUserResult, nil)
+				mock.loginEvent.EXPECT().Create(gomock.Any(), loginEvent(1, entity.LoginReasonSuccess)).Return(entity.LoginEvent{}, nil)
+				mock.user.EXPECT().Update(gomock.Any(), entity.UserParam{ID: 1}, gomock.Any()).Return(nil)
+				mock.auth.EXPECT().GenerateToken(gomock.Any()).Return(accessTokenMock, nil)
+				mock.auth.EXPECT().GenerateRefreshToken().Return(auth.Token{}, assert.AnError)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    entity.TokenResult{},
+			wantErr: true,
+		},
+		{
+			name: "failed to store refresh token",
+			mockFunc: func(mock mockfields, arg args) {
+				noFailure(mock)
+				mock.user.EXPECT().Get(gomock.Any(), gomock.Any()).Return(mockUserResult, nil)
+				mock.loginEvent.EXPECT().Create(gomock.Any(), loginEvent(1, entity.LoginReasonSuccess)).Return(entity.LoginEvent{}, nil)
+				mock.user.EXPECT().Update(gomock.Any(), entity.UserParam{ID: 1}, gomock.Any()).Return(nil)
+				mock.auth.EXPECT().GenerateToken(gomock.Any()).Return(accessTokenMock, nil)
+				mock.auth.EXPECT().GenerateRefreshToken().Return(refreshTokenResultMock, nil)
+				mock.refreshToken.EXPECT().Create(gomock.Any(), createRefreshTokenMock).Return(entity.RefreshToken{}, assert.AnError)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    entity.TokenResult{},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			mockFunc: func(mock mockfields, arg args) {
+				noFailure(mock)
+				mock.user.EXPECT().Get(gomock.Any(), gomock.Any()).Return(mockUserResult, nil)
+				mock.loginEvent.EXPECT().Create(gomock.Any(), loginEvent(1, entity.LoginReasonSuccess)).Return(entity.LoginEvent{}, nil)
+				mock.user.EXPECT().Update(gomock.Any(), entity.UserParam{ID: 1}, gomock.Any()).Return(nil)
+				mock.auth.EXPECT().GenerateToken(gomock.Any()).Return(accessTokenMock, nil)
+				mock.auth.EXPECT().GenerateRefreshToken().Return(refreshTokenResultMock, nil)
+				mock.refreshToken.EXPECT().Create(gomock.Any(), createRefreshTokenMock).Return(createRefreshTokenMock, nil)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    tokenMock,
+			wantErr: false,
+		},
+		{
+			name: "success after backoff elapsed",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.loginEvent.EXPECT().GetFailureSummary(gomock.Any(), gomock.Any()).Return(entity.LoginFailureSummary{TotalFailure: 4, LastFailureAt: &oldFailure}, nil)
+				mock.loginEvent.EXPECT().GetFailureSummary(gomock.Any(), gomock.Any()).Return(entity.LoginFailureSummary{}, nil)
+				mock.user.EXPECT().Get(gomock.Any(), gomock.Any()).Return(mockRehashedUserResult, nil)
+				mock.loginEvent.EXPECT().Create(gomock.Any(), loginEvent(1, entity.LoginReasonSuccess)).Return(entity.LoginEvent{}, nil)
+				mock.auth.EXPECT().GenerateToken(gomock.Any()).Return(accessTokenMock, nil)
+				mock.auth.EXPECT().GenerateRefreshToken().Return(refreshTokenResultMock, nil)
+				mock.refreshToken.EXPECT().Create(gomock.Any(), createRefreshTokenMock).Return(createRefreshTokenMock, nil)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    tokenMock,
+			wantErr: false,
+		},
+		{
+			name: "success after lock expired resets login state",
+			mockFunc: func(mock mockfields, arg args) {
+				noFailure(mock)
+				mock.user.EXPECT().Get(gomock.Any(), gomock.Any()).Return(mockExpiredLockUserResult, nil)
+				mock.user.EXPECT().UpdateLoginState(gomock.Any(), uint(1), 0, nil).Return(nil)
+				mock.loginEvent.EXPECT().Create(gomock.Any(), loginEvent(1, entity.LoginReasonSuccess)).Return(entity.LoginEvent{}, nil)
+				mock.auth.EXPECT().GenerateToken(gomock.Any()).Return(accessTokenMock, nil)
+				mock.auth.EXPECT().GenerateRefreshToken().Return(refreshTokenResultMock, nil)
+				mock.refreshToken.EXPECT().Create(gomock.Any(), createRefreshTokenMock).Return(createRefreshTokenMock, nil)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    tokenMock,
+			wantErr: false,
+		},
+		{
+			name: "failed to rehash password does not block login",
+			mockFunc: func(mock mockfields, arg args) {
+				noFailure(mock)
+				mock.user.EXPECT().Get(gomock.Any(), gomock.Any()).Return(mockUserResult, nil)
+				mock.loginEvent.EXPECT().Create(gomock.Any(), loginEvent(1, entity.LoginReasonSuccess)).Return(entity.LoginEvent{}, nil)
+				mock.user.EXPECT().Update(gomock.Any(), entity.UserParam{ID: 1}, gomock.Any()).Return(assert.AnError)
+				mock.auth.EXPECT().GenerateToken(gomock.Any()).Return(accessTokenMock, nil)
+				mock.auth.EXPECT().GenerateRefreshToken().Return(refreshTokenResultMock, nil)
+				mock.refreshToken.EXPECT().Create(gomock.Any(), createRefreshTokenMock).Return(createRefreshTokenMock, nil)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    tokenMock,
+			wantErr: false,
+		},
+		{
+			name: "success without rehash",
+			mockFunc: func(mock mockfields, arg args) {
+				noFailure(mock)
+				mock.user.EXPECT().Get(gomock.Any(), gomock.Any()).Return(mockRehashedUserResult, nil)
+				mock.loginEvent.EXPECT().Create(gomock.Any(), loginEvent(1, entity.LoginReasonSuccess)).Return(entity.LoginEvent{}, nil)
+				mock.auth.EXPECT().GenerateToken(gomock.Any()).Return(accessTokenMock, nil)
+				mock.auth.EXPECT().GenerateRefreshToken().Return(refreshTokenResultMock, nil)
+				mock.refreshToken.EXPECT().Create(gomock.Any(), createRefreshTokenMock).Return(createRefreshTokenMock, nil)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    tokenMock,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mocks, tt.args)
+			got, err := u.Login(context.Background(), tt.args.params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("user.Login() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_user_RegisterBuyer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userMock := mock_user.NewMockInterface(ctrl)
+	authMock := mock_auth.NewMockInterface(ctrl)
+	cartMock := mock_cart.NewMockInterface(ctrl)
+	refreshTokenMock := mock_refresh_token.NewMockInterface(ctrl)
+	transactionMock := mock_transaction.NewMockInterface(ctrl)
+	now := time.Now()
+
+	accessTokenMock := auth.Token{
+		ID:        "jti",
+		Value:     "token",
+		IssuedAt:  now,
+		ExpiresAt: now.Add(15 * time.Minute),
+	}
+
+	refreshTokenResultMock := auth.Token{
+		Value:     "refresh-token",
+		IssuedAt:  now,
+		ExpiresAt: now.Add(720 * time.Hour),
+	}
+
+	createRefreshTokenMock := entity.RefreshToken{
+		UserID:    1,
+		TokenHash: auth.HashToken("refresh-token"),
+		ExpiresAt: refreshTokenResultMock.ExpiresAt,
+	}
+
+	tokenMock := entity.TokenResult{
+		Token:        "token",
+		RefreshToken: "refresh-token",
+		ExpiresIn:    900,
+	}
+
+	mockParams := entity.RegisterBuyerParam{
+		Email:    "Buyer@Mail.com",
+		Nama:     "buyer",
+		Password: "password123",
+		GuestID:  "device-guest",
+	}
+
+	mockBuyerResult := entity.User{
+		Model: gorm.Model{
+			ID: 1,
+		},
+		Username: "buyer@mail.com",
+		Email:    "buyer@mail.com",
+		Role:     auth.RoleBuyer,
+		GuestID:  "buyer-guest",
+	}
+
+	u := user.Init(userMock, authMock, cartMock, nil, refreshTokenMock, nil, nil, nil, transactionMock, user.Config{BcryptCost: bcrypt.MinCost}, entity.PaginationConfig{})
+
+	type mockfields struct {
+		user         *mock_user.MockInterface
+		auth         *mock_auth.MockInterface
+		cart         *mock_cart.MockInterface
+		refreshToken *mock_refresh_token.MockInterface
+		transaction  *mock_transaction.MockInterface
+	}
+
+	mocks := mockfields{
+		user:         userMock,
+		auth:         authMock,
+		cart:         cartMock,
+		refreshToken: refreshTokenMock,
+		transaction:  transactionMock,
+	}
+
+	generateToken := func(mock mockfields) {
+		mock.auth.EXPECT().GenerateToken(gomock.Any()).Return(accessTokenMock, nil)
+		mock.auth.EXPECT().GenerateRefreshToken().Return(refreshTokenResultMock, nil)
+		mock.refreshToken.EXPECT().Create(gomock.Any(), createRefreshTokenMock).Return(createRefreshTokenMock, nil)
+	}
+
+	type args struct {
+		params entity.RegisterBuyerParam
+	}
+
+	tests := []struct {
+		name     string
+		mockFunc func(mock mockfields, arg args)
+		args     args
+		want     entity.TokenResult
+		wantErr  bool
+	}{
+		{
+			name:     "password too short",
+			mockFunc: func(mock mockfields, arg args) {},
+			args: args{
+				params: entity.RegisterBuyerParam{
+					Email:    "buyer@mail.com",
+					Nama:     "buyer",
+					Password: "pass1",
+				},
+			},
+			want:    entity.TokenResult{},
+			wantErr: true,
+		},
+		{
+			name: "email already registered",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{Username: "buyer@mail.com"}).Return(mockBuyerResult, nil)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    entity.TokenResult{},
+			wantErr: true,
+		},
+		{
+			name: "failed to check username",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{Username: "buyer@mail.com"}).Return(entity.User{}, assert.AnError)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    entity.TokenResult{},
+			wantErr: true,
+		},
+		{
+			name: "email registered concurrently",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{Username: "buyer@mail.com"}).Return(entity.User{}, gorm.ErrRecordNotFound)
+				mock.user.EXPECT().CreateBuyer(gomock.Any(), gomock.Any(), "device-guest").Return(entity.User{}, &mysqldriver.MySQLError{Number: 1062})
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    entity.TokenResult{},
+			wantErr: true,
+		},
+		{
+			name: "failed to create buyer and claim guest session",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{Username: "buyer@mail.com"}).Return(entity.User{}, gorm.ErrRecordNotFound)
+				mock.user.EXPECT().CreateBuyer(gomock.Any(), gomock.Any(), "device-guest").Return(entity.User{}, assert.AnError)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    entity.TokenResult{},
+			wantErr: true,
+		},
+		{
+			name: "success claims guest session",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{Username: "buyer@mail.com"}).Return(entity.User{}, gorm.ErrRecordNotFound)
+				mock.user.EXPECT().CreateBuyer(gomock.Any(), gomock.Any(), "device-guest").DoAndReturn(func(_ context.Context, param entity.User, _ string) (entity.User, error) {
+					assert.Equal(t, "buyer@mail.com", param.Username)
+					assert.Equal(t, "buyer@mail.com", param.Email)
+					assert.Equal(t, auth.RoleBuyer, param.Role)
+					assert.NotEmpty(t, param.GuestID)
+					assert.NotEqual(t, "device-guest", param.GuestID)
+					return mockBuyerResult, nil
+				})
+				generateToken(mock)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    tokenMock,
+			wantErr: false,
+		},
+		{
+			name: "success with phone without guest session",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{Username: "08123456789"}).Return(entity.User{}, gorm.ErrRecordNotFound)
+				mock.user.EXPECT().CreateBuyer(gomock.Any(), gomock.Any(), "").Return(mockBuyerResult, nil)
+				generateToken(mock)
+			},
+			args: args{
+				params: entity.RegisterBuyerParam{
+					Phone:    "08123456789",
+					Nama:     "buyer",
+					Password: "password123",
+				},
+			},
+			want:    tokenMock,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mocks, tt.args)
+			got, err := u.RegisterBuyer(context.Background(), tt.args.params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("user.RegisterBuyer() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_user_Unlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userMock := mock_user.NewMockInterface(ctrl)
+
+	u := user.Init(userMock, nil, nil, nil, nil, nil, nil, nil, nil, user.Config{BcryptCost: bcrypt.MinCost}, entity.PaginationConfig{})
+
+	type mockfields struct {
+		user *mock_user.MockInterface
+	}
+
+	mocks := mockfields{
+		user: userMock,
+	}
+
+	type args struct {
+		param entity.UserParam
+	}
+
+	tests := []struct {
+		name     string
+		mockFunc func(mock mockfields, arg args)
+		args     args
+		wantErr  bool
+	}{
+		{
+			name: "failed to get user",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 1}).Return(entity.User{}, gorm.ErrRecordNotFound)
+			},
+			args: args{
+				param: entity.UserParam{ID: 1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to update login state",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 1}).Return(entity.User{Model: gorm.Model{ID: 1}}, nil)
+				mock.user.EXPECT().UpdateLoginState(gomock.Any(), uint(1), 0, nil).Return(assert.AnError)
+			},
+			args: args{
+				param: entity.UserParam{ID: 1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 1}).Return(entity.User{Model: gorm.Model{ID: 1}}, nil)
+				mock.user.EXPECT().UpdateLoginState(gomock.Any(), uint(1), 0, nil).Return(nil)
+			},
+			args: args{
+				param: entity.UserParam{ID: 1},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mocks, tt.args)
+			if err := u.Unlock(context.Background(), tt.args.param); (err != nil) != tt.wantErr {
+				t.Errorf("user.Unlock() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_user_GetLoginEventList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	loginEventMock := mock_login_event.NewMockInterface(ctrl)
+
+	u := user.Init(nil, nil, nil, nil, nil, nil, nil, loginEventMock, nil, user.Config{BcryptCost: bcrypt.MinCost}, entity.PaginationConfig{})
+
+	failed := false
+	mockLoginEvents := []entity.LoginEvent{
+		{
+			UserID:   1,
+			Username: "username",
+			Reason:   entity.LoginReasonWrongPassword,
+		},
+	}
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	cursorLoginEvents := []entity.LoginEvent{
+		{
+			Model: gorm.Model{
+				ID:        6,
+				CreatedAt: createdAt,
+			},
+			UserID:   1,
+			Username: "username",
+			Reason:   entity.LoginReasonWrongPassword,
+		},
+	}
+
+	type mockfields struct {
+		loginEvent *mock_login_event.MockInterface
+	}
+
+	mocks := mockfields{
+		loginEvent: loginEventMock,
+	}
+
+	type args struct {
+		param entity.LoginEventParam
+	}
+
+	tests := []struct {
+		name           string
+		mockFunc       func(mock mockfields, arg args)
+		args           args
+		want           []entity.LoginEvent
+		wantPagination entity.Pagination
+		wantErr        bool
+	}{
+		{
+			name: "failed to count login events",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.loginEvent.EXPECT().Count(gomock.Any(), gomock.Any()).Return(int64(0), assert.AnError)
+			},
+			args: args{
+				param: entity.LoginEventParam{},
+			},
+			want:    []entity.LoginEvent{},
+			wantErr: true,
+		},
+		{
+			name: "failed to get login event list",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.loginEvent.EXPECT().Count(gomock.Any(), gomock.Any()).Return(int64(1), nil)
+				mock.loginEvent.EXPECT().GetList(gomock.Any(), gomock.Any()).Return([]entity.LoginEvent{}, assert.AnError)
+			},
+			args: args{
+				param: entity.LoginEventParam{},
+			},
+			want:    []entity.LoginEvent{},
+			wantErr: true,
+		},
+		{
+			name: "success with default pagination",
+			mockFunc: func(mock mockfields, arg args) {
+				listParam := entity.LoginEventParam{
+					PaginationParam: entity.PaginationParam{
+						Page:      1,
+						Limit:     20,
+						Offset:    0,
+						SortBy:    "created_at",
+						SortOrder: entity.SortDesc,
+						OrderBy:   "created_at desc, id desc",
+					},
+				}
+				mock.loginEvent.EXPECT().Count(gomock.Any(), listParam).Return(int64(1), nil)
+				mock.loginEvent.EXPECT().GetList(gomock.Any(), listParam).Return(mockLoginEvents, nil)
+			},
+			args: args{
+				param: entity.LoginEventParam{},
+			},
+			want: mockLoginEvents,
+			wantPagination: entity.Pagination{
+				Page:       1,
+				Limit:      20,
+				TotalItems: 1,
+				TotalPages: 1,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success with filter",
+			mockFunc: func(mock mockfields, arg args) {
+				listParam := entity.LoginEventParam{
+					Username: "username",
+					Success:  &failed,
+					PaginationParam: entity.PaginationParam{
+						Page:      2,
+						Limit:     10,
+						Offset:    10,
+						SortBy:    "created_at",
+						SortOrder: entity.SortDesc,
+						OrderBy:   "created_at desc, id desc",
+					},
+				}
+				mock.loginEvent.EXPECT().Count(gomock.Any(), listParam).Return(int64(11), nil)
+				mock.loginEvent.EXPECT().GetList(gomock.Any(), listParam).Return(mockLoginEvents, nil)
+			},
+			args: args{
+				param: entity.LoginEventParam{
+					Username: "username",
+					Success:  &failed,
+					PaginationParam: entity.PaginationParam{
+						Limit: 10,
+						Page:  2,
+					},
+				},
+			},
+			want: mockLoginEvents,
+			wantPagination: entity.Pagination{
+				Page:       2,
+				Limit:      10,
+				TotalItems: 11,
+				TotalPages: 2,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success with cursor",
+			mockFunc: func(mock mockfields, arg args) {
+				listParam := entity.LoginEventParam{
+					PaginationParam: entity.PaginationParam{
+						Limit:     1,
+						SortBy:    "created_at",
+						SortOrder: entity.SortDesc,
+						Cursor:    "eyJzIjoiY3JlYXRlZF9hdCBkZXNjIiwidiI6WyJ0OjIwMjQtMDEtMDJUMDM6MDQ6MDVaIiwidTo3Il19",
+						OrderBy:   "created_at desc, id desc",
+						After: &entity.Keyset{
+							Query: "(created_at, id) < (?, ?)",
+							Args:  []interface{}{createdAt, uint64(7)},
+						},
+					},
+				}
+				mock.loginEvent.EXPECT().Count(gomock.Any(), listParam).Return(int64(11), nil)
+				mock.loginEvent.EXPECT().GetList(gomock.Any(), listParam).Return(cursorLoginEvents, nil)
+			},
+			args: args{
+				param: entity.LoginEventParam{
+					PaginationParam: entity.PaginationParam{
+						Limit:  1,
+						Cursor: "eyJzIjoiY3JlYXRlZF9hdCBkZXNjIiwidiI6WyJ0OjIwMjQtMDEtMDJUMDM6MDQ6MDVaIiwidTo3Il19",
+					},
+				},
+			},
+			want: cursorLoginEvents,
+			wantPagination: entity.Pagination{
+				Limit:      1,
+				TotalItems: 11,
+				TotalPages: 11,
+				NextCursor: "eyJzIjoiY3JlYXRlZF9hdCBkZXNjIiwidiI6WyJ0OjIwMjQtMDEtMDJUMDM6MDQ6MDVaIiwidTo2Il19",
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mocks, tt.args)
+			got, pagination, err := u.GetLoginEventList(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("user.GetLoginEventList() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantPagination, pagination)
+		})
+	}
+}
+
+func Test_user_Get(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userMock := mock_user.NewMockInterface(ctrl)
+
+	paramsMock := entity.UserParam{
+		ID: 1,
+	}
+
+	userResultMock := entity.User{
+		Model: gorm.Model{
+			ID: 1,
+		},
+		Username: "username",
+	}
+
+	u := user.Init(userMock, nil, nil, nil, nil, nil, nil, nil, nil, user.Config{BcryptCost: bcrypt.MinCost}, entity.PaginationConfig{})
+
+	type mockfields struct {
+		user *mock_user.MockInterface
+	}
+
+	mocks := mockfields{
+		user: userMock,
+	}
+
+	type args struct {
+		params entity.UserParam
+	}
+
+	tests := []struct {
+		name     string
+		mockFunc func(mock mockfields, arg args)
+		args     args
+		want     entity.User
+		wantErr  bool
+	}{
+		{
+			name: "failed to get user",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), gomock.Any()).Return(entity.User{}, assert.AnError)
+			},
+			args: args{
+				params: paramsMock,
+			},
+			want:    entity.User{},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), gomock.Any()).Return(userResultMock, nil)
+			},
+			args: args{
+				params: paramsMock,
+			},
+			want:    userResultMock,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mocks, tt.args)
+			got, err := u.Get(context.Background(), tt.args.params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("user.Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_user_GetList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userMock := mock_user.NewMockInterface(ctrl)
+
+	u := user.Init(userMock, nil, nil, nil, nil, nil, nil, nil, nil, user.Config{BcryptCost: bcrypt.MinCost}, entity.PaginationConfig{})
+
+	disabled := true
+
+	type mockfields struct {
+		user *mock_user.MockInterface
+	}
+
+	mocks := mockfields{
+		user: userMock,
+	}
+
+	type args struct {
+		param entity.UserListParam
+	}
+
+	tests := []struct {
+		name           string
+		mockFunc       func(mock mockfields, arg args)
+		args           args
+		want           []entity.User
+		wantPagination entity.Pagination
+		wantErr        bool
+	}{
+		{
+			name:     "invalid page",
+			mockFunc: func(mock mockfields, arg args) {},
+			args: args{
+				param: entity.UserListParam{
+					PaginationParam: entity.PaginationParam{
+						Page: -1,
+					},
+				},
+			},
+			want:    []entity.User{},
+			wantErr: true,
+		},
+		{
+			name:     "invalid cursor",
+			mockFunc: func(mock mockfields, arg args) {},
+			args: args{
+				param: entity.UserListParam{
+					PaginationParam: entity.PaginationParam{
+						Cursor: "not a cursor",
+					},
+				},
+			},
+			want:    []entity.User{},
+			wantErr: true,
+		},
+		{
+			name:     "cursor of another sort",
+			mockFunc: func(mock mockfields, arg args) {},
+			args: args{
+				param: entity.UserListParam{
+					PaginationParam: entity.PaginationParam{
+						SortOrder: entity.SortAsc,
+						Cursor:    "eyJzIjoiaWQgZGVzYyIsInYiOlsidTo1Il19",
+					},
+				},
+			},
+			want:    []entity.User{},
+			wantErr: true,
+		},
+		{
+			name: "failed to count users",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().CountByParam(gomock.Any(), gomock.Any()).Return(int64(0), assert.AnError)
+			},
+			args: args{
+				param: entity.UserListParam{},
+			},
+			want:    []entity.User{},
+			wantErr: true,
+		},
+		{
+			name: "failed to get user list",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().CountByParam(gomock.Any(), gomock.Any()).Return(int64(1), nil)
+				mock.user.EXPECT().GetListByParam(gomock.Any(), gomock.Any()).Return([]entity.User{}, assert.AnError)
+			},
+			args: args{
+				param: entity.UserListParam{},
+			},
+			want:    []entity.User{},
+			wantErr: true,
+		},
+		{
+			name: "success with default pagination",
+			mockFunc: func(mock mockfields, arg args) {
+				listParam := entity.UserListParam{
+					PaginationParam: entity.PaginationParam{
+						Page:      1,
+						Limit:     20,
+						Offset:    0,
+						SortBy:    "id",
+						SortOrder: entity.SortDesc,
+						OrderBy:   "id desc",
+					},
+				}
+				mock.user.EXPECT().CountByParam(gomock.Any(), listParam).Return(int64(1), nil)
+				mock.user.EXPECT().GetListByParam(gomock.Any(), listParam).Return([]entity.User{{Username: "admin", IsAdmin: true}}, nil)
+			},
+			args: args{
+				param: entity.UserListParam{},
+			},
+			want: []entity.User{{Username: "admin", IsAdmin: true, Role: auth.RoleSuperAdmin}},
+			wantPagination: entity.Pagination{
+				Page:       1,
+				Limit:      20,
+				TotalItems: 1,
+				TotalPages: 1,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success with next cursor",
+			mockFunc: func(mock mockfields, arg args) {
+				listParam := entity.UserListParam{
+					PaginationParam: entity.PaginationParam{
+						Page:      1,
+						Limit:     1,
+						Offset:    0,
+						SortBy:    "id",
+						SortOrder: entity.SortDesc,
+						OrderBy:   "id desc",
+					},
+				}
+				mock.user.EXPECT().CountByParam(gomock.Any(), listParam).Return(int64(3), nil)
+				mock.user.EXPECT().GetListByParam(gomock.Any(), listParam).Return([]entity.User{{Model: gorm.Model{ID: 5}, Username: "admin", IsAdmin: true}}, nil)
+			},
+			args: args{
+				param: entity.UserListParam{
+					PaginationParam: entity.PaginationParam{
+						Limit: 1,
+					},
+				},
+			},
+			want: []entity.User{{Model: gorm.Model{ID: 5}, Username: "admin", IsAdmin: true, Role: auth.RoleSuperAdmin}},
+			wantPagination: entity.Pagination{
+				Page:       1,
+				Limit:      1,
+				TotalItems: 3,
+				TotalPages: 3,
+				NextCursor: "eyJzIjoiaWQgZGVzYyIsInYiOlsidTo1Il19",
+			},
+			wantErr: false,
+		},
+		{
+			name: "success with cursor",
+			mockFunc: func(mock mockfields, arg args) {
+				listParam := entity.UserListParam{
+					PaginationParam: entity.PaginationParam{
+						Limit:     1,
+						SortBy:    "id",
+						SortOrder: entity.SortDesc,
+						Cursor:    "eyJzIjoiaWQgZGVzYyIsInYiOlsidTo1Il19",
+						OrderBy:   "id desc",
+						After: &entity.Keyset{
+							Query: "id < ?",
+							Args:  []interface{}{uint64(5)},
+						},
+					},
+				}
+				mock.user.EXPECT().CountByParam(gomock.Any(), listParam).Return(int64(3), nil)
+				mock.user.EXPECT().GetListByParam(gomock.Any(), listParam).Return([]entity.User{{Model: gorm.Model{ID: 3}, Username: "kasir"}}, nil)
+			},
+			args: args{
+				param: entity.UserListParam{
+					PaginationParam: entity.PaginationParam{
+						Limit:  1,
+						Page:   2,
+						Cursor: "eyJzIjoiaWQgZGVzYyIsInYiOlsidTo1Il19",
+					},
+				},
+			},
+			want: []entity.User{{Model: gorm.Model{ID: 3}, Username: "kasir"}},
+			wantPagination: entity.Pagination{
+				Limit:      1,
+				TotalItems: 3,
+				TotalPages: 3,
+				NextCursor: "eyJzIjoiaWQgZGVzYyIsInYiOlsidTozIl19",
+			},
+			wantErr: false,
+		},
+		{
+			name: "success with search, filter and sort",
+			mockFunc: func(mock mockfields, arg args) {
+				listParam := entity.UserListParam{
+					Search:     "kasir",
+					Role:       auth.RoleTenantCashier,
+					IsDisabled: &disabled,
+					PaginationParam: entity.PaginationParam{
+						Page:      3,
+						Limit:     5,
+						Offset:    10,
+						SortBy:    "username",
+						SortOrder: entity.SortAsc,
+						OrderBy:   "username asc, id asc",
+					},
+				}
+				mock.user.EXPECT().CountByParam(gomock.Any(), listParam).Return(int64(16), nil)
+				mock.user.EXPECT().GetListByParam(gomock.Any(), listParam).Return([]entity.User{{Username: "kasir", UmkmID: 1, Role: auth.RoleTenantCashier, IsDisabled: true}}, nil)
+			},
+			args: args{
+				param: entity.UserListParam{
+					Search:     "kasir",
+					Role:       auth.RoleTenantCashier,
+					IsDisabled: &disabled,
+					PaginationParam: entity.PaginationParam{
+						Limit:     5,
+						Page:      3,
+						SortBy:    "username",
+						SortOrder: "ASC",
+					},
+				},
+			},
+			want: []entity.User{{Username: "kasir", UmkmID: 1, Role: auth.RoleTenantCashier, IsDisabled: true}},
+			wantPagination: entity.Pagination{
+				Page:       3,
+				Limit:      5,
+				TotalItems: 16,
+				TotalPages: 4,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mocks, tt.args)
+			got, pagination, err := u.GetList(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("user.GetList() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantPagination, pagination)
+		})
+	}
+}
+
+func Test_user_Update(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userMock := mock_user.NewMockInterface(ctrl)
+	authMock := mock_auth.NewMockInterface(ctrl)
+
+	u := user.Init(userMock, authMock, nil, nil, nil, nil, nil, nil, nil, user.Config{BcryptCost: bcrypt.MinCost}, entity.PaginationConfig{})
+
+	adminAuthInfo := auth.UserAuthInfo{
+		User: auth.User{
+			ID:   99,
+			Role: auth.RoleSuperAdmin,
+		},
+	}
+
+	ownerResult := entity.User{
+		Model: gorm.Model{
+			ID: 1,
+		},
+		Username: "owner",
+		UmkmID:   1,
+	}
+
+	umkmZero := uint(0)
+	umkmTwo := uint(2)
+	isAdmin := true
+	isNotAdmin := false
+
+	type mockfields struct {
+		user *mock_user.MockInterface
+		auth *mock_auth.MockInterface
+	}
+
+	mocks := mockfields{
+		user: userMock,
+		auth: authMock,
+	}
+
+	type args struct {
+		selectParam entity.UserParam
+		param       entity.AdminUpdateUserParam
+	}
+
+	tests := []struct {
+		name     string
+		mockFunc func(mock mockfields, arg args)
+		args     args
+		wantErr  bool
+	}{
+		{
+			name: "failed to get user",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 1}).Return(entity.User{}, gorm.ErrRecordNotFound)
+			},
+			args: args{
+				selectParam: entity.UserParam{ID: 1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "promote without unlinking umkm",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 1}).Return(ownerResult, nil)
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(adminAuthInfo, nil)
+			},
+			args: args{
+				selectParam: entity.UserParam{ID: 1},
+				param:       entity.AdminUpdateUserParam{Role: auth.RoleSuperAdmin},
+			},
+			wantErr: true,
+		},
+		{
+			name: "change own role",
+			mockFunc: func(mock mockfields, arg args) {
+				self := ownerResult
+				self.ID = 99
+				self.UmkmID = 0
+				self.Role = auth.RoleSuperAdmin
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 99}).Return(self, nil)
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(adminAuthInfo, nil)
+			},
+			args: args{
+				selectParam: entity.UserParam{ID: 99},
+				param:       entity.AdminUpdateUserParam{Role: auth.RoleFinanceAdmin},
+			},
+			wantErr: true,
+		},
+		{
+			name: "username already used",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 1}).Return(ownerResult, nil)
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{Username: "taken"}).Return(entity.User{Username: "taken"}, nil)
+			},
+			args: args{
+				selectParam: entity.UserParam{ID: 1},
+				param:       entity.AdminUpdateUserParam{Username: "taken"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "success promote to admin",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 1}).Return(ownerResult, nil)
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(adminAuthInfo, nil)
+				mock.user.EXPECT().Update(gomock.Any(), entity.UserParam{ID: 1}, entity.UpdateUserParam{
+					Role:    auth.RoleSuperAdmin,
+					UmkmID:  &umkmZero,
+					IsAdmin: &isAdmin,
+				}).Return(nil)
+			},
+			args: args{
+				selectParam: entity.UserParam{ID: 1},
+				param:       entity.AdminUpdateUserParam{Role: auth.RoleSuperAdmin, UmkmID: &umkmZero},
+			},
+			wantErr: false,
+		},
+		{
+			name: "success reassign umkm",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 1}).Return(ownerResult, nil)
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{Username: "owner2"}).Return(entity.User{}, gorm.ErrRecordNotFound)
+				mock.user.EXPECT().Update(gomock.Any(), entity.UserParam{ID: 1}, entity.UpdateUserParam{
+					Username: "owner2",
+					Nama:     "nama",
+					Role:     auth.RoleTenantOwner,
+					UmkmID:   &umkmTwo,
+					IsAdmin:  &isNotAdmin,
+				}).Return(nil)
+			},
+			args: args{
+				selectParam: entity.UserParam{ID: 1},
+				param:       entity.AdminUpdateUserParam{Username: "owner2", Nama: "nama", UmkmID: &umkmTwo},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mocks, tt.args)
+			if err := u.Update(context.Background(), tt.args.selectParam, tt.args.param); (err != nil) != tt.wantErr {
+				t.Errorf("user.Update() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_user_SetDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userMock := mock_user.NewMockInterface(ctrl)
+	authMock := mock_auth.NewMockInterface(ctrl)
+	refreshTokenMock := mock_refresh_token.NewMockInterface(ctrl)
+
+	u := user.Init(userMock, authMock, nil, nil, refreshTokenMock, nil, nil, nil, nil, user.Config{BcryptCost: bcrypt.MinCost}, entity.PaginationConfig{})
+
+	adminAuthInfo := auth.UserAuthInfo{
+		User: auth.User{
+			ID:   99,
+			Role: auth.RoleSuperAdmin,
+		},
+	}
+
+	userResult := entity.User{
+		Model: gorm.Model{
+			ID: 1,
+		},
+		Username: "owner",
+		UmkmID:   1,
+	}
+
+	disabled := true
+	enabled := false
+
+	type mockfields struct {
+		user         *mock_user.MockInterface
+		auth         *mock_auth.MockInterface
+		refreshToken *mock_refresh_token.MockInterface
+	}
+
+	mocks := mockfields{
+		user:         userMock,
+		auth:         authMock,
+		refreshToken: refreshTokenMock,
+	}
+
+	type args struct {
+		param      entity.UserParam
+		isDisabled bool
+	}
+
+	tests := []struct {
+		name     string
+		mockFunc func(mock mockfields, arg args)
+		args     args
+		wantErr  bool
+	}{
+		{
+			name: "failed to get user",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 1}).Return(entity.User{}, assert.AnError)
+			},
+			args: args{
+				param:      entity.UserParam{ID: 1},
+				isDisabled: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "disable own account",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 99}).Return(entity.User{Model: gorm.Model{ID: 99}}, nil)
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(adminAuthInfo, nil)
+			},
+			args: args{
+				param:      entity.UserParam{ID: 99},
+				isDisabled: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to revoke sessions",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 1}).Return(userResult, nil)
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(adminAuthInfo, nil)
+				mock.user.EXPECT().Update(gomock.Any(), entity.UserParam{ID: 1}, entity.UpdateUserParam{IsDisabled: &disabled}).Return(nil)
+				mock.refreshToken.EXPECT().RevokeAll(gomock.Any(), uint(1), gomock.Any()).Return(assert.AnError)
+			},
+			args: args{
+				param:      entity.UserParam{ID: 1},
+				isDisabled: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "success disable",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 1}).Return(userResult, nil)
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(adminAuthInfo, nil)
+				mock.user.EXPECT().Update(gomock.Any(), entity.UserParam{ID: 1}, entity.UpdateUserParam{IsDisabled: &disabled}).Return(nil)
+				mock.refreshToken.EXPECT().RevokeAll(gomock.Any(), uint(1), gomock.Any()).Return(nil)
+			},
+			args: args{
+				param:      entity.UserParam{ID: 1},
+				isDisabled: true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success enable",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 1}).Return(userResult, nil)
+				mock.user.EXPECT().Update(gomock.Any(), entity.UserParam{ID: 1}, entity.UpdateUserParam{IsDisabled: &enabled}).Return(nil)
+			},
+			args: args{
+				param:      entity.UserParam{ID: 1},
+				isDisabled: false,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mocks, tt.args)
+			if err := u.SetDisabled(context.Background(), tt.args.param, tt.args.isDisabled); (err != nil) != tt.wantErr {
+				t.Errorf("user.SetDisabled() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_user_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userMock := mock_user.NewMockInterface(ctrl)
+	authMock := mock_auth.NewMockInterface(ctrl)
+	refreshTokenMock := mock_refresh_token.NewMockInterface(ctrl)
+
+	u := user.Init(userMock, authMock, nil, nil, refreshTokenMock, nil, nil, nil, nil, user.Config{BcryptCost: bcrypt.MinCost}, entity.PaginationConfig{})
+
+	adminAuthInfo := auth.UserAuthInfo{
+		User: auth.User{
+			ID:   99,
+			Role: auth.RoleSuperAdmin,
+		},
+	}
+
+	type mockfields struct {
+		user         *mock_user.MockInterface
+		auth         *mock_auth.MockInterface
+		refreshToken *mock_refresh_token.MockInterface
+	}
+
+	mocks := mockfields{
+		user:         userMock,
+		auth:         authMock,
+		refreshToken: refreshTokenMock,
+	}
+
+	type args struct {
+		param entity.UserParam
+	}
+
+	tests := []struct {
+		name     string
+		mockFunc func(mock mockfields, arg args)
+		args     args
+		wantErr  bool
+	}{
+		{
+			name: "delete own account",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 99}).Return(entity.User{Model: gorm.Model{ID: 99}}, nil)
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(adminAuthInfo, nil)
+			},
+			args: args{
+				param: entity.UserParam{ID: 99},
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to delete user",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 1}).Return(entity.User{Model: gorm.Model{ID: 1}}, nil)
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(adminAuthInfo, nil)
+				mock.user.EXPECT().Delete(gomock.Any(), entity.UserParam{ID: 1}).Return(assert.AnError)
+			},
+			args: args{
+				param: entity.UserParam{ID: 1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 1}).Return(entity.User{Model: gorm.Model{ID: 1}}, nil)
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(adminAuthInfo, nil)
+				mock.user.EXPECT().Delete(gomock.Any(), entity.UserParam{ID: 1}).Return(nil)
+				mock.refreshToken.EXPECT().RevokeAll(gomock.Any(), uint(1), gomock.Any()).Return(nil)
+			},
+			args: args{
+				param: entity.UserParam{ID: 1},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mocks, tt.args)
+			if err := u.Delete(context.Background(), tt.args.param); (err != nil) != tt.wantErr {
+				t.Errorf("user.Delete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_user_GenerateGuestToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	authMock := mock_auth.NewMockInterface(ctrl)
+	tokenMock := "token"
+
+	u := user.Init(nil, authMock, nil, nil, nil, nil, nil, nil, nil, user.Config{BcryptCost: bcrypt.MinCost}, entity.PaginationConfig{})
+
+	type mockfields struct {
+		auth *mock_auth.MockInterface
+	}
+
+	mocks := mockfields{
+		auth: authMock,
+	}
+
+	tests := []struct {
+		name     string
+		mockFunc func(mock mockfields)
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "failed to generate guest token",
+			mockFunc: func(mock mockfields) {
+				mock.auth.EXPECT().GenerateGuestToken().Return(auth.Token{}, assert.AnError)
+			},
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name: "success",
+			mockFunc: func(mock mockfields) {
+				mock.auth.EXPECT().GenerateGuestToken().Return(auth.Token{Value: tokenMock}, nil)
+			},
+			want:    tokenMock,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mocks)
+			got, err := u.GenerateGuestToken(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("user.Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_user_Me(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	authMock := mock_auth.NewMockInterface(ctrl)
+	userMock := mock_user.NewMockInterface(ctrl)
+
+	u := user.Init(userMock, authMock, nil, nil, nil, nil, nil, nil, nil, user.Config{BcryptCost: bcrypt.MinCost}, entity.PaginationConfig{})
+
+	mockAuthUserInfo := auth.UserAuthInfo{
+		User: auth.User{
+			ID: 1,
+		},
+	}
+
+	mockUserParam := entity.UserParam{
+		ID: 1,
+	}
+
+	mockUserResult := entity.User{
+		Model: gorm.Model{
+			ID: 1,
+		},
+	}
+
+	type mockfields struct {
+		user *mock_user.MockInterface
+		auth *mock_auth.MockInterface
+	}
+
+	mocks := mockfields{
+		user: userMock,
+		auth: authMock,
+	}
+
+	type args struct {
+		ctx context.Context
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		mockFunc func(mock mockfields, arg args)
+		want     entity.User
+		wantErr  bool
+	}{
+		{
+			name: "failed to get user auth",
+			args: args{
+				ctx: context.Background(),
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(auth.UserAuthInfo{}, assert.AnError)
+			},
+			want:    entity.User{},
+			wantErr: true,
+		},
+		{
+			name: "failed to get user",
+			args: args{
+				ctx: context.Background(),
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(mockAuthUserInfo, nil)
+				mock.user.EXPECT().Get(gomock.Any(), mockUserParam).Return(entity.User{}, assert.AnError)
+			},
+			want:    entity.User{},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			args: args{
+				ctx: context.Background(),
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(mockAuthUserInfo, nil)
+				mock.user.EXPECT().Get(gomock.Any(), mockUserParam).Return(mockUserResult, nil)
+			},
+			want:    mockUserResult,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mocks, tt.args)
+			got, err := u.Me(tt.args.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("user.Me() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_user_RefreshToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userMock := mock_user.NewMockInterface(ctrl)
+	authMock := mock_auth.NewMockInterface(ctrl)
+	refreshTokenMock := mock_refresh_token.NewMockInterface(ctrl)
+	now := time.Now()
+	revokedAt := now.Add(-time.Minute)
+
+	mockParams := entity.RefreshUserTokenParam{
+		RefreshToken: "old-refresh-token",
+	}
+
+	getRefreshTokenParamMock := entity.RefreshTokenParam{
+		TokenHash: auth.HashToken("old-refresh-token"),
+	}
+
+	refreshTokenResultMock := entity.RefreshToken{
+		Model: gorm.Model{
+			ID: 1,
+		},
+		UserID:    1,
+		TokenHash: auth.HashToken("old-refresh-token"),
+		ExpiresAt: now.Add(time.Hour),
+	}
+
+	revokedRefreshTokenResultMock := refreshTokenResultMock
+	revokedRefreshTokenResultMock.RevokedAt = &revokedAt
+
+	expiredRefreshTokenResultMock := refreshTokenResultMock
+	expiredRefreshTokenResultMock.ExpiresAt = now.Add(-time.Hour)
+
+	mockUserResult := entity.User{
+		Model: gorm.Model{
+			ID: 1,
+		},
+		Username: "username",
+	}
+
+	accessTokenMock := auth.Token{
+		ID:        "jti",
+		Value:     "token",
+		IssuedAt:  now,
+		ExpiresAt: now.Add(15 * time.Minute),
+	}
+
+	newRefreshTokenMock := auth.Token{
+		Value:     "new-refresh-token",
+		IssuedAt:  now,
+		ExpiresAt: now.Add(720 * time.Hour),
+	}
+
+	tokenMock := entity.TokenResult{
+		Token:        "token",
+		RefreshToken: "new-refresh-token",
+		ExpiresIn:    900,
+	}
+
+	u := user.Init(userMock, authMock, nil, nil, refreshTokenMock, nil, nil, nil, nil, user.Config{BcryptCost: bcrypt.MinCost}, entity.PaginationConfig{})
+
+	type mockfields struct {
+		user         *mock_user.MockInterface
+		auth         *mock_auth.MockInterface
+		refreshToken *mock_refresh_token.MockInterface
+	}
+
+	mocks := mockfields{
+		user:         userMock,
+		auth:         authMock,
+		refreshToken: refreshTokenMock,
+	}
+
+	type args struct {
+		params entity.RefreshUserTokenParam
+	}
+
+	tests := []struct {
+		name     string
+		mockFunc func(mock mockfields, arg args)
+		args     args
+		want     entity.TokenResult
+		wantErr  bool
+	}{
+		{
+			name: "refresh token not found",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.refreshToken.EXPECT().Get(gomock.Any(), getRefreshTokenParamMock).Return(entity.RefreshToken{}, gorm.ErrRecordNotFound)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    entity.TokenResult{},
+			wantErr: true,
+		},
+		{
+			name: "reused refresh token revokes every session",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.refreshToken.EXPECT().Get(gomock.Any(), getRefreshTokenParamMock).Return(revokedRefreshTokenResultMock, nil)
+				mock.refreshToken.EXPECT().RevokeAll(gomock.Any(), uint(1), gomock.Any()).Return(nil)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    entity.TokenResult{},
+			wantErr: true,
+		},
+		{
+			name: "expired refresh token",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.refreshToken.EXPECT().Get(gomock.Any(), getRefreshTokenParamMock).Return(expiredRefreshTokenResultMock, nil)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    entity.TokenResult{},
+			wantErr: true,
+		},
+		{
+			name: "failed to get user",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.refreshToken.EXPECT().Get(gomock.Any(), getRefreshTokenParamMock).Return(refreshTokenResultMock, nil)
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 1}).Return(entity.User{}, assert.AnError)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    entity.TokenResult{},
+			wantErr: true,
+		},
+		{
+			name: "disabled user",
+			mockFunc: func(mock mockfields, arg args) {
+				disabledUser := mockUserResult
+				disabledUser.IsDisabled = true
+				mock.refreshToken.EXPECT().Get(gomock.Any(), getRefreshTokenParamMock).Return(refreshTokenResultMock, nil)
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 1}).Return(disabledUser, nil)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    entity.TokenResult{},
+			wantErr: true,
+		},
+		{
+			name: "failed to revoke old refresh token",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.refreshToken.EXPECT().Get(gomock.Any(), getRefreshTokenParamMock).Return(refreshTokenResultMock, nil)
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 1}).Return(mockUserResult, nil)
+				mock.refreshToken.EXPECT().Revoke(gomock.Any(), uint(1), gomock.Any()).Return(false, assert.AnError)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    entity.TokenResult{},
+			wantErr: true,
+		},
+		{
+			name: "refresh token rotated concurrently",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.refreshToken.EXPECT().Get(gomock.Any(), getRefreshTokenParamMock).Return(refreshTokenResultMock, nil)
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 1}).Return(mockUserResult, nil)
+				mock.refreshToken.EXPECT().Revoke(gomock.Any(), uint(1), gomock.Any()).Return(false, nil)
+				mock.refreshToken.EXPECT().RevokeAll(gomock.Any(), uint(1), gomock.Any()).Return(nil)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    entity.TokenResult{},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			mockFunc: func(mock mockfields, arg args) {
+				mock.refreshToken.EXPECT().Get(gomock.Any(), getRefreshTokenParamMock).Return(refreshTokenResultMock, nil)
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 1}).Return(mockUserResult, nil)
+				mock.refreshToken.EXPECT().Revoke(gomock.Any(), uint(1), gomock.Any()).Return(true, nil)
+				mock.auth.EXPECT().GenerateToken(gomock.Any()).Return(accessTokenMock, nil)
+				mock.auth.EXPECT().GenerateRefreshToken().Return(newRefreshTokenMock, nil)
+				mock.refreshToken.EXPECT().Create(gomock.Any(), gomock.Any()).Return(entity.RefreshToken{}, nil)
+			},
+			args: args{
+				params: mockParams,
+			},
+			want:    tokenMock,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mocks, tt.args)
+			got, err := u.RefreshToken(context.Background(), tt.args.params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("user.RefreshToken() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_user_Logout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	authMock := mock_auth.NewMockInterface(ctrl)
+	refreshTokenMock := mock_refresh_token.NewMockInterface(ctrl)
+	revokedTokenMock := mock_revoked_token.NewMockInterface(ctrl)
+	expiresAt := time.Now().Add(15 * time.Minute)
+
+	mockAuthUserInfo := auth.UserAuthInfo{
+		User: auth.User{
+			ID: 1,
+		},
+		TokenID:        "jti",
+		TokenExpiresAt: expiresAt,
+	}
+
+	revokedTokenParamMock := entity.RevokedToken{
+		JTI:       "jti",
+		ExpiresAt: expiresAt,
+	}
+
+	refreshTokenParamMock := entity.RefreshTokenParam{
+		UserID:    1,
+		TokenHash: auth.HashToken("refresh-token"),
+	}
+
+	u := user.Init(nil, authMock, nil, nil, refreshTokenMock, revokedTokenMock, nil, nil, nil, user.Config{BcryptCost: bcrypt.MinCost}, entity.PaginationConfig{})
+
+	type mockfields struct {
+		auth         *mock_auth.MockInterface
+		refreshToken *mock_refresh_token.MockInterface
+		revokedToken *mock_revoked_token.MockInterface
+	}
+
+	mocks := mockfields{
+		auth:         authMock,
+		refreshToken: refreshTokenMock,
+		revokedToken: revokedTokenMock,
+	}
+
+	type args struct {
+		ctx    context.Context
+		params entity.LogoutUserParam
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		mockFunc func(mock mockfields, arg args)
+		wantErr  bool
+	}{
+		{
+			name: "failed to get user auth",
+			args: args{
+				ctx: context.Background(),
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(auth.UserAuthInfo{}, assert.AnError)
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to purge expired revoked tokens",
+			args: args{
+				ctx: context.Background(),
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(mockAuthUserInfo, nil)
+				mock.revokedToken.EXPECT().DeleteExpired(gomock.Any(), gomock.Any()).Return(assert.AnError)
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to revoke token",
+			args: args{
+				ctx: context.Background(),
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(mockAuthUserInfo, nil)
+				mock.revokedToken.EXPECT().DeleteExpired(gomock.Any(), gomock.Any()).Return(nil)
+				mock.revokedToken.EXPECT().Create(gomock.Any(), revokedTokenParamMock).Return(entity.RevokedToken{}, assert.AnError)
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to revoke refresh token",
+			args: args{
+				ctx: context.Background(),
+				params: entity.LogoutUserParam{
+					RefreshToken: "refresh-token",
+				},
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(mockAuthUserInfo, nil)
+				mock.revokedToken.EXPECT().DeleteExpired(gomock.Any(), gomock.Any()).Return(nil)
+				mock.revokedToken.EXPECT().Create(gomock.Any(), revokedTokenParamMock).Return(revokedTokenParamMock, nil)
+				mock.refreshToken.EXPECT().Update(gomock.Any(), refreshTokenParamMock, gomock.Any()).Return(assert.AnError)
+			},
+			wantErr: true,
+		},
+		{
+			name: "success without refresh token",
+			args: args{
+				ctx: context.Background(),
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(mockAuthUserInfo, nil)
+				mock.revokedToken.EXPECT().DeleteExpired(gomock.Any(), gomock.Any()).Return(nil)
+				mock.revokedToken.EXPECT().Create(gomock.Any(), revokedTokenParamMock).Return(revokedTokenParamMock, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "success",
+			args: args{
+				ctx: context.Background(),
+				params: entity.LogoutUserParam{
+					RefreshToken: "refresh-token",
+				},
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(mockAuthUserInfo, nil)
+				mock.revokedToken.EXPECT().DeleteExpired(gomock.Any(), gomock.Any()).Return(nil)
+				mock.revokedToken.EXPECT().Create(gomock.Any(), revokedTokenParamMock).Return(revokedTokenParamMock, nil)
+				mock.refreshToken.EXPECT().Update(gomock.Any(), refreshTokenParamMock, gomock.Any()).Return(nil)
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mocks, tt.args)
+			err := u.Logout(tt.args.ctx, tt.args.params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("user.Logout() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func Test_user_ValidateTokenID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	revokedTokenMock := mock_revoked_token.NewMockInterface(ctrl)
+
+	u := user.Init(nil, nil, nil, nil, nil, revokedTokenMock, nil, nil, nil, user.Config{BcryptCost: bcrypt.MinCost}, entity.PaginationConfig{})
+
+	type mockfields struct {
+		revokedToken *mock_revoked_token.MockInterface
+	}
+
+	mocks := mockfields{
+		revokedToken: revokedTokenMock,
+	}
+
+	tests := []struct {
+		name     string
+		mockFunc func(mock mockfields)
+		wantErr  bool
+	}{
+		{
+			name: "failed to get revoked token",
+			mockFunc: func(mock mockfields) {
+				mock.revokedToken.EXPECT().Get(gomock.Any(), entity.RevokedTokenParam{JTI: "jti"}).Return(entity.RevokedToken{}, assert.AnError)
+			},
+			wantErr: true,
+		},
+		{
+			name: "token revoked",
+			mockFunc: func(mock mockfields) {
+				mock.revokedToken.EXPECT().Get(gomock.Any(), entity.RevokedTokenParam{JTI: "jti"}).Return(entity.RevokedToken{Model: gorm.Model{ID: 1}, JTI: "jti"}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			mockFunc: func(mock mockfields) {
+				mock.revokedToken.EXPECT().Get(gomock.Any(), entity.RevokedTokenParam{JTI: "jti"}).Return(entity.RevokedToken{}, nil)
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mocks)
+			err := u.ValidateTokenID(context.Background(), "jti")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("user.ValidateTokenID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func Test_user_ChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userMock := mock_user.NewMockInterface(ctrl)
+	authMock := mock_auth.NewMockInterface(ctrl)
+	refreshTokenMock := mock_refresh_token.NewMockInterface(ctrl)
+	hashPass, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+
+	mockAuthUserInfo := auth.UserAuthInfo{
+		User: auth.User{
+			ID: 1,
+		},
+	}
+
+	mockParams := entity.ChangePasswordParam{
+		OldPassword: "password123",
+		NewPassword: "newpassword123",
+	}
+
+	mockUserResult := entity.User{
+		Model: gorm.Model{
+			ID: 1,
+		},
+		Username: "username",
+		Password: string(hashPass),
+	}
+
+	u := user.Init(userMock, authMock, nil, nil, refreshTokenMock, nil, nil, nil, nil, user.Config{BcryptCost: bcrypt.MinCost}, entity.PaginationConfig{})
+
+	type mockfields struct {
+		user         *mock_user.MockInterface
+		auth         *mock_auth.MockInterface
+		refreshToken *mock_refresh_token.MockInterface
+	}
+
+	mocks := mockfields{
+		user:         userMock,
+		auth:         authMock,
+		refreshToken: refreshTokenMock,
+	}
+
+	type args struct {
+		ctx    context.Context
+		params entity.ChangePasswordParam
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		mockFunc func(mock mockfields, arg args)
+		wantErr  bool
+	}{
+		{
+			name: "failed to get user auth",
+			args: args{
+				ctx:    context.Background(),
+				params: mockParams,
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(auth.UserAuthInfo{}, assert.AnError)
+			},
+			wantErr: true,
+		},
+		{
+			name: "guest cannot change password",
+			args: args{
+				ctx:    context.Background(),
+				params: mockParams,
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(auth.UserAuthInfo{User: auth.User{GuestID: "guest"}}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "new password too weak",
+			args: args{
+				ctx: context.Background(),
+				params: entity.ChangePasswordParam{
+					OldPassword: "password123",
+					NewPassword: "weak",
+				},
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(mockAuthUserInfo, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to get user",
+			args: args{
+				ctx:    context.Background(),
+				params: mockParams,
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(mockAuthUserInfo, nil)
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 1}).Return(entity.User{}, assert.AnError)
+			},
+			wantErr: true,
+		},
+		{
+			name: "old password incorrect",
+			args: args{
+				ctx: context.Background(),
+				params: entity.ChangePasswordParam{
+					OldPassword: "wrongpassword1",
+					NewPassword: "newpassword123",
+				},
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(mockAuthUserInfo, nil)
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 1}).Return(mockUserResult, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to update password",
+			args: args{
+				ctx:    context.Background(),
+				params: mockParams,
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(mockAuthUserInfo, nil)
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 1}).Return(mockUserResult, nil)
+				mock.user.EXPECT().Update(gomock.Any(), entity.UserParam{ID: 1}, gomock.Any()).Return(assert.AnError)
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to revoke sessions",
+			args: args{
+				ctx:    context.Background(),
+				params: mockParams,
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(mockAuthUserInfo, nil)
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 1}).Return(mockUserResult, nil)
+				mock.user.EXPECT().Update(gomock.Any(), entity.UserParam{ID: 1}, gomock.Any()).Return(nil)
+				mock.refreshToken.EXPECT().RevokeAll(gomock.Any(), uint(1), gomock.Any()).Return(assert.AnError)
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			args: args{
+				ctx:    context.Background(),
+				params: mockParams,
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.auth.EXPECT().GetUserAuthInfo(gomock.Any()).Return(mockAuthUserInfo, nil)
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{ID: 1}).Return(mockUserResult, nil)
+				mock.user.EXPECT().Update(gomock.Any(), entity.UserParam{ID: 1}, gomock.Any()).Return(nil)
+				mock.refreshToken.EXPECT().RevokeAll(gomock.Any(), uint(1), gomock.Any()).Return(nil)
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mocks, tt.args)
+			err := u.ChangePassword(tt.args.ctx, tt.args.params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("user.ChangePassword() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func Test_user_CreatePasswordResetCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userMock := mock_user.NewMockInterface(ctrl)
+	passwordResetCodeMock := mock_password_reset_code.NewMockInterface(ctrl)
+
+	mockParam := entity.UserParam{
+		ID: 1,
+	}
+
+	mockUserResult := entity.User{
+		Model: gorm.Model{
+			ID: 1,
+		},
+		Username: "username",
+	}
+
+	u := user.Init(userMock, nil, nil, nil, nil, nil, passwordResetCodeMock, nil, nil, user.Config{BcryptCost: bcrypt.MinCost}, entity.PaginationConfig{})
+
+	type mockfields struct {
+		user              *mock_user.MockInterface
+		passwordResetCode *mock_password_reset_code.MockInterface
+	}
+
+	mocks := mockfields{
+		user:              userMock,
+		passwordResetCode: passwordResetCodeMock,
+	}
+
+	type args struct {
+		param entity.UserParam
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		mockFunc func(mock mockfields, arg args)
+		wantErr  bool
+	}{
+		{
+			name: "failed to get user",
+			args: args{
+				param: mockParam,
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), mockParam).Return(entity.User{}, assert.AnError)
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to invalidate previous codes",
+			args: args{
+				param: mockParam,
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), mockParam).Return(mockUserResult, nil)
+				mock.passwordResetCode.EXPECT().InvalidateAll(gomock.Any(), uint(1), gomock.Any()).Return(assert.AnError)
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to create code",
+			args: args{
+				param: mockParam,
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), mockParam).Return(mockUserResult, nil)
+				mock.passwordResetCode.EXPECT().InvalidateAll(gomock.Any(), uint(1), gomock.Any()).Return(nil)
+				mock.passwordResetCode.EXPECT().Create(gomock.Any(), gomock.Any()).Return(entity.PasswordResetCode{}, assert.AnError)
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			args: args{
+				param: mockParam,
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), mockParam).Return(mockUserResult, nil)
+				mock.passwordResetCode.EXPECT().InvalidateAll(gomock.Any(), uint(1), gomock.Any()).Return(nil)
+				mock.passwordResetCode.EXPECT().Create(gomock.Any(), gomock.Any()).Return(entity.PasswordResetCode{}, nil)
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mocks, tt.args)
+			got, err := u.CreatePasswordResetCode(context.Background(), tt.args.param)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("user.CreatePasswordResetCode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				assert.Len(t, got.Code, 10)
+				assert.True(t, got.ExpiresAt.After(time.Now()))
+			}
+		})
+	}
+}
+
+func Test_user_ResetPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userMock := mock_user.NewMockInterface(ctrl)
+	refreshTokenMock := mock_refresh_token.NewMockInterface(ctrl)
+	passwordResetCodeMock := mock_password_reset_code.NewMockInterface(ctrl)
+	usedAt := time.Now().Add(-time.Minute)
+
+	mockParams := entity.ResetPasswordParam{
+		Username:    "username",
+		Code:        "ABCDEFGHJK",
+		NewPassword: "newpassword123",
+	}
+
+	mockUserResult := entity.User{
+		Model: gorm.Model{
+			ID: 1,
+		},
+		Username: "username",
+	}
+
+	resetCodeParamMock := entity.PasswordResetCodeParam{
+		UserID:   1,
+		CodeHash: auth.HashToken("ABCDEFGHJK"),
+	}
+
+	resetCodeResultMock := entity.PasswordResetCode{
+		Model: gorm.Model{
+			ID: 1,
+		},
+		UserID:    1,
+		CodeHash:  auth.HashToken("ABCDEFGHJK"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	usedResetCodeResultMock := resetCodeResultMock
+	usedResetCodeResultMock.UsedAt = &usedAt
+
+	expiredResetCodeResultMock := resetCodeResultMock
+	expiredResetCodeResultMock.ExpiresAt = time.Now().Add(-time.Hour)
+
+	u := user.Init(userMock, nil, nil, nil, refreshTokenMock, nil, passwordResetCodeMock, nil, nil, user.Config{BcryptCost: bcrypt.MinCost}, entity.PaginationConfig{})
+
+	type mockfields struct {
+		user              *mock_user.MockInterface
+		refreshToken      *mock_refresh_token.MockInterface
+		passwordResetCode *mock_password_reset_code.MockInterface
+	}
+
+	mocks := mockfields{
+		user:              userMock,
+		refreshToken:      refreshTokenMock,
+		passwordResetCode: passwordResetCodeMock,
+	}
+
+	type args struct {
+		params entity.ResetPasswordParam
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		mockFunc func(mock mockfields, arg args)
+		wantErr  bool
+	}{
+		{
+			name: "new password too weak",
+			args: args{
+				params: entity.ResetPasswordParam{
+					Username:    "username",
+					Code:        "ABCDEFGHJK",
+					NewPassword: "weak",
+				},
+			},
+			mockFunc: func(mock mockfields, arg args) {},
+			wantErr:  true,
+		},
+		{
+			name: "user not found",
+			args: args{
+				params: mockParams,
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{Username: "username"}).Return(entity.User{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: true,
+		},
+		{
+			name: "code not found",
+			args: args{
+				params: mockParams,
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{Username: "username"}).Return(mockUserResult, nil)
+				mock.passwordResetCode.EXPECT().Get(gomock.Any(), resetCodeParamMock).Return(entity.PasswordResetCode{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: true,
+		},
+		{
+			name: "code already used",
+			args: args{
+				params: mockParams,
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{Username: "username"}).Return(mockUserResult, nil)
+				mock.passwordResetCode.EXPECT().Get(gomock.Any(), resetCodeParamMock).Return(usedResetCodeResultMock, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "code expired",
+			args: args{
+				params: mockParams,
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{Username: "username"}).Return(mockUserResult, nil)
+				mock.passwordResetCode.EXPECT().Get(gomock.Any(), resetCodeParamMock).Return(expiredResetCodeResultMock, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to mark code as used",
+			args: args{
+				params: mockParams,
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{Username: "username"}).Return(mockUserResult, nil)
+				mock.passwordResetCode.EXPECT().Get(gomock.Any(), resetCodeParamMock).Return(resetCodeResultMock, nil)
+				mock.passwordResetCode.EXPECT().Redeem(gomock.Any(), uint(1), gomock.Any()).Return(false, assert.AnError)
+			},
+			wantErr: true,
+		},
+		{
+			name: "code redeemed concurrently",
+			args: args{
+				params: mockParams,
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{Username: "username"}).Return(mockUserResult, nil)
+				mock.passwordResetCode.EXPECT().Get(gomock.Any(), resetCodeParamMock).Return(resetCodeResultMock, nil)
+				mock.passwordResetCode.EXPECT().Redeem(gomock.Any(), uint(1), gomock.Any()).Return(false, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			args: args{
+				params: mockParams,
+			},
+			mockFunc: func(mock mockfields, arg args) {
+				mock.user.EXPECT().Get(gomock.Any(), entity.UserParam{Username: "username"}).Return(mockUserResult, nil)
+				mock.passwordResetCode.EXPECT().Get(gomock.Any(), resetCodeParamMock).Return(resetCodeResultMock, nil)
+				mock.passwordResetCode.EXPECT().Redeem(gomock.Any(), uint(1), gomock.Any()).Return(true, nil)
+				mock.user.EXPECT().Update(gomock.Any(), entity.UserParam{ID: 1}, gomock.Any()).Return(nil)
+				mock.refreshToken.EXPECT().RevokeAll(gomock.Any(), uint(1), gomock.Any()).Return(nil)
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mocks, tt.args)
+			err := u.ResetPassword(context.Background(), tt.args.params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("user.ResetPassword() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
 		})
 	}
 }
@@ -5,30 +5,43 @@ import (
 	umkmDom "go-clean/src/business/domain/umkm"
 	withdrawDom "go-clean/src/business/domain/withdraw"
 	"go-clean/src/business/entity"
-	"sort"
 )
 
+var withdrawSortOptions = entity.SortOptions{
+	Columns: map[string]string{
+		"id":     "id",
+		"date":   "date",
+		"amount": "amount",
+	},
+	Default:      "date",
+	DefaultOrder: entity.SortDesc,
+	TieBreaker:   "id",
+}
+
 type Interface interface {
 	Create(ctx context.Context, param entity.CreateWithdrawParam) (entity.Withdraw, error)
-	GetList(ctx context.Context, param entity.WithdrawParam) ([]entity.Withdraw, error)
+	Get(ctx context.Context, param entity.WithdrawParam) (entity.Withdraw, error)
+	GetList(ctx context.Context, param entity.WithdrawParam) ([]entity.Withdraw, entity.Pagination, error)
 	Update(ctx context.Context, param entity.WithdrawParam, inputParam entity.UpdateWithdrawParam) error
 }
 
 type withdraw struct {
-	withdraw withdrawDom.Interface
-	umkm     umkmDom.Interface
+	withdraw   withdrawDom.Interface
+	umkm       umkmDom.Interface
+	pagination entity.PaginationConfig
 }
 
-func Init(wd withdrawDom.Interface, ud umkmDom.Interface) Interface {
+func Init(wd withdrawDom.Interface, ud umkmDom.Interface, pagination entity.PaginationConfig) Interface {
 	w := &withdraw{
-		withdraw: wd,
-		umkm:     ud,
+		withdraw:   wd,
+		umkm:       ud,
+		pagination: pagination,
 	}
 	return w
 }
 
 func (w *withdraw) Create(ctx context.Context, param entity.CreateWithdrawParam) (entity.Withdraw, error) {
-	wd, err := w.withdraw.Create(entity.Withdraw{
+	wd, err := w.withdraw.Create(ctx, entity.Withdraw{
 		Date:   param.Date,
 		Amount: param.Amount,
 		UmkmID: param.UmkmID,
@@ -42,21 +55,44 @@ func (w *withdraw) Create(ctx context.Context, param entity.CreateWithdrawParam)
 	return wd, nil
 }
 
-func (w *withdraw) GetList(ctx context.Context, param entity.WithdrawParam) ([]entity.Withdraw, error) {
-	wds, err := w.withdraw.GetList(entity.WithdrawParam{
-		Date:    param.Date,
-		UmkmID:  param.UmkmID,
-		Limit:   param.Limit,
-		Offset:  (param.Page - 1) * param.Limit,
-		OrderBy: "date desc",
+func (w *withdraw) Get(ctx context.Context, param entity.WithdrawParam) (entity.Withdraw, error) {
+	wd, err := w.withdraw.Get(ctx, entity.WithdrawParam{
+		ID: param.ID,
 	})
 	if err != nil {
-		return wds, err
+		return wd, err
+	}
+
+	return wd, nil
+}
+
+func (w *withdraw) GetList(ctx context.Context, param entity.WithdrawParam) ([]entity.Withdraw, entity.Pagination, error) {
+	wds := []entity.Withdraw{}
+
+	paginationParam, err := param.PaginationParam.Paginate(w.pagination, withdrawSortOptions)
+	if err != nil {
+		return wds, entity.Pagination{}, err
+	}
+
+	listParam := entity.WithdrawParam{
+		Date:            param.Date,
+		UmkmID:          param.UmkmID,
+		PaginationParam: paginationParam,
+	}
+
+	total, err := w.withdraw.Count(ctx, listParam)
+	if err != nil {
+		return wds, entity.Pagination{}, err
 	}
 
-	umkms, err := w.umkm.GetList(entity.UmkmParam{})
+	wds, err = w.withdraw.GetList(ctx, listParam)
 	if err != nil {
-		return []entity.Withdraw{}, err
+		return wds, entity.Pagination{}, err
+	}
+
+	umkms, err := w.umkm.GetList(ctx, entity.UmkmParam{})
+	if err != nil {
+		return []entity.Withdraw{}, entity.Pagination{}, err
 	}
 
 	umkmsMap := make(map[uint]entity.Umkm)
@@ -68,22 +104,21 @@ func (w *withdraw) GetList(ctx context.Context, param entity.WithdrawParam) ([]e
 		wds[idx].UmkmName = umkmsMap[wds[idx].UmkmID].Name
 	}
 
-	sort.Slice(wds, func(i, j int) bool {
-		return wds[i].Date > wds[j].Date
-	})
+	pagination := entity.NewPagination(paginationParam, total)
+	pagination.SetNextCursor(paginationParam, withdrawSortOptions, wds)
 
-	return wds, nil
+	return wds, pagination, nil
 }
 
 func (w *withdraw) Update(ctx context.Context, param entity.WithdrawParam, inputParam entity.UpdateWithdrawParam) error {
-	wd, err := w.withdraw.Get(entity.WithdrawParam{
+	wd, err := w.withdraw.Get(ctx, entity.WithdrawParam{
 		ID: param.ID,
 	})
 	if err != nil {
 		return err
 	}
 
-	if err := w.withdraw.Update(entity.WithdrawParam{ID: wd.ID}, inputParam); err != nil {
+	if err := w.withdraw.Update(ctx, entity.WithdrawParam{ID: wd.ID}, inputParam); err != nil {
 		return err
 	}
 
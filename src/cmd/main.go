@@ -1,14 +1,23 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"go-clean/src/business/domain"
+	"go-clean/src/business/entity"
 	"go-clean/src/business/usecase"
 	"go-clean/src/handler/rest"
 	"go-clean/src/lib/auth"
 	"go-clean/src/lib/configreader"
+	"go-clean/src/lib/health"
+	"go-clean/src/lib/log"
+	"go-clean/src/lib/metrics"
 	"go-clean/src/lib/midtrans"
+	"go-clean/src/lib/ratelimit"
 	"go-clean/src/lib/sql"
+	"go-clean/src/lib/tracing"
 	"go-clean/src/utils/config"
+	"time"
 
 	_ "go-clean/docs/swagger"
 )
@@ -22,17 +31,41 @@ import (
 // @name Authorization
 
 const (
-	configFile string = "./etc/cfg/config.json"
+	defaultConfigFile string = "./etc/cfg/config.json"
+
+	tracingShutdownTimeout = 5 * time.Second
 )
 
 func main() {
+	configFile := flag.String("config", defaultConfigFile, "path of the json config file")
+	overrides := configreader.Overrides{}
+	flag.Var(overrides, "set", "override a config value, e.g. --set Gin.Port=9090, can be repeated")
+	flag.Parse()
+
+	configFileSet := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "config" {
+			configFileSet = true
+		}
+	})
+
 	cfg := config.Init()
 	configReader := configreader.Init(configreader.Options{
-		ConfigFile: configFile,
+		ConfigFile: *configFile,
+		Optional:   !configFileSet,
+		Overrides:  overrides,
 	})
 	configReader.ReadConfig(&cfg)
 
-	auth := auth.Init()
+	if err := cfg.Validate(); err != nil {
+		panic(err)
+	}
+
+	log.Init(cfg.Log)
+
+	tracer := tracing.Init(cfg.Tracing)
+
+	auth := auth.Init(cfg.Auth)
 
 	midtrans := midtrans.Init(cfg.Midtrans)
 
@@ -40,9 +73,44 @@ func main() {
 
 	d := domain.Init(db, midtrans)
 
-	uc := usecase.Init(auth, d)
+	uc := usecase.Init(auth, d, cfg.Usecase)
+
+	rateLimitStore := ratelimit.NewMemoryStore()
+	if cfg.RateLimit.Store == ratelimit.StoreSQL {
+		rateLimitStore = ratelimit.NewSQLStore(db)
+	}
+	rateLimit := ratelimit.Init(cfg.RateLimit, rateLimitStore)
 
-	r := rest.Init(cfg.Meta, configReader, uc, auth)
+	if cfg.Metrics.Enabled {
+		registerGauges(d)
+	}
+
+	healthChecker := health.Init(cfg.Health)
+	healthChecker.Register("database", sql.Ping(db))
+	healthChecker.Register("migrations", sql.CheckMigrations(db))
+	if cfg.Health.CheckGateway {
+		healthChecker.Register("midtrans", midtrans.Ping)
+	}
+
+	r := rest.Init(cfg.Meta, cfg.Gin, configReader, uc, auth, rateLimit, cfg.Metrics, cfg.Tracing, healthChecker)
 
 	r.Run()
+
+	ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+	defer cancel()
+	if err := tracer.Shutdown(ctx); err != nil {
+		log.Error(ctx, "failed to flush spans", log.Fields{"error": err})
+	}
+}
+
+func registerGauges(d *domain.Domains) {
+	metrics.RegisterGauge("pending_orders", "Orders waiting to be paid or completed.", func() (float64, error) {
+		count, err := d.Cart.CountTransactionsInByStatus(context.Background(), []string{entity.StatusUnpaid, entity.StatusPaid})
+		return float64(count), err
+	})
+
+	metrics.RegisterGauge("open_tenants", "Tenants currently open for orders.", func() (float64, error) {
+		count, err := d.Umkm.Count(context.Background(), entity.UmkmParam{Status: entity.StatusOpen})
+		return float64(count), err
+	})
 }
@@ -32,7 +32,7 @@ func (r *rest) GetDashboardWidget(ctx *gin.Context) {
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully get dashboard widget", result)
+	r.httpRespSuccess(ctx, http.StatusOK, "success.get_dashboard_widget", result)
 }
 
 // @Summary Get All Dashboard Widget
@@ -40,7 +40,7 @@ func (r *rest) GetDashboardWidget(ctx *gin.Context) {
 // @Security BearerAuth
 // @Tags Analytic
 // @Produce json
-// @Success 200 {object} entity.Response{data=entity.WidgetDashboardResult}
+// @Success 200 {object} entity.Response{data=entity.AdminWidgetDashboardResult}
 // @Failure 400 {object} entity.Response{}
 // @Failure 401 {object} entity.Response{}
 // @Failure 404 {object} entity.Response{}
@@ -53,5 +53,233 @@ func (r *rest) GetAllDashboardWidget(ctx *gin.Context) {
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully get all dashboard widget", result)
+	r.httpRespSuccess(ctx, http.StatusOK, "success.get_all_dashboard_widget", result)
+}
+
+// @Summary Get Sales Analytic
+// @Description Get Sales Time Series by UMKM ID compared with the previous period
+// @Security BearerAuth
+// @Tags Analytic
+// @Param umkm_id path integer true "umkm id"
+// @Param from query string true "from date (YYYY-MM-DD)"
+// @Param to query string true "to date (YYYY-MM-DD)"
+// @Param granularity query string false "hour, day, week or month"
+// @Produce json
+// @Success 200 {object} entity.Response{data=entity.SalesAnalyticResult}
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 404 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/umkm/{umkm_id}/analytic/sales [GET]
+func (r *rest) GetSalesAnalytic(ctx *gin.Context) {
+	var param entity.SalesAnalyticParam
+	if err := ctx.ShouldBindQuery(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := ctx.ShouldBindUri(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := r.uc.Analytic.GetSalesAnalytic(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, http.StatusOK, "success.get_sales_analytic", result)
+}
+
+// @Summary Get All Sales Analytic
+// @Description Get Sales Time Series of all UMKM or a single UMKM compared with the previous period
+// @Security BearerAuth
+// @Tags Analytic
+// @Param umkm_id query integer false "umkm id"
+// @Param from query string true "from date (YYYY-MM-DD)"
+// @Param to query string true "to date (YYYY-MM-DD)"
+// @Param granularity query string false "hour, day, week or month"
+// @Produce json
+// @Success 200 {object} entity.Response{data=entity.SalesAnalyticResult}
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 404 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/admin/analytic/sales [GET]
+func (r *rest) GetAllSalesAnalytic(ctx *gin.Context) {
+	var param entity.SalesAnalyticParam
+	if err := ctx.ShouldBindQuery(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := r.uc.Analytic.GetSalesAnalytic(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, http.StatusOK, "success.get_all_sales_analytic", result)
+}
+
+// @Summary Get Menu Performance
+// @Description Get Best-selling Menus of an UMKM compared with the previous period
+// @Security BearerAuth
+// @Tags Analytic
+// @Param umkm_id path integer true "umkm id"
+// @Param from query string true "from date (YYYY-MM-DD)"
+// @Param to query string true "to date (YYYY-MM-DD)"
+// @Param limit query int false "limit"
+// @Produce json
+// @Success 200 {object} entity.Response{data=entity.MenuPerformanceResult}
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 404 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/umkm/{umkm_id}/analytic/menu-performance [GET]
+func (r *rest) GetMenuPerformance(ctx *gin.Context) {
+	var param entity.MenuPerformanceParam
+	if err := ctx.ShouldBindQuery(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := ctx.ShouldBindUri(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := r.uc.Analytic.GetMenuPerformance(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, http.StatusOK, "success.get_menu_performance", result)
+}
+
+// @Summary Get All Menu Performance
+// @Description Get Best-selling Menus of all UMKM or a single UMKM compared with the previous period
+// @Security BearerAuth
+// @Tags Analytic
+// @Param umkm_id query integer false "umkm id"
+// @Param from query string true "from date (YYYY-MM-DD)"
+// @Param to query string true "to date (YYYY-MM-DD)"
+// @Param limit query int false "limit"
+// @Produce json
+// @Success 200 {object} entity.Response{data=entity.MenuPerformanceResult}
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 404 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/admin/analytic/menu-performance [GET]
+func (r *rest) GetAllMenuPerformance(ctx *gin.Context) {
+	var param entity.MenuPerformanceParam
+	if err := ctx.ShouldBindQuery(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := r.uc.Analytic.GetMenuPerformance(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, http.StatusOK, "success.get_all_menu_performance", result)
+}
+
+// @Summary Get Heatmap
+// @Description Get Orders and Revenue by Day of Week and Hour of an UMKM
+// @Security BearerAuth
+// @Tags Analytic
+// @Param umkm_id path integer true "umkm id"
+// @Param from query string true "from date (YYYY-MM-DD)"
+// @Param to query string true "to date (YYYY-MM-DD)"
+// @Produce json
+// @Success 200 {object} entity.Response{data=entity.HeatmapResult}
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 404 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/umkm/{umkm_id}/analytic/heatmap [GET]
+func (r *rest) GetHeatmap(ctx *gin.Context) {
+	var param entity.HeatmapParam
+	if err := ctx.ShouldBindQuery(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := ctx.ShouldBindUri(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := r.uc.Analytic.GetHeatmap(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, http.StatusOK, "success.get_heatmap", result)
+}
+
+// @Summary Get All Heatmap
+// @Description Get Orders and Revenue by Day of Week and Hour of the whole venue or a single UMKM
+// @Security BearerAuth
+// @Tags Analytic
+// @Param umkm_id query integer false "umkm id"
+// @Param from query string true "from date (YYYY-MM-DD)"
+// @Param to query string true "to date (YYYY-MM-DD)"
+// @Produce json
+// @Success 200 {object} entity.Response{data=entity.HeatmapResult}
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 404 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/admin/analytic/heatmap [GET]
+func (r *rest) GetAllHeatmap(ctx *gin.Context) {
+	var param entity.HeatmapParam
+	if err := ctx.ShouldBindQuery(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := r.uc.Analytic.GetHeatmap(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, http.StatusOK, "success.get_all_heatmap", result)
+}
+
+// @Summary Get Breakdown
+// @Description Get Payment Method, Abandoned Payment, Cancellation and Refund Breakdown
+// @Security BearerAuth
+// @Tags Analytic
+// @Param from query string true "from date (YYYY-MM-DD)"
+// @Param to query string true "to date (YYYY-MM-DD)"
+// @Produce json
+// @Success 200 {object} entity.Response{data=entity.BreakdownResult}
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 404 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/admin/analytic/breakdown [GET]
+func (r *rest) GetBreakdown(ctx *gin.Context) {
+	var param entity.BreakdownParam
+	if err := ctx.ShouldBindQuery(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := r.uc.Analytic.GetBreakdown(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, http.StatusOK, "success.get_breakdown", result)
 }
@@ -0,0 +1,203 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-clean/src/business/entity"
+	"go-clean/src/lib/log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// auditResultKey holds the data of a successful response, it's the after snapshot of created targets.
+const auditResultKey = "audit_result"
+
+// Audit records the action with the actor and a snapshot of the target before and after the handler runs.
+func (r *rest) Audit(action string, targetType string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user, err := r.auth.GetUserAuthInfo(ctx.Request.Context())
+		if err != nil {
+			r.httpRespError(ctx, http.StatusUnauthorized, err)
+			return
+		}
+
+		targetID, before := r.auditSnapshot(ctx, targetType)
+
+		ctx.Next()
+
+		param := entity.CreateAuditLogParam{
+			ActorID:    user.User.ID,
+			ActorName:  user.User.Username,
+			ActorRole:  user.User.Role,
+			UmkmID:     user.User.UmkmID,
+			Action:     action,
+			TargetType: targetType,
+			TargetID:   targetID,
+			Before:     before,
+			IP:         ctx.ClientIP(),
+			Method:     ctx.Request.Method,
+			Path:       ctx.Request.URL.Path,
+			StatusCode: ctx.Writer.Status(),
+		}
+
+		if umkmID, err := strconv.Atoi(ctx.Param("umkm_id")); err == nil {
+			param.UmkmID = uint(umkmID)
+		}
+
+		if param.StatusCode < http.StatusBadRequest {
+			if targetID != "" {
+				_, param.After = r.auditSnapshot(ctx, targetType)
+			} else if result, ok := ctx.Get(auditResultKey); ok {
+				param.TargetID = auditResultID(result)
+				param.After = result
+			}
+		}
+
+		if err := r.uc.AuditLog.Record(ctx.Request.Context(), param); err != nil {
+			log.Error(ctx.Request.Context(), "failed to record audit log", log.Fields{"error": err, "action": action})
+		}
+	}
+}
+
+// auditTargetParams are the route params holding the id of each target type.
+var auditTargetParams = map[string]string{
+	entity.AuditTargetUmkm:        "umkm_id",
+	entity.AuditTargetMenu:        "menu_id",
+	entity.AuditTargetTransaction: "transaction_id",
+	entity.AuditTargetPayment:     "order_id",
+	entity.AuditTargetUser:        "user_id",
+	entity.AuditTargetWithdraw:    "withdraw_id",
+}
+
+// auditSnapshot loads the target named by the route params, routes without one (creates) return an empty id.
+func (r *rest) auditSnapshot(ctx *gin.Context, targetType string) (string, interface{}) {
+	targetID := ctx.Param(auditTargetParams[targetType])
+	if targetID == "" {
+		return "", nil
+	}
+
+	id, _ := strconv.Atoi(targetID)
+
+	var (
+		result interface{}
+		err    error
+	)
+
+	switch targetType {
+	case entity.AuditTargetUmkm:
+		result, err = r.uc.Umkm.Get(ctx.Request.Context(), entity.UmkmParam{ID: uint(id)})
+	case entity.AuditTargetMenu:
+		result, err = r.uc.Menu.Get(ctx.Request.Context(), entity.MenuParam{ID: uint(id)})
+	case entity.AuditTargetTransaction:
+		result, err = r.uc.Transaction.GetOrderDetail(ctx.Request.Context(), entity.TransactionParam{ID: uint(id)})
+	case entity.AuditTargetPayment:
+		result, err = r.uc.MidtransTransaction.GetPaymentDetail(ctx.Request.Context(), entity.MidtransTransactionParam{OrderID: targetID})
+	case entity.AuditTargetUser:
+		result, err = r.uc.User.Get(ctx.Request.Context(), entity.UserParam{ID: uint(id)})
+	case entity.AuditTargetWithdraw:
+		result, err = r.uc.Withdraw.Get(ctx.Request.Context(), entity.WithdrawParam{ID: uint(id)})
+	}
+
+	if err != nil {
+		return targetID, nil
+	}
+
+	return targetID, result
+}
+
+// auditResultID reads the top level ID of a created target.
+func auditResultID(result interface{}) string {
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return ""
+	}
+
+	data := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return ""
+	}
+
+	for _, key := range []string{"ID", "id"} {
+		if v, ok := data[key]; ok {
+			return fmt.Sprint(v)
+		}
+	}
+
+	return ""
+}
+
+// @Summary Get Audit Log List
+// @Description Get the audit log of administrative and tenant actions
+// @Security BearerAuth
+// @Tags Audit
+// @Param actor_id query integer false "actor user id"
+// @Param action query string false "action, e.g. payment.mark_as_paid"
+// @Param target_type query string false "target type"
+// @Param target_id query string false "target id"
+// @Param umkm_id query integer false "umkm id"
+// @Param start_date query string false "start date (YYYY-MM-DD)"
+// @Param end_date query string false "end date (YYYY-MM-DD)"
+// @Param page query int false "page"
+// @Param limit query int false "limit"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort_by query string false "sort column" Enums(id, created_at)
+// @Param sort_order query string false "sort order" Enums(asc, desc)
+// @Produce json
+// @Success 200 {object} entity.Response{data=[]entity.AuditLog}
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 403 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/admin/audit-logs [GET]
+func (r *rest) GetAuditLogList(ctx *gin.Context) {
+	var param entity.AuditLogParam
+	if err := ctx.ShouldBindQuery(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	result, pagination, err := r.uc.AuditLog.GetList(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	r.httpRespSuccessPaginated(ctx, http.StatusOK, "success.get_audit_log_list", result, pagination)
+}
+
+// @Summary Download Audit Log
+// @Description Export the filtered audit log as CSV
+// @Security BearerAuth
+// @Tags Audit
+// @Param actor_id query integer false "actor user id"
+// @Param action query string false "action, e.g. payment.mark_as_paid"
+// @Param target_type query string false "target type"
+// @Param target_id query string false "target id"
+// @Param umkm_id query integer false "umkm id"
+// @Param start_date query string false "start date (YYYY-MM-DD)"
+// @Param end_date query string false "end date (YYYY-MM-DD)"
+// @Produce text/csv
+// @Success 200 {file} file
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 403 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/admin/audit-logs/download [GET]
+func (r *rest) DownloadAuditLog(ctx *gin.Context) {
+	var param entity.AuditLogParam
+	if err := ctx.ShouldBindQuery(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	data, filename, err := r.uc.AuditLog.GenerateCSV(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.csv", filename))
+	ctx.Data(http.StatusOK, "text/csv", data)
+}
@@ -0,0 +1,52 @@
+package rest
+
+import (
+	"go-clean/src/business/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func Test_auditResultID(t *testing.T) {
+	staff := entity.User{
+		Model: gorm.Model{
+			ID: 7,
+		},
+		Username: "kitchen",
+	}
+
+	tests := []struct {
+		name   string
+		result interface{}
+		want   string
+	}{
+		{
+			name:   "created user",
+			result: staff,
+			want:   "7",
+		},
+		{
+			name:   "lower case id",
+			result: map[string]interface{}{"id": "ORDER-1"},
+			want:   "ORDER-1",
+		},
+		{
+			name: "nested target has no top level id",
+			result: entity.StaffInvitationResult{
+				User: staff,
+			},
+			want: "",
+		},
+		{
+			name:   "nil result",
+			result: nil,
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, auditResultID(tt.result))
+		})
+	}
+}
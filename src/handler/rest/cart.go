@@ -17,6 +17,7 @@ import (
 // @Failure 400 {object} entity.Response{}
 // @Failure 401 {object} entity.Response{}
 // @Failure 404 {object} entity.Response{}
+// @Failure 429 {object} entity.Response{}
 // @Failure 500 {object} entity.Response{}
 // @Router /api/v1/cart/create [POST]
 func (r *rest) AddMenuToCart(ctx *gin.Context) {
@@ -32,7 +33,7 @@ func (r *rest) AddMenuToCart(ctx *gin.Context) {
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully add item to cart", cart)
+	r.httpRespSuccess(ctx, http.StatusOK, "success.add_item_to_cart", cart)
 }
 
 // @Summary Decrease Item
@@ -60,7 +61,7 @@ func (r *rest) DecreaseItem(ctx *gin.Context) {
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully decrease item to cart", nil)
+	r.httpRespSuccess(ctx, http.StatusOK, "success.decrease_item_in_cart", nil)
 }
 
 // @Summary Get List Cart
@@ -81,7 +82,7 @@ func (r *rest) GetListCartByUser(ctx *gin.Context) {
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully get list items on cart", carts)
+	r.httpRespSuccess(ctx, http.StatusOK, "success.get_cart_item_list", carts)
 }
 
 // @Summary Delete Cart
@@ -109,7 +110,7 @@ func (r *rest) DeleteItemCart(ctx *gin.Context) {
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully delete an item on cart", nil)
+	r.httpRespSuccess(ctx, http.StatusOK, "success.delete_item_from_cart", nil)
 }
 
 // @Summary Clear Cart
@@ -130,5 +131,5 @@ func (r *rest) ClearCart(ctx *gin.Context) {
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully delete an item on cart", nil)
+	r.httpRespSuccess(ctx, http.StatusOK, "success.delete_item_from_cart", nil)
 }
@@ -0,0 +1,46 @@
+package rest
+
+import (
+	"go-clean/src/lib/health"
+	"go-clean/src/lib/log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Liveness probe
+// @Description Reports that the process is up and serving requests
+// @Tags Health
+// @Produce json
+// @Success 200 {object} health.Report
+// @Router /healthz [GET]
+func (r *rest) Healthz(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, health.Report{
+		Status: health.StatusOK,
+		Checks: map[string]health.CheckResult{},
+	})
+}
+
+// @Summary Readiness probe
+// @Description Checks the database, its migrations, the asset directories and optionally the payment gateway, fails while the server is draining
+// @Tags Health
+// @Produce json
+// @Success 200 {object} health.Report
+// @Failure 503 {object} health.Report
+// @Router /readyz [GET]
+func (r *rest) Readyz(ctx *gin.Context) {
+	report := r.health.Ready(ctx.Request.Context())
+
+	for name, result := range report.Checks {
+		if result.Error != nil {
+			log.Warn(ctx.Request.Context(), "readiness check failed", log.Fields{"check": name, "error": result.Error})
+		}
+	}
+
+	code := http.StatusOK
+	if report.Status != health.StatusOK {
+		code = http.StatusServiceUnavailable
+	}
+
+	ctx.JSON(code, report)
+}
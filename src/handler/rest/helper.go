@@ -1,123 +1,402 @@
 package rest
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"go-clean/src/business/entity"
-	"log"
+	"go-clean/src/lib/apperr"
+	"go-clean/src/lib/auth"
+	"go-clean/src/lib/i18n"
+	"go-clean/src/lib/log"
+	"go-clean/src/lib/metrics"
+	"go-clean/src/lib/ratelimit"
+	"go-clean/src/lib/tracing"
+	"io"
+	"math"
 	"net/http"
-	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt"
+	"github.com/go-playground/validator/v10"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"gorm.io/gorm"
 )
 
+const (
+	requestIDHeader    = "X-Request-ID"
+	traceIDHeader      = "X-Trace-ID"
+	maxRequestIDLength = 64
+)
+
+// httpRespSuccess answers with the translation of the message key in the request's language.
 func (r *rest) httpRespSuccess(ctx *gin.Context, code int, message string, data interface{}) {
 	resp := entity.Response{
 		Meta: entity.Meta{
-			Message: message,
+			Message: i18n.T(ctx.Request.Context(), message),
 			Code:    code,
 			IsError: false,
 		},
 		Data: data,
 	}
+	ctx.Set(auditResultKey, data)
 	ctx.JSON(code, resp)
 }
 
-func (r *rest) httpRespError(ctx *gin.Context, code int, err error) {
+// httpRespSuccessPaginated answers like httpRespSuccess with the page of a list.
+func (r *rest) httpRespSuccessPaginated(ctx *gin.Context, code int, message string, data interface{}, pagination entity.Pagination) {
 	resp := entity.Response{
 		Meta: entity.Meta{
-			Message: err.Error(),
+			Message: i18n.T(ctx.Request.Context(), message),
 			Code:    code,
-			IsError: true,
+			IsError: false,
+		},
+		Data:       data,
+		Pagination: &pagination,
+	}
+	ctx.Set(auditResultKey, data)
+	ctx.JSON(code, resp)
+}
+
+// httpRespError answers with the status and translated safe message of a typed error. Binding and validation errors
+// keep the 4xx status picked by the handler and their message, any other error is internal. The error itself is logged
+// either way.
+func (r *rest) httpRespError(ctx *gin.Context, code int, err error) {
+	code, errorCode, message := errorResponse(ctx.Request.Context(), code, err)
+	resp := entity.Response{
+		Meta: entity.Meta{
+			Message:   message,
+			Code:      code,
+			IsError:   true,
+			ErrorCode: errorCode,
 		},
 		Data: nil,
 	}
-	log.Default().Println(err)
+
+	fields := log.Fields{
+		"error":      err,
+		"error_code": errorCode,
+		"status":     code,
+		"method":     ctx.Request.Method,
+		"route":      ctx.FullPath(),
+	}
+	if code >= http.StatusInternalServerError {
+		log.Error(ctx.Request.Context(), "request failed", fields)
+	} else {
+		log.Warn(ctx.Request.Context(), "request rejected", fields)
+	}
+
 	ctx.AbortWithStatusJSON(code, resp)
 }
 
+func errorResponse(ctx context.Context, code int, err error) (int, string, string) {
+	var authErr *auth.Error
+	if errors.As(err, &authErr) {
+		return http.StatusUnauthorized, authErr.Code, errorMessage(ctx, authErr.Code, authErr.Message)
+	}
+
+	var appErr *apperr.Error
+	if !errors.As(err, &appErr) && code < http.StatusInternalServerError && isBindingError(err) {
+		return code, statusErrorCode(code), err.Error()
+	}
+
+	appErr = apperr.From(err)
+	return appErr.Status(), appErr.Code, errorMessage(ctx, appErr.Code, appErr.Message, appErr.Args...)
+}
+
+// isBindingError reports whether err comes from decoding or validating the request, its message only describes the
+// client's input.
+func isBindingError(err error) bool {
+	var (
+		validationErrs validator.ValidationErrors
+		syntaxErr      *json.SyntaxError
+		unmarshalErr   *json.UnmarshalTypeError
+		numErr         *strconv.NumError
+		timeErr        *time.ParseError
+	)
+
+	return errors.As(err, &validationErrs) ||
+		errors.As(err, &syntaxErr) ||
+		errors.As(err, &unmarshalErr) ||
+		errors.As(err, &numErr) ||
+		errors.As(err, &timeErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, http.ErrMissingFile) ||
+		errors.Is(err, http.ErrNotMultipart) ||
+		errors.Is(err, http.ErrMissingBoundary)
+}
+
+// errorMessage translates the message of an error code, the untranslated message is kept when there is none.
+func errorMessage(ctx context.Context, errorCode string, message string, args ...interface{}) string {
+	if translated, ok := i18n.Lookup(ctx, "error."+errorCode, args...); ok {
+		return translated
+	}
+
+	return message
+}
+
+// statusErrorCode names the status for untyped errors, e.g. bad_request.
+func statusErrorCode(code int) string {
+	return strings.ToLower(strings.ReplaceAll(http.StatusText(code), " ", "_"))
+}
+
+// Language translates the messages of the request to the best match of its Accept-Language.
+func (r *rest) Language(ctx *gin.Context) {
+	lang := i18n.ParseAcceptLanguage(ctx.GetHeader("Accept-Language"))
+
+	ctx.Header("Content-Language", lang)
+	ctx.Request = ctx.Request.WithContext(i18n.WithLanguage(ctx.Request.Context(), lang))
+
+	ctx.Next()
+}
+
+// RequestID tags the request with the caller's X-Request-ID or a new one, every log entry of the request carries it.
+func (r *rest) RequestID(ctx *gin.Context) {
+	requestID := ctx.GetHeader(requestIDHeader)
+	if !isValidRequestID(requestID) {
+		requestID, _ = gonanoid.New()
+	}
+
+	ctx.Header(requestIDHeader, requestID)
+	ctx.Request = ctx.Request.WithContext(log.WithFields(ctx.Request.Context(), log.Fields{
+		"request_id": requestID,
+	}))
+
+	ctx.Next()
+}
+
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+
+	for _, c := range requestID {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// TraceID returns the trace id of the request and attaches it to its log entries.
+func (r *rest) TraceID(ctx *gin.Context) {
+	if traceID := tracing.TraceID(ctx.Request.Context()); traceID != "" {
+		ctx.Header(traceIDHeader, traceID)
+		ctx.Request = ctx.Request.WithContext(log.WithFields(ctx.Request.Context(), log.Fields{
+			"trace_id": traceID,
+		}))
+	}
+
+	ctx.Next()
+}
+
+// Metrics counts the handled requests and their latency per route, unmatched paths share one label.
+func (r *rest) Metrics(ctx *gin.Context) {
+	start := time.Now()
+
+	ctx.Next()
+
+	route := ctx.FullPath()
+	if route == "" {
+		route = "unmatched"
+	}
+	status := strconv.Itoa(ctx.Writer.Status())
+
+	metrics.HTTPRequests.WithLabelValues(ctx.Request.Method, route, status).Inc()
+	metrics.HTTPRequestDuration.WithLabelValues(ctx.Request.Method, route, status).Observe(time.Since(start).Seconds())
+}
+
+// AccessLog writes one entry per request once it's handled.
+func (r *rest) AccessLog(ctx *gin.Context) {
+	start := time.Now()
+
+	ctx.Next()
+
+	fields := log.Fields{
+		"method":  ctx.Request.Method,
+		"path":    ctx.Request.URL.Path,
+		"route":   ctx.FullPath(),
+		"status":  ctx.Writer.Status(),
+		"size":    ctx.Writer.Size(),
+		"ip":      ctx.ClientIP(),
+		"latency": time.Since(start),
+	}
+
+	// probes hit the service every few seconds, only their failures are worth an info entry
+	if isProbeRoute(ctx.FullPath()) && ctx.Writer.Status() == http.StatusOK {
+		log.Debug(ctx.Request.Context(), "request handled", fields)
+		return
+	}
+
+	log.Info(ctx.Request.Context(), "request handled", fields)
+}
+
+func isProbeRoute(route string) bool {
+	return route == healthzRoute || route == readyzRoute
+}
+
 func (r *rest) VerifyUser(ctx *gin.Context) {
 	authHeader := ctx.GetHeader("Authorization")
 	if authHeader == "" {
-		r.httpRespError(ctx, http.StatusUnauthorized, errors.New("empty token"))
+		r.httpRespError(ctx, http.StatusUnauthorized, auth.ErrTokenMissing)
 		return
 	}
 
 	var tokenString string
 	_, err := fmt.Sscanf(authHeader, "Bearer %v", &tokenString)
 	if err != nil {
-		r.httpRespError(ctx, http.StatusUnauthorized, errors.New("invalid token"))
+		r.httpRespError(ctx, http.StatusUnauthorized, auth.ErrTokenMalformed)
 		return
 	}
 
-	token, err := r.ValidateToken(tokenString)
+	claims, err := r.auth.ParseToken(tokenString)
 	if err != nil {
 		r.httpRespError(ctx, http.StatusUnauthorized, err)
 		return
 	}
 
-	claim, ok := token.Claims.(jwt.MapClaims)
-	if !ok || !token.Valid {
-		r.httpRespError(ctx, http.StatusUnauthorized, errors.New("failed to claim token"))
+	if err := r.uc.User.ValidateTokenID(ctx.Request.Context(), claims.Id); err != nil {
+		r.httpRespError(ctx, http.StatusUnauthorized, err)
 		return
 	}
 
 	c := ctx.Request.Context()
 	user := entity.User{}
 
-	if claim["is_guest"].(bool) {
+	if claims.IsGuest {
 		user = entity.User{
-			GuestID: claim["guest_id"].(string),
+			GuestID: claims.GuestID,
 		}
 	} else {
-		user, err = r.uc.User.Get(entity.UserParam{
-			ID: uint(claim["id"].(float64)),
+		user, err = r.uc.User.Get(ctx.Request.Context(), entity.UserParam{
+			ID: claims.UserID,
 		})
 		if err != nil {
-			r.httpRespError(ctx, http.StatusUnauthorized, errors.New("error while getting user"))
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = auth.ErrTokenInvalid
+			}
+			r.httpRespError(ctx, http.StatusUnauthorized, err)
 			return
 		}
+
+		if user.IsDisabled {
+			r.httpRespError(ctx, http.StatusUnauthorized, auth.ErrAccountDisabled)
+			return
+		}
+
+		c = log.WithFields(c, log.Fields{"user_id": user.ID})
 	}
 
-	c = r.auth.SetUserAuthInfo(c, user.ConvertToAuthUser(), tokenString)
+	c = r.auth.SetUserAuthInfo(c, user.ConvertToAuthUser(), auth.Token{
+		ID:        claims.Id,
+		Value:     tokenString,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	})
 	ctx.Request = ctx.Request.WithContext(c)
 
 	ctx.Next()
 }
 
-func (r *rest) VerifyAdmin(ctx *gin.Context) {
-	user, err := r.auth.GetUserAuthInfo(ctx.Request.Context())
-	if err != nil {
-		r.httpRespError(ctx, http.StatusUnauthorized, err)
-		return
+// getGuestID returns the guest id of an optional guest token, any other, invalid or revoked token is ignored.
+func (r *rest) getGuestID(ctx *gin.Context) string {
+	var tokenString string
+	if _, err := fmt.Sscanf(ctx.GetHeader("Authorization"), "Bearer %v", &tokenString); err != nil {
+		return ""
 	}
 
-	if !user.User.IsAdmin {
-		r.httpRespError(ctx, http.StatusUnauthorized, errors.New("dont have access"))
-		return
+	claims, err := r.auth.ParseToken(tokenString)
+	if err != nil || !claims.IsGuest {
+		return ""
 	}
 
-	ctx.Next()
+	// a logged out guest token must not claim the guest's orders
+	if err := r.uc.User.ValidateTokenID(ctx.Request.Context(), claims.Id); err != nil {
+		return ""
+	}
+
+	return claims.GuestID
 }
 
-func (r *rest) VerifyUmkm(ctx *gin.Context) {
-	user, err := r.auth.GetUserAuthInfo(ctx.Request.Context())
-	if err != nil {
-		r.httpRespError(ctx, http.StatusUnauthorized, err)
-		return
-	}
+// RateLimit throttles the route with the named policy, routes whose policy isn't configured are not limited.
+func (r *rest) RateLimit(policy string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		p, ok := r.rateLimit.Policy(policy)
+		if !ok {
+			ctx.Next()
+			return
+		}
 
-	umkmIDp := ctx.Param("umkm_id")
-	umkmID, _ := strconv.Atoi(umkmIDp)
+		key := "ip:" + ctx.ClientIP()
+		if p.KeyBy == ratelimit.KeyByUser {
+			key = r.rateLimitKey(ctx)
+		}
 
-	if err := r.uc.Umkm.ValidateUmkm(ctx, uint(umkmID), user); err != nil {
-		r.httpRespError(ctx, http.StatusUnauthorized, err)
-		return
+		res, err := r.rateLimit.Allow(ctx.Request.Context(), policy, key)
+		if err != nil {
+			// a broken store must not take the api down with it
+			log.Error(ctx.Request.Context(), "failed to check rate limit", log.Fields{"error": err, "policy": policy})
+			ctx.Next()
+			return
+		}
+
+		ctx.Header("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+		ctx.Header("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+
+		if !res.Allowed {
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(res.RetryAfter.Seconds()))))
+			r.httpRespError(ctx, http.StatusTooManyRequests, apperr.TooManyRequests("rate_limited", "terlalu banyak permintaan, coba lagi nanti"))
+			return
+		}
+
+		ctx.Next()
 	}
+}
 
-	ctx.Next()
+// rateLimitKey identifies the caller by the user or guest of a valid token, and by ip otherwise.
+func (r *rest) rateLimitKey(ctx *gin.Context) string {
+	var tokenString string
+	if _, err := fmt.Sscanf(ctx.GetHeader("Authorization"), "Bearer %v", &tokenString); err == nil {
+		if claims, err := r.auth.ParseToken(tokenString); err == nil {
+			if claims.IsGuest {
+				return "guest:" + claims.GuestID
+			}
+			return fmt.Sprintf("user:%d", claims.UserID)
+		}
+	}
+
+	return "ip:" + ctx.ClientIP()
+}
+
+// Authorize requires the permission, on routes with an umkm_id it also requires tenant roles to belong to that umkm.
+func (r *rest) Authorize(permission auth.Permission) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user, err := r.auth.GetUserAuthInfo(ctx.Request.Context())
+		if err != nil {
+			r.httpRespError(ctx, http.StatusUnauthorized, err)
+			return
+		}
+
+		if !user.User.HasPermission(permission) {
+			r.httpRespError(ctx, http.StatusForbidden, apperr.Forbidden("permission_denied", "tidak memiliki akses"))
+			return
+		}
+
+		if umkmIDp, ok := ctx.Params.Get("umkm_id"); ok {
+			umkmID, _ := strconv.Atoi(umkmIDp)
+
+			if err := r.uc.Umkm.ValidateUmkm(ctx.Request.Context(), uint(umkmID), user); err != nil {
+				r.httpRespError(ctx, http.StatusForbidden, err)
+				return
+			}
+		}
+
+		ctx.Next()
+	}
 }
 
 func (r *rest) VerifyMenu(ctx *gin.Context) {
@@ -130,7 +409,7 @@ func (r *rest) VerifyMenu(ctx *gin.Context) {
 	menuIDp := ctx.Param("menu_id")
 	menuID, _ := strconv.Atoi(menuIDp)
 
-	if err := r.uc.Menu.ValidateMenu(ctx, uint(menuID), user); err != nil {
+	if err := r.uc.Menu.ValidateMenu(ctx.Request.Context(), uint(menuID), user); err != nil {
 		r.httpRespError(ctx, http.StatusUnauthorized, err)
 		return
 	}
@@ -151,25 +430,10 @@ func (r *rest) VerifyCart(ctx *gin.Context) {
 		return
 	}
 
-	if err := r.uc.Cart.ValidateCart(ctx, selectParam.ID, user.User.GuestID); err != nil {
+	if err := r.uc.Cart.ValidateCart(ctx.Request.Context(), selectParam.ID, user.User.GuestID); err != nil {
 		r.httpRespError(ctx, http.StatusUnauthorized, err)
 		return
 	}
 
 	ctx.Next()
 }
-
-func (r *rest) ValidateToken(encodedToken string) (*jwt.Token, error) {
-	token, err := jwt.Parse(encodedToken, func(t *jwt.Token) (interface{}, error) {
-		_, ok := t.Method.(*jwt.SigningMethodHMAC)
-		if !ok {
-			return nil, errors.New("token invalid")
-		}
-		return []byte(os.Getenv("JWT_KEY")), nil
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	return token, nil
-}
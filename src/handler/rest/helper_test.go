@@ -0,0 +1,125 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-clean/src/lib/apperr"
+	"go-clean/src/lib/auth"
+	"go-clean/src/lib/i18n"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func Test_errorResponse(t *testing.T) {
+	validationErr := binding.Validator.ValidateStruct(struct {
+		Name string `binding:"required"`
+	}{})
+	syntaxErr := json.Unmarshal([]byte("{"), &struct{}{})
+	_, numErr := strconv.ParseUint("abc", 10, 64)
+
+	type want struct {
+		code      int
+		errorCode string
+		message   string
+	}
+	tests := []struct {
+		name string
+		ctx  context.Context
+		code int
+		err  error
+		want want
+	}{
+		{
+			name: "auth error",
+			ctx:  context.Background(),
+			code: http.StatusForbidden,
+			err:  auth.ErrTokenExpired,
+			want: want{code: http.StatusUnauthorized, errorCode: "token_expired", message: "token sudah kedaluwarsa"},
+		},
+		{
+			name: "typed error keeps its status",
+			ctx:  context.Background(),
+			code: http.StatusInternalServerError,
+			err:  fmt.Errorf("wrapped: %w", apperr.Conflict("cart_empty", "keranjang kosong")),
+			want: want{code: http.StatusConflict, errorCode: "cart_empty", message: "keranjang kosong"},
+		},
+		{
+			name: "typed error is translated",
+			ctx:  i18n.WithLanguage(context.Background(), i18n.LangEN),
+			code: http.StatusBadRequest,
+			err:  apperr.ErrNotFound,
+			want: want{code: http.StatusNotFound, errorCode: "not_found", message: "data not found"},
+		},
+		{
+			name: "missing record",
+			ctx:  context.Background(),
+			code: http.StatusInternalServerError,
+			err:  gorm.ErrRecordNotFound,
+			want: want{code: http.StatusNotFound, errorCode: "not_found", message: "data tidak ditemukan"},
+		},
+		{
+			name: "validation error",
+			ctx:  context.Background(),
+			code: http.StatusBadRequest,
+			err:  validationErr,
+			want: want{code: http.StatusBadRequest, errorCode: "bad_request", message: validationErr.Error()},
+		},
+		{
+			name: "json syntax error",
+			ctx:  context.Background(),
+			code: http.StatusBadRequest,
+			err:  syntaxErr,
+			want: want{code: http.StatusBadRequest, errorCode: "bad_request", message: syntaxErr.Error()},
+		},
+		{
+			name: "invalid number",
+			ctx:  context.Background(),
+			code: http.StatusBadRequest,
+			err:  numErr,
+			want: want{code: http.StatusBadRequest, errorCode: "bad_request", message: numErr.Error()},
+		},
+		{
+			name: "missing file",
+			ctx:  context.Background(),
+			code: http.StatusBadRequest,
+			err:  http.ErrMissingFile,
+			want: want{code: http.StatusBadRequest, errorCode: "bad_request", message: http.ErrMissingFile.Error()},
+		},
+		{
+			name: "binding error with a 5xx status is internal",
+			ctx:  context.Background(),
+			code: http.StatusInternalServerError,
+			err:  syntaxErr,
+			want: want{code: http.StatusInternalServerError, errorCode: "internal_error", message: "terjadi kesalahan pada server, coba lagi nanti"},
+		},
+		{
+			name: "untyped error with a 4xx status is internal",
+			ctx:  context.Background(),
+			code: http.StatusBadRequest,
+			err:  errors.New("dial tcp 10.0.0.1:3306: connection refused"),
+			want: want{code: http.StatusInternalServerError, errorCode: "internal_error", message: "terjadi kesalahan pada server, coba lagi nanti"},
+		},
+		{
+			name: "untyped error with a 401 status is internal",
+			ctx:  i18n.WithLanguage(context.Background(), i18n.LangEN),
+			code: http.StatusUnauthorized,
+			err:  errors.New("failed to get user auth"),
+			want: want{code: http.StatusInternalServerError, errorCode: "internal_error", message: "something went wrong, please try again later"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, errorCode, message := errorResponse(tt.ctx, tt.code, tt.err)
+			assert.Equal(t, tt.want.code, code)
+			assert.Equal(t, tt.want.errorCode, errorCode)
+			assert.Equal(t, tt.want.message, message)
+		})
+	}
+}
@@ -37,13 +37,13 @@ func (r *rest) CreateMenu(ctx *gin.Context) {
 		return
 	}
 
-	menu, err := r.uc.Menu.Create(menuInput, menuParam)
+	menu, err := r.uc.Menu.Create(ctx.Request.Context(), menuInput, menuParam)
 	if err != nil {
 		r.httpRespError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusCreated, "successfully created new menu", menu)
+	r.httpRespSuccess(ctx, http.StatusCreated, "success.create_menu", menu)
 }
 
 // @Summary Get Menu
@@ -65,13 +65,13 @@ func (r *rest) GetMenuByID(ctx *gin.Context) {
 		return
 	}
 
-	menu, err := r.uc.Menu.Get(menuParam)
+	menu, err := r.uc.Menu.Get(ctx.Request.Context(), menuParam)
 	if err != nil {
 		r.httpRespError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully get menu", menu)
+	r.httpRespSuccess(ctx, http.StatusOK, "success.get_menu", menu)
 }
 
 // @Summary Get Menu List
@@ -81,6 +81,11 @@ func (r *rest) GetMenuByID(ctx *gin.Context) {
 // @Produce json
 // @Param umkm_id query integer false "umkm id"
 // @Param name query string false "name"
+// @Param page query int false "page"
+// @Param limit query int false "limit"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort_by query string false "sort column" Enums(id, name, price, created_at)
+// @Param sort_order query string false "sort order" Enums(asc, desc)
 // @Success 200 {object} entity.Response{data=[]entity.Menu{}}
 // @Failure 400 {object} entity.Response{}
 // @Failure 401 {object} entity.Response{}
@@ -94,13 +99,13 @@ func (r *rest) GetMenuList(ctx *gin.Context) {
 		return
 	}
 
-	menus, err := r.uc.Menu.GetAll(menuParam)
+	menus, pagination, err := r.uc.Menu.GetAll(ctx.Request.Context(), menuParam)
 	if err != nil {
 		r.httpRespError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully get menu list", menus)
+	r.httpRespSuccessPaginated(ctx, http.StatusOK, "success.get_menu_list", menus, pagination)
 }
 
 // @Summary Update Menu
@@ -129,13 +134,13 @@ func (r *rest) UpdateMenu(ctx *gin.Context) {
 		return
 	}
 
-	err := r.uc.Menu.Update(selectParam, updateParam)
+	err := r.uc.Menu.Update(ctx.Request.Context(), selectParam, updateParam)
 	if err != nil {
 		r.httpRespError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully update menu", nil)
+	r.httpRespSuccess(ctx, http.StatusOK, "success.update_menu", nil)
 }
 
 // @Summary Delete Menu
@@ -157,13 +162,13 @@ func (r *rest) DeleteMenu(ctx *gin.Context) {
 		return
 	}
 
-	err := r.uc.Menu.Delete(selectParam)
+	err := r.uc.Menu.Delete(ctx.Request.Context(), selectParam)
 	if err != nil {
 		r.httpRespError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully delete menu", nil)
+	r.httpRespSuccess(ctx, http.StatusOK, "success.delete_menu", nil)
 }
 
 // @Summary Upload Menu Image
@@ -193,7 +198,7 @@ func (r *rest) UploadImageMenu(ctx *gin.Context) {
 		return
 	}
 
-	path := fmt.Sprintf("public/assets/menu/%d-%s-%d", selectParam.ID, file.Filename, time.Now().Unix())
+	path := fmt.Sprintf("%s/%d-%s-%d", menuAssetDir, selectParam.ID, file.Filename, time.Now().Unix())
 
 	if err := ctx.SaveUploadedFile(file, path); err != nil {
 		r.httpRespError(ctx, http.StatusBadRequest, err)
@@ -207,5 +212,5 @@ func (r *rest) UploadImageMenu(ctx *gin.Context) {
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully update menu's image", nil)
+	r.httpRespSuccess(ctx, http.StatusOK, "success.update_menu_image", nil)
 }
@@ -27,13 +27,13 @@ func (r *rest) GetPaymentDetail(ctx *gin.Context) {
 		return
 	}
 
-	result, err := r.uc.MidtransTransaction.GetPaymentDetail(param)
+	result, err := r.uc.MidtransTransaction.GetPaymentDetail(ctx.Request.Context(), param)
 	if err != nil {
 		r.httpRespError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully get payment detail", result)
+	r.httpRespSuccess(ctx, http.StatusOK, "success.get_payment_detail", result)
 }
 
 func (r *rest) HandleNotification(ctx *gin.Context) {
@@ -44,12 +44,12 @@ func (r *rest) HandleNotification(ctx *gin.Context) {
 		return
 	}
 
-	if err := r.uc.MidtransTransaction.HandleNotification(notifPayload); err != nil {
+	if err := r.uc.MidtransTransaction.HandleNotification(ctx.Request.Context(), notifPayload); err != nil {
 		r.httpRespError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully handle transaction", nil)
+	r.httpRespSuccess(ctx, http.StatusOK, "success.handle_transaction", nil)
 }
 
 // @Summary Mark As Paid
@@ -71,11 +71,11 @@ func (r *rest) MarkAsPaid(ctx *gin.Context) {
 		return
 	}
 
-	err := r.uc.MidtransTransaction.MarkAsPaid(param)
+	err := r.uc.MidtransTransaction.MarkAsPaid(ctx.Request.Context(), param)
 	if err != nil {
 		r.httpRespError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully mark as paid", nil)
+	r.httpRespSuccess(ctx, http.StatusOK, "success.mark_as_paid", nil)
 }
@@ -4,11 +4,16 @@ import (
 	"context"
 	"fmt"
 	"go-clean/docs/swagger"
+	"go-clean/src/business/entity"
 	"go-clean/src/business/usecase"
 	"go-clean/src/lib/auth"
 	"go-clean/src/lib/configreader"
+	"go-clean/src/lib/health"
+	"go-clean/src/lib/log"
+	"go-clean/src/lib/metrics"
+	"go-clean/src/lib/ratelimit"
+	"go-clean/src/lib/tracing"
 	"go-clean/src/utils/config"
-	"log"
 	"net/http"
 	"os"
 	"os/signal"
@@ -20,98 +25,211 @@ import (
 	"github.com/gin-gonic/gin"
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 var once = &sync.Once{}
 
+// rate limit policies, each is configured under RateLimit.Policies by name
+const (
+	rateLimitDefault  = "default"
+	rateLimitAuth     = "auth"
+	rateLimitGuest    = "guest"
+	rateLimitCart     = "cart"
+	rateLimitCheckout = "checkout"
+	rateLimitWebhook  = "webhook"
+)
+
 type REST interface {
 	Run()
 }
 
+const (
+	defaultPort            = "8080"
+	defaultShutdownTimeout = 5 * time.Second
+
+	corsModeAllowAll  = "allowall"
+	corsModeAllowList = "allowlist"
+
+	healthzRoute = "/healthz"
+	readyzRoute  = "/readyz"
+
+	umkmAssetDir = "public/assets/umkm"
+	menuAssetDir = "public/assets/menu"
+)
+
 type rest struct {
 	http         *gin.Engine
 	conf         config.ApplicationMeta
+	cfg          config.GinConfig
 	configreader configreader.Interface
 	uc           *usecase.Usecase
 	auth         auth.Interface
+	rateLimit    ratelimit.Interface
+	metrics      metrics.Config
+	tracing      tracing.Config
+	health       health.Interface
 }
 
-func Init(conf config.ApplicationMeta, confReader configreader.Interface, uc *usecase.Usecase, auth auth.Interface) REST {
+func Init(conf config.ApplicationMeta, cfg config.GinConfig, confReader configreader.Interface, uc *usecase.Usecase, auth auth.Interface, rateLimit ratelimit.Interface, metricsCfg metrics.Config, tracingCfg tracing.Config, healthChecker health.Interface) REST {
 	r := &rest{}
 	once.Do(func() {
-		httpServ := gin.Default()
+		if cfg.Mode != "" {
+			gin.SetMode(cfg.Mode)
+		}
+
+		httpServ := gin.New()
+		if err := httpServ.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+			panic(err)
+		}
 
 		r = &rest{
 			conf:         conf,
+			cfg:          cfg,
 			configreader: confReader,
 			http:         httpServ,
 			uc:           uc,
 			auth:         auth,
+			rateLimit:    rateLimit,
+			metrics:      metricsCfg,
+			tracing:      tracingCfg,
+			health:       healthChecker,
 		}
 
-		r.http.Use(cors.New(cors.Config{
-			AllowAllOrigins: true,
-			AllowHeaders:    []string{"*"},
-			AllowMethods: []string{
-				http.MethodHead,
-				http.MethodGet,
-				http.MethodPost,
-				http.MethodPut,
-				http.MethodPatch,
-				http.MethodDelete,
-			},
-		}))
+		r.health.Register("assets", health.WritableDirs(umkmAssetDir, menuAssetDir))
+
+		r.http.Use(r.RequestID, r.Language)
+
+		if tracingCfg.Enabled {
+			r.http.Use(otelgin.Middleware(tracingCfg.GetServiceName()), r.TraceID)
+		}
+
+		r.http.Use(r.AccessLog)
+
+		if metricsCfg.Enabled {
+			r.http.Use(r.Metrics)
+		}
+
+		r.http.Use(cors.New(r.corsConfig()))
 
 		// Set Recovery
 		r.http.Use(gin.Recovery())
 
-		r.http.Use(gin.Logger())
-
 		r.Register()
 	})
 
 	return r
 }
 
+func (r *rest) corsConfig() cors.Config {
+	corsCfg := cors.Config{
+		AllowHeaders: []string{"*"},
+		AllowMethods: []string{
+			http.MethodHead,
+			http.MethodGet,
+			http.MethodPost,
+			http.MethodPut,
+			http.MethodPatch,
+			http.MethodDelete,
+		},
+		ExposeHeaders: []string{"Content-Disposition", "Content-Language", "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining"},
+		MaxAge:        r.cfg.CORS.MaxAge,
+	}
+
+	switch r.cfg.CORS.Mode {
+	case "", corsModeAllowAll:
+		corsCfg.AllowAllOrigins = true
+	case corsModeAllowList:
+		if len(r.cfg.CORS.AllowOrigins) == 0 {
+			panic("cors allowlist mode needs at least one origin in AllowOrigins")
+		}
+		corsCfg.AllowOrigins = r.cfg.CORS.AllowOrigins
+		corsCfg.AllowCredentials = r.cfg.CORS.AllowCredentials
+	default:
+		panic(fmt.Sprintf("unknown cors mode %q", r.cfg.CORS.Mode))
+	}
+
+	return corsCfg
+}
+
 func (r *rest) Run() {
-	port := ":8080"
+	port := r.cfg.Port
+	if port == "" {
+		port = defaultPort
+	}
+
+	readTimeout := r.cfg.ReadTimeout
+	if readTimeout == 0 {
+		readTimeout = r.cfg.Timeout
+	}
+
+	writeTimeout := r.cfg.WriteTimeout
+	if writeTimeout == 0 {
+		writeTimeout = r.cfg.Timeout
+	}
+
+	shutdownTimeout := r.cfg.ShutdownTimeout
+	if shutdownTimeout == 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
 
 	server := &http.Server{
-		Addr:    port,
-		Handler: r.http,
+		Addr:         ":" + port,
+		Handler:      r.http,
+		ReadTimeout:  readTimeout,
+		WriteTimeout: writeTimeout,
+		IdleTimeout:  r.cfg.IdleTimeout,
 	}
 
 	go func() {
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			fmt.Println(fmt.Sprintf("Serving HTTP error: %s", err.Error()))
+		var err error
+		if r.cfg.TLS.Enabled {
+			err = server.ListenAndServeTLS(r.cfg.TLS.CertFile, r.cfg.TLS.KeyFile)
+		} else {
+			err = server.ListenAndServe()
+		}
+		// a server that can't listen (port in use, bad certificate) must not keep the process waiting for a signal
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatal(context.Background(), "serving http failed", log.Fields{"error": err})
 		}
 	}()
-	fmt.Println(fmt.Sprintf("Listening and Serving HTTP on %s", server.Addr))
+	log.Info(context.Background(), "listening and serving http", log.Fields{"addr": server.Addr, "tls": r.cfg.TLS.Enabled})
 
 	// Wait for interrupt signal to gracefully shutdown the server with
-	// a timeout of 5 seconds.
-	quit := make(chan os.Signal)
+	// a timeout of ShutdownTimeout.
+	quit := make(chan os.Signal, 1)
 	// kill (no param) default send syscall.SIGTERM
 	// kill -2 is syscall.SIGINT
 	// kill -9 is syscall.SIGKILL but can't be caught, so don't need to add it
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
-	log.Println("Shutting down server...")
 
-	// The context is used to inform the server it has 5 seconds to finish
+	// fail the readiness probe first so no new traffic is routed here while the server keeps serving
+	r.health.Drain()
+	if r.cfg.DrainDelay > 0 {
+		log.Info(context.Background(), "draining server", log.Fields{"delay": r.cfg.DrainDelay})
+		time.Sleep(r.cfg.DrainDelay)
+	}
+
+	log.Info(context.Background(), "shutting down server")
+
+	// The context is used to inform the server it has ShutdownTimeout to finish
 	// the request it is currently handling
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
-		log.Fatal(fmt.Sprintf("Server forced to shutdown: %v", err))
+		log.Fatal(ctx, "server forced to shutdown", log.Fields{"error": err})
 	}
 
-	log.Println("Server exiting")
+	log.Info(context.Background(), "server exiting")
 }
 
 func (r *rest) Register() {
 	r.registerSwaggerRoutes()
+	r.registerMetricsRoutes()
+	r.http.GET(healthzRoute, r.Healthz)
+	r.http.GET(readyzRoute, r.Readyz)
 	publicApi := r.http.Group("/public")
 	publicApi.GET("/", func(ctx *gin.Context) {
 		ctx.JSON(http.StatusOK, gin.H{
@@ -119,10 +237,10 @@ func (r *rest) Register() {
 		})
 	})
 
-	r.http.Static("/public/assets/umkm", "./public/assets/umkm")
-	r.http.Static("/public/assets/menu", "./public/assets/menu")
+	r.http.Static("/"+umkmAssetDir, "./"+umkmAssetDir)
+	r.http.Static("/"+menuAssetDir, "./"+menuAssetDir)
 	api := r.http.Group("/api")
-	v1 := api.Group("/v1")
+	v1 := api.Group("/v1", r.RateLimit(rateLimitDefault))
 
 	v1.GET("/", r.VerifyUser, func(ctx *gin.Context) {
 		ctx.JSON(http.StatusOK, gin.H{
@@ -130,29 +248,33 @@ func (r *rest) Register() {
 		})
 	})
 
-	auth := v1.Group("/auth")
-	auth.POST("/register", r.VerifyUser, r.VerifyAdmin, r.RegisterUser)
-	auth.POST("/login", r.LoginUser)
-	auth.POST("/guest", r.LoginGuestUser)
+	authApi := v1.Group("/auth", r.RateLimit(rateLimitAuth))
+	authApi.POST("/register", r.VerifyUser, r.Authorize(auth.PermissionUserManage), r.Audit(entity.AuditActionUserCreate, entity.AuditTargetUser), r.RegisterUser)
+	authApi.POST("/login", r.LoginUser)
+	authApi.POST("/guest", r.RateLimit(rateLimitGuest), r.LoginGuestUser)
+	authApi.POST("/buyer/register", r.RegisterBuyer)
+	authApi.POST("/refresh", r.RefreshToken)
+	authApi.POST("/logout", r.VerifyUser, r.Logout)
+	authApi.POST("/reset-password", r.ResetPassword)
 
 	umkm := v1.Group("/umkm")
-	umkm.POST("/create", r.VerifyUser, r.VerifyAdmin, r.CreateUmkm)
+	umkm.POST("/create", r.VerifyUser, r.Authorize(auth.PermissionUmkmCreate), r.Audit(entity.AuditActionUmkmCreate, entity.AuditTargetUmkm), r.CreateUmkm)
 	umkm.GET("/:umkm_id", r.VerifyUser, r.GetUmkmByID)
 	umkm.GET("", r.VerifyUser, r.GetUmkmList)
-	umkm.PUT("/:umkm_id", r.VerifyUser, r.VerifyUmkm, r.UpdateUmkm)
-	umkm.DELETE("/:umkm_id", r.VerifyUser, r.VerifyUmkm, r.DeleteUmkm)
-	umkm.POST("/:umkm_id/upload-image", r.VerifyUser, r.VerifyUmkm, r.UploadImageUmkm)
+	umkm.PUT("/:umkm_id", r.VerifyUser, r.Authorize(auth.PermissionUmkmUpdate), r.Audit(entity.AuditActionUmkmUpdate, entity.AuditTargetUmkm), r.UpdateUmkm)
+	umkm.DELETE("/:umkm_id", r.VerifyUser, r.Authorize(auth.PermissionUmkmUpdate), r.Audit(entity.AuditActionUmkmDelete, entity.AuditTargetUmkm), r.DeleteUmkm)
+	umkm.POST("/:umkm_id/upload-image", r.VerifyUser, r.Authorize(auth.PermissionUmkmUpdate), r.Audit(entity.AuditActionUmkmUploadImage, entity.AuditTargetUmkm), r.UploadImageUmkm)
 
 	// menu
 	menu := v1.Group("/menu")
-	umkm.POST("/:umkm_id/menu/create", r.VerifyUser, r.VerifyUmkm, r.CreateMenu)
+	umkm.POST("/:umkm_id/menu/create", r.VerifyUser, r.Authorize(auth.PermissionMenuManage), r.Audit(entity.AuditActionMenuCreate, entity.AuditTargetMenu), r.CreateMenu)
 	menu.GET("/:menu_id", r.VerifyUser, r.GetMenuByID)
 	menu.GET("", r.VerifyUser, r.GetMenuList)
-	menu.PUT("/:menu_id", r.VerifyUser, r.VerifyMenu, r.UpdateMenu)
-	menu.DELETE("/:menu_id", r.VerifyUser, r.VerifyMenu, r.DeleteMenu)
-	umkm.POST("/:umkm_id/menu/:menu_id/upload-image", r.VerifyUser, r.VerifyUmkm, r.UploadImageMenu)
+	menu.PUT("/:menu_id", r.VerifyUser, r.Authorize(auth.PermissionMenuManage), r.VerifyMenu, r.Audit(entity.AuditActionMenuUpdate, entity.AuditTargetMenu), r.UpdateMenu)
+	menu.DELETE("/:menu_id", r.VerifyUser, r.Authorize(auth.PermissionMenuManage), r.VerifyMenu, r.Audit(entity.AuditActionMenuDelete, entity.AuditTargetMenu), r.DeleteMenu)
+	umkm.POST("/:umkm_id/menu/:menu_id/upload-image", r.VerifyUser, r.Authorize(auth.PermissionMenuManage), r.Audit(entity.AuditActionMenuUploadImage, entity.AuditTargetMenu), r.UploadImageMenu)
 
-	cart := v1.Group("/cart")
+	cart := v1.Group("/cart", r.RateLimit(rateLimitCart))
 	cart.POST("/create", r.VerifyUser, r.AddMenuToCart)
 	cart.GET("", r.VerifyUser, r.GetListCartByUser)
 	cart.PUT("/:cart_id/decrease", r.VerifyUser, r.DecreaseItem)
@@ -162,34 +284,62 @@ func (r *rest) Register() {
 	admin := v1.Group("/admin")
 
 	// transaction
-	umkm.GET("/:umkm_id/transactions", r.VerifyUser, r.VerifyUmkm, r.GetTransactionListUmkm)
-	admin.GET("/transactions", r.VerifyUser, r.VerifyAdmin, r.GetTransactionList)
-	admin.GET("/transactions/recap", r.VerifyUser, r.VerifyAdmin, r.GetRecapSalesList)
+	umkm.GET("/:umkm_id/transactions", r.VerifyUser, r.Authorize(auth.PermissionOrderView), r.GetTransactionListUmkm)
+	admin.GET("/transactions", r.VerifyUser, r.Authorize(auth.PermissionReportView), r.GetTransactionList)
+	admin.GET("/transactions/search", r.VerifyUser, r.Authorize(auth.PermissionReportView), r.SearchTransaction)
+	admin.GET("/transactions/search/download", r.VerifyUser, r.Authorize(auth.PermissionReportView), r.DownloadTransactionSearch)
+	admin.GET("/transactions/recap", r.VerifyUser, r.Authorize(auth.PermissionReportView), r.GetRecapSalesList)
 	transaction := v1.Group("/transaction")
-	transaction.POST("/create", r.VerifyUser, r.CreateOrder)
+	transaction.POST("/create", r.RateLimit(rateLimitCheckout), r.VerifyUser, r.CreateOrder)
 	transaction.GET("/:transaction_id/payment-detail", r.VerifyUser, r.GetPaymentDetail)
 	transaction.GET("/:transaction_id", r.GetOrderDetail)
 	transaction.GET("/me", r.VerifyUser, r.GetMyTransaction)
-	umkm.PUT("/:umkm_id/transaction/:transaction_id/mark-as-done", r.VerifyUser, r.VerifyUmkm, r.CompleteOrder)
-	umkm.PUT("/:umkm_id/transaction/:transaction_id/cancel-order", r.VerifyUser, r.VerifyUmkm, r.CancelOrder)
-	admin.PUT("/transaction/:order_id/mark-as-paid", r.VerifyUser, r.VerifyAdmin, r.MarkAsPaid)
-	admin.GET("/transactions/recap/download", r.VerifyUser, r.VerifyAdmin, r.DownloadMonthlyRecap)
-
-	midtransTransaction := v1.Group("/midtrans-transaction")
+	umkm.PUT("/:umkm_id/transaction/:transaction_id/mark-as-done", r.VerifyUser, r.Authorize(auth.PermissionOrderComplete), r.Audit(entity.AuditActionOrderComplete, entity.AuditTargetTransaction), r.CompleteOrder)
+	umkm.PUT("/:umkm_id/transaction/:transaction_id/cancel-order", r.VerifyUser, r.Authorize(auth.PermissionOrderCancel), r.Audit(entity.AuditActionOrderCancel, entity.AuditTargetTransaction), r.CancelOrder)
+	admin.PUT("/transaction/:order_id/mark-as-paid", r.VerifyUser, r.Authorize(auth.PermissionPaymentManage), r.Audit(entity.AuditActionPaymentMarkAsPaid, entity.AuditTargetPayment), r.MarkAsPaid)
+	admin.GET("/transactions/recap/download", r.VerifyUser, r.Authorize(auth.PermissionReportView), r.DownloadMonthlyRecap)
+
+	// Midtrans notifies from a few shared addresses, the webhook has its own policy instead of the default one so
+	// payment notifications aren't throttled along with the api
+	midtransTransaction := api.Group("/v1/midtrans-transaction", r.RateLimit(rateLimitWebhook))
 	midtransTransaction.POST("/handle", r.HandleNotification)
 
 	user := v1.Group("/user")
 	user.GET("/cart-count", r.VerifyUser, r.GetCartCount)
 	user.GET("/me", r.VerifyUser, r.GetMe)
+	user.PUT("/password", r.VerifyUser, r.ChangePassword)
+	admin.GET("/user", r.VerifyUser, r.Authorize(auth.PermissionUserManage), r.GetUserList)
+	admin.POST("/user", r.VerifyUser, r.Authorize(auth.PermissionUserManage), r.Audit(entity.AuditActionUserCreate, entity.AuditTargetUser), r.RegisterUser)
+	admin.GET("/user/:user_id", r.VerifyUser, r.Authorize(auth.PermissionUserManage), r.GetUser)
+	admin.PUT("/user/:user_id", r.VerifyUser, r.Authorize(auth.PermissionUserManage), r.Audit(entity.AuditActionUserUpdate, entity.AuditTargetUser), r.UpdateUser)
+	admin.DELETE("/user/:user_id", r.VerifyUser, r.Authorize(auth.PermissionUserManage), r.Audit(entity.AuditActionUserDelete, entity.AuditTargetUser), r.DeleteUser)
+	admin.PUT("/user/:user_id/disable", r.VerifyUser, r.Authorize(auth.PermissionUserManage), r.Audit(entity.AuditActionUserDisable, entity.AuditTargetUser), r.DisableUser)
+	admin.PUT("/user/:user_id/enable", r.VerifyUser, r.Authorize(auth.PermissionUserManage), r.Audit(entity.AuditActionUserEnable, entity.AuditTargetUser), r.EnableUser)
+	admin.POST("/user/:user_id/reset-code", r.VerifyUser, r.Authorize(auth.PermissionUserManage), r.Audit(entity.AuditActionUserResetCode, entity.AuditTargetUser), r.CreatePasswordResetCode)
+	admin.PUT("/user/:user_id/unlock", r.VerifyUser, r.Authorize(auth.PermissionUserManage), r.Audit(entity.AuditActionUserUnlock, entity.AuditTargetUser), r.UnlockUser)
+	umkm.POST("/:umkm_id/staff", r.VerifyUser, r.Authorize(auth.PermissionStaffManage), r.Audit(entity.AuditActionStaffInvite, entity.AuditTargetUser), r.InviteStaff)
+	umkm.GET("/:umkm_id/staff", r.VerifyUser, r.Authorize(auth.PermissionStaffManage), r.GetStaffList)
+	admin.GET("/login-events", r.VerifyUser, r.Authorize(auth.PermissionUserManage), r.GetLoginEventList)
+
+	// audit
+	admin.GET("/audit-logs", r.VerifyUser, r.Authorize(auth.PermissionAuditView), r.GetAuditLogList)
+	admin.GET("/audit-logs/download", r.VerifyUser, r.Authorize(auth.PermissionAuditView), r.DownloadAuditLog)
 
 	// analytic
-	umkm.GET("/:umkm_id/analytic/dashboard-widget", r.VerifyUser, r.VerifyUmkm, r.GetDashboardWidget)
-	admin.GET("/analytic/dashboard-widget", r.VerifyUser, r.VerifyAdmin, r.GetAllDashboardWidget)
+	umkm.GET("/:umkm_id/analytic/dashboard-widget", r.VerifyUser, r.Authorize(auth.PermissionAnalyticView), r.GetDashboardWidget)
+	admin.GET("/analytic/dashboard-widget", r.VerifyUser, r.Authorize(auth.PermissionReportView), r.GetAllDashboardWidget)
+	umkm.GET("/:umkm_id/analytic/sales", r.VerifyUser, r.Authorize(auth.PermissionAnalyticView), r.GetSalesAnalytic)
+	admin.GET("/analytic/sales", r.VerifyUser, r.Authorize(auth.PermissionReportView), r.GetAllSalesAnalytic)
+	umkm.GET("/:umkm_id/analytic/menu-performance", r.VerifyUser, r.Authorize(auth.PermissionAnalyticView), r.GetMenuPerformance)
+	admin.GET("/analytic/menu-performance", r.VerifyUser, r.Authorize(auth.PermissionReportView), r.GetAllMenuPerformance)
+	umkm.GET("/:umkm_id/analytic/heatmap", r.VerifyUser, r.Authorize(auth.PermissionAnalyticView), r.GetHeatmap)
+	admin.GET("/analytic/heatmap", r.VerifyUser, r.Authorize(auth.PermissionReportView), r.GetAllHeatmap)
+	admin.GET("/analytic/breakdown", r.VerifyUser, r.Authorize(auth.PermissionReportView), r.GetBreakdown)
 
 	// withdraw
-	admin.GET("/withdraw", r.VerifyUser, r.VerifyAdmin, r.GetWithdrawList)
-	admin.POST("/withdraw", r.VerifyUser, r.VerifyAdmin, r.CreateWithdraw)
-	admin.PUT("/withdraw/:withdraw_id", r.VerifyUser, r.VerifyAdmin, r.UpdateWithdraw)
+	admin.GET("/withdraw", r.VerifyUser, r.Authorize(auth.PermissionWithdrawManage), r.GetWithdrawList)
+	admin.POST("/withdraw", r.VerifyUser, r.Authorize(auth.PermissionWithdrawManage), r.Audit(entity.AuditActionWithdrawCreate, entity.AuditTargetWithdraw), r.CreateWithdraw)
+	admin.PUT("/withdraw/:withdraw_id", r.VerifyUser, r.Authorize(auth.PermissionWithdrawManage), r.Audit(entity.AuditActionWithdrawUpdate, entity.AuditTargetWithdraw), r.UpdateWithdraw)
 }
 
 func (r *rest) registerSwaggerRoutes() {
@@ -201,3 +351,11 @@ func (r *rest) registerSwaggerRoutes() {
 
 	r.http.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 }
+
+func (r *rest) registerMetricsRoutes() {
+	if !r.metrics.Enabled {
+		return
+	}
+
+	r.http.GET(r.metrics.GetPath(), gin.BasicAuth(gin.Accounts{r.metrics.Username: r.metrics.Password}), gin.WrapH(metrics.Handler()))
+}
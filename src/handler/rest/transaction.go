@@ -18,6 +18,7 @@ import (
 // @Failure 400 {object} entity.Response{}
 // @Failure 401 {object} entity.Response{}
 // @Failure 404 {object} entity.Response{}
+// @Failure 429 {object} entity.Response{}
 // @Failure 500 {object} entity.Response{}
 // @Router /api/v1/transaction/create [POST]
 func (r *rest) CreateOrder(ctx *gin.Context) {
@@ -33,7 +34,7 @@ func (r *rest) CreateOrder(ctx *gin.Context) {
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusCreated, "successfully created new order", gin.H{"id": id})
+	r.httpRespSuccess(ctx, http.StatusCreated, "success.create_order", gin.H{"id": id})
 }
 
 // @Summary Get Order
@@ -61,7 +62,7 @@ func (r *rest) GetOrderDetail(ctx *gin.Context) {
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully get order detail", result)
+	r.httpRespSuccess(ctx, http.StatusOK, "success.get_order_detail", result)
 }
 
 // @Summary Get Transaction List by UMKM
@@ -71,6 +72,11 @@ func (r *rest) GetOrderDetail(ctx *gin.Context) {
 // @Param umkm_id path integer true "umkm id"
 // @Param statuses query []string false "statuses" collectionFormat(multi)
 // @Param order_id query string false "order_id"
+// @Param page query int false "page"
+// @Param limit query int false "limit"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort_by query string false "sort column" Enums(id)
+// @Param sort_order query string false "sort order" Enums(asc, desc)
 // @Produce json
 // @Success 200 {object} entity.Response{data=entity.TransactionDetailResponse}
 // @Failure 400 {object} entity.Response{}
@@ -90,13 +96,13 @@ func (r *rest) GetTransactionListUmkm(ctx *gin.Context) {
 		return
 	}
 
-	result, err := r.uc.Transaction.GetTransactionListByUmkm(ctx.Request.Context(), param)
+	result, pagination, err := r.uc.Transaction.GetTransactionListByUmkm(ctx.Request.Context(), param)
 	if err != nil {
 		r.httpRespError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully get transactions list", result)
+	r.httpRespSuccessPaginated(ctx, http.StatusOK, "success.get_transaction_list", result, pagination)
 }
 
 // @Summary Get Transaction List
@@ -104,8 +110,11 @@ func (r *rest) GetTransactionListUmkm(ctx *gin.Context) {
 // @Security BearerAuth
 // @Tags Transaction
 // @Param order_id query string false "order_id"
-// @Param limit query int true "limit"
-// @Param page query int true "page"
+// @Param page query int false "page"
+// @Param limit query int false "limit"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort_by query string false "sort column" Enums(id, created_at)
+// @Param sort_order query string false "sort order" Enums(asc, desc)
 // @Produce json
 // @Success 200 {object} entity.Response{data=[]entity.TransactionDetailResponse}
 // @Failure 400 {object} entity.Response{}
@@ -120,13 +129,101 @@ func (r *rest) GetTransactionList(ctx *gin.Context) {
 		return
 	}
 
-	result, err := r.uc.Transaction.GetTransactionList(ctx.Request.Context(), param)
+	result, pagination, err := r.uc.Transaction.GetTransactionList(ctx.Request.Context(), param)
 	if err != nil {
 		r.httpRespError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully get transactions list", result)
+	r.httpRespSuccessPaginated(ctx, http.StatusOK, "success.get_transaction_list", result, pagination)
+}
+
+// @Summary Search Transaction
+// @Description Search transactions by combinable filters
+// @Security BearerAuth
+// @Tags Transaction
+// @Param q query string false "full-text search over buyer name, seat and email"
+// @Param buyer_name query string false "buyer name prefix"
+// @Param seat query string false "seat"
+// @Param email query string false "email"
+// @Param order_id query string false "order_id prefix"
+// @Param umkm_id query integer false "umkm id"
+// @Param menu_name query string false "menu name prefix"
+// @Param statuses query []string false "statuses" collectionFormat(multi)
+// @Param payment_type query string false "payment type" Enums(cash, gopay)
+// @Param min_amount query integer false "minimum total price"
+// @Param max_amount query integer false "maximum total price"
+// @Param start_date query string false "start date (YYYY-MM-DD)"
+// @Param end_date query string false "end date (YYYY-MM-DD)"
+// @Param page query int false "page"
+// @Param limit query int false "limit"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort_by query string false "sort column" Enums(id, created_at, price)
+// @Param sort_order query string false "sort order" Enums(asc, desc)
+// @Produce json
+// @Success 200 {object} entity.Response{data=[]entity.TransactionDetailResponse}
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 403 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/admin/transactions/search [GET]
+func (r *rest) SearchTransaction(ctx *gin.Context) {
+	var param entity.TransactionSearchParam
+	if err := ctx.ShouldBindQuery(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	result, pagination, err := r.uc.Transaction.Search(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	r.httpRespSuccessPaginated(ctx, http.StatusOK, "success.search_transaction", result, pagination)
+}
+
+// @Summary Download Transaction Search
+// @Description Export the filtered transactions as CSV
+// @Security BearerAuth
+// @Tags Transaction
+// @Param q query string false "full-text search over buyer name, seat and email"
+// @Param buyer_name query string false "buyer name prefix"
+// @Param seat query string false "seat"
+// @Param email query string false "email"
+// @Param order_id query string false "order_id prefix"
+// @Param umkm_id query integer false "umkm id"
+// @Param menu_name query string false "menu name prefix"
+// @Param statuses query []string false "statuses" collectionFormat(multi)
+// @Param payment_type query string false "payment type" Enums(cash, gopay)
+// @Param min_amount query integer false "minimum total price"
+// @Param max_amount query integer false "maximum total price"
+// @Param start_date query string false "start date (YYYY-MM-DD)"
+// @Param end_date query string false "end date (YYYY-MM-DD)"
+// @Param sort_by query string false "sort column" Enums(id, created_at, price)
+// @Param sort_order query string false "sort order" Enums(asc, desc)
+// @Produce text/csv
+// @Success 200 {file} file
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 403 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/admin/transactions/search/download [GET]
+func (r *rest) DownloadTransactionSearch(ctx *gin.Context) {
+	var param entity.TransactionSearchParam
+	if err := ctx.ShouldBindQuery(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	data, filename, err := r.uc.Transaction.GenerateSearchCSV(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.csv", filename))
+	ctx.Data(http.StatusOK, "text/csv", data)
 }
 
 // @Summary Get Recap Transaction
@@ -154,13 +251,19 @@ func (r *rest) GetRecapSalesList(ctx *gin.Context) {
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully get recap transaction list", result)
+	r.httpRespSuccess(ctx, http.StatusOK, "success.get_recap_transaction_list", result)
 }
 
 // @Summary Get My Transaction
 // @Description Get My Transactions
 // @Security BearerAuth
 // @Tags Transaction
+// @Param order_id query string false "midtrans order id"
+// @Param page query int false "page"
+// @Param limit query int false "limit"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort_by query string false "sort column" Enums(id, created_at)
+// @Param sort_order query string false "sort order" Enums(asc, desc)
 // @Produce json
 // @Success 200 {object} entity.Response{data=[]entity.TransactionDetailResponse}
 // @Failure 400 {object} entity.Response{}
@@ -176,13 +279,13 @@ func (r *rest) GetMyTransaction(ctx *gin.Context) {
 		return
 	}
 
-	result, err := r.uc.Transaction.GetMyTransaction(ctx.Request.Context(), param)
+	result, pagination, err := r.uc.Transaction.GetMyTransaction(ctx.Request.Context(), param)
 	if err != nil {
 		r.httpRespError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully get transactions list", result)
+	r.httpRespSuccessPaginated(ctx, http.StatusOK, "success.get_transaction_list", result, pagination)
 }
 
 // @Summary Complete Orders
@@ -211,7 +314,7 @@ func (r *rest) CompleteOrder(ctx *gin.Context) {
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully mark as done", nil)
+	r.httpRespSuccess(ctx, http.StatusOK, "success.mark_as_done", nil)
 }
 
 // @Summary Cancel Order
@@ -240,7 +343,7 @@ func (r *rest) CancelOrder(ctx *gin.Context) {
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully cancel an order", nil)
+	r.httpRespSuccess(ctx, http.StatusOK, "success.cancel_order", nil)
 }
 
 // @Summary Download Recap
@@ -30,13 +30,13 @@ func (r *rest) CreateUmkm(ctx *gin.Context) {
 		return
 	}
 
-	umkm, err := r.uc.Umkm.Create(umkmInput)
+	umkm, err := r.uc.Umkm.Create(ctx.Request.Context(), umkmInput)
 	if err != nil {
 		r.httpRespError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusCreated, "successfully created new umkm", umkm)
+	r.httpRespSuccess(ctx, http.StatusCreated, "success.create_umkm", umkm)
 }
 
 // @Summary Get Umkm
@@ -58,13 +58,13 @@ func (r *rest) GetUmkmByID(ctx *gin.Context) {
 		return
 	}
 
-	umkm, err := r.uc.Umkm.Get(umkmParam)
+	umkm, err := r.uc.Umkm.Get(ctx.Request.Context(), umkmParam)
 	if err != nil {
 		r.httpRespError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully get umkm", umkm)
+	r.httpRespSuccess(ctx, http.StatusOK, "success.get_umkm", umkm)
 }
 
 // @Summary Get Umkm List
@@ -74,6 +74,11 @@ func (r *rest) GetUmkmByID(ctx *gin.Context) {
 // @Produce json
 // @Param name query string false "name param"
 // @Param status query string false "status umkm" Enums(open, close)
+// @Param page query int false "page"
+// @Param limit query int false "limit"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort_by query string false "sort column" Enums(id, name, created_at)
+// @Param sort_order query string false "sort order" Enums(asc, desc)
 // @Success 200 {object} entity.Response{data=entity.Umkm{}}
 // @Failure 400 {object} entity.Response{}
 // @Failure 401 {object} entity.Response{}
@@ -87,13 +92,13 @@ func (r *rest) GetUmkmList(ctx *gin.Context) {
 		return
 	}
 
-	umkms, err := r.uc.Umkm.GetList(umkmParam)
+	umkms, pagination, err := r.uc.Umkm.GetList(ctx.Request.Context(), umkmParam)
 	if err != nil {
 		r.httpRespError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully get umkm list", umkms)
+	r.httpRespSuccessPaginated(ctx, http.StatusOK, "success.get_umkm_list", umkms, pagination)
 }
 
 // @Summary Update Umkm
@@ -122,13 +127,13 @@ func (r *rest) UpdateUmkm(ctx *gin.Context) {
 		return
 	}
 
-	err := r.uc.Umkm.Update(selectParam, updateParam)
+	err := r.uc.Umkm.Update(ctx.Request.Context(), selectParam, updateParam)
 	if err != nil {
 		r.httpRespError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully update umkm", nil)
+	r.httpRespSuccess(ctx, http.StatusOK, "success.update_umkm", nil)
 }
 
 // @Summary Delete Umkm
@@ -150,13 +155,13 @@ func (r *rest) DeleteUmkm(ctx *gin.Context) {
 		return
 	}
 
-	err := r.uc.Umkm.Delete(selectParam)
+	err := r.uc.Umkm.Delete(ctx.Request.Context(), selectParam)
 	if err != nil {
 		r.httpRespError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully delete umkm", nil)
+	r.httpRespSuccess(ctx, http.StatusOK, "success.delete_umkm", nil)
 }
 
 // @Summary Upload Umkm Image
@@ -186,7 +191,7 @@ func (r *rest) UploadImageUmkm(ctx *gin.Context) {
 		return
 	}
 
-	path := fmt.Sprintf("public/assets/umkm/%d-%s-%d", selectParam.ID, file.Filename, time.Now().Unix())
+	path := fmt.Sprintf("%s/%d-%s-%d", umkmAssetDir, selectParam.ID, file.Filename, time.Now().Unix())
 
 	if err := ctx.SaveUploadedFile(file, path); err != nil {
 		r.httpRespError(ctx, http.StatusBadRequest, err)
@@ -200,5 +205,5 @@ func (r *rest) UploadImageUmkm(ctx *gin.Context) {
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully update tenant's image", nil)
+	r.httpRespSuccess(ctx, http.StatusOK, "success.update_tenant_image", nil)
 }
@@ -26,21 +26,21 @@ func (r *rest) RegisterUser(ctx *gin.Context) {
 		return
 	}
 
-	user, err := r.uc.User.Create(userParam)
+	user, err := r.uc.User.Create(ctx.Request.Context(), userParam)
 	if err != nil {
 		r.httpRespError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusCreated, "successfully registered new user", user)
+	r.httpRespSuccess(ctx, http.StatusCreated, "success.register_user", user)
 }
 
 // @Summary Login User
-// @Description Login User
+// @Description Login User, a Buyer Sending a Guest Token in the Authorization Header Claims the Guest Orders
 // @Tags Auth
 // @Param user body entity.LoginUserParam true "user info"
 // @Produce json
-// @Success 200 {object} entity.Response{}
+// @Success 200 {object} entity.Response{data=entity.TokenResult}
 // @Failure 400 {object} entity.Response{}
 // @Failure 401 {object} entity.Response{}
 // @Failure 404 {object} entity.Response{}
@@ -53,13 +53,99 @@ func (r *rest) LoginUser(ctx *gin.Context) {
 		return
 	}
 
-	token, err := r.uc.User.Login(userParam)
+	userParam.IP = ctx.ClientIP()
+	userParam.GuestID = r.getGuestID(ctx)
+
+	token, err := r.uc.User.Login(ctx.Request.Context(), userParam)
+	if err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, http.StatusOK, "success.login", token)
+}
+
+// @Summary Register Buyer
+// @Description Register a Buyer Account with Email or Phone, Orders of the Guest Token in the Authorization Header are Moved into the Account
+// @Tags Auth
+// @Param buyer body entity.RegisterBuyerParam true "buyer info"
+// @Produce json
+// @Success 201 {object} entity.Response{data=entity.TokenResult}
+// @Failure 400 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/auth/buyer/register [POST]
+func (r *rest) RegisterBuyer(ctx *gin.Context) {
+	var param entity.RegisterBuyerParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	param.GuestID = r.getGuestID(ctx)
+
+	token, err := r.uc.User.RegisterBuyer(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, http.StatusCreated, "success.register_buyer", token)
+}
+
+// @Summary Refresh Token
+// @Description Exchange a Refresh Token for a New Token Pair, the Old Refresh Token is Revoked
+// @Tags Auth
+// @Param token body entity.RefreshUserTokenParam true "refresh token"
+// @Produce json
+// @Success 200 {object} entity.Response{data=entity.TokenResult}
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 404 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/auth/refresh [POST]
+func (r *rest) RefreshToken(ctx *gin.Context) {
+	var param entity.RefreshUserTokenParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	token, err := r.uc.User.RefreshToken(ctx.Request.Context(), param)
 	if err != nil {
+		r.httpRespError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, http.StatusOK, "success.refresh_token", token)
+}
+
+// @Summary Logout
+// @Description Revoke the Current Token and the Given Refresh Token
+// @Security BearerAuth
+// @Tags Auth
+// @Param token body entity.LogoutUserParam false "refresh token"
+// @Produce json
+// @Success 200 {object} entity.Response{}
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 404 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/auth/logout [POST]
+func (r *rest) Logout(ctx *gin.Context) {
+	var param entity.LogoutUserParam
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&param); err != nil {
+			r.httpRespError(ctx, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	if err := r.uc.User.Logout(ctx.Request.Context(), param); err != nil {
 		r.httpRespError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully login", gin.H{"token": token})
+	r.httpRespSuccess(ctx, http.StatusOK, "success.logout", nil)
 }
 
 // @Summary Guest User
@@ -70,16 +156,17 @@ func (r *rest) LoginUser(ctx *gin.Context) {
 // @Failure 400 {object} entity.Response{}
 // @Failure 401 {object} entity.Response{}
 // @Failure 404 {object} entity.Response{}
+// @Failure 429 {object} entity.Response{}
 // @Failure 500 {object} entity.Response{}
 // @Router /api/v1/auth/guest [POST]
 func (r *rest) LoginGuestUser(ctx *gin.Context) {
-	token, err := r.uc.User.GenerateGuestToken()
+	token, err := r.uc.User.GenerateGuestToken(ctx.Request.Context())
 	if err != nil {
 		r.httpRespError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully get guest token", token)
+	r.httpRespSuccess(ctx, http.StatusOK, "success.get_guest_token", token)
 }
 
 // @Summary Get Cart Count
@@ -100,7 +187,7 @@ func (r *rest) GetCartCount(ctx *gin.Context) {
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully get cart count", gin.H{"count": count})
+	r.httpRespSuccess(ctx, http.StatusOK, "success.get_cart_count", gin.H{"count": count})
 }
 
 // @Summary Get Me
@@ -121,5 +208,407 @@ func (r *rest) GetMe(ctx *gin.Context) {
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully get cart count", user)
+	r.httpRespSuccess(ctx, http.StatusOK, "success.get_cart_count", user)
+}
+
+// @Summary Change Password
+// @Description Change Password of the User Logged In
+// @Security BearerAuth
+// @Tags User
+// @Param password body entity.ChangePasswordParam true "old and new password"
+// @Produce json
+// @Success 200 {object} entity.Response{}
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 404 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/user/password [PUT]
+func (r *rest) ChangePassword(ctx *gin.Context) {
+	var param entity.ChangePasswordParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := r.uc.User.ChangePassword(ctx.Request.Context(), param); err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, http.StatusOK, "success.change_password", nil)
+}
+
+// @Summary Create Password Reset Code
+// @Description Issue a One-Time Password Reset Code for a User
+// @Security BearerAuth
+// @Tags User
+// @Param user_id path integer true "user id"
+// @Produce json
+// @Success 200 {object} entity.Response{data=entity.PasswordResetCodeResult}
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 404 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/admin/user/{user_id}/reset-code [POST]
+func (r *rest) CreatePasswordResetCode(ctx *gin.Context) {
+	var param entity.UserParam
+	if err := ctx.ShouldBindUri(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := r.uc.User.CreatePasswordResetCode(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, http.StatusOK, "success.create_password_reset_code", result)
+}
+
+// @Summary Get User List
+// @Description Get User List with Search and Filters
+// @Security BearerAuth
+// @Tags User
+// @Param search query string false "username or name"
+// @Param role query string false "role"
+// @Param umkm_id query int false "umkm id"
+// @Param is_disabled query bool false "is disabled"
+// @Param page query int false "page"
+// @Param limit query int false "limit"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort_by query string false "sort column" Enums(id, username, nama, created_at)
+// @Param sort_order query string false "sort order" Enums(asc, desc)
+// @Produce json
+// @Success 200 {object} entity.Response{data=[]entity.User}
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 403 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/admin/user [GET]
+func (r *rest) GetUserList(ctx *gin.Context) {
+	var param entity.UserListParam
+	if err := ctx.ShouldBindQuery(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	result, pagination, err := r.uc.User.GetList(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	r.httpRespSuccessPaginated(ctx, http.StatusOK, "success.get_user_list", result, pagination)
+}
+
+// @Summary Get User
+// @Description Get User by ID
+// @Security BearerAuth
+// @Tags User
+// @Param user_id path integer true "user id"
+// @Produce json
+// @Success 200 {object} entity.Response{data=entity.User}
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 403 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/admin/user/{user_id} [GET]
+func (r *rest) GetUser(ctx *gin.Context) {
+	var param entity.UserParam
+	if err := ctx.ShouldBindUri(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := r.uc.User.Get(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, http.StatusOK, "success.get_user", result)
+}
+
+// @Summary Update User
+// @Description Update Profile, Role or Linked Umkm of a User, Promote by Setting an Admin Role with Umkm ID 0
+// @Security BearerAuth
+// @Tags User
+// @Param user_id path integer true "user id"
+// @Param user body entity.AdminUpdateUserParam true "user info"
+// @Produce json
+// @Success 200 {object} entity.Response{}
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 403 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/admin/user/{user_id} [PUT]
+func (r *rest) UpdateUser(ctx *gin.Context) {
+	var selectParam entity.UserParam
+	if err := ctx.ShouldBindUri(&selectParam); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var param entity.AdminUpdateUserParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := r.uc.User.Update(ctx.Request.Context(), selectParam, param); err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, http.StatusOK, "success.update_user", nil)
+}
+
+// @Summary Disable User
+// @Description Disable a User, Their Sessions are Ended and New Logins are Rejected
+// @Security BearerAuth
+// @Tags User
+// @Param user_id path integer true "user id"
+// @Produce json
+// @Success 200 {object} entity.Response{}
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 403 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/admin/user/{user_id}/disable [PUT]
+func (r *rest) DisableUser(ctx *gin.Context) {
+	var param entity.UserParam
+	if err := ctx.ShouldBindUri(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := r.uc.User.SetDisabled(ctx.Request.Context(), param, true); err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, http.StatusOK, "success.disable_user", nil)
+}
+
+// @Summary Enable User
+// @Description Enable a Disabled User
+// @Security BearerAuth
+// @Tags User
+// @Param user_id path integer true "user id"
+// @Produce json
+// @Success 200 {object} entity.Response{}
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 403 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/admin/user/{user_id}/enable [PUT]
+func (r *rest) EnableUser(ctx *gin.Context) {
+	var param entity.UserParam
+	if err := ctx.ShouldBindUri(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := r.uc.User.SetDisabled(ctx.Request.Context(), param, false); err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, http.StatusOK, "success.enable_user", nil)
+}
+
+// @Summary Delete User
+// @Description Delete a User
+// @Security BearerAuth
+// @Tags User
+// @Param user_id path integer true "user id"
+// @Produce json
+// @Success 200 {object} entity.Response{}
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 403 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/admin/user/{user_id} [DELETE]
+func (r *rest) DeleteUser(ctx *gin.Context) {
+	var param entity.UserParam
+	if err := ctx.ShouldBindUri(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := r.uc.User.Delete(ctx.Request.Context(), param); err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, http.StatusOK, "success.delete_user", nil)
+}
+
+// @Summary Invite Staff
+// @Description Create a Staff Account for an Umkm, the Reset Code is Used by the Staff to Set Their Password
+// @Security BearerAuth
+// @Tags User
+// @Param umkm_id path integer true "umkm id"
+// @Param staff body entity.InviteStaffParam true "staff info"
+// @Produce json
+// @Success 201 {object} entity.Response{data=entity.StaffInvitationResult}
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 403 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/umkm/{umkm_id}/staff [POST]
+func (r *rest) InviteStaff(ctx *gin.Context) {
+	var param entity.InviteStaffParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := ctx.ShouldBindUri(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := r.uc.User.InviteStaff(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, http.StatusCreated, "success.invite_staff", result)
+
+	// the staff account is the audit target, the reset code is for the invitee only
+	ctx.Set(auditResultKey, result.User)
+}
+
+// @Summary Get Staff List
+// @Description Get Accounts Assigned to an Umkm
+// @Security BearerAuth
+// @Tags User
+// @Param umkm_id path integer true "umkm id"
+// @Param search query string false "username or name"
+// @Param role query string false "role"
+// @Param is_disabled query bool false "is disabled"
+// @Param page query int false "page"
+// @Param limit query int false "limit"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort_by query string false "sort column" Enums(id, username, nama, created_at)
+// @Param sort_order query string false "sort order" Enums(asc, desc)
+// @Produce json
+// @Success 200 {object} entity.Response{data=[]entity.User}
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 403 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/umkm/{umkm_id}/staff [GET]
+func (r *rest) GetStaffList(ctx *gin.Context) {
+	var selectParam entity.UserParam
+	if err := ctx.ShouldBindUri(&selectParam); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var param entity.UserListParam
+	if err := ctx.ShouldBindQuery(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	result, pagination, err := r.uc.User.GetStaffList(ctx.Request.Context(), selectParam, param)
+	if err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	r.httpRespSuccessPaginated(ctx, http.StatusOK, "success.get_staff_list", result, pagination)
+}
+
+// @Summary Unlock User
+// @Description Clear the Failed Login Counter and Lockout of a User
+// @Security BearerAuth
+// @Tags User
+// @Param user_id path integer true "user id"
+// @Produce json
+// @Success 200 {object} entity.Response{}
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 404 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/admin/user/{user_id}/unlock [PUT]
+func (r *rest) UnlockUser(ctx *gin.Context) {
+	var param entity.UserParam
+	if err := ctx.ShouldBindUri(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := r.uc.User.Unlock(ctx.Request.Context(), param); err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, http.StatusOK, "success.unlock_user", nil)
+}
+
+// @Summary Get Login Event List
+// @Description Get Login Success and Failure Events
+// @Security BearerAuth
+// @Tags User
+// @Param user_id query int false "user id"
+// @Param username query string false "username"
+// @Param ip query string false "ip"
+// @Param success query bool false "success"
+// @Param page query int false "page"
+// @Param limit query int false "limit"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort_by query string false "sort column" Enums(created_at)
+// @Param sort_order query string false "sort order" Enums(asc, desc)
+// @Produce json
+// @Success 200 {object} entity.Response{data=[]entity.LoginEvent}
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 404 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/admin/login-events [GET]
+func (r *rest) GetLoginEventList(ctx *gin.Context) {
+	var param entity.LoginEventParam
+	if err := ctx.ShouldBindQuery(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	result, pagination, err := r.uc.User.GetLoginEventList(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	r.httpRespSuccessPaginated(ctx, http.StatusOK, "success.get_login_event_list", result, pagination)
+}
+
+// @Summary Reset Password
+// @Description Reset Password with a Code Issued by Admin
+// @Tags Auth
+// @Param password body entity.ResetPasswordParam true "reset code and new password"
+// @Produce json
+// @Success 200 {object} entity.Response{}
+// @Failure 400 {object} entity.Response{}
+// @Failure 401 {object} entity.Response{}
+// @Failure 404 {object} entity.Response{}
+// @Failure 500 {object} entity.Response{}
+// @Router /api/v1/auth/reset-password [POST]
+func (r *rest) ResetPassword(ctx *gin.Context) {
+	var param entity.ResetPasswordParam
+	if err := ctx.ShouldBindJSON(&param); err != nil {
+		r.httpRespError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := r.uc.User.ResetPassword(ctx.Request.Context(), param); err != nil {
+		r.httpRespError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, http.StatusOK, "success.reset_password", nil)
 }
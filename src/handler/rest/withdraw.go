@@ -12,8 +12,11 @@ import (
 // @Security BearerAuth
 // @Tags Withdraw
 // @Param date query string false "date"
-// @Param limit query int true "limit"
-// @Param page query int true "page"
+// @Param page query int false "page"
+// @Param limit query int false "limit"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort_by query string false "sort column" Enums(id, date, amount)
+// @Param sort_order query string false "sort order" Enums(asc, desc)
 // @Produce json
 // @Success 200 {object} entity.Response{data=[]entity.TransactionDetailResponse}
 // @Failure 400 {object} entity.Response{}
@@ -28,13 +31,13 @@ func (r *rest) GetWithdrawList(ctx *gin.Context) {
 		return
 	}
 
-	result, err := r.uc.Withdraw.GetList(ctx.Request.Context(), param)
+	result, pagination, err := r.uc.Withdraw.GetList(ctx.Request.Context(), param)
 	if err != nil {
 		r.httpRespError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusOK, "successfully get withdraw list", result)
+	r.httpRespSuccessPaginated(ctx, http.StatusOK, "success.get_withdraw_list", result, pagination)
 }
 
 // @Summary Create Withdraw
@@ -62,7 +65,7 @@ func (r *rest) CreateWithdraw(ctx *gin.Context) {
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusCreated, "successfully created new withdraw", withdraw)
+	r.httpRespSuccess(ctx, http.StatusCreated, "success.create_withdraw", withdraw)
 }
 
 // @Summary Update Withdraw
@@ -97,5 +100,5 @@ func (r *rest) UpdateWithdraw(ctx *gin.Context) {
 		return
 	}
 
-	r.httpRespSuccess(ctx, http.StatusCreated, "successfully update withdraw", nil)
+	r.httpRespSuccess(ctx, http.StatusCreated, "success.update_withdraw", nil)
 }
@@ -0,0 +1,151 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+type Kind string
+
+const (
+	KindValidation      Kind = "validation"
+	KindUnauthorized    Kind = "unauthorized"
+	KindForbidden       Kind = "forbidden"
+	KindNotFound        Kind = "not_found"
+	KindConflict        Kind = "conflict"
+	KindLocked          Kind = "locked"
+	KindTooManyRequests Kind = "too_many_requests"
+	KindUpstream        Kind = "upstream"
+	KindInternal        Kind = "internal"
+)
+
+var statuses = map[Kind]int{
+	KindValidation:      http.StatusBadRequest,
+	KindUnauthorized:    http.StatusUnauthorized,
+	KindForbidden:       http.StatusForbidden,
+	KindNotFound:        http.StatusNotFound,
+	KindConflict:        http.StatusConflict,
+	KindLocked:          http.StatusLocked,
+	KindTooManyRequests: http.StatusTooManyRequests,
+	KindUpstream:        http.StatusBadGateway,
+	KindInternal:        http.StatusInternalServerError,
+}
+
+var (
+	ErrNotFound = NotFound("not_found", "data tidak ditemukan")
+	ErrInternal = New(KindInternal, "internal_error", "terjadi kesalahan pada server, coba lagi nanti")
+)
+
+// Error is a failure that is safe to show to clients, Code is stable for clients and also names the translation
+// of Message, which is formatted with Args. The wrapped error is only logged.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Args    []interface{}
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches another Error with the same code, so errors.Is works for errors that wrap different causes.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+
+	return e.Code == t.Code
+}
+
+// Status is the HTTP status answered for the error.
+func (e *Error) Status() int {
+	if status, ok := statuses[e.Kind]; ok {
+		return status
+	}
+
+	return http.StatusInternalServerError
+}
+
+// Wrap returns a copy of the error caused by err.
+func (e *Error) Wrap(err error) *Error {
+	wrapped := *e
+	wrapped.Err = err
+	return &wrapped
+}
+
+// WithArgs returns a copy of the error whose message is formatted with args.
+func (e *Error) WithArgs(args ...interface{}) *Error {
+	formatted := *e
+	formatted.Message = fmt.Sprintf(e.Message, args...)
+	formatted.Args = args
+	return &formatted
+}
+
+func New(kind Kind, code string, message string) *Error {
+	return &Error{
+		Kind:    kind,
+		Code:    code,
+		Message: message,
+	}
+}
+
+func Validation(code string, message string) *Error {
+	return New(KindValidation, code, message)
+}
+
+func Unauthorized(code string, message string) *Error {
+	return New(KindUnauthorized, code, message)
+}
+
+func Forbidden(code string, message string) *Error {
+	return New(KindForbidden, code, message)
+}
+
+func NotFound(code string, message string) *Error {
+	return New(KindNotFound, code, message)
+}
+
+func Conflict(code string, message string) *Error {
+	return New(KindConflict, code, message)
+}
+
+func Locked(code string, message string) *Error {
+	return New(KindLocked, code, message)
+}
+
+func TooManyRequests(code string, message string) *Error {
+	return New(KindTooManyRequests, code, message)
+}
+
+// Upstream reports a failing dependency outside of the service, Wrap keeps its error for the logs.
+func Upstream(code string, message string) *Error {
+	return New(KindUpstream, code, message)
+}
+
+// From finds the Error in err's chain, a missing record is not found and any other error is internal.
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound.Wrap(err)
+	}
+
+	return ErrInternal.Wrap(err)
+}
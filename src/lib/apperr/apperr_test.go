@@ -0,0 +1,77 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func Test_From(t *testing.T) {
+	cartEmpty := Validation("cart_empty", "keranjang kosong")
+	dbErr := errors.New("connection refused")
+
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   string
+		wantStatus int
+		wantCause  error
+	}{
+		{
+			name:       "typed error",
+			err:        cartEmpty,
+			wantCode:   "cart_empty",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "wrapped typed error",
+			err:        fmt.Errorf("checkout: %w", Upstream("payment_gateway_error", "gagal").Wrap(dbErr)),
+			wantCode:   "payment_gateway_error",
+			wantStatus: http.StatusBadGateway,
+			wantCause:  dbErr,
+		},
+		{
+			name:       "missing record",
+			err:        fmt.Errorf("get menu: %w", gorm.ErrRecordNotFound),
+			wantCode:   "not_found",
+			wantStatus: http.StatusNotFound,
+			wantCause:  gorm.ErrRecordNotFound,
+		},
+		{
+			name:       "untyped error",
+			err:        dbErr,
+			wantCode:   "internal_error",
+			wantStatus: http.StatusInternalServerError,
+			wantCause:  dbErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := From(tt.err)
+			assert.Equal(t, tt.wantCode, got.Code)
+			assert.Equal(t, tt.wantStatus, got.Status())
+			if tt.wantCause != nil {
+				assert.ErrorIs(t, got, tt.wantCause)
+			}
+		})
+	}
+}
+
+func Test_Error_Is(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", ErrNotFound.Wrap(gorm.ErrRecordNotFound))
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NotErrorIs(t, err, ErrInternal)
+}
+
+func Test_Error_WithArgs(t *testing.T) {
+	err := Validation("min_amount", "minimal %d").WithArgs(1000)
+
+	assert.Equal(t, "minimal 1000", err.Message)
+	assert.Equal(t, []interface{}{1000}, err.Args)
+	assert.Equal(t, http.StatusBadRequest, err.Status())
+}
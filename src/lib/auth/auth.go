@@ -2,8 +2,13 @@ package auth
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
-	"os"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/golang-jwt/jwt"
 	gonanoid "github.com/matoous/go-nanoid/v2"
@@ -15,24 +20,115 @@ const (
 	userAuthInfo contextKey = "UserAuthInfo"
 )
 
+const (
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+	defaultGuestTokenTTL   = 7 * 24 * time.Hour
+	refreshTokenLength     = 48
+	defaultKeyID           = "default"
+)
+
 type Interface interface {
-	SetUserAuthInfo(ctx context.Context, user User, token string) context.Context
+	SetUserAuthInfo(ctx context.Context, user User, token Token) context.Context
 	GetUserAuthInfo(ctx context.Context) (UserAuthInfo, error)
-	GenerateToken(user User) (string, error)
-	GenerateGuestToken() (string, error)
+	GenerateToken(user User) (Token, error)
+	GenerateGuestToken() (Token, error)
+	GenerateRefreshToken() (Token, error)
+	ParseToken(tokenString string) (Claims, error)
+}
+
+type Config struct {
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	GuestTokenTTL   time.Duration
+	Issuer          string
+	Audience        string
+	// Key is the HMAC secret used when Keys is empty
+	Key string `env:"JWT_KEY"`
+	// Keys maps a kid to its HMAC secret, new tokens are signed with ActiveKeyID
+	// while tokens signed with any other listed key stay valid until they expire.
+	Keys        map[string]string
+	ActiveKeyID string
+}
+
+func (c Config) Validate() error {
+	if len(c.Keys) == 0 {
+		if c.Key == "" {
+			return errors.New("Auth.Key (JWT_KEY) is required when Auth.Keys is empty")
+		}
+		return nil
+	}
+
+	kids := make([]string, 0, len(c.Keys))
+	for kid := range c.Keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	errs := []string{}
+	activeKeyListed := false
+	for _, kid := range kids {
+		if c.Keys[kid] == "" {
+			errs = append(errs, fmt.Sprintf("Auth.Keys.%s is empty", kid))
+		}
+		if strings.EqualFold(kid, c.ActiveKeyID) {
+			activeKeyListed = true
+		}
+	}
+
+	if !activeKeyListed {
+		errs = append(errs, fmt.Sprintf("Auth.ActiveKeyID %q is not listed in Auth.Keys", c.ActiveKeyID))
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
 }
 
 type auth struct {
+	cfg Config
 }
 
-func Init() Interface {
-	return &auth{}
+func Init(cfg Config) Interface {
+	if cfg.AccessTokenTTL == 0 {
+		cfg.AccessTokenTTL = defaultAccessTokenTTL
+	}
+	if cfg.RefreshTokenTTL == 0 {
+		cfg.RefreshTokenTTL = defaultRefreshTokenTTL
+	}
+	if cfg.GuestTokenTTL == 0 {
+		cfg.GuestTokenTTL = defaultGuestTokenTTL
+	}
+
+	// the config reader lowercases map keys, so kids are compared case-insensitively
+	keys := make(map[string]string)
+	for kid, key := range cfg.Keys {
+		keys[strings.ToLower(kid)] = key
+	}
+	if len(keys) == 0 {
+		keys[defaultKeyID] = cfg.Key
+		cfg.ActiveKeyID = defaultKeyID
+	}
+	cfg.Keys = keys
+	cfg.ActiveKeyID = strings.ToLower(cfg.ActiveKeyID)
+
+	if _, ok := cfg.Keys[cfg.ActiveKeyID]; !ok {
+		panic(fmt.Errorf("active jwt key id %q is not listed in keys", cfg.ActiveKeyID))
+	}
+
+	return &auth{
+		cfg: cfg,
+	}
 }
 
-func (a *auth) SetUserAuthInfo(ctx context.Context, user User, token string) context.Context {
+func (a *auth) SetUserAuthInfo(ctx context.Context, user User, token Token) context.Context {
 	userAuth := UserAuthInfo{
-		User:  user,
-		Token: token,
+		User:           user,
+		Token:          token.Value,
+		TokenID:        token.ID,
+		TokenExpiresAt: token.ExpiresAt,
 	}
 
 	return context.WithValue(ctx, userAuthInfo, userAuth)
@@ -48,32 +144,138 @@ func (a *auth) GetUserAuthInfo(ctx context.Context) (UserAuthInfo, error) {
 	return user, nil
 }
 
-func (a *auth) GenerateToken(user User) (string, error) {
-	claim := jwt.MapClaims{}
-	claim["id"] = user.ID
-	claim["is_admin"] = user.IsAdmin
-	claim["is_guest"] = false
+func (a *auth) GenerateToken(user User) (Token, error) {
+	return a.signToken(Claims{
+		UserID:  user.ID,
+		IsAdmin: user.IsPlatformAdmin(),
+		Role:    user.Role,
+	}, a.cfg.AccessTokenTTL)
+}
+
+func (a *auth) GenerateGuestToken() (Token, error) {
+	guestId, _ := gonanoid.New()
+
+	return a.signToken(Claims{
+		GuestID: guestId,
+		IsGuest: true,
+	}, a.cfg.GuestTokenTTL)
+}
+
+func (a *auth) GenerateRefreshToken() (Token, error) {
+	now := time.Now()
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claim)
-	signedToken, err := token.SignedString([]byte(os.Getenv("JWT_KEY")))
+	value, err := gonanoid.New(refreshTokenLength)
 	if err != nil {
-		return "", err
+		return Token{}, err
 	}
 
-	return signedToken, nil
+	return Token{
+		Value:     value,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(a.cfg.RefreshTokenTTL),
+	}, nil
 }
 
-func (a *auth) GenerateGuestToken() (string, error) {
-	claim := jwt.MapClaims{}
-	guestId, _ := gonanoid.New()
-	claim["guest_id"] = guestId
-	claim["is_guest"] = true
+func (a *auth) ParseToken(tokenString string) (Claims, error) {
+	claims := Claims{}
+
+	_, err := jwt.ParseWithClaims(tokenString, &claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrTokenInvalid
+		}
+
+		// tokens issued before key rotation have no kid and were signed with the active key
+		kid, _ := t.Header["kid"].(string)
+		if kid == "" {
+			kid = a.cfg.ActiveKeyID
+		}
+
+		key, ok := a.cfg.Keys[strings.ToLower(kid)]
+		if !ok {
+			return nil, ErrTokenInvalid
+		}
+
+		return []byte(key), nil
+	})
+	if err != nil {
+		var validationErr *jwt.ValidationError
+		if errors.As(err, &validationErr) {
+			var authErr *Error
+			switch {
+			case errors.As(validationErr.Inner, &authErr):
+				return Claims{}, authErr
+			case validationErr.Errors&jwt.ValidationErrorMalformed != 0:
+				return Claims{}, ErrTokenMalformed
+			case validationErr.Errors&jwt.ValidationErrorExpired != 0:
+				return Claims{}, ErrTokenExpired
+			}
+		}
+		return Claims{}, ErrTokenInvalid
+	}
+
+	if err := a.validateClaims(claims); err != nil {
+		return Claims{}, err
+	}
+
+	return claims, nil
+}
+
+func (a *auth) validateClaims(claims Claims) error {
+	if claims.Id == "" || claims.ExpiresAt == 0 {
+		return ErrTokenInvalidClaims
+	}
+
+	if a.cfg.Issuer != "" && !claims.VerifyIssuer(a.cfg.Issuer, true) {
+		return ErrTokenInvalidClaims
+	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claim)
-	signedToken, err := token.SignedString([]byte(os.Getenv("JWT_KEY")))
+	if a.cfg.Audience != "" && !claims.VerifyAudience(a.cfg.Audience, true) {
+		return ErrTokenInvalidClaims
+	}
+
+	if claims.IsGuest && claims.GuestID == "" {
+		return ErrTokenInvalidClaims
+	}
+
+	if !claims.IsGuest && claims.UserID == 0 {
+		return ErrTokenInvalidClaims
+	}
+
+	return nil
+}
+
+func (a *auth) signToken(claims Claims, ttl time.Duration) (Token, error) {
+	now := time.Now()
+
+	id, err := gonanoid.New()
+	if err != nil {
+		return Token{}, err
+	}
+
+	claims.Id = id
+	claims.Issuer = a.cfg.Issuer
+	claims.Audience = a.cfg.Audience
+	claims.IssuedAt = now.Unix()
+	claims.ExpiresAt = now.Add(ttl).Unix()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = a.cfg.ActiveKeyID
+
+	signedToken, err := token.SignedString([]byte(a.cfg.Keys[a.cfg.ActiveKeyID]))
 	if err != nil {
-		return "", err
+		return Token{}, err
 	}
 
-	return signedToken, nil
+	return Token{
+		ID:        id,
+		Value:     signedToken,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(ttl),
+	}, nil
+}
+
+// HashToken is used to store opaque tokens without keeping their raw value.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
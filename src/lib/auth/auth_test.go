@@ -0,0 +1,215 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func signTestToken(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signedToken, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signedToken
+}
+
+func Test_auth_ParseToken(t *testing.T) {
+	a := Init(Config{
+		Issuer:   "go-clean",
+		Audience: "go-clean-api",
+		Keys: map[string]string{
+			"Current": "current-secret",
+			"old":     "old-secret",
+		},
+		ActiveKeyID: "current",
+	})
+
+	now := time.Now()
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"jti":      "token-id",
+			"iss":      "go-clean",
+			"aud":      "go-clean-api",
+			"iat":      now.Unix(),
+			"exp":      now.Add(time.Hour).Unix(),
+			"id":       1,
+			"is_admin": false,
+			"is_guest": false,
+			"role":     RoleTenantOwner,
+		}
+	}
+	withClaims := func(change func(c jwt.MapClaims)) jwt.MapClaims {
+		c := validClaims()
+		change(c)
+		return c
+	}
+
+	wantClaims := Claims{
+		StandardClaims: jwt.StandardClaims{
+			Id:        "token-id",
+			Issuer:    "go-clean",
+			Audience:  "go-clean-api",
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(time.Hour).Unix(),
+		},
+		UserID: 1,
+		Role:   RoleTenantOwner,
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		want    Claims
+		wantErr error
+	}{
+		{
+			name:    "malformed token",
+			token:   "not-a-jwt",
+			wantErr: ErrTokenMalformed,
+		},
+		{
+			name:  "signed with the active key",
+			token: signTestToken(t, jwt.SigningMethodHS256, []byte("current-secret"), "current", validClaims()),
+			want:  wantClaims,
+		},
+		{
+			name:  "kid is case insensitive",
+			token: signTestToken(t, jwt.SigningMethodHS256, []byte("current-secret"), "CURRENT", validClaims()),
+			want:  wantClaims,
+		},
+		{
+			name:  "signed with a retired key still listed",
+			token: signTestToken(t, jwt.SigningMethodHS256, []byte("old-secret"), "old", validClaims()),
+			want:  wantClaims,
+		},
+		{
+			name:  "no kid falls back to the active key",
+			token: signTestToken(t, jwt.SigningMethodHS256, []byte("current-secret"), "", validClaims()),
+			want:  wantClaims,
+		},
+		{
+			name:    "no kid signed with a retired key",
+			token:   signTestToken(t, jwt.SigningMethodHS256, []byte("old-secret"), "", validClaims()),
+			wantErr: ErrTokenInvalid,
+		},
+		{
+			name:    "unknown kid",
+			token:   signTestToken(t, jwt.SigningMethodHS256, []byte("current-secret"), "unknown", validClaims()),
+			wantErr: ErrTokenInvalid,
+		},
+		{
+			name:    "wrong signature",
+			token:   signTestToken(t, jwt.SigningMethodHS256, []byte("other-secret"), "current", validClaims()),
+			wantErr: ErrTokenInvalid,
+		},
+		{
+			name:    "non hmac alg",
+			token:   signTestToken(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "current", validClaims()),
+			wantErr: ErrTokenInvalid,
+		},
+		{
+			name: "expired token",
+			token: signTestToken(t, jwt.SigningMethodHS256, []byte("current-secret"), "current", withClaims(func(c jwt.MapClaims) {
+				c["exp"] = now.Add(-time.Minute).Unix()
+			})),
+			wantErr: ErrTokenExpired,
+		},
+		{
+			name: "missing jti",
+			token: signTestToken(t, jwt.SigningMethodHS256, []byte("current-secret"), "current", withClaims(func(c jwt.MapClaims) {
+				delete(c, "jti")
+			})),
+			wantErr: ErrTokenInvalidClaims,
+		},
+		{
+			name: "missing exp",
+			token: signTestToken(t, jwt.SigningMethodHS256, []byte("current-secret"), "current", withClaims(func(c jwt.MapClaims) {
+				delete(c, "exp")
+			})),
+			wantErr: ErrTokenInvalidClaims,
+		},
+		{
+			name: "ill-typed user id",
+			token: signTestToken(t, jwt.SigningMethodHS256, []byte("current-secret"), "current", withClaims(func(c jwt.MapClaims) {
+				c["id"] = "1"
+			})),
+			wantErr: ErrTokenMalformed,
+		},
+		{
+			name: "missing user id",
+			token: signTestToken(t, jwt.SigningMethodHS256, []byte("current-secret"), "current", withClaims(func(c jwt.MapClaims) {
+				delete(c, "id")
+			})),
+			wantErr: ErrTokenInvalidClaims,
+		},
+		{
+			name: "guest without guest id",
+			token: signTestToken(t, jwt.SigningMethodHS256, []byte("current-secret"), "current", withClaims(func(c jwt.MapClaims) {
+				delete(c, "id")
+				c["is_guest"] = true
+			})),
+			wantErr: ErrTokenInvalidClaims,
+		},
+		{
+			name: "wrong issuer",
+			token: signTestToken(t, jwt.SigningMethodHS256, []byte("current-secret"), "current", withClaims(func(c jwt.MapClaims) {
+				c["iss"] = "someone-else"
+			})),
+			wantErr: ErrTokenInvalidClaims,
+		},
+		{
+			name: "wrong audience",
+			token: signTestToken(t, jwt.SigningMethodHS256, []byte("current-secret"), "current", withClaims(func(c jwt.MapClaims) {
+				c["aud"] = "other-api"
+			})),
+			wantErr: ErrTokenInvalidClaims,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := a.ParseToken(tt.token)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_auth_GenerateToken(t *testing.T) {
+	a := Init(Config{
+		Issuer:   "go-clean",
+		Audience: "go-clean-api",
+		Key:      "secret",
+	})
+
+	token, err := a.GenerateToken(User{ID: 1, Role: RoleTenantOwner})
+	assert.NoError(t, err)
+
+	claims, err := a.ParseToken(token.Value)
+	assert.NoError(t, err)
+	assert.Equal(t, token.ID, claims.Id)
+	assert.Equal(t, uint(1), claims.UserID)
+	assert.Equal(t, RoleTenantOwner, claims.Role)
+
+	guestToken, err := a.GenerateGuestToken()
+	assert.NoError(t, err)
+
+	guestClaims, err := a.ParseToken(guestToken.Value)
+	assert.NoError(t, err)
+	assert.True(t, guestClaims.IsGuest)
+	assert.NotEmpty(t, guestClaims.GuestID)
+}
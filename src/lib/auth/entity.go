@@ -1,8 +1,26 @@
 package auth
 
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+var (
+	ErrTokenMissing       = &Error{Code: "token_missing", Message: "empty token"}
+	ErrTokenMalformed     = &Error{Code: "token_malformed", Message: "malformed token"}
+	ErrTokenInvalid       = &Error{Code: "token_invalid", Message: "invalid token"}
+	ErrTokenInvalidClaims = &Error{Code: "token_invalid_claims", Message: "invalid token claims"}
+	ErrTokenExpired       = &Error{Code: "token_expired", Message: "token expired"}
+	ErrTokenRevoked       = &Error{Code: "token_revoked", Message: "token sudah tidak berlaku"}
+	ErrAccountDisabled    = &Error{Code: "account_disabled", Message: "akun dinonaktifkan"}
+)
+
 type UserAuthInfo struct {
-	User  User
-	Token string
+	User           User
+	Token          string
+	TokenID        string
+	TokenExpiresAt time.Time
 }
 
 type User struct {
@@ -13,4 +31,31 @@ type User struct {
 	Nama     string
 	IsAdmin  bool
 	UmkmID   uint
+	Role     string
+}
+
+type Token struct {
+	ID        string
+	Value     string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+type Claims struct {
+	jwt.StandardClaims
+	UserID  uint   `json:"id,omitempty"`
+	GuestID string `json:"guest_id,omitempty"`
+	IsAdmin bool   `json:"is_admin"`
+	IsGuest bool   `json:"is_guest"`
+	Role    string `json:"role,omitempty"`
+}
+
+// Error is returned for any token that must be answered with 401, Code is stable for clients.
+type Error struct {
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
 }
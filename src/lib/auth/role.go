@@ -0,0 +1,107 @@
+package auth
+
+const (
+	RoleSuperAdmin    = "super_admin"
+	RoleFinanceAdmin  = "finance_admin"
+	RoleTenantOwner   = "tenant_owner"
+	RoleTenantCashier = "tenant_cashier"
+	RoleKitchenStaff  = "kitchen_staff"
+	RoleBuyer         = "buyer"
+)
+
+type Permission string
+
+// platform permissions
+const (
+	PermissionUserManage     Permission = "user:manage"
+	PermissionUmkmCreate     Permission = "umkm:create"
+	PermissionReportView     Permission = "report:view"
+	PermissionPaymentManage  Permission = "payment:manage"
+	PermissionWithdrawManage Permission = "withdraw:manage"
+	PermissionAuditView      Permission = "audit:view"
+)
+
+// tenant permissions, tenant roles only hold them for their own umkm
+const (
+	PermissionUmkmUpdate    Permission = "umkm:update"
+	PermissionMenuManage    Permission = "menu:manage"
+	PermissionOrderView     Permission = "order:view"
+	PermissionOrderComplete Permission = "order:complete"
+	PermissionOrderCancel   Permission = "order:cancel"
+	PermissionAnalyticView  Permission = "analytic:view"
+	PermissionStaffManage   Permission = "staff:manage"
+)
+
+var rolePermissions = map[string][]Permission{
+	RoleSuperAdmin: {
+		PermissionUserManage,
+		PermissionUmkmCreate,
+		PermissionReportView,
+		PermissionPaymentManage,
+		PermissionWithdrawManage,
+		PermissionAuditView,
+		PermissionUmkmUpdate,
+		PermissionMenuManage,
+		PermissionOrderView,
+		PermissionOrderComplete,
+		PermissionOrderCancel,
+		PermissionAnalyticView,
+		PermissionStaffManage,
+	},
+	RoleFinanceAdmin: {
+		PermissionReportView,
+		PermissionPaymentManage,
+		PermissionWithdrawManage,
+		PermissionOrderView,
+		PermissionAnalyticView,
+	},
+	RoleTenantOwner: {
+		PermissionUmkmUpdate,
+		PermissionMenuManage,
+		PermissionOrderView,
+		PermissionOrderComplete,
+		PermissionOrderCancel,
+		PermissionAnalyticView,
+		PermissionStaffManage,
+	},
+	RoleTenantCashier: {
+		PermissionOrderView,
+		PermissionOrderComplete,
+		PermissionOrderCancel,
+	},
+	RoleKitchenStaff: {
+		PermissionOrderView,
+		PermissionOrderComplete,
+	},
+	// buyers only reach the endpoints any authenticated session can use
+	RoleBuyer: {},
+}
+
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// IsPlatformRole reports whether the role works across every umkm instead of a single tenant.
+func IsPlatformRole(role string) bool {
+	return role == RoleSuperAdmin || role == RoleFinanceAdmin
+}
+
+// IsTenantRole reports whether the role must be linked to an umkm.
+func IsTenantRole(role string) bool {
+	return role == RoleTenantOwner || role == RoleTenantCashier || role == RoleKitchenStaff
+}
+
+func (u User) HasPermission(permission Permission) bool {
+	for _, p := range rolePermissions[u.Role] {
+		if p == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (u User) IsPlatformAdmin() bool {
+	return IsPlatformRole(u.Role)
+}
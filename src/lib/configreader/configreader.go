@@ -1,7 +1,12 @@
 package configreader
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -10,8 +15,16 @@ type Interface interface {
 	ReadConfig(cfg interface{})
 }
 
+// Options values are layered, flag overrides win over env vars which win over the config file.
 type Options struct {
 	ConfigFile string
+	// Optional lets the config file be missing, the config then comes from env vars and overrides only
+	Optional bool
+	// EnvPrefix namespaces the env vars, every field can be set with PREFIX_SECTION_FIELD
+	// (SECTION_FIELD without a prefix), fields tagged `env:"NAME"` also accept NAME
+	EnvPrefix string
+	// Overrides maps a key like Gin.Port to its value
+	Overrides map[string]string
 }
 
 type configReader struct {
@@ -24,7 +37,17 @@ func Init(opt Options) Interface {
 	v.SetConfigFile(opt.ConfigFile)
 	v.SetConfigType("json")
 	if err := v.ReadInConfig(); err != nil {
-		panic(fmt.Errorf("fatal error found during reading file. err : %w", err))
+		var pathErr *os.PathError
+		if !opt.Optional || !errors.As(err, &pathErr) {
+			panic(fmt.Errorf("fatal error found during reading file. err : %w", err))
+		}
+	}
+
+	v.SetEnvPrefix(opt.EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+
+	for key, value := range opt.Overrides {
+		v.Set(key, value)
 	}
 
 	c := &configReader{
@@ -36,7 +59,56 @@ func Init(opt Options) Interface {
 }
 
 func (c *configReader) ReadConfig(cfg interface{}) {
+	// viper only reads env vars of keys it knows, so every field of the config is bound up front
+	c.bindEnvs(reflect.TypeOf(cfg), "")
+
 	if err := c.viper.Unmarshal(&cfg); err != nil {
 		panic(fmt.Errorf("fatal error found during unmarshaling config. err: %w", err))
 	}
 }
+
+func (c *configReader) bindEnvs(t reflect.Type, prefix string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		key := prefix + strings.ToLower(f.Name)
+		if f.Type.Kind() == reflect.Struct && f.Type != reflect.TypeOf(time.Time{}) {
+			c.bindEnvs(f.Type, key+".")
+			continue
+		}
+
+		c.viper.MustBindEnv(key)
+		if env, ok := f.Tag.Lookup("env"); ok {
+			c.viper.MustBindEnv(key, env)
+		}
+	}
+}
+
+// Overrides collects repeated key=value flags into Options.Overrides.
+type Overrides map[string]string
+
+func (o Overrides) String() string {
+	pairs := []string{}
+	for k, v := range o {
+		pairs = append(pairs, k+"="+v)
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+func (o Overrides) Set(value string) error {
+	kv := strings.SplitN(value, "=", 2)
+	if len(kv) != 2 || kv[0] == "" {
+		return fmt.Errorf("override %q must look like Section.Field=value", value)
+	}
+	o[kv[0]] = kv[1]
+
+	return nil
+}
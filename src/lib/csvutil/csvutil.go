@@ -0,0 +1,40 @@
+package csvutil
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+// formulaPrefixes are the leading characters spreadsheets read as the start of a formula.
+const formulaPrefixes = "=+-@\t\r"
+
+// Escape prefixes value with a quote when a spreadsheet would evaluate it as a formula.
+func Escape(value string) string {
+	if value != "" && strings.ContainsRune(formulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
+
+// Writer is a csv.Writer that escapes every field, exports hold user input that must not run in the reader's
+// spreadsheet.
+type Writer struct {
+	*csv.Writer
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		Writer: csv.NewWriter(w),
+	}
+}
+
+func (w *Writer) Write(record []string) error {
+	escaped := make([]string, len(record))
+	for i, field := range record {
+		escaped[i] = Escape(field)
+	}
+
+	return w.Writer.Write(escaped)
+}
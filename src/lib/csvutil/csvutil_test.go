@@ -0,0 +1,41 @@
+package csvutil
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "empty", value: "", want: ""},
+		{name: "plain text", value: "budi", want: "budi"},
+		{name: "equals", value: "=HYPERLINK(\"http://evil\")", want: "'=HYPERLINK(\"http://evil\")"},
+		{name: "plus", value: "+1+1", want: "'+1+1"},
+		{name: "minus", value: "-2+3", want: "'-2+3"},
+		{name: "at", value: "@SUM(A1)", want: "'@SUM(A1)"},
+		{name: "tab", value: "\t=1", want: "'\t=1"},
+		{name: "carriage return", value: "\r=1", want: "'\r=1"},
+		{name: "formula char inside", value: "a=b", want: "a=b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Escape(tt.value))
+		})
+	}
+}
+
+func TestWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := NewWriter(buf)
+
+	assert.NoError(t, w.Write([]string{"1", "=cmd", "budi"}))
+	w.Flush()
+	assert.NoError(t, w.Error())
+	assert.Equal(t, "1,'=cmd,budi\n", buf.String())
+}
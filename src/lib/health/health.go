@@ -0,0 +1,164 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK       = "ok"
+	StatusFail     = "fail"
+	StatusDraining = "draining"
+
+	defaultTimeout = 2 * time.Second
+)
+
+// Check reports an unhealthy dependency with an error, it must give up once ctx is done.
+type Check func(ctx context.Context) error
+
+type Interface interface {
+	// Register adds a readiness check, checks are run concurrently on every readiness probe.
+	Register(name string, check Check)
+	// Ready runs every check, the report fails as soon as one check fails or the service is draining.
+	Ready(ctx context.Context) Report
+	// Drain makes every following readiness probe fail so no new traffic is routed here.
+	Drain()
+}
+
+type Config struct {
+	// Timeout bounds every check, defaults to 2s
+	Timeout time.Duration
+	// CheckGateway adds the reachability of the payment gateway to the readiness checks
+	CheckGateway bool
+}
+
+func (c Config) Validate() error {
+	if c.Timeout < 0 {
+		return fmt.Errorf("Health.Timeout %v must not be negative", c.Timeout)
+	}
+
+	return nil
+}
+
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+type CheckResult struct {
+	Status   string `json:"status"`
+	Duration string `json:"duration"`
+	// Error is kept out of the response since it may describe the infrastructure
+	Error error `json:"-"`
+}
+
+type health struct {
+	cfg      Config
+	mu       sync.RWMutex
+	checks   map[string]Check
+	draining int32
+}
+
+func Init(cfg Config) Interface {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	return &health{
+		cfg:    cfg,
+		checks: make(map[string]Check),
+	}
+}
+
+func (h *health) Register(name string, check Check) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checks[name] = check
+}
+
+func (h *health) Drain() {
+	atomic.StoreInt32(&h.draining, 1)
+}
+
+func (h *health) Ready(ctx context.Context) Report {
+	h.mu.RLock()
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	checks := make([]Check, len(names))
+	for i, name := range names {
+		checks[i] = h.checks[name]
+	}
+	h.mu.RUnlock()
+
+	results := make([]CheckResult, len(checks))
+	wg := sync.WaitGroup{}
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = h.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := Report{
+		Status: StatusOK,
+		Checks: make(map[string]CheckResult),
+	}
+	for i, name := range names {
+		report.Checks[name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+
+	if atomic.LoadInt32(&h.draining) == 1 {
+		report.Status = StatusDraining
+	}
+
+	return report
+}
+
+func (h *health) run(ctx context.Context, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, h.cfg.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := CheckResult{
+		Status:   StatusOK,
+		Duration: time.Since(start).String(),
+		Error:    err,
+	}
+	if err != nil {
+		result.Status = StatusFail
+	}
+
+	return result
+}
+
+// WritableDirs checks that a file can be created in every directory.
+func WritableDirs(dirs ...string) Check {
+	return func(ctx context.Context) error {
+		for _, dir := range dirs {
+			f, err := os.CreateTemp(dir, ".healthcheck-*")
+			if err != nil {
+				return fmt.Errorf("%s isn't writable: %w", filepath.Clean(dir), err)
+			}
+			f.Close()
+			os.Remove(f.Name())
+		}
+
+		return nil
+	}
+}
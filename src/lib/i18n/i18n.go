@@ -0,0 +1,106 @@
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+
+	"golang.org/x/text/language"
+)
+
+const (
+	LangID = "id"
+	LangEN = "en"
+
+	DefaultLanguage = LangID
+)
+
+//go:embed locales/*.json
+var locales embed.FS
+
+var (
+	bundles = map[string]map[string]string{}
+
+	// the first tag is answered when nothing in Accept-Language matches
+	supported = []string{LangID, LangEN}
+	matcher   = language.NewMatcher([]language.Tag{language.Indonesian, language.English})
+)
+
+func init() {
+	for _, lang := range supported {
+		raw, err := locales.ReadFile(path.Join("locales", lang+".json"))
+		if err != nil {
+			panic(fmt.Errorf("failed to read %s bundle. err: %w", lang, err))
+		}
+
+		bundle := map[string]string{}
+		if err := json.Unmarshal(raw, &bundle); err != nil {
+			panic(fmt.Errorf("failed to parse %s bundle. err: %w", lang, err))
+		}
+		bundles[lang] = bundle
+	}
+}
+
+type languageKey struct{}
+
+// WithLanguage returns a copy of ctx whose messages are translated to lang.
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageKey{}, lang)
+}
+
+// Language is the language of ctx, DefaultLanguage when none was picked.
+func Language(ctx context.Context) string {
+	if lang, ok := ctx.Value(languageKey{}).(string); ok {
+		return lang
+	}
+
+	return DefaultLanguage
+}
+
+// ParseAcceptLanguage picks the supported language that best matches an Accept-Language header.
+func ParseAcceptLanguage(header string) string {
+	_, index := language.MatchStrings(matcher, header)
+	return supported[index]
+}
+
+// Lookup translates key to the language of ctx and formats it with args, a key missing from that language falls
+// back to DefaultLanguage.
+func Lookup(ctx context.Context, key string, args ...interface{}) (string, bool) {
+	message, ok := bundles[Language(ctx)][key]
+	if !ok {
+		message, ok = bundles[DefaultLanguage][key]
+	}
+	if !ok {
+		return "", false
+	}
+
+	if len(args) > 0 {
+		message = fmt.Sprintf(message, args...)
+	}
+
+	return message, true
+}
+
+// T translates key like Lookup, an unknown key is returned as is.
+func T(ctx context.Context, key string, args ...interface{}) string {
+	message, ok := Lookup(ctx, key, args...)
+	if !ok {
+		return key
+	}
+
+	return message
+}
+
+// Plural translates the form of key for n, key.one is used for exactly one when the language has it and key.other
+// otherwise. The message is formatted with n.
+func Plural(ctx context.Context, key string, n int) string {
+	if n == 1 {
+		if message, ok := Lookup(ctx, key+".one", n); ok {
+			return message
+		}
+	}
+
+	return T(ctx, key+".other", n)
+}
@@ -0,0 +1,254 @@
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+type contextKey string
+
+const (
+	contextFields contextKey = "LogFields"
+
+	redactedValue = "[REDACTED]"
+)
+
+type Level int
+
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+	FatalLevel
+)
+
+var levelNames = map[Level]string{
+	DebugLevel: "debug",
+	InfoLevel:  "info",
+	WarnLevel:  "warn",
+	ErrorLevel: "error",
+	FatalLevel: "fatal",
+}
+
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+// Fields are attached to an entry as top level keys, sensitive keys are redacted.
+type Fields map[string]interface{}
+
+type Config struct {
+	// Level is the lowest level written, debug, info, warn or error, defaults to info
+	Level string
+	// RedactKeys are redacted on top of the built in sensitive keys, matched case-insensitively
+	RedactKeys []string
+}
+
+func (c Config) Validate() error {
+	if _, err := parseLevel(c.Level); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type logger struct {
+	mu         sync.Mutex
+	out        io.Writer
+	level      Level
+	redactKeys map[string]bool
+	now        func() time.Time
+	exit       func(code int)
+}
+
+var defaultRedactKeys = []string{
+	"password",
+	"token",
+	"refresh_token",
+	"refreshtoken",
+	"authorization",
+	"code",
+	"email",
+	"phone",
+	"server_key",
+	"serverkey",
+}
+
+var std = newLogger(os.Stdout, Config{})
+
+func newLogger(out io.Writer, cfg Config) *logger {
+	level, err := parseLevel(cfg.Level)
+	if err != nil {
+		level = InfoLevel
+	}
+
+	redactKeys := make(map[string]bool)
+	for _, k := range append(defaultRedactKeys, cfg.RedactKeys...) {
+		redactKeys[strings.ToLower(k)] = true
+	}
+
+	return &logger{
+		out:        out,
+		level:      level,
+		redactKeys: redactKeys,
+		now:        time.Now,
+		exit:       os.Exit,
+	}
+}
+
+// Init replaces the package logger, entries are written to stdout as one JSON object per line.
+func Init(cfg Config) {
+	std = newLogger(os.Stdout, cfg)
+}
+
+func parseLevel(level string) (Level, error) {
+	if level == "" {
+		return InfoLevel, nil
+	}
+
+	for l, name := range levelNames {
+		if strings.EqualFold(level, name) {
+			return l, nil
+		}
+	}
+
+	return InfoLevel, fmt.Errorf("Log.Level %q must be debug, info, warn or error", level)
+}
+
+// WithFields returns a context whose entries carry the fields, e.g. the request id of a request.
+func WithFields(ctx context.Context, fields Fields) context.Context {
+	merged := Fields{}
+	for k, v := range contextFieldsOf(ctx) {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	return context.WithValue(ctx, contextFields, merged)
+}
+
+func contextFieldsOf(ctx context.Context) Fields {
+	if ctx == nil {
+		return nil
+	}
+
+	fields, _ := ctx.Value(contextFields).(Fields)
+	return fields
+}
+
+func Debug(ctx context.Context, msg string, fields ...Fields) {
+	std.write(ctx, DebugLevel, msg, fields)
+}
+
+func Info(ctx context.Context, msg string, fields ...Fields) {
+	std.write(ctx, InfoLevel, msg, fields)
+}
+
+func Warn(ctx context.Context, msg string, fields ...Fields) {
+	std.write(ctx, WarnLevel, msg, fields)
+}
+
+func Error(ctx context.Context, msg string, fields ...Fields) {
+	std.write(ctx, ErrorLevel, msg, fields)
+}
+
+// Fatal writes the entry and exits the process.
+func Fatal(ctx context.Context, msg string, fields ...Fields) {
+	std.write(ctx, FatalLevel, msg, fields)
+	std.exit(1)
+}
+
+func (l *logger) write(ctx context.Context, level Level, msg string, fields []Fields) {
+	if level < l.level {
+		return
+	}
+
+	entry := map[string]interface{}{}
+	for k, v := range contextFieldsOf(ctx) {
+		entry[k] = l.redact(k, v)
+	}
+	for _, f := range fields {
+		for k, v := range f {
+			entry[k] = l.redact(k, v)
+		}
+	}
+
+	entry["time"] = l.now().Format(time.RFC3339Nano)
+	entry["level"] = level.String()
+	entry["msg"] = msg
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		line, _ = json.Marshal(map[string]interface{}{
+			"time":  entry["time"],
+			"level": entry["level"],
+			"msg":   msg,
+			"error": fmt.Sprintf("failed to encode log fields: %v", err),
+		})
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.out.Write(append(line, '\n'))
+}
+
+// redact hides the value of sensitive keys, nested maps and structs are redacted through their JSON form.
+func (l *logger) redact(key string, v interface{}) interface{} {
+	if l.redactKeys[strings.ToLower(key)] {
+		return redactedValue
+	}
+
+	switch val := v.(type) {
+	case nil, string, bool, int, int64, uint, uint64, float64, time.Time:
+		return val
+	case error:
+		return val.Error()
+	case fmt.Stringer:
+		return val.String()
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return string(raw)
+	}
+
+	return l.redactValue(data)
+}
+
+// Redact hides the values of the sensitive keys in v, a value decoded from JSON, with the keys redacted from logs so
+// anything stored outside the logs hides the same fields.
+func Redact(v interface{}) interface{} {
+	return std.redactValue(v)
+}
+
+func (l *logger) redactValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			if l.redactKeys[strings.ToLower(k)] {
+				val[k] = redactedValue
+				continue
+			}
+			val[k] = l.redactValue(child)
+		}
+	case []interface{}:
+		for i, child := range val {
+			val[i] = l.redactValue(child)
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,228 @@
+package log
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_logger_redactValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		value  interface{}
+		want   interface{}
+	}{
+		{
+			name: "every default key",
+			value: map[string]interface{}{
+				"password":      "secret",
+				"token":         "jwt",
+				"refresh_token": "refresh",
+				"refreshtoken":  "refresh",
+				"authorization": "Bearer jwt",
+				"code":          "123456",
+				"email":         "buyer@mail.com",
+				"phone":         "08123456789",
+				"server_key":    "SB-Mid-server",
+				"serverkey":     "SB-Mid-server",
+			},
+			want: map[string]interface{}{
+				"password":      redactedValue,
+				"token":         redactedValue,
+				"refresh_token": redactedValue,
+				"refreshtoken":  redactedValue,
+				"authorization": redactedValue,
+				"code":          redactedValue,
+				"email":         redactedValue,
+				"phone":         redactedValue,
+				"server_key":    redactedValue,
+				"serverkey":     redactedValue,
+			},
+		},
+		{
+			name: "keys match case-insensitively",
+			value: map[string]interface{}{
+				"Password":      "secret",
+				"RefreshToken":  "refresh",
+				"AUTHORIZATION": "Bearer jwt",
+				"Email":         "buyer@mail.com",
+			},
+			want: map[string]interface{}{
+				"Password":      redactedValue,
+				"RefreshToken":  redactedValue,
+				"AUTHORIZATION": redactedValue,
+				"Email":         redactedValue,
+			},
+		},
+		{
+			name: "non-sensitive fields pass through",
+			value: map[string]interface{}{
+				"ID":       float64(1),
+				"Username": "buyer",
+				"IsAdmin":  false,
+				"Notes":    nil,
+			},
+			want: map[string]interface{}{
+				"ID":       float64(1),
+				"Username": "buyer",
+				"IsAdmin":  false,
+				"Notes":    nil,
+			},
+		},
+		{
+			name: "nested maps",
+			value: map[string]interface{}{
+				"User": map[string]interface{}{
+					"Username": "kitchen",
+					"Password": "hash",
+				},
+				"ResetCode": map[string]interface{}{
+					"Code":      "123456",
+					"ExpiresAt": "2026-10-19T00:00:00Z",
+				},
+			},
+			want: map[string]interface{}{
+				"User": map[string]interface{}{
+					"Username": "kitchen",
+					"Password": redactedValue,
+				},
+				"ResetCode": map[string]interface{}{
+					"Code":      redactedValue,
+					"ExpiresAt": "2026-10-19T00:00:00Z",
+				},
+			},
+		},
+		{
+			name: "slices",
+			value: []interface{}{
+				map[string]interface{}{"Username": "a", "Phone": "0811"},
+				map[string]interface{}{"Username": "b", "Phone": "0812"},
+				"plain",
+			},
+			want: []interface{}{
+				map[string]interface{}{"Username": "a", "Phone": redactedValue},
+				map[string]interface{}{"Username": "b", "Phone": redactedValue},
+				"plain",
+			},
+		},
+		{
+			name: "sensitive key holding a nested value",
+			value: map[string]interface{}{
+				"token": map[string]interface{}{"Value": "jwt"},
+			},
+			want: map[string]interface{}{
+				"token": redactedValue,
+			},
+		},
+		{
+			name:   "configured keys",
+			config: Config{RedactKeys: []string{"NIK"}},
+			value: map[string]interface{}{
+				"nik":      "3201",
+				"password": "secret",
+				"nama":     "buyer",
+			},
+			want: map[string]interface{}{
+				"nik":      redactedValue,
+				"password": redactedValue,
+				"nama":     "buyer",
+			},
+		},
+		{
+			name:  "scalar",
+			value: "buyer",
+			want:  "buyer",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLogger(io.Discard, tt.config)
+			assert.Equal(t, tt.want, l.redactValue(tt.value))
+		})
+	}
+}
+
+func Test_logger_redact(t *testing.T) {
+	type resetCode struct {
+		Code      string
+		ExpiresAt string
+	}
+
+	type invitation struct {
+		Username  string
+		Phone     string
+		ResetCode resetCode
+		Roles     []string
+	}
+
+	tests := []struct {
+		name  string
+		key   string
+		value interface{}
+		want  interface{}
+	}{
+		{
+			name:  "sensitive key",
+			key:   "Authorization",
+			value: "Bearer jwt",
+			want:  redactedValue,
+		},
+		{
+			name:  "plain value",
+			key:   "path",
+			value: "/api/v1/user",
+			want:  "/api/v1/user",
+		},
+		{
+			name: "nested structs",
+			key:  "invitation",
+			value: invitation{
+				Username:  "kitchen",
+				Phone:     "08123456789",
+				ResetCode: resetCode{Code: "123456", ExpiresAt: "2026-10-19T00:00:00Z"},
+				Roles:     []string{"kitchen_staff"},
+			},
+			want: map[string]interface{}{
+				"Username": "kitchen",
+				"Phone":    redactedValue,
+				"ResetCode": map[string]interface{}{
+					"Code":      redactedValue,
+					"ExpiresAt": "2026-10-19T00:00:00Z",
+				},
+				"Roles": []interface{}{"kitchen_staff"},
+			},
+		},
+		{
+			name: "slice of structs",
+			key:  "codes",
+			value: []resetCode{
+				{Code: "111111", ExpiresAt: "a"},
+				{Code: "222222", ExpiresAt: "b"},
+			},
+			want: []interface{}{
+				map[string]interface{}{"Code": redactedValue, "ExpiresAt": "a"},
+				map[string]interface{}{"Code": redactedValue, "ExpiresAt": "b"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLogger(io.Discard, Config{})
+			assert.Equal(t, tt.want, l.redact(tt.key, tt.value))
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	got := Redact(map[string]interface{}{
+		"Username": "buyer",
+		"Email":    "buyer@mail.com",
+	})
+
+	assert.Equal(t, map[string]interface{}{
+		"Username": "buyer",
+		"Email":    redactedValue,
+	}, got)
+}
@@ -0,0 +1,160 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	defaultPath = "/metrics"
+
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+type Config struct {
+	Enabled bool
+	// Path serves the metrics, defaults to /metrics
+	Path string
+	// Username and Password protect the endpoint with basic auth, they're required when enabled since the metrics are
+	// served on the api port
+	Username string
+	Password string
+}
+
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	errs := []string{}
+	if c.Path != "" && !strings.HasPrefix(c.Path, "/") {
+		errs = append(errs, "Metrics.Path must start with /")
+	}
+	if c.Username == "" || c.Password == "" {
+		errs = append(errs, "Metrics.Username and Metrics.Password are required when metrics are enabled")
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+func (c Config) GetPath() string {
+	if c.Path == "" {
+		return defaultPath
+	}
+
+	return c.Path
+}
+
+var registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Handled HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of the handled HTTP requests by method, route and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	OrdersCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "orders_created_total",
+		Help: "Created orders by payment method.",
+	}, []string{"method"})
+
+	Payments = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "payments_total",
+		Help: "Payment status changes by payment method and status.",
+	}, []string{"method", "status"})
+
+	WebhookNotifications = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "webhook_notifications_total",
+		Help: "Handled payment gateway notifications by outcome.",
+	}, []string{"outcome"})
+
+	MidtransRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "midtrans_request_duration_seconds",
+		Help:    "Latency of the Midtrans API calls by operation.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation"})
+
+	MidtransRequestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "midtrans_request_errors_total",
+		Help: "Failed Midtrans API calls by operation.",
+	}, []string{"operation"})
+
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Latency of the database queries by operation, table and outcome.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table", "outcome"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		OrdersCreated,
+		Payments,
+		WebhookNotifications,
+		MidtransRequestDuration,
+		MidtransRequestErrors,
+		DBQueryDuration,
+	)
+}
+
+// RegisterGauge exposes a gauge whose value is read on every scrape, a failing read reports no sample.
+func RegisterGauge(name string, help string, value func() (float64, error)) {
+	registry.MustRegister(&gaugeFunc{
+		desc:  prometheus.NewDesc(name, help, nil, nil),
+		value: value,
+	})
+}
+
+type gaugeFunc struct {
+	desc  *prometheus.Desc
+	value func() (float64, error)
+}
+
+func (g *gaugeFunc) Describe(ch chan<- *prometheus.Desc) {
+	ch <- g.desc
+}
+
+func (g *gaugeFunc) Collect(ch chan<- prometheus.Metric) {
+	v, err := g.value()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(g.desc, err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(g.desc, prometheus.GaugeValue, v)
+}
+
+// Handler serves every registered metric in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{
+		ErrorHandling: promhttp.ContinueOnError,
+	})
+}
+
+// Outcome labels the result of an operation by its error.
+func Outcome(err error) string {
+	if err != nil {
+		return OutcomeError
+	}
+
+	return OutcomeSuccess
+}
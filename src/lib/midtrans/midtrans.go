@@ -1,23 +1,45 @@
 package midtrans
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"go-clean/src/lib/metrics"
+	"go-clean/src/lib/tracing"
+	"net/http"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	midtransSdk "github.com/midtrans/midtrans-go"
 	"github.com/midtrans/midtrans-go/coreapi"
 )
 
+const (
+	operationCharge = "charge"
+	operationCheck  = "check"
+)
+
 type Interface interface {
-	CreateOrder(param CreateOrderParam) (*coreapi.ChargeResponse, error)
-	HandleNotification(id string) (*coreapi.TransactionStatusResponse, error)
+	CreateOrder(ctx context.Context, param CreateOrderParam) (*coreapi.ChargeResponse, error)
+	HandleNotification(ctx context.Context, id string) (*coreapi.TransactionStatusResponse, error)
+	// Ping checks that the API is reachable, any HTTP response counts.
+	Ping(ctx context.Context) error
 }
 
 type Config struct {
 	ServerKey string
 }
 
+func (c Config) Validate() error {
+	if c.ServerKey == "" {
+		return errors.New("Midtrans.ServerKey (MIDTRANS_SERVERKEY) is required")
+	}
+
+	return nil
+}
+
 type midtrans struct {
 	conf    Config
 	coreapi *coreapi.Client
@@ -37,8 +59,7 @@ func (m *midtrans) connect() {
 	m.coreapi = &c
 }
 
-func (m *midtrans) CreateOrder(param CreateOrderParam) (*coreapi.ChargeResponse, error) {
-	fmt.Printf("isi param : %#v\n", param)
+func (m *midtrans) CreateOrder(ctx context.Context, param CreateOrderParam) (*coreapi.ChargeResponse, error) {
 	chargeReq := &coreapi.ChargeReq{
 		TransactionDetails: midtransSdk.TransactionDetails{
 			OrderID:  fmt.Sprintf("%s-%d-%d", "CL", param.OrderID, time.Now().Unix()),
@@ -57,19 +78,56 @@ func (m *midtrans) CreateOrder(param CreateOrderParam) (*coreapi.ChargeResponse,
 		return &coreapi.ChargeResponse{}, errors.New("undeifned payment method")
 	}
 
+	_, span := tracing.Start(ctx, "midtrans.charge", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("midtrans.order_id", chargeReq.TransactionDetails.OrderID),
+		attribute.String("midtrans.payment_type", string(chargeReq.PaymentType)),
+	))
+	start := time.Now()
 	coreApiRes, err := m.coreapi.ChargeTransaction(chargeReq)
+	observe(operationCharge, start, err != nil)
 	if err != nil {
+		tracing.End(span, err)
 		return coreApiRes, err
 	}
+	span.End()
 
 	return coreApiRes, nil
 }
 
-func (m *midtrans) HandleNotification(id string) (*coreapi.TransactionStatusResponse, error) {
+func (m *midtrans) HandleNotification(ctx context.Context, id string) (*coreapi.TransactionStatusResponse, error) {
+	_, span := tracing.Start(ctx, "midtrans.check", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("midtrans.order_id", id),
+	))
+	start := time.Now()
 	midtransReport, err := m.coreapi.CheckTransaction(id)
+	observe(operationCheck, start, err != nil)
 	if err != nil {
+		tracing.End(span, err)
 		return midtransReport, err
 	}
+	span.End()
 
 	return midtransReport, nil
 }
+
+func (m *midtrans) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, midtransSdk.Sandbox.BaseUrl(), nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+
+	return nil
+}
+
+func observe(operation string, start time.Time, failed bool) {
+	metrics.MidtransRequestDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	if failed {
+		metrics.MidtransRequestErrors.WithLabelValues(operation).Inc()
+	}
+}
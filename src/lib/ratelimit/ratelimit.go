@@ -0,0 +1,178 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	StoreMemory = "memory"
+	StoreSQL    = "sql"
+
+	KeyByIP   = "ip"
+	KeyByUser = "user"
+)
+
+type Interface interface {
+	// Allow takes a token from the bucket of the key under the named policy, unknown policies always allow.
+	Allow(ctx context.Context, policy string, key string) (Result, error)
+	// Policy returns the named policy, the config reader lowercases names so they're matched case-insensitively.
+	Policy(name string) (Policy, bool)
+}
+
+type Config struct {
+	Enabled bool
+	// Store is either memory (per instance) or sql (shared by every instance using the database)
+	Store    string
+	Policies map[string]Policy
+}
+
+func (c Config) Validate() error {
+	errs := []string{}
+	if c.Store != "" && c.Store != StoreMemory && c.Store != StoreSQL {
+		errs = append(errs, fmt.Sprintf("RateLimit.Store %q must be %s or %s", c.Store, StoreMemory, StoreSQL))
+	}
+
+	names := make([]string, 0, len(c.Policies))
+	for name := range c.Policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		p := c.Policies[name]
+		if p.Limit <= 0 || p.Period <= 0 {
+			errs = append(errs, fmt.Sprintf("RateLimit.Policies.%s needs a positive Limit and Period", name))
+		}
+		if p.KeyBy != "" && p.KeyBy != KeyByIP && p.KeyBy != KeyByUser {
+			errs = append(errs, fmt.Sprintf("RateLimit.Policies.%s.KeyBy %q must be %s or %s", name, p.KeyBy, KeyByIP, KeyByUser))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// Policy allows Limit requests per Period, with bursts up to Burst requests (defaults to Limit).
+type Policy struct {
+	Limit  int
+	Period time.Duration
+	Burst  int
+	// KeyBy is ip to count every request of an address together, or user to count per user or guest
+	// session and fall back to the ip for anonymous requests
+	KeyBy string
+}
+
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// Store keeps the buckets, a shared store lets every instance enforce the same limit.
+type Store interface {
+	Take(ctx context.Context, key string, policy Policy, now time.Time) (Result, error)
+}
+
+type rateLimit struct {
+	cfg   Config
+	store Store
+}
+
+func Init(cfg Config, store Store) Interface {
+	policies := make(map[string]Policy)
+	for name, p := range cfg.Policies {
+		if p.Burst == 0 {
+			p.Burst = p.Limit
+		}
+		if p.KeyBy == "" {
+			p.KeyBy = KeyByUser
+		}
+		policies[strings.ToLower(name)] = p
+	}
+	cfg.Policies = policies
+
+	return &rateLimit{
+		cfg:   cfg,
+		store: store,
+	}
+}
+
+func (r *rateLimit) Allow(ctx context.Context, policy string, key string) (Result, error) {
+	p, ok := r.Policy(policy)
+	if !ok {
+		return Result{Allowed: true}, nil
+	}
+
+	return r.store.Take(ctx, strings.ToLower(policy)+":"+key, p, time.Now())
+}
+
+func (r *rateLimit) Policy(name string) (Policy, bool) {
+	if !r.cfg.Enabled {
+		return Policy{}, false
+	}
+
+	p, ok := r.cfg.Policies[strings.ToLower(name)]
+	if !ok || p.Limit <= 0 || p.Period <= 0 {
+		return Policy{}, false
+	}
+
+	return p, true
+}
+
+// bucket is a token bucket refilled continuously at Limit tokens per Period.
+type bucket struct {
+	Tokens     float64
+	RefilledAt time.Time
+}
+
+func newBucket(policy Policy, now time.Time) bucket {
+	return bucket{
+		Tokens:     float64(policy.Burst),
+		RefilledAt: now,
+	}
+}
+
+func (b *bucket) take(policy Policy, now time.Time) Result {
+	rate := float64(policy.Limit) / policy.Period.Seconds()
+
+	if elapsed := now.Sub(b.RefilledAt).Seconds(); elapsed > 0 {
+		b.Tokens = math.Min(float64(policy.Burst), b.Tokens+elapsed*rate)
+		b.RefilledAt = now
+	}
+
+	res := Result{
+		Limit: policy.Burst,
+	}
+
+	if b.Tokens >= 1 {
+		b.Tokens--
+		res.Allowed = true
+		res.Remaining = int(b.Tokens)
+		return res
+	}
+
+	res.RetryAfter = time.Duration((1 - b.Tokens) / rate * float64(time.Second))
+	return res
+}
+
+// full reports whether the bucket has refilled completely, such a bucket can be dropped and recreated later.
+func (b *bucket) full(policy Policy, now time.Time) bool {
+	return !now.Before(b.fullAt(policy))
+}
+
+// fullAt is when the bucket will have refilled completely.
+func (b *bucket) fullAt(policy Policy) time.Time {
+	rate := float64(policy.Limit) / policy.Period.Seconds()
+	missing := math.Max(0, float64(policy.Burst)-b.Tokens)
+	return b.RefilledAt.Add(time.Duration(missing / rate * float64(time.Second)))
+}
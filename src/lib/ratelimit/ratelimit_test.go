@@ -0,0 +1,206 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_bucket_take(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	// 60 per minute refills a token every second
+	policy := Policy{Limit: 60, Period: time.Minute, Burst: 5}
+
+	tests := []struct {
+		name       string
+		bucket     bucket
+		now        time.Time
+		want       Result
+		wantTokens float64
+	}{
+		{
+			name:       "new bucket allows a burst",
+			bucket:     newBucket(policy, now),
+			now:        now,
+			want:       Result{Allowed: true, Limit: 5, Remaining: 4},
+			wantTokens: 4,
+		},
+		{
+			name:       "last token",
+			bucket:     bucket{Tokens: 1, RefilledAt: now},
+			now:        now,
+			want:       Result{Allowed: true, Limit: 5, Remaining: 0},
+			wantTokens: 0,
+		},
+		{
+			name:       "empty bucket waits for the next token",
+			bucket:     bucket{Tokens: 0, RefilledAt: now},
+			now:        now,
+			want:       Result{Allowed: false, Limit: 5, RetryAfter: time.Second},
+			wantTokens: 0,
+		},
+		{
+			name:       "partly refilled bucket waits for the rest of the token",
+			bucket:     bucket{Tokens: 0, RefilledAt: now},
+			now:        now.Add(250 * time.Millisecond),
+			want:       Result{Allowed: false, Limit: 5, RetryAfter: 750 * time.Millisecond},
+			wantTokens: 0.25,
+		},
+		{
+			name:       "refills with elapsed time",
+			bucket:     bucket{Tokens: 0, RefilledAt: now},
+			now:        now.Add(3 * time.Second),
+			want:       Result{Allowed: true, Limit: 5, Remaining: 2},
+			wantTokens: 2,
+		},
+		{
+			name:       "refill is capped at the burst",
+			bucket:     bucket{Tokens: 0, RefilledAt: now},
+			now:        now.Add(time.Hour),
+			want:       Result{Allowed: true, Limit: 5, Remaining: 4},
+			wantTokens: 4,
+		},
+		{
+			name:       "clock going back doesn't refill",
+			bucket:     bucket{Tokens: 0, RefilledAt: now},
+			now:        now.Add(-time.Minute),
+			want:       Result{Allowed: false, Limit: 5, RetryAfter: time.Second},
+			wantTokens: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.bucket
+			got := b.take(policy, tt.now)
+			assert.Equal(t, tt.want.Allowed, got.Allowed)
+			assert.Equal(t, tt.want.Limit, got.Limit)
+			assert.Equal(t, tt.want.Remaining, got.Remaining)
+			assert.InDelta(t, float64(tt.want.RetryAfter), float64(got.RetryAfter), float64(time.Millisecond))
+			assert.InDelta(t, tt.wantTokens, b.Tokens, 0.0001)
+		})
+	}
+}
+
+func Test_bucket_full(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	policy := Policy{Limit: 60, Period: time.Minute, Burst: 5}
+
+	b := bucket{Tokens: 2, RefilledAt: now}
+
+	assert.Equal(t, now.Add(3*time.Second), b.fullAt(policy))
+	assert.False(t, b.full(policy, now.Add(2*time.Second)))
+	assert.True(t, b.full(policy, now.Add(3*time.Second)))
+}
+
+func Test_rateLimit_Policy(t *testing.T) {
+	policies := map[string]Policy{
+		"Default": {Limit: 10, Period: time.Minute},
+		"auth":    {Limit: 5, Period: time.Minute, Burst: 8, KeyBy: KeyByIP},
+		"broken":  {Limit: 0, Period: time.Minute},
+	}
+
+	tests := []struct {
+		name   string
+		cfg    Config
+		policy string
+		want   Policy
+		wantOk bool
+	}{
+		{
+			name:   "disabled",
+			cfg:    Config{Enabled: false, Policies: policies},
+			policy: "default",
+			wantOk: false,
+		},
+		{
+			name:   "unknown policy",
+			cfg:    Config{Enabled: true, Policies: policies},
+			policy: "checkout",
+			wantOk: false,
+		},
+		{
+			name:   "policy without a limit",
+			cfg:    Config{Enabled: true, Policies: policies},
+			policy: "broken",
+			wantOk: false,
+		},
+		{
+			name:   "defaults burst to limit and keys by user",
+			cfg:    Config{Enabled: true, Policies: policies},
+			policy: "DEFAULT",
+			want:   Policy{Limit: 10, Period: time.Minute, Burst: 10, KeyBy: KeyByUser},
+			wantOk: true,
+		},
+		{
+			name:   "keeps configured burst and key",
+			cfg:    Config{Enabled: true, Policies: policies},
+			policy: "auth",
+			want:   Policy{Limit: 5, Period: time.Minute, Burst: 8, KeyBy: KeyByIP},
+			wantOk: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Init(tt.cfg, NewMemoryStore())
+			got, ok := r.Policy(tt.policy)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_rateLimit_Allow(t *testing.T) {
+	r := Init(Config{
+		Enabled: true,
+		Policies: map[string]Policy{
+			"auth": {Limit: 1, Period: time.Hour},
+		},
+	}, NewMemoryStore())
+
+	got, err := r.Allow(context.Background(), "unknown", "ip:127.0.0.1")
+	assert.NoError(t, err)
+	assert.True(t, got.Allowed)
+
+	got, err = r.Allow(context.Background(), "auth", "ip:127.0.0.1")
+	assert.NoError(t, err)
+	assert.True(t, got.Allowed)
+
+	got, err = r.Allow(context.Background(), "AUTH", "ip:127.0.0.1")
+	assert.NoError(t, err)
+	assert.False(t, got.Allowed)
+
+	got, err = r.Allow(context.Background(), "auth", "ip:10.0.0.1")
+	assert.NoError(t, err)
+	assert.True(t, got.Allowed)
+}
+
+func Test_memoryStore_sweep(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	policy := Policy{Limit: 60, Period: time.Minute, Burst: 60}
+
+	m := NewMemoryStore().(*memoryStore)
+
+	// drains the busy bucket and takes a single token of the idle one
+	for i := 0; i < 60; i++ {
+		_, err := m.Take(context.Background(), "busy", policy, now)
+		assert.NoError(t, err)
+	}
+	_, err := m.Take(context.Background(), "idle", policy, now)
+	assert.NoError(t, err)
+
+	// no sweep before sweepInterval
+	_, err = m.Take(context.Background(), "busy", policy, now.Add(time.Second))
+	assert.NoError(t, err)
+	assert.Len(t, m.buckets, 2)
+
+	// the idle bucket refilled after a second, the busy one needs a minute
+	m.lastSweep = now
+	_, err = m.Take(context.Background(), "other", policy, now.Add(sweepInterval))
+	assert.NoError(t, err)
+	assert.Contains(t, m.buckets, "other")
+	assert.Contains(t, m.buckets, "busy")
+	assert.NotContains(t, m.buckets, "idle")
+	assert.Equal(t, now.Add(sweepInterval), m.lastSweep)
+}
@@ -0,0 +1,145 @@
+package ratelimit
+
+import (
+	"context"
+	"go-clean/src/lib/log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const sweepInterval = time.Minute
+
+type memoryEntry struct {
+	bucket bucket
+	policy Policy
+}
+
+type memoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryEntry
+	lastSweep time.Time
+}
+
+// NewMemoryStore keeps the buckets in the process, every instance enforces its own limit.
+func NewMemoryStore() Store {
+	return &memoryStore{
+		buckets: make(map[string]*memoryEntry),
+	}
+}
+
+func (m *memoryStore) Take(ctx context.Context, key string, policy Policy, now time.Time) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if now.Sub(m.lastSweep) >= sweepInterval {
+		m.sweep(now)
+	}
+
+	e, ok := m.buckets[key]
+	if !ok {
+		e = &memoryEntry{
+			bucket: newBucket(policy, now),
+		}
+		m.buckets[key] = e
+	}
+	e.policy = policy
+
+	return e.bucket.take(policy, now), nil
+}
+
+// sweep drops the buckets that refilled completely so idle keys don't pile up.
+func (m *memoryStore) sweep(now time.Time) {
+	for key, e := range m.buckets {
+		if e.bucket.full(e.policy, now) {
+			delete(m.buckets, key)
+		}
+	}
+	m.lastSweep = now
+}
+
+type sqlBucket struct {
+	Key        string `gorm:"type:varchar(191);primaryKey"`
+	Tokens     float64
+	RefilledAt time.Time
+	FullAt     time.Time `gorm:"index"`
+}
+
+func (sqlBucket) TableName() string {
+	return "rate_limit_buckets"
+}
+
+type sqlStore struct {
+	db        *gorm.DB
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+// NewSQLStore keeps the buckets in the database so every instance shares the same limit.
+func NewSQLStore(db *gorm.DB) Store {
+	if err := db.AutoMigrate(&sqlBucket{}); err != nil {
+		panic(err)
+	}
+
+	return &sqlStore{
+		db: db,
+	}
+}
+
+func (s *sqlStore) Take(ctx context.Context, key string, policy Policy, now time.Time) (Result, error) {
+	res := Result{}
+
+	s.sweep(ctx, now)
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		b := newBucket(policy, now)
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&sqlBucket{
+			Key:        key,
+			Tokens:     b.Tokens,
+			RefilledAt: b.RefilledAt,
+			FullAt:     b.fullAt(policy),
+		}).Error; err != nil {
+			return err
+		}
+
+		row := sqlBucket{}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("`key` = ?", key).First(&row).Error; err != nil {
+			return err
+		}
+
+		b = bucket{
+			Tokens:     row.Tokens,
+			RefilledAt: row.RefilledAt,
+		}
+		res = b.take(policy, now)
+
+		return tx.Model(&sqlBucket{}).Where("`key` = ?", key).Updates(map[string]interface{}{
+			"tokens":      b.Tokens,
+			"refilled_at": b.RefilledAt,
+			"full_at":     b.fullAt(policy),
+		}).Error
+	})
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// sweep deletes the buckets that have been full for a sweepInterval so idle keys don't pile up, each instance sweeps at
+// most once per sweepInterval. A failed sweep is retried on the next one and doesn't fail the request.
+func (s *sqlStore) sweep(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastSweep) < sweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = now
+	s.mu.Unlock()
+
+	if err := s.db.WithContext(ctx).Where("full_at < ?", now.Add(-sweepInterval)).Delete(&sqlBucket{}).Error; err != nil {
+		log.Error(ctx, "failed to sweep rate limit buckets", log.Fields{"error": err})
+	}
+}
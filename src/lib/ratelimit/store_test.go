@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func Test_sqlStore_Take(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	policy := Policy{Limit: 60, Period: time.Minute, Burst: 5}
+
+	sqlServer, sqlMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlServer.Close()
+
+	sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlServer,
+		SkipInitializeWithVersion: true,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &sqlStore{db: sqlClient}
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec(regexp.QuoteMeta("DELETE FROM `rate_limit_buckets` WHERE full_at < ?")).
+		WithArgs(now.Add(-sweepInterval)).WillReturnResult(sqlmock.NewResult(0, 3))
+	sqlMock.ExpectCommit()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO `rate_limit_buckets`")).
+		WithArgs("auth:ip:127.0.0.1", float64(5), now, now).WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `rate_limit_buckets` WHERE `key` = ? ORDER BY `rate_limit_buckets`.`key` LIMIT 1 FOR UPDATE")).
+		WithArgs("auth:ip:127.0.0.1").
+		WillReturnRows(sqlmock.NewRows([]string{"key", "tokens", "refilled_at", "full_at"}).AddRow("auth:ip:127.0.0.1", 0.5, now.Add(-time.Second), now.Add(3500*time.Millisecond)))
+	sqlMock.ExpectExec(regexp.QuoteMeta("UPDATE `rate_limit_buckets` SET `full_at`=?,`refilled_at`=?,`tokens`=? WHERE `key` = ?")).
+		WithArgs(now.Add(4500*time.Millisecond), now, 0.5, "auth:ip:127.0.0.1").WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectCommit()
+
+	got, err := s.Take(context.Background(), "auth:ip:127.0.0.1", policy, now)
+	assert.NoError(t, err)
+	assert.Equal(t, Result{Allowed: true, Limit: 5, Remaining: 0}, got)
+	assert.Equal(t, now, s.lastSweep)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func Test_sqlStore_sweep(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	sqlServer, sqlMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlServer.Close()
+
+	sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlServer,
+		SkipInitializeWithVersion: true,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &sqlStore{db: sqlClient, lastSweep: now}
+
+	// swept recently
+	s.sweep(context.Background(), now.Add(time.Second))
+	assert.Equal(t, now, s.lastSweep)
+
+	// a failed sweep is retried on the next interval
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec(regexp.QuoteMeta("DELETE FROM `rate_limit_buckets` WHERE full_at < ?")).WillReturnError(assert.AnError)
+	sqlMock.ExpectRollback()
+	s.sweep(context.Background(), now.Add(sweepInterval))
+	assert.Equal(t, now.Add(sweepInterval), s.lastSweep)
+
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
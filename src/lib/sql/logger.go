@@ -0,0 +1,48 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"go-clean/src/lib/log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const defaultSlowQueryThreshold = 200 * time.Millisecond
+
+// gormLogger writes gorm errors and slow queries through lib/log, the statement itself is left out
+// since its bound values can hold personal data.
+type gormLogger struct {
+	slowThreshold time.Duration
+}
+
+func (g *gormLogger) LogMode(logger.LogLevel) logger.Interface {
+	return g
+}
+
+func (g *gormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	log.Info(ctx, msg, log.Fields{"source": "gorm"})
+}
+
+func (g *gormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	log.Warn(ctx, msg, log.Fields{"source": "gorm"})
+}
+
+func (g *gormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	log.Error(ctx, msg, log.Fields{"source": "gorm"})
+}
+
+func (g *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	elapsed := time.Since(begin)
+
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		_, rows := fc()
+		log.Error(ctx, "sql query failed", log.Fields{"error": err, "rows": rows, "elapsed": elapsed})
+	case elapsed > g.slowThreshold:
+		_, rows := fc()
+		log.Warn(ctx, "slow sql query", log.Fields{"rows": rows, "elapsed": elapsed})
+	}
+}
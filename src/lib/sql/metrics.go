@@ -0,0 +1,74 @@
+package sql
+
+import (
+	"errors"
+	"go-clean/src/lib/metrics"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const metricsStartKey = "metrics:start"
+
+// metricsPlugin observes the duration of every query in metrics.DBQueryDuration.
+type metricsPlugin struct{}
+
+func (metricsPlugin) Name() string {
+	return "metrics"
+}
+
+func (p metricsPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	callbacks := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+
+	for _, c := range callbacks {
+		if err := c.before("metrics:before_"+c.operation, p.start); err != nil {
+			return err
+		}
+		if err := c.after("metrics:after_"+c.operation, p.observe(c.operation)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (metricsPlugin) start(db *gorm.DB) {
+	db.InstanceSet(metricsStartKey, time.Now())
+}
+
+func (metricsPlugin) observe(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.InstanceGet(metricsStartKey)
+		if !ok {
+			return
+		}
+		start, ok := v.(time.Time)
+		if !ok {
+			return
+		}
+
+		err := db.Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = nil
+		}
+
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+
+		metrics.DBQueryDuration.WithLabelValues(operation, table, metrics.Outcome(err)).Observe(time.Since(start).Seconds())
+	}
+}
@@ -1,33 +1,224 @@
 package sql
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"go-clean/src/business/entity"
+	"strings"
+	"time"
 
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
+// mysqlDuplicateEntry is the error number of a row breaking a unique index.
+const mysqlDuplicateEntry = 1062
+
 type Config struct {
 	Host     string
 	Username string
 	Password string
 	Port     string
 	Database string
+	// SlowQueryThreshold logs queries slower than it as warnings, defaults to 200ms
+	SlowQueryThreshold time.Duration
+}
+
+func (c Config) Validate() error {
+	missing := []string{}
+	if c.Host == "" {
+		missing = append(missing, "SQL.Host")
+	}
+	if c.Port == "" {
+		missing = append(missing, "SQL.Port")
+	}
+	if c.Username == "" {
+		missing = append(missing, "SQL.Username")
+	}
+	if c.Database == "" {
+		missing = append(missing, "SQL.Database")
+	}
+
+	if len(missing) == 1 {
+		return fmt.Errorf("%s is required", missing[0])
+	} else if len(missing) > 1 {
+		return fmt.Errorf("%s are required", strings.Join(missing, ", "))
+	}
+
+	return nil
 }
 
 func Init(cfg Config) *gorm.DB {
+	slowThreshold := cfg.SlowQueryThreshold
+	if slowThreshold == 0 {
+		slowThreshold = defaultSlowQueryThreshold
+	}
+
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", cfg.Username, cfg.Password, cfg.Host, cfg.Port, cfg.Database)
 	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
 		DisableForeignKeyConstraintWhenMigrating: true,
+		Logger:                                   &gormLogger{slowThreshold: slowThreshold},
 	})
 	if err != nil {
 		panic(err)
 	}
 
-	if err := db.AutoMigrate(&entity.User{}, &entity.Umkm{}, &entity.Menu{}, &entity.Cart{}, &entity.Transaction{}, &entity.MidtransTransaction{}, &entity.Withdraw{}); err != nil {
+	if err := db.Use(metricsPlugin{}); err != nil {
+		panic(err)
+	}
+
+	if err := db.Use(tracingPlugin{}); err != nil {
+		panic(err)
+	}
+
+	if err := checkColumnLengths(db); err != nil {
+		panic(err)
+	}
+
+	if err := checkUniqueColumns(db); err != nil {
+		panic(err)
+	}
+
+	if err := db.AutoMigrate(models()...); err != nil {
+		panic(err)
+	}
+
+	if err := createIndexes(db); err != nil {
 		panic(err)
 	}
 
 	return db
 }
+
+func models() []interface{} {
+	return []interface{}{&entity.User{}, &entity.Umkm{}, &entity.Menu{}, &entity.Cart{}, &entity.Transaction{}, &entity.MidtransTransaction{}, &entity.Withdraw{}, &entity.RefreshToken{}, &entity.RevokedToken{}, &entity.PasswordResetCode{}, &entity.LoginEvent{}, &entity.AuditLog{}}
+}
+
+// shrunkColumns were text columns before they got a length to be indexed, AutoMigrate would truncate or reject
+// longer values.
+var shrunkColumns = []struct {
+	table  string
+	column string
+	length int
+}{
+	{table: "users", column: "username", length: 191},
+	{table: "transactions", column: "buyer_name", length: 191},
+	{table: "transactions", column: "seat", length: 64},
+	{table: "transactions", column: "guest_id", length: 32},
+	{table: "menus", column: "name", length: 191},
+	{table: "midtrans_transactions", column: "order_id", length: 64},
+	{table: "midtrans_transactions", column: "status", length: 32},
+}
+
+// checkColumnLengths refuses to migrate while a shrunk column holds a longer value, it has to be shortened by hand.
+func checkColumnLengths(db *gorm.DB) error {
+	for _, c := range shrunkColumns {
+		var columns int64
+		if err := db.Raw("SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? AND column_name = ?", c.table, c.column).Scan(&columns).Error; err != nil {
+			return fmt.Errorf("failed to look up %s.%s. err: %w", c.table, c.column, err)
+		}
+		if columns == 0 {
+			continue
+		}
+
+		var count int64
+		if err := db.Table(c.table).Where(fmt.Sprintf("CHAR_LENGTH(%s) > ?", c.column), c.length).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check the length of %s.%s. err: %w", c.table, c.column, err)
+		}
+		if count > 0 {
+			return fmt.Errorf("%d rows of %s.%s are longer than %d characters, shorten them before migrating", count, c.table, c.column, c.length)
+		}
+	}
+
+	return nil
+}
+
+// uniqueColumns got a unique index after rows were stored, AutoMigrate fails on duplicates without naming them.
+var uniqueColumns = []struct {
+	table  string
+	column string
+}{
+	{table: "users", column: "username"},
+}
+
+// checkUniqueColumns refuses to migrate while a column about to be unique holds duplicates, they have to be renamed by
+// hand. Soft deleted rows count too, the index covers them.
+func checkUniqueColumns(db *gorm.DB) error {
+	for _, c := range uniqueColumns {
+		var columns int64
+		if err := db.Raw("SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? AND column_name = ?", c.table, c.column).Scan(&columns).Error; err != nil {
+			return fmt.Errorf("failed to look up %s.%s. err: %w", c.table, c.column, err)
+		}
+		if columns == 0 {
+			continue
+		}
+
+		var count int64
+		if err := db.Raw(fmt.Sprintf("SELECT COUNT(*) FROM (SELECT %s FROM %s GROUP BY %s HAVING COUNT(*) > 1) AS duplicates", c.column, c.table, c.column)).Scan(&count).Error; err != nil {
+			return fmt.Errorf("failed to check duplicates of %s.%s. err: %w", c.table, c.column, err)
+		}
+		if count > 0 {
+			return fmt.Errorf("%d values of %s.%s are duplicated, rename them before migrating", count, c.table, c.column)
+		}
+	}
+
+	return nil
+}
+
+// IsDuplicateKey reports whether err is MySQL rejecting a row that breaks a unique index.
+func IsDuplicateKey(err error) bool {
+	var mysqlErr *mysqldriver.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry
+}
+
+// indexes covers the columns of gorm.Model that are filtered on, AutoMigrate can't index them.
+var indexes = []struct {
+	table   string
+	name    string
+	columns string
+}{
+	{table: "transactions", name: "idx_transactions_created_at", columns: "created_at"},
+}
+
+func createIndexes(db *gorm.DB) error {
+	migrator := db.Migrator()
+	for _, idx := range indexes {
+		if migrator.HasIndex(idx.table, idx.name) {
+			continue
+		}
+
+		if err := db.Exec(fmt.Sprintf("CREATE INDEX %s ON %s (%s)", idx.name, idx.table, idx.columns)).Error; err != nil {
+			return fmt.Errorf("failed to create index %s. err: %w", idx.name, err)
+		}
+	}
+
+	return nil
+}
+
+// Ping checks that the database accepts connections.
+func Ping(db *gorm.DB) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+
+		return sqlDB.PingContext(ctx)
+	}
+}
+
+// CheckMigrations checks that the table of every model exists.
+func CheckMigrations(db *gorm.DB) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		migrator := db.WithContext(ctx).Migrator()
+		for _, model := range models() {
+			if !migrator.HasTable(model) {
+				return fmt.Errorf("table of %T is missing", model)
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,222 @@
+package sql
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func Test_checkColumnLengths(t *testing.T) {
+	const (
+		columnQuery = `SELECT COUNT\(\*\) FROM information_schema.columns WHERE table_schema = DATABASE\(\) AND table_name = \? AND column_name = \?`
+		lengthQuery = "SELECT count\\(\\*\\) FROM `%s` WHERE CHAR_LENGTH\\(%s\\) > \\?"
+	)
+
+	expectColumn := func(sqlMock sqlmock.Sqlmock, table string, column string, exists bool) {
+		count := 0
+		if exists {
+			count = 1
+		}
+		sqlMock.ExpectQuery(columnQuery).WithArgs(table, column).WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(count))
+	}
+	expectLength := func(sqlMock sqlmock.Sqlmock, table string, column string, length int, count int) {
+		sqlMock.ExpectQuery(fmt.Sprintf(lengthQuery, table, column)).WithArgs(length).WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(count))
+	}
+
+	tests := []struct {
+		name     string
+		mockFunc func(sqlMock sqlmock.Sqlmock)
+		wantErr  bool
+	}{
+		{
+			name: "new database",
+			mockFunc: func(sqlMock sqlmock.Sqlmock) {
+				for _, c := range shrunkColumns {
+					expectColumn(sqlMock, c.table, c.column, false)
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "every value fits",
+			mockFunc: func(sqlMock sqlmock.Sqlmock) {
+				for _, c := range shrunkColumns {
+					expectColumn(sqlMock, c.table, c.column, true)
+					expectLength(sqlMock, c.table, c.column, c.length, 0)
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "buyer name too long",
+			mockFunc: func(sqlMock sqlmock.Sqlmock) {
+				expectColumn(sqlMock, "users", "username", false)
+				expectColumn(sqlMock, "transactions", "buyer_name", true)
+				expectLength(sqlMock, "transactions", "buyer_name", 191, 2)
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to look up the column",
+			mockFunc: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectQuery(columnQuery).WillReturnError(assert.AnError)
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to check the length",
+			mockFunc: func(sqlMock sqlmock.Sqlmock) {
+				expectColumn(sqlMock, "users", "username", true)
+				sqlMock.ExpectQuery(fmt.Sprintf(lengthQuery, "users", "username")).WillReturnError(assert.AnError)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, sqlMock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sqlServer.Close()
+
+			tt.mockFunc(sqlMock)
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = checkColumnLengths(sqlClient)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkColumnLengths() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_checkUniqueColumns(t *testing.T) {
+	const (
+		columnQuery    = `SELECT COUNT\(\*\) FROM information_schema.columns WHERE table_schema = DATABASE\(\) AND table_name = \? AND column_name = \?`
+		duplicateQuery = `SELECT COUNT\(\*\) FROM \(SELECT username FROM users GROUP BY username HAVING COUNT\(\*\) > 1\) AS duplicates`
+	)
+
+	tests := []struct {
+		name     string
+		mockFunc func(sqlMock sqlmock.Sqlmock)
+		wantErr  bool
+	}{
+		{
+			name: "new database",
+			mockFunc: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectQuery(columnQuery).WithArgs("users", "username").WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(0))
+			},
+			wantErr: false,
+		},
+		{
+			name: "every value is unique",
+			mockFunc: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectQuery(columnQuery).WithArgs("users", "username").WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(1))
+				sqlMock.ExpectQuery(duplicateQuery).WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(0))
+			},
+			wantErr: false,
+		},
+		{
+			name: "duplicated usernames",
+			mockFunc: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectQuery(columnQuery).WithArgs("users", "username").WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(1))
+				sqlMock.ExpectQuery(duplicateQuery).WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(2))
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to look up the column",
+			mockFunc: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectQuery(columnQuery).WillReturnError(assert.AnError)
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to check duplicates",
+			mockFunc: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectQuery(columnQuery).WithArgs("users", "username").WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(1))
+				sqlMock.ExpectQuery(duplicateQuery).WillReturnError(assert.AnError)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, sqlMock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sqlServer.Close()
+
+			tt.mockFunc(sqlMock)
+
+			sqlClient, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      sqlServer,
+				SkipInitializeWithVersion: true,
+			}))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = checkUniqueColumns(sqlClient)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkUniqueColumns() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestIsDuplicateKey(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "duplicate entry",
+			err:  &mysqldriver.MySQLError{Number: 1062, Message: "Duplicate entry 'budi' for key 'idx_users_username'"},
+			want: true,
+		},
+		{
+			name: "wrapped duplicate entry",
+			err:  fmt.Errorf("failed to create user. err: %w", &mysqldriver.MySQLError{Number: 1062}),
+			want: true,
+		},
+		{
+			name: "other mysql error",
+			err:  &mysqldriver.MySQLError{Number: 1213},
+			want: false,
+		},
+		{
+			name: "other error",
+			err:  errors.New("connection refused"),
+			want: false,
+		},
+		{
+			name: "no error",
+			err:  nil,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsDuplicateKey(tt.err))
+		})
+	}
+}
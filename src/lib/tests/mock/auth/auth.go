@@ -36,10 +36,10 @@ func (m *MockInterface) EXPECT() *MockInterfaceMockRecorder {
 }
 
 // GenerateGuestToken mocks base method.
-func (m *MockInterface) GenerateGuestToken() (string, error) {
+func (m *MockInterface) GenerateGuestToken() (auth.Token, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GenerateGuestToken")
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(auth.Token)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -50,11 +50,26 @@ func (mr *MockInterfaceMockRecorder) GenerateGuestToken() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateGuestToken", reflect.TypeOf((*MockInterface)(nil).GenerateGuestToken))
 }
 
+// GenerateRefreshToken mocks base method.
+func (m *MockInterface) GenerateRefreshToken() (auth.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateRefreshToken")
+	ret0, _ := ret[0].(auth.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateRefreshToken indicates an expected call of GenerateRefreshToken.
+func (mr *MockInterfaceMockRecorder) GenerateRefreshToken() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateRefreshToken", reflect.TypeOf((*MockInterface)(nil).GenerateRefreshToken))
+}
+
 // GenerateToken mocks base method.
-func (m *MockInterface) GenerateToken(user auth.User) (string, error) {
+func (m *MockInterface) GenerateToken(user auth.User) (auth.Token, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GenerateToken", user)
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(auth.Token)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -80,8 +95,23 @@ func (mr *MockInterfaceMockRecorder) GetUserAuthInfo(ctx interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserAuthInfo", reflect.TypeOf((*MockInterface)(nil).GetUserAuthInfo), ctx)
 }
 
+// ParseToken mocks base method.
+func (m *MockInterface) ParseToken(tokenString string) (auth.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseToken", tokenString)
+	ret0, _ := ret[0].(auth.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParseToken indicates an expected call of ParseToken.
+func (mr *MockInterfaceMockRecorder) ParseToken(tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseToken", reflect.TypeOf((*MockInterface)(nil).ParseToken), tokenString)
+}
+
 // SetUserAuthInfo mocks base method.
-func (m *MockInterface) SetUserAuthInfo(ctx context.Context, user auth.User, token string) context.Context {
+func (m *MockInterface) SetUserAuthInfo(ctx context.Context, user auth.User, token auth.Token) context.Context {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SetUserAuthInfo", ctx, user, token)
 	ret0, _ := ret[0].(context.Context)
@@ -0,0 +1,19 @@
+package timeutils
+
+import (
+	"fmt"
+	"time"
+)
+
+// UTCOffset formats the zone offset of t as ±HH:MM, the form accepted by MySQL CONVERT_TZ.
+func UTCOffset(t time.Time) string {
+	_, offset := t.Zone()
+
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+
+	return fmt.Sprintf("%s%02d:%02d", sign, offset/3600, (offset%3600)/60)
+}
@@ -1,24 +1,26 @@
 package timeutils
 
 import (
-	"fmt"
+	"context"
+	"go-clean/src/lib/i18n"
 	"time"
 )
 
-func DiffForHumans(t time.Time) string {
+// DiffForHumans describes how long ago t was in the language of ctx.
+func DiffForHumans(ctx context.Context, t time.Time) string {
 	now := time.Now()
 	duration := now.Sub(t)
 
 	switch {
 	case duration.Hours() < 1:
-		return fmt.Sprintf("%d menit yang lalu", int(duration.Minutes()))
+		return i18n.Plural(ctx, "time.minutes_ago", int(duration.Minutes()))
 	case duration.Hours() < 24:
-		return fmt.Sprintf("%d jam yang lalu", int(duration.Hours()))
+		return i18n.Plural(ctx, "time.hours_ago", int(duration.Hours()))
 	case duration.Hours() < 24*30:
-		return fmt.Sprintf("%d hari yang lalu", int(duration.Hours()/24))
+		return i18n.Plural(ctx, "time.days_ago", int(duration.Hours()/24))
 	case duration.Hours() < 24*30*12:
-		return fmt.Sprintf("%d bulan yang lalu", int(duration.Hours()/(24*30)))
+		return i18n.Plural(ctx, "time.months_ago", int(duration.Hours()/(24*30)))
 	default:
-		return fmt.Sprintf("%d tahun yang lalu", int(duration.Hours()/(24*30*12)))
+		return i18n.Plural(ctx, "time.years_ago", int(duration.Hours()/(24*30*12)))
 	}
 }
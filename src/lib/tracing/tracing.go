@@ -0,0 +1,160 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+
+	instrumentationName = "go-clean"
+	defaultServiceName  = "go-clean"
+)
+
+type Interface interface {
+	// Shutdown flushes the spans that are still buffered.
+	Shutdown(ctx context.Context) error
+}
+
+type Config struct {
+	Enabled     bool
+	ServiceName string
+	// Exporter is otlp to send spans to Endpoint over OTLP/HTTP or stdout to print them for local use
+	Exporter string
+	// Endpoint is the host:port of the OTLP/HTTP collector, defaults to localhost:4318
+	Endpoint string
+	Insecure bool
+	// SampleRatio is the share of new traces that are recorded, defaults to 1
+	SampleRatio float64
+}
+
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	errs := []string{}
+	if c.Exporter != ExporterOTLP && c.Exporter != ExporterStdout {
+		errs = append(errs, fmt.Sprintf("Tracing.Exporter %q must be %s or %s", c.Exporter, ExporterOTLP, ExporterStdout))
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		errs = append(errs, fmt.Sprintf("Tracing.SampleRatio %v must be between 0 and 1", c.SampleRatio))
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+func (c Config) GetServiceName() string {
+	if c.ServiceName == "" {
+		return defaultServiceName
+	}
+
+	return c.ServiceName
+}
+
+type tracing struct {
+	provider *sdktrace.TracerProvider
+}
+
+// Init installs the global tracer provider, spans are dropped when tracing is disabled.
+func Init(cfg Config) Interface {
+	t := &tracing{}
+	if !cfg.Enabled {
+		return t
+	}
+
+	exporter, err := newExporter(cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceNameKey.String(cfg.GetServiceName()),
+	))
+	if err != nil {
+		panic(err)
+	}
+
+	sampleRatio := cfg.SampleRatio
+	if sampleRatio == 0 {
+		sampleRatio = 1
+	}
+
+	t.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	)
+
+	otel.SetTracerProvider(t.provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return t
+}
+
+func newExporter(cfg Config) (sdktrace.SpanExporter, error) {
+	if cfg.Exporter == ExporterStdout {
+		return stdouttrace.New()
+	}
+
+	opts := []otlptracehttp.Option{}
+	if cfg.Endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	return otlptracehttp.New(context.Background(), opts...)
+}
+
+func (t *tracing) Shutdown(ctx context.Context) error {
+	if t.provider == nil {
+		return nil
+	}
+
+	return t.provider.Shutdown(ctx)
+}
+
+// Start opens a span as a child of the span in ctx.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// End marks the span as failed when err isn't nil and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TraceID returns the id of the trace in ctx, empty when ctx isn't traced.
+func TraceID(ctx context.Context) string {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.HasTraceID() {
+		return ""
+	}
+
+	return spanCtx.TraceID().String()
+}
@@ -1,16 +1,36 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"go-clean/src/business/usecase"
+	"go-clean/src/lib/auth"
+	"go-clean/src/lib/health"
+	"go-clean/src/lib/log"
+	"go-clean/src/lib/metrics"
 	"go-clean/src/lib/midtrans"
+	"go-clean/src/lib/ratelimit"
 	"go-clean/src/lib/sql"
+	"go-clean/src/lib/tracing"
+	"net"
+	"strings"
 	"time"
+
+	"github.com/gin-gonic/gin"
 )
 
 type Application struct {
-	Meta     ApplicationMeta
-	Gin      GinConfig
-	SQL      sql.Config
-	Midtrans midtrans.Config
+	Meta      ApplicationMeta
+	Gin       GinConfig
+	Log       log.Config
+	SQL       sql.Config
+	Auth      auth.Config
+	Midtrans  midtrans.Config
+	RateLimit ratelimit.Config
+	Metrics   metrics.Config
+	Tracing   tracing.Config
+	Health    health.Config
+	Usecase   usecase.Config
 }
 
 type ApplicationMeta struct {
@@ -22,18 +42,94 @@ type ApplicationMeta struct {
 }
 
 type GinConfig struct {
-	Port            string
-	Mode            string
+	Port string
+	// Mode is the gin mode, debug, release or test
+	Mode string
+	// Timeout is used for ReadTimeout and WriteTimeout when they aren't set
 	Timeout         time.Duration
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	IdleTimeout     time.Duration
 	ShutdownTimeout time.Duration
-	CORS            CORSConfig
-	Meta            ApplicationMeta
+	// DrainDelay keeps serving after SIGTERM with a failing readiness probe so the load balancer can stop
+	// routing traffic before the server shuts down
+	DrainDelay time.Duration
+	// TrustedProxies lists the IPs or CIDRs of the proxies allowed to set X-Forwarded-For, none when empty so the
+	// client IP used for login backoff, rate limits and audit logs can't be forged
+	TrustedProxies []string
+	CORS           CORSConfig
+	TLS            TLSConfig
+	Meta           ApplicationMeta
 }
 
 type CORSConfig struct {
-	Mode string
+	// Mode is allowall to accept any origin or allowlist to accept only AllowOrigins
+	Mode             string
+	AllowOrigins     []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+type TLSConfig struct {
+	Enabled  bool
+	CertFile string
+	KeyFile  string
 }
 
 func Init() Application {
 	return Application{}
 }
+
+// Validate reports every invalid setting at once so a broken deployment can be fixed in one go.
+func (a Application) Validate() error {
+	errs := []string{}
+	for _, v := range []interface{ Validate() error }{a.Gin, a.Log, a.SQL, a.Auth, a.Midtrans, a.RateLimit, a.Metrics, a.Tracing, a.Health, a.Usecase} {
+		if err := v.Validate(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config:\n- %s", strings.Join(errs, "\n- "))
+	}
+
+	return nil
+}
+
+func (g GinConfig) Validate() error {
+	errs := []string{}
+
+	switch g.Mode {
+	case "", gin.DebugMode, gin.ReleaseMode, gin.TestMode:
+	default:
+		errs = append(errs, fmt.Sprintf("Gin.Mode %q must be %s, %s or %s", g.Mode, gin.DebugMode, gin.ReleaseMode, gin.TestMode))
+	}
+
+	switch g.CORS.Mode {
+	case "", "allowall":
+	case "allowlist":
+		if len(g.CORS.AllowOrigins) == 0 {
+			errs = append(errs, "Gin.CORS.AllowOrigins is required in allowlist mode")
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("Gin.CORS.Mode %q must be allowall or allowlist", g.CORS.Mode))
+	}
+
+	for _, proxy := range g.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				errs = append(errs, fmt.Sprintf("Gin.TrustedProxies %q must be an IP or a CIDR", proxy))
+			}
+		}
+	}
+
+	if g.TLS.Enabled && (g.TLS.CertFile == "" || g.TLS.KeyFile == "") {
+		errs = append(errs, "Gin.TLS.CertFile and Gin.TLS.KeyFile are required when TLS is enabled")
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"go-clean/src/lib/auth"
+	"go-clean/src/lib/midtrans"
+	"go-clean/src/lib/sql"
+	"go-clean/src/lib/tracing"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplication_Validate(t *testing.T) {
+	valid := func() Application {
+		return Application{
+			SQL: sql.Config{
+				Host:     "localhost",
+				Port:     "3306",
+				Username: "root",
+				Database: "go_clean",
+			},
+			Auth: auth.Config{
+				Key: "secret",
+			},
+			Midtrans: midtrans.Config{
+				ServerKey: "server-key",
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		app      func() Application
+		wantErrs []string
+	}{
+		{
+			name:     "valid",
+			app:      valid,
+			wantErrs: nil,
+		},
+		{
+			name: "every invalid field of one section",
+			app: func() Application {
+				a := valid()
+				a.Gin = GinConfig{
+					Mode:           "prod",
+					CORS:           CORSConfig{Mode: "allowlist"},
+					TrustedProxies: []string{"10.0.0.1", "proxy"},
+					TLS:            TLSConfig{Enabled: true},
+				}
+				return a
+			},
+			wantErrs: []string{
+				`Gin.Mode "prod" must be debug, release or test`,
+				"Gin.CORS.AllowOrigins is required in allowlist mode",
+				`Gin.TrustedProxies "proxy" must be an IP or a CIDR`,
+				"Gin.TLS.CertFile and Gin.TLS.KeyFile are required when TLS is enabled",
+			},
+		},
+		{
+			name: "invalid fields across sections",
+			app: func() Application {
+				a := valid()
+				a.SQL.Host = ""
+				a.Auth = auth.Config{
+					Keys:        map[string]string{"v1": "", "v2": ""},
+					ActiveKeyID: "v3",
+				}
+				a.Tracing = tracing.Config{
+					Enabled:     true,
+					Exporter:    "zipkin",
+					SampleRatio: 2,
+				}
+				return a
+			},
+			wantErrs: []string{
+				"SQL.Host is required",
+				"Auth.Keys.v1 is empty",
+				"Auth.Keys.v2 is empty",
+				`Auth.ActiveKeyID "v3" is not listed in Auth.Keys`,
+				`Tracing.Exporter "zipkin" must be otlp or stdout`,
+				"Tracing.SampleRatio 2 must be between 0 and 1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.app().Validate()
+			if len(tt.wantErrs) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.Error(t, err)
+			for _, want := range tt.wantErrs {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}